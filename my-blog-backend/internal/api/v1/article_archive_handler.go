@@ -0,0 +1,193 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	dtoRequest "my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/pkg/middleware"
+	"my-blog-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArticleArchiveHandler struct {
+	archiveService services.ArticleArchiveService
+	maxImportSize  int64
+}
+
+func NewArticleArchiveHandler(archiveService services.ArticleArchiveService, maxImportSize int64) *ArticleArchiveHandler {
+	return &ArticleArchiveHandler{
+		archiveService: archiveService,
+		maxImportSize:  maxImportSize,
+	}
+}
+
+// Export 导出全部文章
+// @Summary 导出全部文章为 Markdown 归档
+// @Description zip 归档，articles/ 下为带 YAML front matter 的 Markdown 文件，images/ 下为文章引用的本地图片
+// @Tags 文章管理
+// @Produce application/zip
+// @Success 200 {file} file "zip 归档"
+// @Router /api/v1/rbac/articles/export [get]
+func (h *ArticleArchiveHandler) Export(c *gin.Context) {
+	filename := fmt.Sprintf("articles-%s.zip", time.Now().Format("20060102-150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	// 归档直接写入响应，开始写入后出错只能中断响应
+	if err := h.archiveService.Export(c.Writer); err != nil {
+		logger.Error("导出文章失败", logger.Err("error", err))
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+// Import 导入文章归档
+// @Summary 导入 Markdown 归档
+// @Description 按 slug 新建或覆盖文章，自动新建缺少的分类和标签，上传归档中引用的图片并改写链接；dryRun 只返回导入报告
+// @Tags 文章管理
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "zip 归档"
+// @Param dryRun formData bool false "只检查不写入"
+// @Param onConflict formData string false "站内文章在导出后被修改时的处理方式: skip（默认）、overwrite"
+// @Success 200 {object} response.Response{data=response.ArticleArchiveImportReport}
+// @Router /api/v1/rbac/articles/import/archive [post]
+func (h *ArticleArchiveHandler) Import(c *gin.Context) {
+	var req dtoRequest.ImportArticleArchiveRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误", err)
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "获取文件失败", err)
+		return
+	}
+	if !strings.EqualFold(filepath.Ext(file.Filename), ".zip") {
+		response.Error(c, http.StatusBadRequest, "只支持 .zip 格式的归档", nil)
+		return
+	}
+	if file.Size > h.maxImportSize {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("归档大小不能超过 %d MB", h.maxImportSize/(1024*1024)), nil)
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		logger.Error("打开文件失败", logger.Err("error", err))
+		response.Error(c, http.StatusInternalServerError, "打开文件失败", err)
+		return
+	}
+	defer src.Close()
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	report, err := h.archiveService.Import(src, file.Size, &services.ArticleArchiveImportOptions{
+		DryRun:    req.DryRun,
+		Overwrite: req.OnConflict == "overwrite",
+		EditorID:  uint(userID),
+	})
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "导入失败", err)
+		return
+	}
+
+	logger.Info("导入文章归档",
+		logger.String("filename", file.Filename),
+		logger.Bool("dry_run", report.DryRun),
+		logger.Int("created", report.Created),
+		logger.Int("updated", report.Updated),
+		logger.Int("failed", report.Failed),
+	)
+	response.Success(c, report, "")
+}
+
+// ImportBlog 导入其他博客系统的文章
+// @Summary 导入 Hexo、Hugo、Jekyll 或 WordPress
+// @Description Hexo、Hugo、Jekyll 上传站点目录的 zip 归档，WordPress 上传导出的 WXR 文件（.xml）；
+// @Description 分类、标签、图片的处理与归档导入相同，原站图片下载后重新上传，WordPress 评论一并导入，文章原地址保存为 301 重定向
+// @Tags 文章管理
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "站点目录 zip 归档或 WXR 文件"
+// @Param format formData string true "博客系统: hexo、hugo、jekyll、wordpress"
+// @Param dryRun formData bool false "只检查不写入"
+// @Param onConflict formData string false "冲突处理方式: skip（默认）、overwrite"
+// @Param siteUrl formData string false "原站地址，默认取原站配置"
+// @Param permalink formData string false "原站文章链接格式，默认取原站配置"
+// @Param downloadImages formData string false "远程图片下载范围: none、site（默认）、all"
+// @Success 200 {object} response.Response{data=response.ArticleArchiveImportReport}
+// @Router /api/v1/rbac/articles/import/blog [post]
+func (h *ArticleArchiveHandler) ImportBlog(c *gin.Context) {
+	var req dtoRequest.ImportBlogRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误", err)
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "获取文件失败", err)
+		return
+	}
+	ext := ".zip"
+	if req.Format == "wordpress" {
+		ext = ".xml"
+	}
+	if !strings.EqualFold(filepath.Ext(file.Filename), ext) {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("%s 只支持 %s 格式的文件", req.Format, ext), nil)
+		return
+	}
+	if file.Size > h.maxImportSize {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("文件大小不能超过 %d MB", h.maxImportSize/(1024*1024)), nil)
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		logger.Error("打开文件失败", logger.Err("error", err))
+		response.Error(c, http.StatusInternalServerError, "打开文件失败", err)
+		return
+	}
+	defer src.Close()
+
+	downloadImages := req.DownloadImages
+	if downloadImages == "" {
+		downloadImages = services.ImageDownloadSite
+	}
+	userID, _ := middleware.GetCurrentUserID(c)
+	report, err := h.archiveService.ImportBlog(req.Format, src, file.Size, &services.BlogImportOptions{
+		ArticleArchiveImportOptions: services.ArticleArchiveImportOptions{
+			DryRun:    req.DryRun,
+			Overwrite: req.OnConflict == "overwrite",
+			EditorID:  uint(userID),
+		},
+		SiteURL:        req.SiteURL,
+		Permalink:      req.Permalink,
+		DownloadImages: downloadImages,
+	})
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "导入失败", err)
+		return
+	}
+
+	logger.Info("导入博客",
+		logger.String("format", req.Format),
+		logger.String("filename", file.Filename),
+		logger.Bool("dry_run", report.DryRun),
+		logger.Int("created", report.Created),
+		logger.Int("updated", report.Updated),
+		logger.Int("failed", report.Failed),
+		logger.Int("comments", report.Comments),
+		logger.Int("redirects", report.Redirects),
+	)
+	response.Success(c, report, "")
+}
@@ -1,12 +1,14 @@
 package api
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	dtoRequest "my-blog-backend/internal/api/v1/dto/request"
 	"my-blog-backend/internal/api/v1/dto/response"
@@ -19,12 +21,19 @@ import (
 )
 
 type ArticleHandler struct {
-	articleService services.ArticleService
+	articleService  services.ArticleService
+	seoService      services.SEOService
+	markdownService services.MarkdownService
+	viewService     services.ArticleViewService
 }
 
-func NewArticleHandler(articleService services.ArticleService) *ArticleHandler {
+func NewArticleHandler(articleService services.ArticleService, seoService services.SEOService, markdownService services.MarkdownService,
+	viewService services.ArticleViewService) *ArticleHandler {
 	return &ArticleHandler{
-		articleService: articleService,
+		articleService:  articleService,
+		seoService:      seoService,
+		markdownService: markdownService,
+		viewService:     viewService,
 	}
 }
 
@@ -43,19 +52,32 @@ func (h *ArticleHandler) CreateArticle(c *gin.Context) {
 		return
 	}
 
+	publishAt, unpublishAt, err := parseScheduleTimes(req.PublishedAt, req.UnpublishAt)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "时间格式错误", err)
+		return
+	}
+
 	// 从JWT中获取当前用户ID
-	var userID uint = 1
+	userID, _ := middleware.GetCurrentUserID(c)
 
 	article := &models.Article{
-		Title:      req.Title,
-		Content:    req.Content,
-		Summary:    req.Summary,
-		Cover:      response.SafeDerefString(req.Cover),
-		CategoryID: response.SafeDerefUint64(req.CategoryID),
-		AuthorID:   uint64(userID),
-		Status:     req.Status,
-		IsTop:      req.IsTop,
-		Slug:       req.Slug,
+		Title:       req.Title,
+		Content:     req.Content,
+		Summary:     req.Summary,
+		Cover:       response.SafeDerefString(req.Cover),
+		CategoryID:  response.SafeDerefUint64(req.CategoryID),
+		AuthorID:    uint64(userID),
+		Status:      req.Status,
+		IsTop:       req.IsTop,
+		Slug:        req.Slug,
+		PublishedAt: publishAt,
+		UnpublishAt: unpublishAt,
+
+		MetaTitle:       req.MetaTitle,
+		MetaDescription: req.MetaDescription,
+		CanonicalURL:    req.CanonicalURL,
+		OGImage:         req.OGImage,
 	}
 
 	if err := h.articleService.CreateArticle(article, req.TagIDs); err != nil {
@@ -88,17 +110,32 @@ func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
 		return
 	}
 
-	article := &models.Article{
-		Title:      req.Title,
-		Content:    req.Content,
-		Summary:    req.Summary,
-		Cover:      response.SafeDerefString(req.Cover),
-		CategoryID: response.SafeDerefUint64(req.CategoryID),
-		Status:     req.Status,
-		IsTop:      req.IsTop,
+	publishAt, unpublishAt, err := parseScheduleTimes(req.PublishedAt, req.UnpublishAt)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "时间格式错误", err)
+		return
 	}
 
-	if err := h.articleService.UpdateArticle(uint(id), article, req.TagIDs); err != nil {
+	article := &models.Article{
+		Title:       req.Title,
+		Content:     req.Content,
+		Summary:     req.Summary,
+		Cover:       response.SafeDerefString(req.Cover),
+		CategoryID:  response.SafeDerefUint64(req.CategoryID),
+		Status:      req.Status,
+		IsTop:       req.IsTop,
+		Slug:        req.Slug,
+		PublishedAt: publishAt,
+		UnpublishAt: unpublishAt,
+
+		MetaTitle:       req.MetaTitle,
+		MetaDescription: req.MetaDescription,
+		CanonicalURL:    req.CanonicalURL,
+		OGImage:         req.OGImage,
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	if err := h.articleService.UpdateArticle(uint(id), article, req.TagIDs, uint(userID)); err != nil {
 		response.Error(c, http.StatusInternalServerError, "更新文章失败", err)
 		return
 	}
@@ -131,12 +168,13 @@ func (h *ArticleHandler) DeleteArticle(c *gin.Context) {
 
 // GetArticle 获取文章详情
 // @Summary 获取文章详情
+// @Description 只返回已发布文章，草稿和未到时间的定时文章返回 404
 // @Tags 文章管理
 // @Accept json
 // @Produce json
 // @Param id path int true "文章ID"
 // @Success 200 {object} response.Response
-// @Router /api/v1/articles/{id} [get]
+// @Router /api/v1/public/articles/{id} [get]
 func (h *ArticleHandler) GetArticle(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -150,12 +188,63 @@ func (h *ArticleHandler) GetArticle(c *gin.Context) {
 		return
 	}
 
+	h.articleDetail(c, article)
+}
+
+// GetArticleBySlug 根据 slug 获取文章详情
+// @Summary 根据slug获取文章详情
+// @Description 只返回已发布文章；slug 已修改时按旧 slug 返回文章，返回的 article.slug 与请求不同时前台应替换为新地址
+// @Tags 文章管理
+// @Produce json
+// @Param slug path string true "文章slug"
+// @Success 200 {object} response.Response{data=response.ArticleDetailResponse}
+// @Failure 404 {object} response.Response
+// @Router /api/v1/public/articles/slug/{slug} [get]
+func (h *ArticleHandler) GetArticleBySlug(c *gin.Context) {
+	article, err := h.articleService.GetArticleBySlug(c.Param("slug"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "文章不存在", err)
+		return
+	}
+
+	h.articleDetail(c, article)
+}
+
+// articleDetail 返回文章详情，附带 SEO 信息和渲染后的正文
+func (h *ArticleHandler) articleDetail(c *gin.Context, article *models.Article) {
 	// 构建完整的封面URL
 	if article.Cover != "" {
 		article.Cover = response.BuildFullURL(article.Cover)
 	}
 
-	response.Success(c, article, "")
+	// 渲染失败时仍返回原文，由前端自行渲染
+	rendered, err := h.markdownService.Render(article.Content)
+	if err != nil {
+		logger.Warn("渲染文章失败", logger.Uint("article_id", uint(article.ID)), logger.Err("error", err))
+	}
+
+	response.Success(c, response.ArticleDetailResponse{
+		Article:  article,
+		SEO:      h.seoService.ArticleMeta(article),
+		Rendered: rendered,
+	}, "")
+}
+
+// HighlightCSS 获取代码高亮样式表
+// @Summary 获取代码高亮样式表
+// @Description 文章详情 rendered.html 中的代码块使用 CSS 类名高亮，需引入此样式表
+// @Tags 文章管理
+// @Produce text/css
+// @Success 200 {string} string "样式表"
+// @Router /api/v1/public/articles/highlight.css [get]
+func (h *ArticleHandler) HighlightCSS(c *gin.Context) {
+	css, err := h.markdownService.HighlightCSS()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "生成样式表失败", err)
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "text/css; charset=utf-8", []byte(css))
 }
 
 // ListArticles 获取文章列表
@@ -215,16 +304,21 @@ func (h *ArticleHandler) ListArticles(c *gin.Context) {
 
 // SearchArticles 搜索文章
 // @Summary 搜索文章
+// @Description 全文检索已发布文章，按相关度排序（标题、标签、摘要权重高于正文），返回高亮片段
 // @Tags 文章管理
 // @Accept json
 // @Produce json
-// @Param keyword query string false "搜索关键词"
+// @Param keyword query string false "搜索关键词，为空时只按过滤条件查询"
+// @Param categoryId query int false "分类ID"
+// @Param tagId query int false "标签ID"
+// @Param startDate query string false "发布日期起始 (2006-01-02)"
+// @Param endDate query string false "发布日期截止 (2006-01-02)"
 // @Param page query int false "页码" default(1)
 // @Param pageSize query int false "每页数量" default(10)
-// @Success 200 {object} response.Response
+// @Success 200 {object} response.Response{data=response.SearchResponse}
 // @Router /api/v1/articles/search [get]
 func (h *ArticleHandler) SearchArticles(c *gin.Context) {
-	var req dtoRequest.ArticleListRequest
+	var req dtoRequest.SearchRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
 		response.Error(c, http.StatusBadRequest, "参数错误", err)
 		return
@@ -237,29 +331,32 @@ func (h *ArticleHandler) SearchArticles(c *gin.Context) {
 		req.PageSize = 10
 	}
 
-	articles, total, err := h.articleService.SearchArticles(req.Keyword, req.Page, req.PageSize)
+	result, err := h.articleService.SearchArticles(&req)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "搜索失败", err)
 		return
 	}
 
-	response.Success(c, gin.H{
-		"items":     articles,
-		"total":     total,
-		"page":      req.Page,
-		"page_size": req.PageSize,
-		"keyword":   req.Keyword,
-	}, "")
+	// 构建完整的封面URL
+	for i := range result.Items {
+		if result.Items[i].Cover != "" {
+			result.Items[i].Cover = response.BuildFullURL(result.Items[i].Cover)
+		}
+	}
+
+	response.Success(c, result, "")
 }
 
 // ViewArticle 查看文章（增加阅读量）
 // @Summary 查看文章（增加阅读量）
+// @Description 登录用户按用户去重，游客按 IP 和 User-Agent 去重，爬虫不计入；阅读量定时批量写入，会有短暂延迟
 // @Tags 文章管理
 // @Accept json
 // @Produce json
 // @Param id path int true "文章ID"
-// @Success 200 {object} response.Response
-// @Router /api/v1/articles/{id}/view [post]
+// @Success 200 {object} response.Response{data=bool} "是否计入阅读量"
+// @Failure 404 {object} response.Response
+// @Router /api/v1/public/articles/{id}/view [post]
 func (h *ArticleHandler) ViewArticle(c *gin.Context) {
 	var req dtoRequest.LikeArticleRequest
 	if err := c.ShouldBindUri(&req); err != nil {
@@ -267,12 +364,23 @@ func (h *ArticleHandler) ViewArticle(c *gin.Context) {
 		return
 	}
 
-	if err := h.articleService.ViewArticle(req.ArticleID); err != nil {
+	userID, _ := middleware.GetCurrentUserID(c)
+	counted, err := h.viewService.RecordView(req.ArticleID, &services.ArticleVisitor{
+		UserID:    userID,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrArticleNotFound) {
+			response.Error(c, http.StatusNotFound, "文章不存在", err)
+			return
+		}
+		logger.Error("记录文章阅读失败", logger.Uint("article_id", req.ArticleID), logger.Err("error", err))
 		response.Error(c, http.StatusInternalServerError, "操作失败", err)
 		return
 	}
 
-	response.Success(c, nil, "成功")
+	response.Success(c, counted, "成功")
 }
 
 // GetHotArticles 获取热门文章
@@ -481,11 +589,12 @@ func (h *ArticleHandler) CheckArticleLikeStatus(c *gin.Context) {
 
 // UpdateArticleStatus 更新文章状态
 // @Summary 更新文章状态
+// @Description 发布、转为草稿或定时发布，定时发布可同时设置定时下线时间
 // @Tags 文章管理
 // @Accept json
 // @Produce json
 // @Param id path int true "文章ID"
-// @Param request body object true "状态信息"
+// @Param request body dtoRequest.UpdateArticleStatusRequest true "状态信息"
 // @Success 200 {object} response.Response
 // @Router /api/v1/articles/{id}/status [put]
 func (h *ArticleHandler) UpdateArticleStatus(c *gin.Context) {
@@ -495,20 +604,20 @@ func (h *ArticleHandler) UpdateArticleStatus(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Status int `json:"status" binding:"required,oneof=0 1"`
-	}
+	var req dtoRequest.UpdateArticleStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.Error(c, http.StatusBadRequest, "参数错误", err)
 		return
 	}
 
-	article := &models.Article{
-		Status: uint8(req.Status),
+	publishAt, unpublishAt, err := parseScheduleTimes(req.PublishAt, req.UnpublishAt)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "时间格式错误", err)
+		return
 	}
 
-	if err := h.articleService.UpdateArticle(uint(id), article, nil); err != nil {
-		response.Error(c, http.StatusInternalServerError, "更新文章状态失败", err)
+	if err := h.articleService.UpdateArticleStatus(uint(id), req.Status, publishAt, unpublishAt); err != nil {
+		response.Error(c, http.StatusBadRequest, "更新文章状态失败", err)
 		return
 	}
 
@@ -592,3 +701,166 @@ func (h *ArticleHandler) ImportMarkdownArticle(c *gin.Context) {
 
 	response.Success(c, result, "文件上传成功")
 }
+
+// ListRevisions 获取文章修订历史
+// @Summary 获取文章修订历史
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=response.ArticleRevisionListResponse}
+// @Router /api/v1/rbac/articles/{id}/revisions [get]
+func (h *ArticleHandler) ListRevisions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "无效的文章ID", err)
+		return
+	}
+
+	var req dtoRequest.ListArticleRevisionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误", err)
+		return
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 20
+	}
+
+	result, err := h.articleService.ListRevisions(uint(id), req.Page, req.PageSize)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, err.Error(), err)
+		return
+	}
+
+	response.Success(c, result, "")
+}
+
+// GetRevision 获取文章指定修订
+// @Summary 获取文章指定修订
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param version path int true "修订版本号"
+// @Success 200 {object} response.Response{data=response.ArticleRevisionResponse}
+// @Router /api/v1/rbac/articles/{id}/revisions/{version} [get]
+func (h *ArticleHandler) GetRevision(c *gin.Context) {
+	id, version, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	revision, err := h.articleService.GetRevision(id, version)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, err.Error(), err)
+		return
+	}
+
+	response.Success(c, revision, "")
+}
+
+// DiffRevisions 对比文章两个修订
+// @Summary 对比文章两个修订
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param from query int true "起始版本号"
+// @Param to query int true "目标版本号"
+// @Param mode query string false "正文对比方式: line(按行) word(按词)" default(line)
+// @Success 200 {object} response.Response{data=response.ArticleRevisionDiffResponse}
+// @Router /api/v1/rbac/articles/{id}/diff [get]
+func (h *ArticleHandler) DiffRevisions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "无效的文章ID", err)
+		return
+	}
+
+	var req dtoRequest.DiffArticleRevisionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误", err)
+		return
+	}
+
+	diff, err := h.articleService.DiffRevisions(uint(id), req.From, req.To, req.Mode)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, err.Error(), err)
+		return
+	}
+
+	response.Success(c, diff, "")
+}
+
+// RestoreRevision 恢复文章到指定修订
+// @Summary 恢复文章到指定修订
+// @Description 用指定修订的标题、摘要、正文和标签覆盖文章，并生成一个新的修订
+// @Tags 文章管理
+// @Accept json
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param version path int true "修订版本号"
+// @Success 200 {object} response.Response
+// @Router /api/v1/rbac/articles/{id}/revisions/{version}/restore [post]
+func (h *ArticleHandler) RestoreRevision(c *gin.Context) {
+	id, version, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	article, err := h.articleService.RestoreRevision(id, version, uint(userID))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "恢复修订失败", err)
+		return
+	}
+
+	response.Success(c, article, "恢复成功")
+}
+
+// parseRevisionParams 解析路径中的文章ID和修订版本号
+func parseRevisionParams(c *gin.Context) (uint, int, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "无效的文章ID", err)
+		return 0, 0, false
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		response.Error(c, http.StatusBadRequest, "无效的修订版本号", err)
+		return 0, 0, false
+	}
+	return uint(id), version, true
+}
+
+// parseScheduleTimes 解析定时发布和定时下线时间，支持 RFC3339 和 2006-01-02 15:04:05（服务器时区）
+func parseScheduleTimes(publishAt, unpublishAt *string) (*time.Time, *time.Time, error) {
+	parse := func(value *string) (*time.Time, error) {
+		if value == nil || *value == "" {
+			return nil, nil
+		}
+		if t, err := time.Parse(time.RFC3339, *value); err == nil {
+			return &t, nil
+		}
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", *value, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		return &t, nil
+	}
+
+	publish, err := parse(publishAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	unpublish, err := parse(unpublishAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return publish, unpublish, nil
+}
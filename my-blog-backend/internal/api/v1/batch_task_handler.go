@@ -0,0 +1,159 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	ws "github.com/gorilla/websocket"
+	"my-blog-backend/internal/api/v1/dto/request"
+	dtoResponse "my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/middleware"
+	"my-blog-backend/internal/services"
+)
+
+type BatchTaskHandler struct {
+	taskService *services.BatchTaskService
+}
+
+func NewBatchTaskHandler(taskService *services.BatchTaskService) *BatchTaskHandler {
+	return &BatchTaskHandler{taskService: taskService}
+}
+
+// taskOperator 当前请求的用户，提交批量任务时用于校验目标主机权限
+func taskOperator(c *gin.Context) services.TaskOperator {
+	userID, _ := middleware.GetCurrentUserID(c)
+	return services.TaskOperator{
+		UserID:     uint(userID),
+		SuperAdmin: middleware.IsSuperAdmin(middleware.GetCurrentRoleIDs(c)),
+	}
+}
+
+// taskErrorStatus 无权访问主机、任务或命令被安全策略禁止时返回 403，其余返回 fallback
+func taskErrorStatus(err error, fallback int) int {
+	if errors.Is(err, services.ErrHostAccessDenied) || errors.Is(err, services.ErrTaskAccessDenied) || errors.Is(err, services.ErrCommandDenied) {
+		return 403
+	}
+	return fallback
+}
+
+// ListTasks 批量任务列表
+// @Summary 批量任务列表
+// @Tags 批量任务
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param type query int false "任务类型"
+// @Param status query int false "任务状态"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.BatchTaskListResponse}
+// @Router /api/v1/rbac/tasks [get]
+func (h *BatchTaskHandler) ListTasks(c *gin.Context) {
+	var req request.ListBatchTaskRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	list, err := h.taskService.ListTasks(&req)
+	if err != nil {
+		dtoResponse.Error(c, 500, "获取任务列表失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, list, "获取成功")
+}
+
+// GetTask 获取任务详情（含每台主机的执行结果）
+// @Summary 获取任务详情
+// @Tags 批量任务
+// @Param id path int true "任务ID"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.BatchTaskDetailResponse}
+// @Router /api/v1/rbac/tasks/{id} [get]
+func (h *BatchTaskHandler) GetTask(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的任务ID", err)
+		return
+	}
+
+	task, err := h.taskService.GetTask(uint(id))
+	if err != nil {
+		dtoResponse.Error(c, 404, "任务不存在", err)
+		return
+	}
+
+	dtoResponse.Success(c, task, "获取成功")
+}
+
+// CancelTask 取消正在执行的任务
+// @Summary 取消任务
+// @Tags 批量任务
+// @Param id path int true "任务ID"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/tasks/{id}/cancel [post]
+func (h *BatchTaskHandler) CancelTask(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的任务ID", err)
+		return
+	}
+
+	if err := h.taskService.CancelTask(uint(id)); err != nil {
+		dtoResponse.Error(c, 400, err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, nil, "已取消")
+}
+
+// StreamTask 通过 WebSocket 实时推送任务输出，同一任务可被多个客户端同时查看
+// 连接后先收到快照（执行中主机已有的输出、已结束主机的结果），之后逐行推送 stdout/stderr 以及每台主机的退出码
+// @Summary 任务实时输出
+// @Tags 批量任务
+// @Param id path int true "任务ID"
+// @Success 101
+// @Router /api/v1/rbac/tasks/{id}/stream [get]
+func (h *BatchTaskHandler) StreamTask(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的任务ID", err)
+		return
+	}
+	if err := h.taskService.CheckTaskAccess(uint(id), taskOperator(c)); err != nil {
+		dtoResponse.Error(c, taskErrorStatus(err, 404), err.Error(), err)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+
+	client, snapshot, finished, err := h.taskService.SubscribeTask(uint(id), conn)
+	if err != nil {
+		conn.WriteMessage(ws.TextMessage, []byte(err.Error()))
+		conn.Close()
+		return
+	}
+
+	// 快照在 WritePump 启动前写入，保证先于实时消息到达
+	for _, msg := range snapshot {
+		conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		if err := conn.WriteJSON(msg); err != nil {
+			client.Hub.Unregister(client)
+			conn.Close()
+			return
+		}
+	}
+	if finished {
+		client.Hub.Unregister(client)
+		conn.WriteControl(ws.CloseMessage, ws.FormatCloseMessage(ws.CloseNormalClosure, ""), time.Now().Add(wsWriteWait))
+		conn.Close()
+		return
+	}
+
+	go client.WritePump()
+	client.ReadPump()
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"my-blog-backend/internal/api/v1/dto/request"
+	dtoResponse "my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/middleware"
+	"my-blog-backend/internal/services"
+)
+
+type CommandExecHandler struct {
+	hostService *services.HostService
+	execService *services.CommandExecService
+}
+
+func NewCommandExecHandler(hostService *services.HostService, execService *services.CommandExecService) *CommandExecHandler {
+	return &CommandExecHandler{hostService: hostService, execService: execService}
+}
+
+// ExecCommand 在主机上非交互执行命令（支持 API Token 认证，供 CI 等自动化调用）
+// @Summary 执行命令
+// @Tags 主机管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "主机ID"
+// @Param request body request.ExecCommandRequest true "命令"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.ExecCommandResponse}
+// @Router /api/v1/rbac/hosts/{id}/exec [post]
+func (h *CommandExecHandler) ExecCommand(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的主机ID", err)
+		return
+	}
+
+	var req request.ExecCommandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	superAdmin := middleware.IsSuperAdmin(middleware.GetCurrentRoleIDs(c))
+	if err := h.hostService.CheckHostAccess(uint(userID), superAdmin, uint(id)); err != nil {
+		if errors.Is(err, services.ErrHostAccessDenied) {
+			dtoResponse.Error(c, 403, err.Error(), err)
+			return
+		}
+		dtoResponse.Error(c, 500, "校验主机权限失败", err)
+		return
+	}
+
+	result, err := h.execService.Exec(c.Request.Context(), uint(userID), uint(id), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrCommandDenied) {
+			dtoResponse.Error(c, 403, err.Error(), err)
+			return
+		}
+		dtoResponse.Error(c, 500, "执行命令失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, result, "执行完成")
+}
@@ -268,6 +268,9 @@ func (h *CommentHandler) GetReplies(c *gin.Context) {
 				Name:   name,
 				Avatar: comment.User.Avatar,
 			}
+		} else if comment.AuthorName != "" {
+			// 导入的游客评论没有关联用户
+			item.Author = &response.CommentAuthorWithInfo{Name: comment.AuthorName}
 		}
 
 		// 设置被回复的用户信息（如果有）
@@ -282,6 +285,9 @@ func (h *CommentHandler) GetReplies(c *gin.Context) {
 				Name:   replyToName,
 				Avatar: comment.Parent.User.Avatar,
 			}
+		} else if comment.Parent != nil && comment.Parent.AuthorName != "" {
+			item.ReplyTo = comment.Parent.ID
+			item.ReplyToUser = &response.CommentAuthorWithInfo{Name: comment.Parent.AuthorName}
 		}
 
 		items = append(items, item)
@@ -431,6 +437,9 @@ func (h *CommentHandler) ListComments(c *gin.Context) {
 				Name:   name,
 				Avatar: comment.User.Avatar,
 			}
+		} else if comment.AuthorName != "" {
+			// 导入的游客评论没有关联用户
+			item.Author = &response.CommentAuthorWithInfo{Name: comment.AuthorName}
 		}
 
 		items = append(items, item)
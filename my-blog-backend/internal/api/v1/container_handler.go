@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	dtoResponse "my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/middleware"
+	"my-blog-backend/internal/services"
+)
+
+type ContainerHandler struct {
+	hostService      *services.HostService
+	containerService *services.ContainerService
+}
+
+func NewContainerHandler(hostService *services.HostService, containerService *services.ContainerService) *ContainerHandler {
+	return &ContainerHandler{hostService: hostService, containerService: containerService}
+}
+
+// ListContainers 列出主机上可进入的运行中容器
+// @Summary 容器列表
+// @Tags 主机管理
+// @Param id path int true "主机ID"
+// @Success 200 {object} dtoResponse.Response{data=[]dtoResponse.ContainerResponse}
+// @Router /api/v1/rbac/hosts/{id}/containers [get]
+func (h *ContainerHandler) ListContainers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的主机ID", err)
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	superAdmin := middleware.IsSuperAdmin(middleware.GetCurrentRoleIDs(c))
+	if err := h.hostService.CheckHostAccess(uint(userID), superAdmin, uint(id)); err != nil {
+		if errors.Is(err, services.ErrHostAccessDenied) {
+			dtoResponse.Error(c, 403, err.Error(), err)
+			return
+		}
+		dtoResponse.Error(c, 500, "校验主机权限失败", err)
+		return
+	}
+
+	containers, err := h.containerService.ListContainers(c.Request.Context(), uint(userID), superAdmin, uint(id))
+	if err != nil {
+		dtoResponse.Error(c, 500, "获取容器列表失败: "+err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, containers, "获取成功")
+}
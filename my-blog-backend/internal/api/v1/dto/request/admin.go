@@ -9,10 +9,17 @@ type CreateArticleRequest struct {
 	CategoryID  *uint64 `json:"categoryId" binding:"omitempty"`
 	TagIDs      []uint  `json:"tags" binding:"omitempty"`
 	AuthorID    uint64  `json:"author_id" binding:"omitempty"`
-	Status      uint8   `json:"status" binding:"omitempty,oneof=0 1" default:"1"`
-	PublishedAt *string `json:"publishedAt" binding:"omitempty"`
+	Status      uint8   `json:"status" binding:"omitempty,oneof=0 1 2" default:"1"` // 0-草稿，1-发布，2-定时发布
+	PublishedAt *string `json:"publishedAt" binding:"omitempty"`                    // 定时发布时间，status=2 时必填
+	UnpublishAt *string `json:"unpublishAt" binding:"omitempty"`                    // 定时下线时间，可选
 	IsTop       bool    `json:"is_top" binding:"omitempty"`
 	Slug        string  `json:"slug" binding:"required,max=200"`
+
+	// SEO 信息，为空时使用默认值
+	MetaTitle       string `json:"metaTitle" binding:"max=200"`
+	MetaDescription string `json:"metaDescription" binding:"max=500"`
+	CanonicalURL    string `json:"canonicalUrl" binding:"omitempty,url,max=500"`
+	OGImage         string `json:"ogImage" binding:"max=500"`
 }
 
 // UpdateArticleRequest 更新文章请求
@@ -20,6 +27,13 @@ type UpdateArticleRequest struct {
 	CreateArticleRequest
 }
 
+// UpdateArticleStatusRequest 更新文章状态请求，时间格式为 RFC3339 或 2006-01-02 15:04:05
+type UpdateArticleStatusRequest struct {
+	Status      uint8   `json:"status" binding:"oneof=0 1 2"`    // 0-草稿，1-发布，2-定时发布
+	PublishAt   *string `json:"publishAt" binding:"omitempty"`   // 定时发布时间，status=2 时必填
+	UnpublishAt *string `json:"unpublishAt" binding:"omitempty"` // 定时下线时间，可选
+}
+
 // ArticleIDRequest 文章ID请求
 type ArticleIDRequest struct {
 	ID uint `uri:"id" binding:"required"`
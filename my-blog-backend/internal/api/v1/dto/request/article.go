@@ -20,3 +20,32 @@ type LikeArticleRequest struct {
 type FavoriteArticleRequest struct {
 	ArticleID uint `uri:"id" binding:"required"`
 }
+
+// ListArticleRevisionRequest 获取文章修订列表请求
+type ListArticleRevisionRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1" default:"1"`
+	PageSize int `form:"pageSize" binding:"omitempty,min=1,max=100" default:"20"`
+}
+
+// DiffArticleRevisionRequest 对比文章修订请求
+type DiffArticleRevisionRequest struct {
+	From int    `form:"from" binding:"required,min=1"`
+	To   int    `form:"to" binding:"required,min=1"`
+	Mode string `form:"mode" binding:"omitempty,oneof=line word"` // 正文对比方式：line 按行（默认）、word 按词
+}
+
+// ImportArticleArchiveRequest 批量导入文章归档，归档文件通过 multipart 表单的 file 字段上传
+type ImportArticleArchiveRequest struct {
+	DryRun     bool   `form:"dryRun"`                                              // 只检查不写入，返回导入报告
+	OnConflict string `form:"onConflict" binding:"omitempty,oneof=skip overwrite"` // 站内文章在导出后被修改时的处理方式：skip 跳过（默认）、overwrite 覆盖
+}
+
+// ImportBlogRequest 导入其他博客系统，Hexo、Hugo、Jekyll 上传站点目录的 zip 归档，WordPress 上传 WXR 文件
+type ImportBlogRequest struct {
+	Format         string `form:"format" binding:"required,oneof=hexo hugo jekyll wordpress"` // 博客系统
+	DryRun         bool   `form:"dryRun"`                                                     // 只检查不写入，返回导入报告
+	OnConflict     string `form:"onConflict" binding:"omitempty,oneof=skip overwrite"`        // 站内已有同 slug 文章且在原站最后修改后被修改过时的处理方式
+	SiteURL        string `form:"siteUrl" binding:"omitempty,url"`                            // 原站地址，默认取原站配置
+	Permalink      string `form:"permalink"`                                                  // 原站文章链接格式，默认取原站配置
+	DownloadImages string `form:"downloadImages" binding:"omitempty,oneof=none site all"`     // 远程图片下载范围：none、site（默认，只下载原站图片）、all
+}
@@ -0,0 +1,29 @@
+package request
+
+type ListBatchTaskRequest struct {
+	Page     int  `form:"page,default=1"`
+	PageSize int  `form:"page_size,default=10"`
+	Type     uint `form:"type" binding:"omitempty,min=1,max=5"`
+	Status   uint `form:"status" binding:"omitempty,min=1,max=5"`
+}
+
+// DistributeFileRequest 文件分发（multipart 表单，文件字段名为 file）
+type DistributeFileRequest struct {
+	Name       string `form:"name" binding:"max=100"`
+	HostIDs    []uint `form:"host_ids" binding:"required_without=Selector"`
+	Selector   string `form:"selector" binding:"max=500"`             // 标签选择器，执行时解析，与 host_ids 取并集
+	TargetPath string `form:"target_path" binding:"required,max=500"` // 以 / 结尾时视为目录
+	Mode       string `form:"mode" binding:"omitempty,max=4"`         // 八进制权限，如 0644
+	Owner      string `form:"owner" binding:"max=100"`                // user 或 user:group
+	Backup     bool   `form:"backup"`
+	Timeout    int    `form:"timeout" binding:"omitempty,min=1,max=86400"` // 秒
+}
+
+// CollectFileRequest 从多台主机拉取同一路径的文件
+type CollectFileRequest struct {
+	Name       string `json:"name" binding:"max=100"`
+	HostIDs    []uint `json:"host_ids" binding:"required_without=Selector"`
+	Selector   string `json:"selector" binding:"max=500"` // 标签选择器，执行时解析，与 host_ids 取并集
+	SourcePath string `json:"source_path" binding:"required,max=500"`
+	Timeout    int    `json:"timeout" binding:"omitempty,min=1,max=86400"` // 秒
+}
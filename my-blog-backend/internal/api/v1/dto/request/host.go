@@ -1,26 +1,28 @@
 package request
 
 type CreateHostRequest struct {
-	Name      string `json:"name" binding:"required"`
-	Address   string `json:"address" binding:"required"`
-	Port      int    `json:"port" binding:"required,min=1,max=65535"`
-	Username  string `json:"username" binding:"required"`
-	Password  string `json:"password"`
-	SecretKey string `json:"secret_key"`
-	Type      string `json:"type" binding:"required,oneof=password key both"` // password, key, both
-	Status    string `json:"status" binding:"omitempty,oneof=active inactive"`
+	Name      string            `json:"name" binding:"required"`
+	Address   string            `json:"address" binding:"required"`
+	Port      int               `json:"port" binding:"required,min=1,max=65535"`
+	Username  string            `json:"username" binding:"required"`
+	Password  string            `json:"password"`
+	SecretKey string            `json:"secret_key"`
+	Type      string            `json:"type" binding:"required,oneof=password key both"` // password, key, both
+	Status    string            `json:"status" binding:"omitempty,oneof=active inactive"`
+	Labels    map[string]string `json:"labels"` // 主机标签，如 {"env":"prod","role":"db"}
 }
 
 type UpdateHostRequest struct {
-	ID        uint   `json:"id" binding:"required"`
-	Name      string `json:"name" binding:"required"`
-	Address   string `json:"address" binding:"required"`
-	Port      int    `json:"port" binding:"required,min=1,max=65535"`
-	Username  string `json:"username" binding:"required"`
-	Password  string `json:"password"`
-	SecretKey string `json:"secret_key"`
-	Type      string `json:"type" binding:"required,oneof=password key both"`
-	Status    string `json:"status" binding:"omitempty,oneof=active inactive"`
+	ID        uint              `json:"id" binding:"required"`
+	Name      string            `json:"name" binding:"required"`
+	Address   string            `json:"address" binding:"required"`
+	Port      int               `json:"port" binding:"required,min=1,max=65535"`
+	Username  string            `json:"username" binding:"required"`
+	Password  string            `json:"password"`
+	SecretKey string            `json:"secret_key"`
+	Type      string            `json:"type" binding:"required,oneof=password key both"`
+	Status    string            `json:"status" binding:"omitempty,oneof=active inactive"`
+	Labels    map[string]string `json:"labels"` // 为 nil 时不修改标签，传空对象清空标签
 }
 
 type DeleteHostRequest struct {
@@ -34,8 +36,33 @@ type ListHostRequest struct {
 	Address  string `form:"address"`
 	Type     string `form:"type" binding:"omitempty,oneof=password key both"`
 	Status   string `form:"status" binding:"omitempty,oneof=active inactive"`
+	Selector string `form:"selector"` // 标签选择器，如 env=prod,role in (db,cache)
+}
+
+// DiagnoseHostsRequest 批量诊断主机连接
+type DiagnoseHostsRequest struct {
+	HostIDs  []uint `json:"host_ids" binding:"required_without=Selector"`
+	Selector string `json:"selector" binding:"max=500"` // 标签选择器，与 host_ids 取并集
+}
+
+// CreateHostPermissionRequest 为用户组授权主机组或匹配标签选择器的主机，二者只能选一个
+type CreateHostPermissionRequest struct {
+	UserGroupID      uint   `json:"user_group_id" binding:"required"`
+	HostGroupID      uint   `json:"host_group_id" binding:"required_without=HostSelector"`
+	HostSelector     string `json:"host_selector" binding:"max=500"`     // 标签选择器，如 env=prod,role in (db,cache)
+	ContainerPattern string `json:"container_pattern" binding:"max=500"` // 可进入的容器(名称或ID通配符，逗号分隔)
+}
+
+type ListHostPermissionRequest struct {
+	UserGroupID uint `form:"user_group_id"`
 }
 
 type GetHostRequest struct {
 	ID uint `uri:"id" binding:"required"`
 }
+
+// ExecCommandRequest 非交互执行命令请求
+type ExecCommandRequest struct {
+	Command string `json:"command" binding:"required,max=65535"`
+	Timeout int    `json:"timeout" binding:"omitempty,min=1"` // 超时秒数，不传使用默认值，超过上限时按上限处理
+}
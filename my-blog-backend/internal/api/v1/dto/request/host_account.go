@@ -0,0 +1,36 @@
+package request
+
+type CreateHostAccountRequest struct {
+	HostID     uint   `json:"host_id" binding:"required"`
+	Name       string `json:"name" binding:"required,max=50"`
+	Username   string `json:"username" binding:"required,max=50"`
+	Password   string `json:"password" binding:"max=128"`
+	SecretKey  string `json:"secret_key"`
+	Type       string `json:"type" binding:"required,oneof=root normal"`
+	Remark     string `json:"remark" binding:"max=255"`
+	RotateDays int    `json:"rotate_days" binding:"min=0,max=3650"` // 密码自动轮换周期（天），0 表示不自动轮换
+}
+
+type UpdateHostAccountRequest struct {
+	ID         uint   `json:"id" binding:"required"`
+	Name       string `json:"name" binding:"required,max=50"`
+	Username   string `json:"username" binding:"required,max=50"`
+	Password   string `json:"password" binding:"max=128"` // 为空时不修改
+	SecretKey  string `json:"secret_key"`                 // 为空时不修改
+	Type       string `json:"type" binding:"required,oneof=root normal"`
+	Status     string `json:"status" binding:"omitempty,oneof=active inactive"`
+	Remark     string `json:"remark" binding:"max=255"`
+	RotateDays int    `json:"rotate_days" binding:"min=0,max=3650"`
+}
+
+type ListHostAccountRequest struct {
+	Page     int    `form:"page,default=1"`
+	PageSize int    `form:"page_size,default=10"`
+	HostID   uint   `form:"host_id"`
+	Name     string `form:"name"`
+}
+
+type ListAccountRotationRequest struct {
+	Page     int `form:"page,default=1"`
+	PageSize int `form:"page_size,default=10"`
+}
@@ -0,0 +1,16 @@
+package request
+
+// RedirectListRequest 重定向列表请求
+type RedirectListRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	Source   string `form:"source" binding:"omitempty,oneof=import slug manual"` // 来源筛选
+	Keyword  string `form:"keyword" binding:"omitempty,max=100"`                 // 匹配来源路径和目标路径
+}
+
+// RedirectRequest 创建或更新重定向请求
+type RedirectRequest struct {
+	FromPath   string `json:"fromPath" binding:"required,max=500"`                  // 旧路径，如 /old/post.html
+	ToPath     string `json:"toPath" binding:"required,max=500"`                    // 站内路径或完整URL
+	StatusCode int    `json:"statusCode" binding:"omitempty,oneof=301 302 307 308"` // 默认 301
+}
@@ -0,0 +1,53 @@
+package request
+
+type ScriptParameterRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Type        string   `json:"type" binding:"required,oneof=string int bool enum"`
+	Default     string   `json:"default"`
+	Required    bool     `json:"required"`
+	Options     []string `json:"options"`
+	Raw         bool     `json:"raw"` // 不按脚本语言转义，仅限 int 和 enum 类型
+	Description string   `json:"description"`
+}
+
+type CreateScriptRequest struct {
+	Name        string                   `json:"name" binding:"required,max=100"`
+	Language    string                   `json:"language" binding:"required,oneof=bash shell python perl"`
+	Description string                   `json:"description" binding:"max=500"`
+	Content     string                   `json:"content" binding:"required"`
+	Parameters  []ScriptParameterRequest `json:"parameters" binding:"dive"`
+	ChangeLog   string                   `json:"change_log" binding:"max=255"`
+}
+
+// UpdateScriptRequest 更新脚本，内容或参数变化时生成新版本
+type UpdateScriptRequest struct {
+	ID          uint                     `json:"id" binding:"required"`
+	Description string                   `json:"description" binding:"max=500"`
+	Content     string                   `json:"content" binding:"required"`
+	Parameters  []ScriptParameterRequest `json:"parameters" binding:"dive"`
+	ChangeLog   string                   `json:"change_log" binding:"max=255"`
+	Status      string                   `json:"status" binding:"omitempty,oneof=active inactive"`
+}
+
+type ListScriptRequest struct {
+	Page     int    `form:"page,default=1"`
+	PageSize int    `form:"page_size,default=10"`
+	Name     string `form:"name"`
+	Language string `form:"language" binding:"omitempty,oneof=bash shell python perl"`
+	Owner    uint   `form:"owner"`
+}
+
+type DiffScriptRequest struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"required,min=1"`
+}
+
+type RunScriptRequest struct {
+	ScriptID uint              `json:"script_id" binding:"required"`
+	Version  int               `json:"version"` // 为0时使用最新版本
+	Name     string            `json:"name" binding:"max=100"`
+	HostIDs  []uint            `json:"host_ids" binding:"required_without=Selector"`
+	Selector string            `json:"selector" binding:"max=500"` // 标签选择器，执行时解析，与 host_ids 取并集
+	Params   map[string]string `json:"params"`
+	Timeout  int               `json:"timeout" binding:"omitempty,min=1,max=86400"` // 秒
+}
@@ -1,8 +1,12 @@
 package request
 
-// SearchRequest 搜索文章请求
+// SearchRequest 搜索文章请求，关键词为空时只按过滤条件查询
 type SearchRequest struct {
-	Keyword  string `form:"keyword" binding:"required"`
-	Page     int    `form:"page" binding:"omitempty,min=1" default:"1"`
-	PageSize int    `form:"pageSize" binding:"omitempty,min=1" default:"12"`
+	Keyword    string `form:"keyword" binding:"omitempty,max=100"`
+	Page       int    `form:"page" binding:"omitempty,min=1" default:"1"`
+	PageSize   int    `form:"pageSize" binding:"omitempty,min=1,max=50" default:"10"`
+	CategoryID uint   `form:"categoryId" binding:"omitempty"`
+	TagID      uint   `form:"tagId" binding:"omitempty"`
+	StartDate  string `form:"startDate" binding:"omitempty,datetime=2006-01-02"` // 发布日期起始（含）
+	EndDate    string `form:"endDate" binding:"omitempty,datetime=2006-01-02"`   // 发布日期截止（含）
 }
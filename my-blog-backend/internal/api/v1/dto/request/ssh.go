@@ -19,3 +19,11 @@ type UploadFileFormData struct {
 	ChunkIndex  int    `form:"chunk_index" binding:"required"`
 	TotalChunks int    `form:"total_chunks" binding:"required"`
 }
+
+type ListTerminalSessionRequest struct {
+	Page     int    `form:"page,default=1"`
+	PageSize int    `form:"page_size,default=10"`
+	UserID   uint   `form:"user_id"`
+	HostID   uint   `form:"host_id"`
+	Keyword  string `form:"keyword"` // 匹配用户名、主机名称、主机地址、客户端IP或会话ID
+}
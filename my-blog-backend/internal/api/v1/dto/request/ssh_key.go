@@ -0,0 +1,30 @@
+package request
+
+type GenerateSSHKeyRequest struct {
+	Name       string `json:"name" binding:"required,max=100"`
+	KeyType    string `json:"key_type" binding:"required,oneof=ed25519 rsa"`
+	Bits       int    `json:"bits" binding:"omitempty,oneof=2048 3072 4096"` // 仅 RSA，默认 4096
+	RotateDays int    `json:"rotate_days" binding:"min=0,max=3650"`          // 自动轮换周期（天），0 表示不自动轮换
+}
+
+type UpdateSSHKeyRequest struct {
+	ID         uint   `json:"id" binding:"required"`
+	Name       string `json:"name" binding:"required,max=100"`
+	RotateDays int    `json:"rotate_days" binding:"min=0,max=3650"`
+}
+
+type ListSSHKeyRequest struct {
+	Page     int    `form:"page,default=1"`
+	PageSize int    `form:"page_size,default=10"`
+	Name     string `form:"name"`
+	Status   string `form:"status" binding:"omitempty,oneof=active retired"`
+}
+
+// DeploySSHKeyRequest 将公钥分发到主机，验证登录后可切换为密钥认证
+type DeploySSHKeyRequest struct {
+	HostIDs        []uint `json:"host_ids" binding:"required_without=Selector"`
+	Selector       string `json:"selector" binding:"max=500"` // 标签选择器，执行时解析，与 host_ids 取并集
+	SwitchAuth     bool   `json:"switch_auth"`                // 验证通过后将主机切换为该密钥登录
+	RemovePassword bool   `json:"remove_password"`            // 切换后清除主机保存的密码
+	Timeout        int    `json:"timeout" binding:"omitempty,min=1,max=86400"`
+}
@@ -12,3 +12,9 @@ type SysLoginRequest struct {
 type SysSendEmailCodeRequest struct {
 	Email string `json:"email" binding:"required,email" example:"admin@example.com"`
 }
+
+// CreateAPITokenRequest 创建 API Token 请求
+type CreateAPITokenRequest struct {
+	Name       string `json:"name" binding:"required,max=100" example:"ci-deploy"`
+	ExpireDays int    `json:"expire_days" binding:"omitempty,min=1,max=3650" example:"90"` // 有效天数，不传表示永不过期
+}
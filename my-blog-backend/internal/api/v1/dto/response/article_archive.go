@@ -0,0 +1,40 @@
+package response
+
+// 导入动作
+const (
+	ArchiveActionCreate    = "create"    // 新建文章
+	ArchiveActionUpdate    = "update"    // 覆盖同 slug 的文章
+	ArchiveActionUnchanged = "unchanged" // 内容与站内文章相同，跳过
+	ArchiveActionSkip      = "skip"      // 存在冲突，跳过
+	ArchiveActionError     = "error"     // 文件无效或导入失败
+)
+
+// ArticleArchiveImportItem 单个文件的导入结果
+type ArticleArchiveImportItem struct {
+	File      string   `json:"file"`
+	Slug      string   `json:"slug"`
+	Title     string   `json:"title"`
+	Action    string   `json:"action"`               // create、update、unchanged、skip、error
+	ArticleID uint64   `json:"article_id,omitempty"` // 导入后的文章ID，预检查时为已有文章的ID
+	Conflicts []string `json:"conflicts,omitempty"`  // 冲突，onConflict=skip 时跳过该文件
+	Warnings  []string `json:"warnings,omitempty"`   // 不影响导入的问题，如引用的图片不存在、系列不存在
+	Redirects []string `json:"redirects,omitempty"`  // 重定向到该文章的原站地址，仅导入其他博客时有值
+	Comments  int      `json:"comments,omitempty"`   // 导入的评论数量
+	Error     string   `json:"error,omitempty"`
+}
+
+// ArticleArchiveImportReport 导入报告
+type ArticleArchiveImportReport struct {
+	DryRun            bool                       `json:"dry_run"`
+	Created           int                        `json:"created"`
+	Updated           int                        `json:"updated"`
+	Unchanged         int                        `json:"unchanged"`
+	Skipped           int                        `json:"skipped"`
+	Failed            int                        `json:"failed"`
+	CreatedCategories []string                   `json:"created_categories"` // 新建（预检查时为将要新建）的分类 slug
+	CreatedTags       []string                   `json:"created_tags"`       // 新建（预检查时为将要新建）的标签名称
+	UploadedImages    int                        `json:"uploaded_images"`    // 上传的图片数量，预检查时为将要上传的数量
+	Redirects         int                        `json:"redirects"`          // 保存的原站地址重定向数量
+	Comments          int                        `json:"comments"`           // 导入的评论数量
+	Items             []ArticleArchiveImportItem `json:"items"`
+}
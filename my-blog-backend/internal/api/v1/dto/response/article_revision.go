@@ -0,0 +1,49 @@
+package response
+
+import "my-blog-backend/internal/pkg/utils"
+
+// ArticleRevisionTag 修订中的标签
+type ArticleRevisionTag struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+// ArticleRevisionResponse 文章修订
+type ArticleRevisionResponse struct {
+	ArticleID    uint64               `json:"article_id"`
+	Version      int                  `json:"version"`
+	Title        string               `json:"title"`
+	Summary      string               `json:"summary"`
+	Content      string               `json:"content,omitempty"` // 列表中不返回正文
+	Tags         []ArticleRevisionTag `json:"tags"`
+	EditorID     uint64               `json:"editor_id"`
+	Action       string               `json:"action"`                  // create、update、restore、import
+	RestoredFrom int                  `json:"restored_from,omitempty"` // 恢复来源版本号
+	CreatedAt    string               `json:"created_at"`
+}
+
+// ArticleRevisionListResponse 文章修订列表
+type ArticleRevisionListResponse struct {
+	Items    []ArticleRevisionResponse `json:"items"`
+	Total    int64                     `json:"total"`
+	Page     int                       `json:"page"`
+	PageSize int                       `json:"page_size"`
+}
+
+// ArticleRevisionDiffResponse 文章修订对比
+type ArticleRevisionDiffResponse struct {
+	ArticleID   uint64              `json:"article_id"`
+	From        int                 `json:"from"`
+	To          int                 `json:"to"`
+	Mode        string              `json:"mode"`
+	TooLarge    bool                `json:"too_large,omitempty"` // 正文过大，按词对比已退回按行对比
+	Title       []utils.DiffSegment `json:"title"`
+	Summary     []utils.DiffSegment `json:"summary"`
+	TagsAdded   []string            `json:"tags_added"`
+	TagsRemoved []string            `json:"tags_removed"`
+	Added       int                 `json:"added"`             // 正文新增行数
+	Removed     int                 `json:"removed"`           // 正文删除行数
+	Lines       []utils.DiffLine    `json:"lines,omitempty"`   // 按行对比结果
+	Words       []utils.DiffSegment `json:"words,omitempty"`   // 按词对比结果
+	Unified     string              `json:"unified,omitempty"` // 按行对比的 +/- 文本
+}
@@ -0,0 +1,71 @@
+package response
+
+type BatchTaskResponse struct {
+	ID           uint    `json:"id"`
+	Name         string  `json:"name"`
+	Type         string  `json:"type"`
+	Status       string  `json:"status"`
+	ScriptType   string  `json:"script_type,omitempty"`
+	ScriptID     uint    `json:"script_id,omitempty"`
+	ScriptVer    int     `json:"script_version,omitempty"`
+	HostSelector string  `json:"host_selector,omitempty"`
+	SourcePath   string  `json:"source_path,omitempty"`
+	TargetPath   string  `json:"target_path,omitempty"`
+	FileMode     string  `json:"file_mode,omitempty"`
+	FileOwner    string  `json:"file_owner,omitempty"`
+	Checksum     string  `json:"checksum,omitempty"`
+	Timeout      int     `json:"timeout"`
+	SuccessCount int     `json:"success_count"`
+	FailedCount  int     `json:"failed_count"`
+	TotalHosts   int     `json:"total_hosts"`
+	Progress     float64 `json:"progress"`
+	Remark       string  `json:"remark"`
+	CreatedBy    uint    `json:"created_by"`
+	CreatedAt    string  `json:"created_at"`
+	StartedAt    string  `json:"started_at"`
+	FinishedAt   string  `json:"finished_at"`
+}
+
+type TaskHostResponse struct {
+	HostID     uint    `json:"host_id"`
+	HostName   string  `json:"host_name"`
+	HostAddr   string  `json:"host_addr"`
+	Status     string  `json:"status"`
+	ExitCode   int     `json:"exit_code"`
+	Progress   float64 `json:"progress"`
+	Checksum   string  `json:"checksum,omitempty"`
+	BackupPath string  `json:"backup_path,omitempty"`
+	Output     string  `json:"output"`
+	Error      string  `json:"error"`
+	Duration   int64   `json:"duration"`
+	StartedAt  string  `json:"started_at"`
+	FinishedAt string  `json:"finished_at"`
+}
+
+type BatchTaskDetailResponse struct {
+	BatchTaskResponse
+	Command string             `json:"command"`
+	Hosts   []TaskHostResponse `json:"hosts"`
+}
+
+type BatchTaskListResponse struct {
+	Total int64               `json:"total"`
+	Items []BatchTaskResponse `json:"items"`
+}
+
+// TaskStreamMessage 任务实时输出推送消息
+// type: snapshot(订阅时执行中主机已产生的输出) / output(新输出行) / exit(单台主机结束) / done(任务结束)
+type TaskStreamMessage struct {
+	Type     string `json:"type"`
+	TaskID   uint   `json:"task_id"`
+	HostID   uint   `json:"host_id,omitempty"`
+	HostName string `json:"host_name,omitempty"`
+	Stream   string `json:"stream,omitempty"` // stdout / stderr
+	Data     string `json:"data,omitempty"`
+	Seq      int64  `json:"seq,omitempty"` // 单台主机内的输出行序号，序号不大于快照 seq 的行已包含在快照中
+	ExitCode int    `json:"exit_code"`
+	Status   string `json:"status,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Success  int    `json:"success_count,omitempty"`
+	Failed   int    `json:"failed_count,omitempty"`
+}
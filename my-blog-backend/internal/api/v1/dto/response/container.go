@@ -0,0 +1,13 @@
+package response
+
+// ContainerResponse 主机上运行中的容器
+type ContainerResponse struct {
+	ID        string   `json:"id"`
+	ShortID   string   `json:"short_id"`
+	Names     []string `json:"names"`
+	Image     string   `json:"image"`
+	State     string   `json:"state"`
+	Status    string   `json:"status"`
+	Ports     string   `json:"ports"`
+	CreatedAt string   `json:"created_at"`
+}
@@ -8,6 +8,8 @@ type HostResponse struct {
 	Username string `json:"username"`
 	Type     string `json:"type"`
 	Status   string `json:"status"`
+	Labels   map[string]string `json:"labels"`
+	KeyID    uint   `json:"key_id,omitempty"` // 托管密钥ID
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
@@ -17,7 +19,53 @@ type HostListResponse struct {
 	Items []HostResponse   `json:"items"`
 }
 
+// HostLabelResponse 标签键及其已使用的值，用于构建选择器
+type HostLabelResponse struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values"`
+}
+
+// HostPermissionResponse 用户组的主机授权
+type HostPermissionResponse struct {
+	ID               uint   `json:"id"`
+	UserGroupID      uint   `json:"user_group_id"`
+	HostGroupID      uint   `json:"host_group_id,omitempty"`
+	HostSelector     string `json:"host_selector,omitempty"`
+	ContainerPattern string `json:"container_pattern"`
+	CreatedBy        uint   `json:"created_by"`
+	CreatedAt        string `json:"created_at"`
+}
+
+// TestConnectionResponse 主机连接诊断报告
 type TestConnectionResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	HostID      uint                    `json:"host_id"`
+	HostName    string                  `json:"host_name"`
+	Address     string                  `json:"address"`
+	Success     bool                    `json:"success"`
+	Message     string                  `json:"message"`
+	FailedStage string                  `json:"failed_stage,omitempty"` // 第一个失败的阶段
+	Duration    int64                   `json:"duration"`               // 诊断总耗时(毫秒)
+	Stages      []DiagnoseStageResponse `json:"stages"`
+}
+
+// DiagnoseStageResponse 诊断阶段结果
+// 阶段依次为 dns、tcp、banner、kex、host_key、auth、shell、sudo
+type DiagnoseStageResponse struct {
+	Name     string            `json:"name"`
+	Status   string            `json:"status"`   // ok, warning, failed, skipped
+	Duration float64           `json:"duration"` // 阶段耗时(毫秒)
+	Message  string            `json:"message"`
+	Details  map[string]string `json:"details,omitempty"`
+}
+
+// ExecCommandResponse 非交互执行命令结果
+type ExecCommandResponse struct {
+	HostID          uint   `json:"host_id"`
+	ExitCode        int    `json:"exit_code"` // 超时时为 -1
+	Stdout          string `json:"stdout"`
+	Stderr          string `json:"stderr"`
+	StdoutTruncated bool   `json:"stdout_truncated"`
+	StderrTruncated bool   `json:"stderr_truncated"`
+	TimedOut        bool   `json:"timed_out"`
+	Duration        int64  `json:"duration"` // 执行耗时(毫秒)
 }
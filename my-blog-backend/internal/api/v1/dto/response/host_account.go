@@ -0,0 +1,42 @@
+package response
+
+// HostAccountResponse 主机账号（不包含密码和私钥）
+type HostAccountResponse struct {
+	ID            uint   `json:"id"`
+	HostID        uint   `json:"host_id"`
+	Name          string `json:"name"`
+	Username      string `json:"username"`
+	Type          string `json:"type"`
+	Status        string `json:"status"`
+	Remark        string `json:"remark"`
+	RotateDays    int    `json:"rotate_days"`
+	NextRotateAt  string `json:"next_rotate_at"`
+	LastRotatedAt string `json:"last_rotated_at"`
+	CreatedBy     uint   `json:"created_by"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+type HostAccountListResponse struct {
+	Total int64                 `json:"total"`
+	Items []HostAccountResponse `json:"items"`
+}
+
+// AccountRotationResponse 密码轮换记录
+type AccountRotationResponse struct {
+	ID         uint   `json:"id"`
+	AccountID  uint   `json:"account_id"`
+	HostID     uint   `json:"host_id"`
+	Method     string `json:"method"`
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+	Source     string `json:"source"`
+	CreatedBy  uint   `json:"created_by"`
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at"`
+}
+
+type AccountRotationListResponse struct {
+	Total int64                     `json:"total"`
+	Items []AccountRotationResponse `json:"items"`
+}
@@ -0,0 +1,8 @@
+package response
+
+// RedirectTarget 旧地址对应的跳转目标
+type RedirectTarget struct {
+	FromPath   string `json:"from_path"`
+	Location   string `json:"location"`    // 完整的跳转地址
+	StatusCode int    `json:"status_code"` // 301 或 302
+}
@@ -0,0 +1,55 @@
+package response
+
+import "my-blog-backend/internal/pkg/utils"
+
+type ScriptParameterResponse struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Default     string   `json:"default"`
+	Required    bool     `json:"required"`
+	Options     []string `json:"options,omitempty"`
+	Raw         bool     `json:"raw"`
+	Description string   `json:"description"`
+}
+
+type ScriptResponse struct {
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Language      string `json:"language"`
+	Description   string `json:"description"`
+	LatestVersion int    `json:"latest_version"`
+	Owner         uint   `json:"owner"`
+	Status        string `json:"status"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+type ScriptDetailResponse struct {
+	ScriptResponse
+	Version    int                       `json:"version"`
+	Content    string                    `json:"content"`
+	Parameters []ScriptParameterResponse `json:"parameters"`
+	ChangeLog  string                    `json:"change_log"`
+}
+
+type ScriptListResponse struct {
+	Total int64            `json:"total"`
+	Items []ScriptResponse `json:"items"`
+}
+
+type ScriptVersionResponse struct {
+	Version   int    `json:"version"`
+	ChangeLog string `json:"change_log"`
+	CreatedBy uint   `json:"created_by"`
+	CreatedAt string `json:"created_at"`
+}
+
+type ScriptDiffResponse struct {
+	ScriptID uint             `json:"script_id"`
+	From     int              `json:"from"`
+	To       int              `json:"to"`
+	Added    int              `json:"added"`
+	Removed  int              `json:"removed"`
+	Lines    []utils.DiffLine `json:"lines"`
+	Unified  string           `json:"unified"`
+}
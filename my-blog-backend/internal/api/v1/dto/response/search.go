@@ -1,17 +1,39 @@
 package response
 
+import "time"
+
 // SearchResultItem 搜索结果项
 type SearchResultItem struct {
-	ID      uint    `json:"id"`
-	Title   string  `json:"title"`
-	Summary string  `json:"summary"`
-	Cover   *string `json:"cover"`
+	ID           uint64          `json:"id"`
+	Title        string          `json:"title"`
+	Slug         string          `json:"slug"`
+	Summary      string          `json:"summary"`
+	Cover        string          `json:"cover"`
+	CategoryID   uint64          `json:"category_id"`
+	AuthorID     uint64          `json:"author_id"`
+	Views        uint64          `json:"views"`
+	Likes        uint32          `json:"likes"`
+	Favorites    uint32          `json:"favorites"`
+	CommentCount uint32          `json:"comments"`
+	IsTop        bool            `json:"is_top"`
+	PublishedAt  *time.Time      `json:"published_at"`
+	CreatedAt    time.Time       `json:"created_at"`
+	Score        float64         `json:"score"`     // 相关度得分，无关键词时为0
+	Highlight    SearchHighlight `json:"highlight"` // 高亮片段，已做 HTML 转义，匹配词使用 <mark> 包裹
+}
+
+// SearchHighlight 搜索结果高亮
+type SearchHighlight struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Content string `json:"content"` // 正文中包含匹配词的片段
 }
 
 // SearchResponse 搜索响应
 type SearchResponse struct {
-	List     []SearchResultItem `json:"list"`
+	Items    []SearchResultItem `json:"items"`
 	Total    int64              `json:"total"`
 	Page     int                `json:"page"`
-	PageSize int                `json:"pageSize"`
+	PageSize int                `json:"page_size"`
+	Keyword  string             `json:"keyword"`
 }
@@ -0,0 +1,30 @@
+package response
+
+import (
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/pkg/markdown"
+)
+
+// SEOMetaTag 页面 <meta> 标签，Name 和 Property 只有一个有值
+type SEOMetaTag struct {
+	Name     string `json:"name,omitempty"`
+	Property string `json:"property,omitempty"` // Open Graph 标签
+	Content  string `json:"content"`
+}
+
+// ArticleSEO 文章页面元数据，前端直接渲染到 <head>
+type ArticleSEO struct {
+	Title       string                 `json:"title"`       // <title>
+	Description string                 `json:"description"` // <meta name="description">
+	Canonical   string                 `json:"canonical"`   // <link rel="canonical">
+	Robots      string                 `json:"robots"`      // <meta name="robots">，未发布的文章为 noindex
+	Meta        []SEOMetaTag           `json:"meta"`        // 其余 <meta> 标签，包括关键词、Open Graph 和 Twitter Card
+	JSONLD      map[string]interface{} `json:"json_ld"`     // <script type="application/ld+json"> 结构化数据
+}
+
+// ArticleDetailResponse 文章详情，在文章字段之外附带页面元数据和服务端渲染结果
+type ArticleDetailResponse struct {
+	*models.Article
+	SEO      *ArticleSEO      `json:"seo"`
+	Rendered *markdown.Result `json:"rendered"` // content 渲染后的 HTML、目录、字数和阅读时间，渲染失败时为 null
+}
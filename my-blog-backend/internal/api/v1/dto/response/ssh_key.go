@@ -0,0 +1,29 @@
+package response
+
+// SSHKeyResponse 托管密钥（不包含私钥）
+type SSHKeyResponse struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	KeyType      string `json:"key_type"`
+	Bits         int    `json:"bits,omitempty"`
+	PublicKey    string `json:"public_key"`
+	Fingerprint  string `json:"fingerprint"`
+	Status       string `json:"status"`
+	RotateDays   int    `json:"rotate_days"`
+	NextRotateAt string `json:"next_rotate_at"`
+	ReplacedBy   uint   `json:"replaced_by,omitempty"`
+	HostCount    int    `json:"host_count"`
+	CreatedBy    uint   `json:"created_by"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type SSHKeyListResponse struct {
+	Total int64            `json:"total"`
+	Items []SSHKeyResponse `json:"items"`
+}
+
+// RotateSSHKeyResponse 轮换结果，没有主机使用旧密钥时 TaskID 为0
+type RotateSSHKeyResponse struct {
+	NewKeyID uint `json:"new_key_id"`
+	TaskID   uint `json:"task_id"`
+}
@@ -0,0 +1,9 @@
+package response
+
+import "my-blog-backend/internal/models"
+
+// CreateAPITokenResponse 创建 API Token 响应，明文 Token 只在创建时返回一次
+type CreateAPITokenResponse struct {
+	*models.SysAPIToken
+	Token string `json:"token"`
+}
@@ -0,0 +1,30 @@
+package response
+
+// TerminalSessionResponse 终端会话，进行中的会话来自内存，ID 为 0、EndedAt 为空
+type TerminalSessionResponse struct {
+	ID          uint   `json:"id"`
+	SessionID   string `json:"session_id"`
+	UserID      uint   `json:"user_id"`
+	UserName    string `json:"user_name"`
+	HostID      uint   `json:"host_id"`
+	HostName    string `json:"host_name"`
+	HostAddress string `json:"host_address"`
+	HostAccount string `json:"host_account"`
+	ContainerID string `json:"container_id"`
+	ClientIP    string `json:"client_ip"`
+	ClientAgent string `json:"client_agent"`
+	RecordingID string `json:"recording_id"`
+	Status      string `json:"status"`
+	CloseReason string `json:"close_reason"`
+	BytesIn     int64  `json:"bytes_in"`
+	BytesOut    int64  `json:"bytes_out"`
+	Duration    int64  `json:"duration"` // 会话时长（秒）
+	StartedAt   string `json:"started_at"`
+	LastInputAt string `json:"last_input_at"`
+	EndedAt     string `json:"ended_at"`
+}
+
+type TerminalSessionListResponse struct {
+	Total int64                     `json:"total"`
+	Items []TerminalSessionResponse `json:"items"`
+}
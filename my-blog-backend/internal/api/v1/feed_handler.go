@@ -0,0 +1,175 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/feed"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedCacheControl 订阅源响应的客户端缓存策略，过期后通过 ETag 重新验证
+const feedCacheControl = "public, max-age=600"
+
+type FeedHandler struct {
+	feedService services.FeedService
+}
+
+func NewFeedHandler(feedService services.FeedService) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+	}
+}
+
+// RSS 全站 RSS 订阅源
+// @Summary 全站 RSS 订阅源
+// @Tags 订阅源
+// @Produce xml
+// @Param mode query string false "输出方式: full 或 summary"
+// @Success 200 {string} string "RSS 2.0"
+// @Success 304 {string} string "未修改"
+// @Router /feed.xml [get]
+func (h *FeedHandler) RSS(c *gin.Context) {
+	h.serve(c, services.FeedScopeSite, "", feed.FormatRSS)
+}
+
+// Atom 全站 Atom 订阅源
+// @Summary 全站 Atom 订阅源
+// @Tags 订阅源
+// @Produce xml
+// @Param mode query string false "输出方式: full 或 summary"
+// @Success 200 {string} string "Atom 1.0"
+// @Success 304 {string} string "未修改"
+// @Router /atom.xml [get]
+func (h *FeedHandler) Atom(c *gin.Context) {
+	h.serve(c, services.FeedScopeSite, "", feed.FormatAtom)
+}
+
+// JSON 全站 JSON Feed 订阅源
+// @Summary 全站 JSON Feed 订阅源
+// @Tags 订阅源
+// @Produce json
+// @Param mode query string false "输出方式: full 或 summary"
+// @Success 200 {string} string "JSON Feed 1.1"
+// @Success 304 {string} string "未修改"
+// @Router /feed.json [get]
+func (h *FeedHandler) JSON(c *gin.Context) {
+	h.serve(c, services.FeedScopeSite, "", feed.FormatJSON)
+}
+
+// CategoryFeed 分类订阅源
+// @Summary 分类订阅源
+// @Tags 订阅源
+// @Produce xml,json
+// @Param slug path string true "分类slug"
+// @Param format query string false "格式: rss、atom 或 json" default(rss)
+// @Param mode query string false "输出方式: full 或 summary"
+// @Success 200 {string} string "订阅源"
+// @Success 304 {string} string "未修改"
+// @Router /categories/{slug}/feed [get]
+func (h *FeedHandler) CategoryFeed(c *gin.Context) {
+	h.serveScoped(c, services.FeedScopeCategory)
+}
+
+// TagFeed 标签订阅源
+// @Summary 标签订阅源
+// @Tags 订阅源
+// @Produce xml,json
+// @Param slug path string true "标签slug"
+// @Param format query string false "格式: rss、atom 或 json" default(rss)
+// @Param mode query string false "输出方式: full 或 summary"
+// @Success 200 {string} string "订阅源"
+// @Success 304 {string} string "未修改"
+// @Router /tags/{slug}/feed [get]
+func (h *FeedHandler) TagFeed(c *gin.Context) {
+	h.serveScoped(c, services.FeedScopeTag)
+}
+
+// SeriesFeed 系列订阅源
+// @Summary 系列订阅源
+// @Tags 订阅源
+// @Produce xml,json
+// @Param slug path string true "系列slug"
+// @Param format query string false "格式: rss、atom 或 json" default(rss)
+// @Param mode query string false "输出方式: full 或 summary"
+// @Success 200 {string} string "订阅源"
+// @Success 304 {string} string "未修改"
+// @Router /series/{slug}/feed [get]
+func (h *FeedHandler) SeriesFeed(c *gin.Context) {
+	h.serveScoped(c, services.FeedScopeSeries)
+}
+
+func (h *FeedHandler) serveScoped(c *gin.Context, scope services.FeedScope) {
+	format, err := feed.ParseFormat(c.Query("format"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误", err)
+		return
+	}
+	h.serve(c, scope, c.Param("slug"), format)
+}
+
+// serve 生成订阅源并处理 If-None-Match / If-Modified-Since 条件请求
+func (h *FeedHandler) serve(c *gin.Context, scope services.FeedScope, slug string, format feed.Format) {
+	mode := c.Query("mode")
+	if mode != "" && mode != "full" && mode != "summary" {
+		response.Error(c, http.StatusBadRequest, "参数错误", errors.New("mode 只能为 full 或 summary"))
+		return
+	}
+
+	output, err := h.feedService.GetFeed(&services.FeedRequest{
+		Scope:  scope,
+		Slug:   slug,
+		Format: format,
+		Mode:   mode,
+		Path:   c.Request.URL.Path,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrFeedNotFound) {
+			response.Error(c, http.StatusNotFound, "订阅源不存在", err)
+			return
+		}
+		logger.Error("生成订阅源失败", logger.String("path", c.Request.URL.Path), logger.Err("error", err))
+		response.Error(c, http.StatusInternalServerError, "生成订阅源失败", err)
+		return
+	}
+
+	c.Header("ETag", output.ETag)
+	c.Header("Cache-Control", feedCacheControl)
+	if !output.LastModified.IsZero() {
+		c.Header("Last-Modified", output.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if feedNotModified(c.Request, output) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, output.ContentType, output.Body)
+}
+
+// feedNotModified 判断条件请求是否命中，If-None-Match 优先于 If-Modified-Since
+func feedNotModified(req *http.Request, output *services.FeedOutput) bool {
+	if match := req.Header.Get("If-None-Match"); match != "" {
+		return etagMatches(match, output.ETag)
+	}
+	if since := req.Header.Get("If-Modified-Since"); since != "" && !output.LastModified.IsZero() {
+		t, err := http.ParseTime(since)
+		return err == nil && !output.LastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// etagMatches 判断 If-None-Match 是否包含指定 ETag，按弱比较处理
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"my-blog-backend/internal/api/v1/dto/request"
+	dtoResponse "my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/services"
+)
+
+type FileTransferHandler struct {
+	transferService *services.FileTransferService
+}
+
+func NewFileTransferHandler(transferService *services.FileTransferService) *FileTransferHandler {
+	return &FileTransferHandler{transferService: transferService}
+}
+
+// Distribute 将文件分发到多台主机
+// @Summary 文件分发
+// @Tags 批量任务
+// @Accept multipart/form-data
+// @Param file formData file true "分发的文件"
+// @Param host_ids formData []int true "目标主机ID"
+// @Param target_path formData string true "目标路径（以/结尾表示目录）"
+// @Param mode formData string false "文件权限，如0644"
+// @Param owner formData string false "文件属主，如 www:www"
+// @Param backup formData bool false "是否备份原文件"
+// @Param timeout formData int false "每台主机超时（秒）"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/files/distribute [post]
+func (h *FileTransferHandler) Distribute(c *gin.Context) {
+	var req request.DistributeFileRequest
+	if err := c.ShouldBind(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		dtoResponse.Error(c, 400, "请选择要分发的文件", err)
+		return
+	}
+
+	taskID, err := h.transferService.Distribute(&req, file, taskOperator(c))
+	if err != nil {
+		dtoResponse.Error(c, taskErrorStatus(err, 400), err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, gin.H{"task_id": taskID}, "分发任务已提交")
+}
+
+// Collect 从多台主机拉取文件
+// @Summary 文件拉取
+// @Tags 批量任务
+// @Param request body request.CollectFileRequest true "拉取参数"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/files/collect [post]
+func (h *FileTransferHandler) Collect(c *gin.Context) {
+	var req request.CollectFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	taskID, err := h.transferService.Collect(&req, taskOperator(c))
+	if err != nil {
+		dtoResponse.Error(c, taskErrorStatus(err, 400), err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, gin.H{"task_id": taskID}, "拉取任务已提交")
+}
+
+// DownloadArchive 下载拉取任务的结果（zip，每台主机一个目录）
+// @Summary 下载拉取结果
+// @Tags 批量任务
+// @Produce application/zip
+// @Param id path int true "任务ID"
+// @Param host_id query int false "只下载指定主机的文件"
+// @Router /api/v1/rbac/tasks/{id}/archive [get]
+func (h *FileTransferHandler) DownloadArchive(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的任务ID", err)
+		return
+	}
+	var hostID uint64
+	if v := c.Query("host_id"); v != "" {
+		if hostID, err = strconv.ParseUint(v, 10, 32); err != nil {
+			dtoResponse.Error(c, 400, "无效的主机ID", err)
+			return
+		}
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", h.transferService.ArchiveName(uint(id), uint(hostID))))
+	if err := h.transferService.WriteArchive(uint(id), uint(hostID), taskOperator(c), c.Writer); err != nil {
+		logger.Error("打包拉取结果失败", logger.Uint("task_id", uint(id)), logger.Err("error", err))
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			dtoResponse.Error(c, taskErrorStatus(err, 400), err.Error(), err)
+		}
+	}
+}
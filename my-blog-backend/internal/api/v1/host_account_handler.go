@@ -0,0 +1,187 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"my-blog-backend/internal/api/v1/dto/request"
+	dtoResponse "my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/middleware"
+	"my-blog-backend/internal/services"
+)
+
+type HostAccountHandler struct {
+	accountService *services.HostAccountService
+}
+
+func NewHostAccountHandler(accountService *services.HostAccountService) *HostAccountHandler {
+	return &HostAccountHandler{accountService: accountService}
+}
+
+// CreateAccount 创建主机账号
+// @Summary 创建主机账号
+// @Tags 主机账号
+// @Accept json
+// @Produce json
+// @Param request body request.CreateHostAccountRequest true "账号信息"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/host-accounts [post]
+func (h *HostAccountHandler) CreateAccount(c *gin.Context) {
+	var req request.CreateHostAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	if err := h.accountService.CreateAccount(&req, uint(userID)); err != nil {
+		dtoResponse.Error(c, 500, "创建账号失败: "+err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, nil, "创建成功")
+}
+
+// UpdateAccount 更新主机账号
+// @Summary 更新主机账号
+// @Tags 主机账号
+// @Accept json
+// @Produce json
+// @Param request body request.UpdateHostAccountRequest true "账号信息"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/host-accounts [put]
+func (h *HostAccountHandler) UpdateAccount(c *gin.Context) {
+	var req request.UpdateHostAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	if err := h.accountService.UpdateAccount(&req); err != nil {
+		dtoResponse.Error(c, 500, "更新账号失败: "+err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, nil, "更新成功")
+}
+
+// DeleteAccount 删除主机账号
+// @Summary 删除主机账号
+// @Tags 主机账号
+// @Param id path int true "账号ID"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/host-accounts/{id} [delete]
+func (h *HostAccountHandler) DeleteAccount(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的账号ID", err)
+		return
+	}
+
+	if err := h.accountService.DeleteAccount(uint(id)); err != nil {
+		dtoResponse.Error(c, 500, "删除账号失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, nil, "删除成功")
+}
+
+// GetAccount 获取主机账号详情
+// @Summary 获取主机账号详情
+// @Tags 主机账号
+// @Param id path int true "账号ID"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.HostAccountResponse}
+// @Router /api/v1/rbac/host-accounts/{id} [get]
+func (h *HostAccountHandler) GetAccount(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的账号ID", err)
+		return
+	}
+
+	account, err := h.accountService.GetAccount(uint(id))
+	if err != nil {
+		dtoResponse.Error(c, 500, "获取账号失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, account, "获取成功")
+}
+
+// ListAccounts 主机账号列表
+// @Summary 主机账号列表
+// @Tags 主机账号
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param host_id query int false "主机ID"
+// @Param name query string false "账号名称或用户名"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.HostAccountListResponse}
+// @Router /api/v1/rbac/host-accounts [get]
+func (h *HostAccountHandler) ListAccounts(c *gin.Context) {
+	var req request.ListHostAccountRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	list, err := h.accountService.ListAccounts(&req)
+	if err != nil {
+		dtoResponse.Error(c, 500, "获取账号列表失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, list, "获取成功")
+}
+
+// RotatePassword 立即轮换账号密码
+// @Summary 轮换账号密码
+// @Tags 主机账号
+// @Param id path int true "账号ID"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.AccountRotationResponse}
+// @Router /api/v1/rbac/host-accounts/{id}/rotate [post]
+func (h *HostAccountHandler) RotatePassword(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的账号ID", err)
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	result, err := h.accountService.RotatePassword(uint(id), uint(userID), services.RotateSourceManual)
+	if err != nil {
+		dtoResponse.Error(c, 500, "轮换密码失败: "+err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, result, "轮换成功")
+}
+
+// ListRotations 账号密码轮换记录
+// @Summary 密码轮换记录
+// @Tags 主机账号
+// @Param id path int true "账号ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.AccountRotationListResponse}
+// @Router /api/v1/rbac/host-accounts/{id}/rotations [get]
+func (h *HostAccountHandler) ListRotations(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的账号ID", err)
+		return
+	}
+
+	var req request.ListAccountRotationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	list, err := h.accountService.ListRotations(uint(id), &req)
+	if err != nil {
+		dtoResponse.Error(c, 500, "获取轮换记录失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, list, "获取成功")
+}
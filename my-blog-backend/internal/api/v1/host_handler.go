@@ -1,11 +1,13 @@
 package api
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"my-blog-backend/internal/api/v1/dto/request"
 	dtoResponse "my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/middleware"
 	"my-blog-backend/internal/services"
 )
 
@@ -117,6 +119,7 @@ func (h *HostHandler) GetHost(c *gin.Context) {
 // @Param address query string false "主机地址"
 // @Param type query string false "认证类型"
 // @Param status query string false "状态"
+// @Param selector query string false "标签选择器，如 env=prod,role in (db,cache)"
 // @Success 200 {object} dtoResponse.Response{data=dtoResponse.HostListResponse}
 // @Router /api/v1/hosts [get]
 func (h *HostHandler) ListHosts(c *gin.Context) {
@@ -150,8 +153,94 @@ func (h *HostHandler) GetAllHosts(c *gin.Context) {
 	dtoResponse.Success(c, hosts, "获取成功")
 }
 
-// TestConnection 测试连接
-// @Summary 测试连接
+// ListLabels 获取所有主机标签键值（用于构建标签选择器）
+// @Summary 获取主机标签
+// @Tags 主机管理
+// @Success 200 {object} dtoResponse.Response{data=[]dtoResponse.HostLabelResponse}
+// @Router /api/v1/hosts/labels [get]
+func (h *HostHandler) ListLabels(c *gin.Context) {
+	labels, err := h.hostService.ListLabels()
+	if err != nil {
+		dtoResponse.Error(c, 500, "获取主机标签失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, labels, "获取成功")
+}
+
+// ListPermissions 获取主机授权
+// @Summary 获取主机授权
+// @Tags 主机管理
+// @Param user_group_id query int false "用户组ID"
+// @Success 200 {object} dtoResponse.Response{data=[]dtoResponse.HostPermissionResponse}
+// @Router /api/v1/host-permissions [get]
+func (h *HostHandler) ListPermissions(c *gin.Context) {
+	var req request.ListHostPermissionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	permissions, err := h.hostService.ListPermissions(req.UserGroupID)
+	if err != nil {
+		dtoResponse.Error(c, 500, "获取主机授权失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, permissions, "获取成功")
+}
+
+// CreatePermission 为用户组授权主机组或匹配标签选择器的主机
+// @Summary 创建主机授权
+// @Tags 主机管理
+// @Accept json
+// @Produce json
+// @Param request body request.CreateHostPermissionRequest true "授权信息"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/host-permissions [post]
+func (h *HostHandler) CreatePermission(c *gin.Context) {
+	var req request.CreateHostPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	if err := h.hostService.CreatePermission(&req, uint(userID)); err != nil {
+		status := 400
+		if errors.Is(err, services.ErrHostPermissionExists) {
+			status = 409
+		}
+		dtoResponse.Error(c, status, err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, nil, "授权成功")
+}
+
+// DeletePermission 删除主机授权
+// @Summary 删除主机授权
+// @Tags 主机管理
+// @Param id path int true "授权ID"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/host-permissions/{id} [delete]
+func (h *HostHandler) DeletePermission(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的授权ID", err)
+		return
+	}
+
+	if err := h.hostService.DeletePermission(uint(id)); err != nil {
+		dtoResponse.Error(c, 500, "删除主机授权失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, nil, "删除成功")
+}
+
+// TestConnection 分阶段诊断主机连接
+// @Summary 诊断主机连接
 // @Tags 主机管理
 // @Param id path int true "主机ID"
 // @Success 200 {object} dtoResponse.Response{data=dtoResponse.TestConnectionResponse}
@@ -172,3 +261,27 @@ func (h *HostHandler) TestConnection(c *gin.Context) {
 
 	dtoResponse.Success(c, result, "测试完成")
 }
+
+// DiagnoseHosts 批量诊断主机连接
+// @Summary 批量诊断主机连接
+// @Tags 主机管理
+// @Accept json
+// @Produce json
+// @Param request body request.DiagnoseHostsRequest true "主机ID列表或标签选择器"
+// @Success 200 {object} dtoResponse.Response{data=[]dtoResponse.TestConnectionResponse}
+// @Router /api/v1/hosts/test [post]
+func (h *HostHandler) DiagnoseHosts(c *gin.Context) {
+	var req request.DiagnoseHostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	results, err := h.hostService.DiagnoseHosts(&req)
+	if err != nil {
+		dtoResponse.Error(c, 400, err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, results, "测试完成")
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RedirectHandler struct {
+	redirectService services.RedirectService
+}
+
+func NewRedirectHandler(redirectService services.RedirectService) *RedirectHandler {
+	return &RedirectHandler{
+		redirectService: redirectService,
+	}
+}
+
+// Resolve 查询旧地址的跳转目标
+// @Summary 查询旧地址的跳转目标
+// @Description 前台路由未匹配时调用，有重定向时跳转到 location
+// @Tags 重定向
+// @Produce json
+// @Param path query string true "访问路径，如 /2020/01/02/hello/"
+// @Success 200 {object} response.Response{data=response.RedirectTarget}
+// @Failure 404 {object} response.Response
+// @Router /api/v1/public/redirects/resolve [get]
+func (h *RedirectHandler) Resolve(c *gin.Context) {
+	target, err := h.redirectService.Resolve(c.Query("path"))
+	if err != nil {
+		logger.Error("查询重定向失败", logger.Err("error", err))
+		response.Error(c, http.StatusInternalServerError, "查询重定向失败", err)
+		return
+	}
+	if target == nil {
+		response.Error(c, http.StatusNotFound, "没有对应的重定向", nil)
+		return
+	}
+	response.Success(c, target, "")
+}
+
+// NoRoute 未匹配的路由，旧地址有重定向时直接跳转，用于前台与后端同域部署、未知路径转发到后端的场景
+func (h *RedirectHandler) NoRoute(c *gin.Context) {
+	if (c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead) && !strings.HasPrefix(c.Request.URL.Path, "/api/") {
+		target, err := h.redirectService.Resolve(c.Request.URL.Path)
+		if err != nil {
+			logger.Error("查询重定向失败", logger.String("path", c.Request.URL.Path), logger.Err("error", err))
+		}
+		if target != nil {
+			c.Redirect(target.StatusCode, target.Location)
+			return
+		}
+	}
+	c.String(http.StatusNotFound, "404 page not found")
+}
+
+// ListRedirects 获取重定向列表
+// @Summary 重定向列表
+// @Description 包含导入、修改 slug 自动生成和手动添加的重定向，以及命中次数
+// @Tags 重定向
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码"
+// @Param pageSize query int false "每页数量"
+// @Param source query string false "来源：import、slug、manual"
+// @Param keyword query string false "匹配旧路径和目标地址"
+// @Success 200 {object} response.Response
+// @Router /api/v1/rbac/redirects [get]
+func (h *RedirectHandler) ListRedirects(c *gin.Context) {
+	var req request.RedirectListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误", err)
+		return
+	}
+
+	redirects, total, err := h.redirectService.ListRedirects(&req)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "获取重定向列表失败", err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"items":     redirects,
+		"total":     total,
+		"page":      req.Page,
+		"page_size": req.PageSize,
+	}, "")
+}
+
+// CreateRedirect 创建重定向
+// @Summary 创建重定向
+// @Tags 重定向
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.RedirectRequest true "重定向信息"
+// @Success 200 {object} response.Response{data=models.Redirect}
+// @Router /api/v1/rbac/redirects [post]
+func (h *RedirectHandler) CreateRedirect(c *gin.Context) {
+	var req request.RedirectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误", err)
+		return
+	}
+
+	redirect, err := h.redirectService.CreateRedirect(&req)
+	if err != nil {
+		h.redirectError(c, "创建重定向失败", err)
+		return
+	}
+
+	response.Success(c, redirect, "创建成功")
+}
+
+// UpdateRedirect 更新重定向
+// @Summary 更新重定向
+// @Description 修改旧路径、目标地址和状态码，保留来源和命中次数
+// @Tags 重定向
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "重定向ID"
+// @Param request body request.RedirectRequest true "重定向信息"
+// @Success 200 {object} response.Response{data=models.Redirect}
+// @Router /api/v1/rbac/redirects/{id} [put]
+func (h *RedirectHandler) UpdateRedirect(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "无效的重定向ID", err)
+		return
+	}
+
+	var req request.RedirectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误", err)
+		return
+	}
+
+	redirect, err := h.redirectService.UpdateRedirect(id, &req)
+	if err != nil {
+		h.redirectError(c, "更新重定向失败", err)
+		return
+	}
+
+	response.Success(c, redirect, "更新成功")
+}
+
+// DeleteRedirect 删除重定向
+// @Summary 删除重定向
+// @Tags 重定向
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "重定向ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/rbac/redirects/{id} [delete]
+func (h *RedirectHandler) DeleteRedirect(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "无效的重定向ID", err)
+		return
+	}
+
+	if err := h.redirectService.DeleteRedirect(id); err != nil {
+		h.redirectError(c, "删除重定向失败", err)
+		return
+	}
+
+	response.Success(c, nil, "删除成功")
+}
+
+// redirectError 按错误类型返回状态码
+func (h *RedirectHandler) redirectError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrRedirectNotFound):
+		response.Error(c, http.StatusNotFound, err.Error(), err)
+	case errors.Is(err, services.ErrRedirectExists):
+		response.Error(c, http.StatusConflict, err.Error(), err)
+	case errors.Is(err, services.ErrRedirectLoop), errors.Is(err, services.ErrInvalidRedirectURL), errors.Is(err, services.ErrInvalidRedirectPath):
+		response.Error(c, http.StatusBadRequest, err.Error(), err)
+	default:
+		logger.Error(message, logger.Err("error", err))
+		response.Error(c, http.StatusInternalServerError, message, err)
+	}
+}
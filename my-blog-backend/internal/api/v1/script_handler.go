@@ -0,0 +1,214 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"my-blog-backend/internal/api/v1/dto/request"
+	dtoResponse "my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/middleware"
+	"my-blog-backend/internal/services"
+)
+
+type ScriptHandler struct {
+	scriptService *services.ScriptService
+}
+
+func NewScriptHandler(scriptService *services.ScriptService) *ScriptHandler {
+	return &ScriptHandler{scriptService: scriptService}
+}
+
+// CreateScript 创建脚本
+// @Summary 创建脚本
+// @Tags 脚本库
+// @Accept json
+// @Produce json
+// @Param request body request.CreateScriptRequest true "脚本信息"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/scripts [post]
+func (h *ScriptHandler) CreateScript(c *gin.Context) {
+	var req request.CreateScriptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	if err := h.scriptService.CreateScript(&req, uint(userID)); err != nil {
+		dtoResponse.Error(c, 500, "创建脚本失败: "+err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, nil, "创建成功")
+}
+
+// UpdateScript 更新脚本（内容或参数变化时生成新版本）
+// @Summary 更新脚本
+// @Tags 脚本库
+// @Accept json
+// @Produce json
+// @Param request body request.UpdateScriptRequest true "脚本信息"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/scripts [put]
+func (h *ScriptHandler) UpdateScript(c *gin.Context) {
+	var req request.UpdateScriptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	if err := h.scriptService.UpdateScript(&req, uint(userID)); err != nil {
+		dtoResponse.Error(c, 500, "更新脚本失败: "+err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, nil, "更新成功")
+}
+
+// DeleteScript 删除脚本
+// @Summary 删除脚本
+// @Tags 脚本库
+// @Param id path int true "脚本ID"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/scripts/{id} [delete]
+func (h *ScriptHandler) DeleteScript(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的脚本ID", err)
+		return
+	}
+
+	if err := h.scriptService.DeleteScript(uint(id)); err != nil {
+		dtoResponse.Error(c, 500, "删除脚本失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, nil, "删除成功")
+}
+
+// GetScript 获取脚本详情
+// @Summary 获取脚本详情
+// @Tags 脚本库
+// @Param id path int true "脚本ID"
+// @Param version query int false "版本号，默认最新版本"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.ScriptDetailResponse}
+// @Router /api/v1/rbac/scripts/{id} [get]
+func (h *ScriptHandler) GetScript(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的脚本ID", err)
+		return
+	}
+	version, _ := strconv.Atoi(c.Query("version"))
+
+	script, err := h.scriptService.GetScript(uint(id), version)
+	if err != nil {
+		dtoResponse.Error(c, 404, err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, script, "获取成功")
+}
+
+// ListScripts 脚本列表
+// @Summary 脚本列表
+// @Tags 脚本库
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param name query string false "脚本名称"
+// @Param language query string false "脚本语言"
+// @Param owner query int false "所有者ID"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.ScriptListResponse}
+// @Router /api/v1/rbac/scripts [get]
+func (h *ScriptHandler) ListScripts(c *gin.Context) {
+	var req request.ListScriptRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	list, err := h.scriptService.ListScripts(&req)
+	if err != nil {
+		dtoResponse.Error(c, 500, "获取脚本列表失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, list, "获取成功")
+}
+
+// ListVersions 脚本版本历史
+// @Summary 脚本版本历史
+// @Tags 脚本库
+// @Param id path int true "脚本ID"
+// @Success 200 {object} dtoResponse.Response{data=[]dtoResponse.ScriptVersionResponse}
+// @Router /api/v1/rbac/scripts/{id}/versions [get]
+func (h *ScriptHandler) ListVersions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的脚本ID", err)
+		return
+	}
+
+	versions, err := h.scriptService.ListVersions(uint(id))
+	if err != nil {
+		dtoResponse.Error(c, 500, "获取版本历史失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, versions, "获取成功")
+}
+
+// DiffVersions 对比脚本两个版本
+// @Summary 对比脚本版本
+// @Tags 脚本库
+// @Param id path int true "脚本ID"
+// @Param from query int true "起始版本"
+// @Param to query int true "目标版本"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.ScriptDiffResponse}
+// @Router /api/v1/rbac/scripts/{id}/diff [get]
+func (h *ScriptHandler) DiffVersions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的脚本ID", err)
+		return
+	}
+
+	var req request.DiffScriptRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	diff, err := h.scriptService.DiffVersions(uint(id), req.From, req.To)
+	if err != nil {
+		dtoResponse.Error(c, 404, err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, diff, "获取成功")
+}
+
+// RunScript 在主机上批量执行脚本
+// @Summary 执行脚本
+// @Tags 脚本库
+// @Accept json
+// @Produce json
+// @Param request body request.RunScriptRequest true "执行参数"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/scripts/run [post]
+func (h *ScriptHandler) RunScript(c *gin.Context) {
+	var req request.RunScriptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	taskID, err := h.scriptService.RunScript(&req, taskOperator(c))
+	if err != nil {
+		dtoResponse.Error(c, taskErrorStatus(err, 500), "执行脚本失败: "+err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, gin.H{"task_id": taskID}, "任务已提交")
+}
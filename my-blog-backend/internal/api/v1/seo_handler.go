@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sitemapCacheControl 站点地图和 robots.txt 的客户端缓存策略
+const sitemapCacheControl = "public, max-age=3600"
+
+type SEOHandler struct {
+	seoService services.SEOService
+}
+
+func NewSEOHandler(seoService services.SEOService) *SEOHandler {
+	return &SEOHandler{
+		seoService: seoService,
+	}
+}
+
+// Sitemap 站点地图
+// @Summary 站点地图，内容较多时为站点地图索引
+// @Tags SEO
+// @Produce xml
+// @Success 200 {string} string "sitemap"
+// @Success 304 {string} string "未修改"
+// @Router /sitemap.xml [get]
+func (h *SEOHandler) Sitemap(c *gin.Context) {
+	output, err := h.seoService.Sitemap()
+	h.serveSitemap(c, output, err)
+}
+
+// SitemapPage 站点地图索引中的子站点地图
+// @Summary 子站点地图
+// @Tags SEO
+// @Produce xml
+// @Param name path string true "名称，如 articles-1.xml"
+// @Success 200 {string} string "sitemap"
+// @Success 304 {string} string "未修改"
+// @Router /sitemap/{name} [get]
+func (h *SEOHandler) SitemapPage(c *gin.Context) {
+	output, err := h.seoService.SitemapPage(c.Param("name"))
+	h.serveSitemap(c, output, err)
+}
+
+// Robots robots.txt
+// @Summary robots.txt
+// @Tags SEO
+// @Produce plain
+// @Success 200 {string} string "robots.txt"
+// @Router /robots.txt [get]
+func (h *SEOHandler) Robots(c *gin.Context) {
+	c.Header("Cache-Control", sitemapCacheControl)
+	c.String(http.StatusOK, h.seoService.Robots())
+}
+
+func (h *SEOHandler) serveSitemap(c *gin.Context, output *services.SitemapOutput, err error) {
+	if err != nil {
+		if errors.Is(err, services.ErrSitemapNotFound) {
+			response.Error(c, http.StatusNotFound, "站点地图不存在", err)
+			return
+		}
+		logger.Error("生成站点地图失败", logger.String("path", c.Request.URL.Path), logger.Err("error", err))
+		response.Error(c, http.StatusInternalServerError, "生成站点地图失败", err)
+		return
+	}
+
+	c.Header("ETag", output.ETag)
+	c.Header("Cache-Control", sitemapCacheControl)
+	if etagMatches(c.Request.Header.Get("If-None-Match"), output.ETag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", output.Body)
+}
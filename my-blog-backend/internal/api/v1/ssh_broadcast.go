@@ -0,0 +1,420 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/middleware"
+	"my-blog-backend/internal/services"
+	"my-blog-backend/internal/ssh"
+
+	"github.com/gin-gonic/gin"
+
+	ws "github.com/gorilla/websocket"
+)
+
+// maxBroadcastHosts 广播终端一次最多连接的主机数
+const maxBroadcastHosts = 20
+
+// 广播终端发给浏览器的消息类型和主机状态
+const (
+	broadcastOutput = "output"
+	broadcastStatus = "status"
+
+	broadcastConnected = "connected"
+	broadcastFailed    = "failed"
+	broadcastAttached  = "attached"
+	broadcastDetached  = "detached"
+	broadcastClosed    = "closed"
+)
+
+// broadcastMessage 广播终端发给浏览器的消息，输出和状态都带有所属主机
+type broadcastMessage struct {
+	Type     string `json:"type"` // output, status
+	HostID   uint   `json:"host_id"`
+	HostName string `json:"host_name,omitempty"`
+	Data     string `json:"data,omitempty"`
+	Status   string `json:"status,omitempty"` // connected, failed, attached, detached, closed
+	Message  string `json:"message,omitempty"`
+
+	closeConn bool // 发送完之前的消息后关闭 WebSocket
+}
+
+// broadcastCommand 浏览器发来的消息
+// input: host_id 为 0 时发送到所有未分离的主机，否则只发送到该主机
+// resize: host_id 为 0 时调整所有主机的窗口
+// detach/attach: 主机退出或重新加入广播；close: 关闭该主机的会话
+type broadcastCommand struct {
+	Type   string `json:"type"`
+	HostID uint   `json:"host_id"`
+	Data   string `json:"data"`
+	Rows   int    `json:"rows"`
+	Cols   int    `json:"cols"`
+}
+
+// broadcastTarget 广播终端中的一台主机
+type broadcastTarget struct {
+	hostID   uint
+	session  *ssh.Session
+	auditCtx *services.TerminalAuditContext
+	record   *opsModel.TerminalSession
+	line     ssh.LineBuffer // 只由读协程访问
+	attached bool
+}
+
+// broadcastTerminal 一个 WebSocket 控制的多台主机会话，所有写 WebSocket 的操作都经过 out
+type broadcastTerminal struct {
+	h    *SshHandler
+	conn *ws.Conn
+	out  chan broadcastMessage
+	done chan struct{}
+
+	mu         sync.Mutex
+	targets    map[uint]*broadcastTarget
+	order      []uint // 主机的连接顺序
+	connecting bool   // 仍在连接其他主机时，已连接的主机全部关闭也不断开 WebSocket
+	pumps      sync.WaitGroup
+}
+
+// BroadcastConnect 广播终端 WebSocket 连接，键盘输入同时发送到多台主机，输出按主机分别返回
+// @Summary 广播终端 WebSocket 连接
+// @Tags SSH终端
+// @Param host_ids query string true "主机ID列表，逗号分隔"
+// @Param session_id query string true "会话ID，每台主机的会话ID为 {session_id}-{host_id}"
+// @Success 101
+// @Router /api/v1/ssh/broadcast [get]
+func (h *SshHandler) BroadcastConnect(c *gin.Context) {
+	sessionID := c.Query("session_id")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少会话ID"})
+		return
+	}
+
+	hostIDs, err := parseHostIDs(c.Query("host_ids"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 任意一台主机无权访问时拒绝整个连接
+	userID, _ := middleware.GetCurrentUserID(c)
+	superAdmin := middleware.IsSuperAdmin(middleware.GetCurrentRoleIDs(c))
+	for _, hostID := range hostIDs {
+		if err := h.hostService.CheckHostAccess(uint(userID), superAdmin, hostID); err != nil {
+			log.Printf("Broadcast connect error: user %d access host %d denied: %v", userID, hostID, err)
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("主机 %d: %v", hostID, err)})
+			return
+		}
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	conn.SetReadLimit(wsReadLimit)
+
+	b := &broadcastTerminal{
+		h:          h,
+		conn:       conn,
+		out:        make(chan broadcastMessage, 1024),
+		done:       make(chan struct{}),
+		targets:    make(map[uint]*broadcastTarget),
+		connecting: true,
+	}
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		b.writeLoop()
+	}()
+
+	b.connect(c, uint(userID), sessionID, hostIDs)
+	if b.count() > 0 {
+		b.readLoop()
+	} else {
+		log.Printf("Broadcast %s: no host connected", sessionID)
+	}
+
+	// 浏览器断开或所有主机都已关闭
+	b.closeAll()
+	b.pumps.Wait()
+	close(b.done)
+	<-writerDone
+	conn.Close()
+}
+
+// connect 并发连接所有主机，逐台返回连接结果
+func (b *broadcastTerminal) connect(c *gin.Context, userID uint, sessionID string, hostIDs []uint) {
+	var wg sync.WaitGroup
+	for _, hostID := range hostIDs {
+		wg.Add(1)
+		go func(hostID uint) {
+			defer wg.Done()
+			auditCtx := b.h.auditContext(c, userID, hostID, fmt.Sprintf("%s-%d", sessionID, hostID))
+			target, err := b.open(c, auditCtx)
+			if err != nil {
+				log.Printf("Broadcast %s: host %d failed: %v", sessionID, hostID, err)
+				b.send(broadcastMessage{Type: broadcastStatus, HostID: hostID, HostName: auditCtx.HostName, Status: broadcastFailed, Message: err.Error()})
+				return
+			}
+			b.add(target)
+			b.send(broadcastMessage{Type: broadcastStatus, HostID: hostID, HostName: auditCtx.HostName, Status: broadcastConnected})
+		}(hostID)
+	}
+	wg.Wait()
+
+	b.mu.Lock()
+	b.connecting = false
+	b.mu.Unlock()
+}
+
+// open 创建并启动一台主机的终端会话
+func (b *broadcastTerminal) open(c *gin.Context, auditCtx *services.TerminalAuditContext) (*broadcastTarget, error) {
+	session, err := b.h.newTerminalSession(c, auditCtx.HostID, auditCtx.SessionID, nil, auditCtx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// 多台主机共用一个 WebSocket，无法转发 rz/sz 的二进制帧
+	session.DisableTransfer = true
+
+	b.h.sessions.Add(session)
+	if err := session.Start(ssh.PtyConfig{Term: "xterm", Rows: 24, Cols: 80}); err != nil {
+		b.h.sessions.Remove(session)
+		session.Close()
+		return nil, fmt.Errorf("启动会话失败: %v", err)
+	}
+
+	return &broadcastTarget{
+		hostID:   auditCtx.HostID,
+		session:  session,
+		auditCtx: auditCtx,
+		record:   b.h.sessionService.Open(session),
+		attached: true,
+	}, nil
+}
+
+// add 登记主机并启动输出转发
+func (b *broadcastTerminal) add(target *broadcastTarget) {
+	b.mu.Lock()
+	b.targets[target.hostID] = target
+	b.order = append(b.order, target.hostID)
+	b.mu.Unlock()
+
+	b.pumps.Add(1)
+	go func() {
+		defer b.pumps.Done()
+		b.pump(target)
+	}()
+}
+
+// pump 转发一台主机的输出，会话结束后清理并通知浏览器
+func (b *broadcastTerminal) pump(target *broadcastTarget) {
+	session := target.session
+	defer func() {
+		b.h.sessions.Remove(session)
+		session.Close()
+		b.h.sessionService.Close(target.record, session)
+
+		b.mu.Lock()
+		delete(b.targets, target.hostID)
+		last := len(b.targets) == 0 && !b.connecting
+		b.mu.Unlock()
+
+		b.send(broadcastMessage{Type: broadcastStatus, HostID: target.hostID, HostName: target.auditCtx.HostName, Status: broadcastClosed, Message: session.CloseReason()})
+		// 所有主机都已关闭时断开 WebSocket，结束读协程
+		if last {
+			b.send(broadcastMessage{closeConn: true})
+		}
+	}()
+
+	for {
+		select {
+		case <-session.Done:
+			return
+		case data := <-session.OutputChan:
+			// 合并已到达的输出，减少消息数量
+			data = drainOutput(session, data)
+			b.send(broadcastMessage{Type: broadcastOutput, HostID: target.hostID, Data: string(data)})
+		case frame := <-session.FrameChan:
+			// 禁用 rz/sz 后只剩强制结束会话的提示
+			if frame.Type == ws.TextMessage {
+				data := drainOutput(session, nil)
+				b.send(broadcastMessage{Type: broadcastOutput, HostID: target.hostID, Data: string(append(data, frame.Data...))})
+			}
+			if frame.Close {
+				log.Printf("Session %s terminated, closing", session.ID)
+				return
+			}
+		}
+	}
+}
+
+// readLoop 读取浏览器消息直到连接断开
+func (b *broadcastTerminal) readLoop() {
+	for {
+		_, message, err := b.conn.ReadMessage()
+		if err != nil {
+			log.Printf("Broadcast WebSocket read error: %v", err)
+			return
+		}
+
+		var cmd broadcastCommand
+		if err := json.Unmarshal(message, &cmd); err != nil {
+			continue
+		}
+
+		switch cmd.Type {
+		case "input":
+			b.input(cmd.HostID, []byte(cmd.Data))
+		case "resize":
+			for _, target := range b.targetsFor(cmd.HostID, false) {
+				if err := target.session.ReSize(cmd.Rows, cmd.Cols); err != nil {
+					log.Printf("Resize window failed for session %s: %v", target.session.ID, err)
+				}
+			}
+		case "detach", "attach":
+			b.setAttached(cmd.HostID, cmd.Type == "attach")
+		case "close":
+			for _, target := range b.targetsFor(cmd.HostID, false) {
+				target.session.Close()
+			}
+		}
+	}
+}
+
+// input 将输入发送到目标主机，每台主机单独按命令策略过滤并审计提交的命令
+func (b *broadcastTerminal) input(hostID uint, data []byte) {
+	for _, target := range b.targetsFor(hostID, true) {
+		filtered := b.h.filterInput(target.session, &target.line, data, func(cmd string, err error) {
+			if strings.TrimSpace(cmd) == "" {
+				return
+			}
+			go b.h.auditService.RecordCommand(target.auditCtx, b.h.masker.Mask(cmd), err)
+		})
+		if len(filtered) > 0 && !target.session.SendInput(filtered) {
+			log.Printf("Broadcast input dropped for session %s", target.session.ID)
+		}
+	}
+}
+
+// targetsFor 返回 hostID 对应的主机，hostID 为 0 时返回所有主机（attachedOnly 时只返回未分离的主机）
+func (b *broadcastTerminal) targetsFor(hostID uint, attachedOnly bool) []*broadcastTarget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if hostID != 0 {
+		if target, ok := b.targets[hostID]; ok {
+			return []*broadcastTarget{target}
+		}
+		return nil
+	}
+	targets := make([]*broadcastTarget, 0, len(b.targets))
+	for _, id := range b.order {
+		target, ok := b.targets[id]
+		if ok && (target.attached || !attachedOnly) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// setAttached 主机退出或重新加入广播，退出后仍可单独向该主机输入
+func (b *broadcastTerminal) setAttached(hostID uint, attached bool) {
+	b.mu.Lock()
+	target, ok := b.targets[hostID]
+	if ok {
+		target.attached = attached
+	}
+	b.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	status := broadcastDetached
+	if attached {
+		status = broadcastAttached
+	}
+	b.send(broadcastMessage{Type: broadcastStatus, HostID: hostID, HostName: target.auditCtx.HostName, Status: status})
+}
+
+func (b *broadcastTerminal) count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.targets)
+}
+
+// closeAll 关闭所有主机会话
+func (b *broadcastTerminal) closeAll() {
+	for _, target := range b.targetsFor(0, false) {
+		target.session.Close()
+	}
+}
+
+// send 将消息交给写协程，连接结束后丢弃
+func (b *broadcastTerminal) send(msg broadcastMessage) {
+	select {
+	case b.out <- msg:
+	case <-b.done:
+	}
+}
+
+// writeLoop 唯一写 WebSocket 的协程，写失败后继续消费消息直到结束，避免发送方阻塞
+func (b *broadcastTerminal) writeLoop() {
+	failed := false
+	for {
+		select {
+		case msg := <-b.out:
+			if failed {
+				continue
+			}
+			if msg.closeConn {
+				b.conn.WriteControl(ws.CloseMessage, ws.FormatCloseMessage(ws.CloseNormalClosure, "all sessions closed"), time.Now().Add(wsWriteWait))
+				failed = true
+				b.conn.Close()
+				continue
+			}
+			data, _ := json.Marshal(msg)
+			b.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := b.conn.WriteMessage(ws.TextMessage, data); err != nil {
+				log.Printf("Broadcast WebSocket write error: %v", err)
+				failed = true
+				b.conn.Close()
+			}
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// parseHostIDs 解析逗号分隔的主机ID列表，去重并限制数量
+func parseHostIDs(value string) ([]uint, error) {
+	seen := make(map[uint]bool)
+	var ids []uint
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := parseUint(part)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("无效的主机ID: %s", part)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("缺少主机ID")
+	}
+	if len(ids) > maxBroadcastHosts {
+		return nil, fmt.Errorf("一次最多连接 %d 台主机", maxBroadcastHosts)
+	}
+	return ids, nil
+}
@@ -6,6 +6,7 @@ import (
 	"my-blog-backend/internal/api/v1/dto/request"
 	"my-blog-backend/internal/api/v1/dto/response"
 	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/pkg/middleware"
 	"my-blog-backend/internal/services"
 	"my-blog-backend/internal/ssh"
 	"net/http"
@@ -128,11 +129,10 @@ var chunkManager = ChunkManager{
 type SshFileHandler struct {
 	hostService *services.HostService
 	pool        *ssh.Pool
-	sessions    map[string]*ssh.Session // 共享 SshHandler 的 sessions
-	mu          sync.RWMutex
+	sessions    *ssh.Registry // 共享 SshHandler 的会话登记表
 }
 
-func NewSshFileHandler(hostService *services.HostService, pool *ssh.Pool, sessions map[string]*ssh.Session) *SshFileHandler {
+func NewSshFileHandler(hostService *services.HostService, pool *ssh.Pool, sessions *ssh.Registry) *SshFileHandler {
 	return &SshFileHandler{
 		hostService: hostService,
 		pool:        pool,
@@ -140,6 +140,21 @@ func NewSshFileHandler(hostService *services.HostService, pool *ssh.Pool, sessio
 	}
 }
 
+// ownedSession 获取当前用户自己的终端会话，文件操作沿用终端的主机授权，不能借用他人的会话
+func (h *SshFileHandler) ownedSession(c *gin.Context, sessionID string) (*ssh.Session, bool) {
+	session, ok := h.sessions.Get(sessionID)
+	if !ok {
+		response.Error(c, http.StatusBadRequest, "无效的session_id", fmt.Errorf("无效的session_id: %s", sessionID))
+		return nil, false
+	}
+	userID, _ := middleware.GetCurrentUserID(c)
+	if session.UserID != uint(userID) {
+		response.Error(c, http.StatusForbidden, "无权访问该会话", fmt.Errorf("用户 %d 无权访问会话 %s", userID, sessionID))
+		return nil, false
+	}
+	return session, true
+}
+
 // UploadFile 处理文件分片上传
 func (h *SshFileHandler) UploadFile(c *gin.Context) {
 	// 获取参数
@@ -176,11 +191,8 @@ func (h *SshFileHandler) UploadFile(c *gin.Context) {
 	}
 
 	// 获取 SSH 会话和 SFTP 客户端
-	h.mu.RLock()
-	session, ok := h.sessions[req.SessionID]
-	h.mu.RUnlock()
+	session, ok := h.ownedSession(c, req.SessionID)
 	if !ok {
-		response.Error(c, http.StatusBadRequest, "无效的session_id", fmt.Errorf("无效的session_id: %s", req.SessionID))
 		return
 	}
 
@@ -313,9 +325,7 @@ func (h *SshFileHandler) UploadFile(c *gin.Context) {
 // mergeChunksAsync 异步合并所有分片为最终文件
 func (h *SshFileHandler) mergeChunksAsync(sessionID, filePath, name string, count int) {
 	// 获取 SSH 会话和 SFTP 客户端
-	h.mu.Lock()
-	session, ok := h.sessions[sessionID]
-	h.mu.Unlock()
+	session, ok := h.sessions.Get(sessionID)
 	if !ok {
 		logger.Error("异步合并时，获取session出错")
 		return
@@ -352,6 +362,12 @@ func (h *SshFileHandler) mergeChunksAsync(sessionID, filePath, name string, coun
 			tempChunkPath = path.Join(filePath, fmt.Sprintf(".tmp_%s_chunk_%d", name, i))
 		}
 
+		// 会话被强制断开时停止合并
+		if !session.IsActive() {
+			logger.Warn("会话已关闭，停止合并", logger.String("session_id", sessionID), logger.String("file", targetPath))
+			break
+		}
+
 		// 打开临时分片文件
 		tempFile, err := sftpClient.Open(tempChunkPath)
 		if err != nil {
@@ -383,9 +399,9 @@ func (h *SshFileHandler) mergeChunksAsync(sessionID, filePath, name string, coun
 	}
 
 	// 清理切片管理器
-	h.mu.Lock()
+	chunkManager.mu.Lock()
 	delete(chunkManager.chunkMap, targetPath)
-	h.mu.Unlock()
+	chunkManager.mu.Unlock()
 
 	logger.Info("文件合并完成", logger.String("file", targetPath))
 }
@@ -404,11 +420,8 @@ func (h *SshFileHandler) List(c *gin.Context) {
 	}
 
 	// 获取 SSH 会话
-	h.mu.RLock()
-	session, ok := h.sessions[sessionID]
-	h.mu.RUnlock()
+	session, ok := h.ownedSession(c, sessionID)
 	if !ok {
-		response.Error(c, http.StatusBadRequest, "无效的session_id", fmt.Errorf("无效的session_id: %s", sessionID))
 		return
 	}
 
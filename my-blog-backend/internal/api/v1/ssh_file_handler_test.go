@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"my-blog-backend/internal/ssh"
+	"my-blog-backend/internal/ssh/sshtest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/sftp"
+)
+
+// uploadChunk 以 multipart 表单上传一个分片，返回响应中的 completed 字段
+func uploadChunk(t *testing.T, router *gin.Engine, dir, name string, index, total int, data []byte) bool {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	_ = form.WriteField("path", dir)
+	_ = form.WriteField("file_name", name)
+	_ = form.WriteField("chunk_index", strconv.Itoa(index))
+	_ = form.WriteField("total_chunks", strconv.Itoa(total))
+	part, err := form.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatalf("创建表单文件失败: %v", err)
+	}
+	_, _ = part.Write(data)
+	_ = form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload?session_id=s1", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("上传分片 %d 失败: %d %s", index, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data struct {
+			Completed bool `json:"completed"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	return resp.Data.Completed
+}
+
+func TestUploadFileMergesChunks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := sshtest.NewServer(t)
+	pool := ssh.NewPool(time.Minute)
+	t.Cleanup(func() { _ = pool.Close() })
+
+	client, err := pool.Get(context.Background(), server.PasswordConfig(), 1)
+	if err != nil {
+		t.Fatalf("获取连接失败: %v", err)
+	}
+	sftpClient, err := client.GetSFTP()
+	if err != nil {
+		t.Fatalf("获取 SFTP 失败: %v", err)
+	}
+	if err := sftpClient.Mkdir("/upload"); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+
+	session := ssh.NewSession(client, nil, "s1")
+	session.UserID = 7
+	sessions := ssh.NewRegistry()
+	sessions.Add(session)
+	handler := NewSshFileHandler(nil, pool, sessions)
+	router := gin.New()
+	router.POST("/upload", func(c *gin.Context) {
+		c.Set("user_id", uint64(7))
+		handler.UploadFile(c)
+	})
+
+	chunks := [][]byte{[]byte("first-"), []byte("second-"), []byte("third")}
+	// 分片乱序到达，只有最后一个分片返回完成
+	for i, index := range []int{2, 0, 1} {
+		completed := uploadChunk(t, router, "/upload", "merged.txt", index, len(chunks), chunks[index])
+		if completed != (i == len(chunks)-1) {
+			t.Fatalf("第 %d 次上传 completed = %v", i+1, completed)
+		}
+	}
+
+	// 合并在后台进行，等待目标文件内容完整且临时分片被清理
+	want := string(bytes.Join(chunks, nil))
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		content, leftover := readUploadDir(t, sftpClient, "/upload/merged.txt")
+		if content == want && leftover == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("合并结果 = %q，剩余临时分片 %d 个", content, leftover)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// readUploadDir 读取目标文件内容并统计目录中剩余的临时分片
+func readUploadDir(t *testing.T, sftpClient *sftp.Client, target string) (string, int) {
+	t.Helper()
+	var content string
+	if file, err := sftpClient.Open(target); err == nil {
+		data, _ := io.ReadAll(file)
+		_ = file.Close()
+		content = string(data)
+	}
+
+	entries, err := sftpClient.ReadDir(path.Dir(target))
+	if err != nil {
+		t.Fatalf("读取目录失败: %v", err)
+	}
+	leftover := 0
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".tmp_") {
+			leftover++
+		}
+	}
+	return content, leftover
+}
@@ -2,13 +2,18 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
+	"my-blog-backend/internal/api/v1/dto/request"
 	dtoResponse "my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/config"
+	"my-blog-backend/internal/pkg/middleware"
 	"my-blog-backend/internal/services"
 	"my-blog-backend/internal/ssh"
 
@@ -34,22 +39,33 @@ const (
 )
 
 type SshHandler struct {
-	hostService *services.HostService
-	pool        *ssh.Pool
-	sessions    map[string]*ssh.Session
-	mu          sync.RWMutex
+	hostService      *services.HostService
+	containerService *services.ContainerService
+	pool             *ssh.Pool
+	policy           *services.CommandPolicy
+	auditService     *services.AuditLogService
+	sessionService   *services.TerminalSessionService
+	masker           *services.OutputMasker
+	cfg              *config.OpsConfig
+	sessions         *ssh.Registry
 }
 
-func NewSshHandler(hostService *services.HostService, pool *ssh.Pool) *SshHandler {
+func NewSshHandler(hostService *services.HostService, containerService *services.ContainerService, pool *ssh.Pool, policy *services.CommandPolicy, auditService *services.AuditLogService, sessionService *services.TerminalSessionService, masker *services.OutputMasker, cfg *config.OpsConfig) *SshHandler {
 	return &SshHandler{
-		hostService: hostService,
-		pool:        pool,
-		sessions:    make(map[string]*ssh.Session),
+		hostService:      hostService,
+		containerService: containerService,
+		pool:             pool,
+		policy:           policy,
+		auditService:     auditService,
+		sessionService:   sessionService,
+		masker:           masker,
+		cfg:              cfg,
+		sessions:         ssh.NewRegistry(),
 	}
 }
 
-// GetSessions 获取 sessions map 供其他 handler 共享
-func (h *SshHandler) GetSessions() map[string]*ssh.Session {
+// GetSessions 获取会话登记表供其他 handler 共享
+func (h *SshHandler) GetSessions() *ssh.Registry {
 	return h.sessions
 }
 
@@ -78,60 +94,113 @@ func (h *SshHandler) WebSocketConnect(c *gin.Context) {
 
 	log.Printf("WebSocket connection request: hostID=%d, sessionID=%s", hostID, sessionID)
 
-	// 升级为 WebSocket 连接
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
-	if err != nil {
-		log.Printf("WebSocket upgrade error: %v", err)
+	// 校验主机权限（与命令执行接口一致）
+	userID, _ := middleware.GetCurrentUserID(c)
+	superAdmin := middleware.IsSuperAdmin(middleware.GetCurrentRoleIDs(c))
+	if err := h.hostService.CheckHostAccess(uint(userID), superAdmin, hostID); err != nil {
+		log.Printf("WebSocket connect error: user %d access host %d denied: %v", userID, hostID, err)
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		return
 	}
 
-	log.Printf("WebSocket connection established for hostID=%d", hostID)
+	auditCtx := h.auditContext(c, uint(userID), hostID, sessionID)
+	h.serveTerminal(c, hostID, sessionID, ssh.PtyConfig{
+		Term: "xterm",
+		Rows: 50,
+		Cols: 150,
+	}, auditCtx, nil)
+}
 
-	// 获取 SSH 配置
-	sshConfig, err := h.hostService.GetSSHConfig(hostID)
+// ContainerConnect 容器终端 WebSocket 连接，在 PTY 中执行 docker exec 进入容器
+// @Summary 容器终端 WebSocket 连接
+// @Tags SSH终端
+// @Param host_id path string true "主机ID"
+// @Param container path string true "容器ID、ID前缀或名称"
+// @Param session_id query string true "会话ID"
+// @Success 101
+// @Router /api/v1/ssh/connect/{host_id}/containers/{container} [get]
+func (h *SshHandler) ContainerConnect(c *gin.Context) {
+	hostID, err := parseUint(c.Param("host_id"))
 	if err != nil {
-		log.Printf("Get SSH config error: %v", err)
-		conn.WriteMessage(ws.TextMessage, []byte("获取主机配置失败: "+err.Error()))
-		conn.Close()
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的主机ID"})
 		return
 	}
 
-	log.Printf("SSH config: host=%s, port=%d, user=%s, authType=%v",
-		sshConfig.Host, sshConfig.Port, sshConfig.Username, sshConfig.AuthType)
+	sessionID := c.Query("session_id")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少会话ID"})
+		return
+	}
 
-	// 从连接池获取 SSH 客户端
-	sshClient, err := h.pool.Get(c.Request.Context(), sshConfig, hostID)
+	userID, _ := middleware.GetCurrentUserID(c)
+	superAdmin := middleware.IsSuperAdmin(middleware.GetCurrentRoleIDs(c))
+	if err := h.hostService.CheckHostAccess(uint(userID), superAdmin, hostID); err != nil {
+		log.Printf("Container connect error: user %d access host %d denied: %v", userID, hostID, err)
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	container, err := h.containerService.ResolveContainer(c.Request.Context(), uint(userID), superAdmin, hostID, c.Param("container"))
 	if err != nil {
-		log.Printf("Get SSH client from pool error: %v", err)
-		conn.WriteMessage(ws.TextMessage, []byte("SSH 连接失败: "+err.Error()))
-		conn.Close()
+		log.Printf("Container connect error: host %d container %s: %v", hostID, c.Param("container"), err)
+		switch {
+		case errors.Is(err, services.ErrContainerAccessDenied), errors.Is(err, services.ErrHostAccessDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrContainerNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "获取容器失败: " + err.Error()})
+		}
 		return
 	}
 
-	log.Printf("SSH client obtained successfully for hostID=%d", hostID)
+	auditCtx := h.auditContext(c, uint(userID), hostID, sessionID)
+	auditCtx.ContainerID = container.ID
+	command := ssh.DockerExecCommand(container.ID)
+	start := time.Now()
+	err = h.serveTerminal(c, hostID, sessionID, ssh.PtyConfig{
+		Term:    "xterm",
+		Rows:    50,
+		Cols:    150,
+		Command: command,
+	}, auditCtx, container)
+	h.auditService.RecordSession(auditCtx, command, start, err)
+}
 
-	// 创建会话
-	session := ssh.NewSession(sshClient, conn, sessionID)
+// serveTerminal 升级 WebSocket 并在 SSH 会话中运行终端，直到连接断开
+// 返回会话建立阶段的错误，建立后的断开不视为错误
+func (h *SshHandler) serveTerminal(c *gin.Context, hostID uint, sessionID string, pty ssh.PtyConfig, auditCtx *services.TerminalAuditContext, container *ssh.Container) error {
+	// 升级为 WebSocket 连接
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return err
+	}
 
-	h.mu.Lock()
-	h.sessions[sessionID] = session
-	h.mu.Unlock()
+	log.Printf("WebSocket connection established for hostID=%d", hostID)
+
+	session, err := h.newTerminalSession(c, hostID, sessionID, conn, auditCtx, container)
+	if err != nil {
+		conn.WriteMessage(ws.TextMessage, []byte(err.Error()))
+		conn.Close()
+		return err
+	}
+
+	h.sessions.Add(session)
 
 	log.Printf("SSH session created: sessionID=%s", sessionID)
 
 	// 启动会话
-	if err := session.Start(ssh.PtyConfig{
-		Term: "xterm",
-		Rows: 50,
-		Cols: 150,
-	}); err != nil {
+	if err := session.Start(pty); err != nil {
 		log.Printf("Session start error: %v", err)
 		conn.WriteMessage(ws.TextMessage, []byte("启动会话失败: "+err.Error()))
+		h.sessions.Remove(session)
 		session.Close()
-		return
+		return err
 	}
 
 	log.Printf("SSH session started successfully for hostID=%d", hostID)
+	record := h.sessionService.Open(session)
 
 	// 发送连接成功消息
 	conn.WriteMessage(ws.TextMessage, []byte("SSH 会话已建立，连接到远程主机...\r\n"))
@@ -167,23 +236,72 @@ func (h *SshHandler) WebSocketConnect(c *gin.Context) {
 	wg.Wait()
 
 	// 清理会话
-	h.mu.Lock()
-	delete(h.sessions, sessionID)
-	h.mu.Unlock()
+	h.sessions.Remove(session)
 	session.Close()
+	h.sessionService.Close(record, session)
+	return nil
+}
+
+// newTerminalSession 从连接池获取主机连接并创建终端会话（未启动），失败时返回可直接展示给用户的错误
+func (h *SshHandler) newTerminalSession(c *gin.Context, hostID uint, sessionID string, conn *ws.Conn, auditCtx *services.TerminalAuditContext, container *ssh.Container) (*ssh.Session, error) {
+	// 获取 SSH 配置
+	sshConfig, err := h.hostService.GetSSHConfig(hostID)
+	if err != nil {
+		log.Printf("Get SSH config error: %v", err)
+		return nil, fmt.Errorf("获取主机配置失败: %v", err)
+	}
+
+	log.Printf("SSH config: host=%s, port=%d, user=%s, authType=%v",
+		sshConfig.Host, sshConfig.Port, sshConfig.Username, sshConfig.AuthType)
+
+	// 从连接池获取 SSH 客户端
+	sshClient, err := h.pool.Get(c.Request.Context(), sshConfig, hostID)
+	if err != nil {
+		log.Printf("Get SSH client from pool error: %v", err)
+		return nil, fmt.Errorf("SSH 连接失败: %v", err)
+	}
+
+	log.Printf("SSH client obtained successfully for hostID=%d", hostID)
+
+	// 创建会话
+	session := ssh.NewSession(sshClient, conn, sessionID)
+	session.MaxTransferSize = h.cfg.TerminalMaxTransferSize
+	session.OutputMask = h.masker.NewStream().Mask
+	session.MaskLiveView = h.cfg.OutputMaskLiveView && !middleware.IsSuperAdmin(middleware.GetCurrentRoleIDs(c))
+	session.UserID = auditCtx.UserID
+	session.Container = container
+	session.Meta = ssh.SessionMeta{
+		UserName:    auditCtx.UserName,
+		HostName:    auditCtx.HostName,
+		HostAddress: auditCtx.HostAddress,
+		HostAccount: sshConfig.Username,
+		ClientIP:    auditCtx.ClientIP,
+		ClientAgent: auditCtx.ClientAgent,
+	}
+	h.sessionService.StartRecording(session)
+	session.OnFileTransfer = func(file ssh.ZmodemFile) {
+		h.auditService.RecordFileTransfer(auditCtx, file)
+	}
+	return session, nil
 }
 
 // readWebSocket 从 WebSocket 读取数据并发送到 SSH
 func (h *SshHandler) readWebSocket(conn *ws.Conn, session *ssh.Session) {
 	log.Printf("readWebSocket: starting to read...")
+	var line ssh.LineBuffer
 	for {
 		log.Printf("readWebSocket: waiting for message...")
-		_, message, err := conn.ReadMessage()
+		msgType, message, err := conn.ReadMessage()
 		if err != nil {
 			log.Printf("WebSocket read error: %v", err)
 			return
 		}
 
+		// rz/sz 传输中，浏览器以二进制帧发送 ZMODEM 数据，直接转发且不记录内容
+		if msgType == ws.BinaryMessage && session.WriteTransfer(message) {
+			continue
+		}
+
 		// 记录输入，方便调试
 		log.Printf("Received from WebSocket: %d bytes, content: %q", len(message), string(message))
 
@@ -200,13 +318,22 @@ func (h *SshHandler) readWebSocket(conn *ws.Conn, session *ssh.Session) {
 			}
 			continue // 不发送到 SSH，只用于刷新
 		} else {
-			// 处理 JSON 消息（resize）
+			// 处理 JSON 消息（resize、zmodem）
 			var msg struct {
-				Type string `json:"type"`
-				Rows int    `json:"rows"`
-				Cols int    `json:"cols"`
+				Type   string `json:"type"`
+				Rows   int    `json:"rows"`
+				Cols   int    `json:"cols"`
+				Action string `json:"action"`
 			}
-			if err := json.Unmarshal(message, &msg); err == nil && msg.Type == "resize" {
+			isJSON := json.Unmarshal(message, &msg) == nil
+			if isJSON && msg.Type == "zmodem" {
+				// 浏览器取消或拒绝 rz/sz 传输
+				if msg.Action == "cancel" {
+					session.CancelTransfer("用户取消传输")
+				}
+				continue
+			}
+			if isJSON && msg.Type == "resize" {
 				// 调整窗口大小
 				if err := session.ReSize(msg.Rows, msg.Cols); err != nil {
 					log.Printf("Resize window failed: %v", err)
@@ -216,6 +343,12 @@ func (h *SshHandler) readWebSocket(conn *ws.Conn, session *ssh.Session) {
 				continue // 不发送到 InputChan，直接 continue
 			}
 
+			// 按命令策略过滤，被禁止的命令不会执行
+			message = h.filterInput(session, &line, message, nil)
+			if len(message) == 0 {
+				continue
+			}
+
 			// 发送到 SSH
 			log.Printf("readWebSocket: sending to InputChan...")
 			select {
@@ -229,6 +362,31 @@ func (h *SshHandler) readWebSocket(conn *ws.Conn, session *ssh.Session) {
 	}
 }
 
+// filterInput 在回车时按命令策略校验当前行，被禁止的命令用 Ctrl+U 清空而不发送回车
+// onCommand 不为空时在每行命令提交时调用，err 为策略拒绝的原因
+func (h *SshHandler) filterInput(session *ssh.Session, line *ssh.LineBuffer, data []byte, onCommand func(cmd string, err error)) []byte {
+	out := make([]byte, 0, len(data))
+	for _, b := range data {
+		if cmd, done := line.Feed(b); done {
+			err := h.policy.Check(cmd)
+			if onCommand != nil {
+				onCommand(cmd, err)
+			}
+			if err != nil {
+				log.Printf("Command denied in session %s: %q", session.ID, cmd)
+				out = append(out, 0x15)
+				select {
+				case session.OutputChan <- []byte("\r\n\x1b[31m" + err.Error() + "\x1b[0m\r\n"):
+				default:
+				}
+				continue
+			}
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
 // writeWebSocket 从 SSH 读取数据并发送到 WebSocket
 func (h *SshHandler) writeWebSocket(conn *ws.Conn, session *ssh.Session) {
 	// 增加心跳间隔到 30 秒
@@ -260,6 +418,31 @@ func (h *SshHandler) writeWebSocket(conn *ws.Conn, session *ssh.Session) {
 			flushTimer.Stop()
 			flushTimer.Reset(100 * time.Millisecond)
 
+		case frame := <-session.FrameChan:
+			// 先发送已缓冲的普通输出，保证与 rz/sz 数据的顺序一致
+			batchBuffer = drainOutput(session, batchBuffer)
+			if len(batchBuffer) > 0 {
+				conn.SetWriteDeadline(time.Now().Add(60 * time.Second))
+				if err := conn.WriteMessage(ws.TextMessage, batchBuffer); err != nil {
+					log.Printf("WebSocket write error (frame flush): %v", err)
+					return
+				}
+				batchBuffer = batchBuffer[:0]
+				lastFlush = time.Now()
+			}
+			conn.SetWriteDeadline(time.Now().Add(60 * time.Second))
+			if err := conn.WriteMessage(frame.Type, frame.Data); err != nil {
+				log.Printf("WebSocket write error (frame): %v", err)
+				return
+			}
+			if frame.Close {
+				// 强制结束会话：提示已发送，通知浏览器后关闭
+				conn.WriteControl(ws.CloseMessage, ws.FormatCloseMessage(ws.ClosePolicyViolation, "session terminated"), time.Now().Add(wsWriteWait))
+				log.Printf("Session %s terminated, closing", session.ID)
+				session.Close()
+				return
+			}
+
 		case <-flushTimer.C:
 			// 定时器到期，检查并刷新缓冲区
 			if len(batchBuffer) > 0 {
@@ -333,6 +516,35 @@ func (h *SshHandler) writeWebSocket(conn *ws.Conn, session *ssh.Session) {
 	}
 }
 
+// drainOutput 取出 OutputChan 中已有的输出追加到缓冲区
+func drainOutput(session *ssh.Session, buffer []byte) []byte {
+	for {
+		select {
+		case data := <-session.OutputChan:
+			buffer = append(buffer, data...)
+		default:
+			return buffer
+		}
+	}
+}
+
+// auditContext 构造终端会话的审计上下文
+func (h *SshHandler) auditContext(c *gin.Context, userID, hostID uint, sessionID string) *services.TerminalAuditContext {
+	auditCtx := &services.TerminalAuditContext{
+		UserID:      userID,
+		UserName:    middleware.GetCurrentUsername(c),
+		HostID:      hostID,
+		SessionID:   sessionID,
+		ClientIP:    c.ClientIP(),
+		ClientAgent: c.Request.UserAgent(),
+	}
+	if host, err := h.hostService.GetHost(hostID); err == nil {
+		auditCtx.HostName = host.Name
+		auditCtx.HostAddress = host.Address
+	}
+	return auditCtx
+}
+
 // handleResize 处理窗口大小调整
 func (h *SshHandler) handleResize(conn *ws.Conn, session *ssh.Session) {
 	for {
@@ -375,46 +587,157 @@ func (h *SshHandler) handleResize(conn *ws.Conn, session *ssh.Session) {
 func (h *SshHandler) CloseSession(c *gin.Context) {
 	sessionID := c.Param("session_id")
 
-	h.mu.RLock()
-	session, exists := h.sessions[sessionID]
-	h.mu.RUnlock()
-
+	session, exists := h.sessions.Get(sessionID)
 	if !exists {
 		dtoResponse.Error(c, 404, "会话不存在", nil)
 		return
 	}
 
-	if err := session.Close(); err != nil {
-		dtoResponse.Error(c, 500, "关闭会话失败", err)
+	session.Terminate("会话已被管理员关闭")
+	h.sessions.Remove(session)
+
+	dtoResponse.Success(c, nil, "会话已关闭")
+}
+
+// KickUser 强制断开用户的所有终端会话
+// @Summary 强制断开用户的所有终端会话
+// @Tags SSH终端
+// @Param user_id path int true "用户ID"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/ssh/users/{user_id}/sessions [delete]
+func (h *SshHandler) KickUser(c *gin.Context) {
+	userID, err := parseUint(c.Param("user_id"))
+	if err != nil {
+		dtoResponse.Error(c, http.StatusBadRequest, "无效的用户ID", err)
+		return
+	}
+
+	count := h.terminateSessions(h.sessions.ByUser(userID), "会话已被管理员强制断开")
+	dtoResponse.Success(c, gin.H{"count": count}, "已断开用户会话")
+}
+
+// HandleUserEvent 处理用户账号事件：禁用、删除、登出时断开该用户的所有终端，
+// 角色变更时重新校验权限，只断开已无权访问的主机或容器的终端
+func (h *SshHandler) HandleUserEvent(event services.UserEvent) {
+	sessions := h.sessions.ByUser(event.UserID)
+	if len(sessions) == 0 {
 		return
 	}
 
-	h.mu.Lock()
-	delete(h.sessions, sessionID)
-	h.mu.Unlock()
+	// 事件由账号管理接口同步发布，校验和断开在后台进行
+	go func() {
+		if event.Type == services.UserEventRolesChanged {
+			superAdmin := middleware.IsSuperAdmin(event.RoleIDs)
+			denied := make([]*ssh.Session, 0, len(sessions))
+			for _, session := range sessions {
+				if h.checkSessionAccess(session, superAdmin) != nil {
+					denied = append(denied, session)
+				}
+			}
+			sessions = denied
+		}
+		h.terminateSessions(sessions, event.Reason())
+	}()
+}
 
-	dtoResponse.Success(c, nil, "会话已关闭")
+// checkSessionAccess 校验会话所属用户对主机或容器的访问权限
+func (h *SshHandler) checkSessionAccess(session *ssh.Session, superAdmin bool) error {
+	hostID := session.Client.GetHostID()
+	if session.Container != nil {
+		return h.hostService.CheckContainerAccess(session.UserID, superAdmin, hostID, session.Container.ID, session.Container.Names)
+	}
+	return h.hostService.CheckHostAccess(session.UserID, superAdmin, hostID)
+}
+
+// terminateSessions 并发断开会话并返回数量
+func (h *SshHandler) terminateSessions(sessions []*ssh.Session, reason string) int {
+	var wg sync.WaitGroup
+	for _, session := range sessions {
+		wg.Add(1)
+		go func(session *ssh.Session) {
+			defer wg.Done()
+			log.Printf("Terminating session %s of user %d: %s", session.ID, session.UserID, reason)
+			session.Terminate(reason)
+			h.sessions.Remove(session)
+		}(session)
+	}
+	wg.Wait()
+	return len(sessions)
 }
 
 // ListSessions 列出活跃会话
 // @Summary 列出活跃会话
 // @Tags SSH终端
-// @Success 200 {object} dtoResponse.Response
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param user_id query int false "用户ID"
+// @Param host_id query int false "主机ID"
+// @Param keyword query string false "用户名、主机、客户端IP或会话ID"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.TerminalSessionListResponse}
 // @Router /api/v1/ssh/sessions [get]
 func (h *SshHandler) ListSessions(c *gin.Context) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	var req request.ListTerminalSessionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		dtoResponse.Error(c, http.StatusBadRequest, "请求参数错误", err)
+		return
+	}
 
-	sessions := make([]map[string]interface{}, 0, len(h.sessions))
-	for sessionID, session := range h.sessions {
-		sessions = append(sessions, map[string]interface{}{
-			"session_id": sessionID,
-			"host_id":    session.Client.GetHostID(),
-			"active":     session.IsActive(),
-		})
+	dtoResponse.Success(c, h.sessionService.ListActive(h.sessions.List(), &req), "获取成功")
+}
+
+// ListSessionHistory 终端会话历史记录
+// @Summary 终端会话历史记录
+// @Tags SSH终端
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param user_id query int false "用户ID"
+// @Param host_id query int false "主机ID"
+// @Param keyword query string false "用户名、主机、客户端IP或会话ID"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.TerminalSessionListResponse}
+// @Router /api/v1/ssh/sessions/history [get]
+func (h *SshHandler) ListSessionHistory(c *gin.Context) {
+	var req request.ListTerminalSessionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		dtoResponse.Error(c, http.StatusBadRequest, "请求参数错误", err)
+		return
 	}
 
-	dtoResponse.Success(c, sessions, "获取成功")
+	list, err := h.sessionService.ListHistory(&req)
+	if err != nil {
+		dtoResponse.Error(c, http.StatusInternalServerError, "获取会话记录失败", err)
+		return
+	}
+	dtoResponse.Success(c, list, "获取成功")
+}
+
+// DownloadRecording 下载终端会话录像（asciicast v2，可用 asciinema play 回放）
+// @Summary 下载终端会话录像
+// @Tags SSH终端
+// @Produce application/x-asciicast
+// @Param id path int true "会话记录ID"
+// @Router /api/v1/ssh/sessions/history/{id}/recording [get]
+func (h *SshHandler) DownloadRecording(c *gin.Context) {
+	id, err := parseUint(c.Param("id"))
+	if err != nil {
+		dtoResponse.Error(c, http.StatusBadRequest, "无效的会话记录ID", err)
+		return
+	}
+
+	file, err := h.sessionService.RecordingFile(id)
+	if err != nil {
+		if errors.Is(err, services.ErrRecordingNotFound) {
+			dtoResponse.Error(c, http.StatusNotFound, "录像不存在", err)
+			return
+		}
+		dtoResponse.Error(c, http.StatusInternalServerError, "获取录像失败", err)
+		return
+	}
+	if _, err := os.Stat(file); err != nil {
+		dtoResponse.Error(c, http.StatusNotFound, "录像文件不存在", err)
+		return
+	}
+	c.Header("Content-Type", "application/x-asciicast")
+	c.FileAttachment(file, fmt.Sprintf("session-%d.cast", id))
 }
 
 // 辅助函数
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"my-blog-backend/internal/api/v1/dto/request"
+	dtoResponse "my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/middleware"
+	"my-blog-backend/internal/services"
+)
+
+type SSHKeyHandler struct {
+	keyService *services.SSHKeyService
+}
+
+func NewSSHKeyHandler(keyService *services.SSHKeyService) *SSHKeyHandler {
+	return &SSHKeyHandler{keyService: keyService}
+}
+
+// GenerateKey 生成密钥对
+// @Summary 生成密钥对
+// @Tags 密钥管理
+// @Accept json
+// @Produce json
+// @Param request body request.GenerateSSHKeyRequest true "密钥信息"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.SSHKeyResponse}
+// @Router /api/v1/rbac/keys [post]
+func (h *SSHKeyHandler) GenerateKey(c *gin.Context) {
+	var req request.GenerateSSHKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	key, err := h.keyService.GenerateKey(&req, uint(userID))
+	if err != nil {
+		dtoResponse.Error(c, 500, "生成密钥失败: "+err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, key, "生成成功")
+}
+
+// UpdateKey 更新密钥名称和轮换周期
+// @Summary 更新密钥
+// @Tags 密钥管理
+// @Accept json
+// @Produce json
+// @Param request body request.UpdateSSHKeyRequest true "密钥信息"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/keys [put]
+func (h *SSHKeyHandler) UpdateKey(c *gin.Context) {
+	var req request.UpdateSSHKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	if err := h.keyService.UpdateKey(&req); err != nil {
+		dtoResponse.Error(c, 500, "更新密钥失败: "+err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, nil, "更新成功")
+}
+
+// DeleteKey 删除密钥
+// @Summary 删除密钥
+// @Tags 密钥管理
+// @Param id path int true "密钥ID"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/keys/{id} [delete]
+func (h *SSHKeyHandler) DeleteKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的密钥ID", err)
+		return
+	}
+
+	if err := h.keyService.DeleteKey(uint(id)); err != nil {
+		dtoResponse.Error(c, 400, err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, nil, "删除成功")
+}
+
+// GetKey 获取密钥详情
+// @Summary 获取密钥详情
+// @Tags 密钥管理
+// @Param id path int true "密钥ID"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.SSHKeyResponse}
+// @Router /api/v1/rbac/keys/{id} [get]
+func (h *SSHKeyHandler) GetKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的密钥ID", err)
+		return
+	}
+
+	key, err := h.keyService.GetKey(uint(id))
+	if err != nil {
+		dtoResponse.Error(c, 404, "密钥不存在", err)
+		return
+	}
+
+	dtoResponse.Success(c, key, "获取成功")
+}
+
+// ListKeys 密钥列表
+// @Summary 密钥列表
+// @Tags 密钥管理
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param name query string false "密钥名称"
+// @Param status query string false "状态(active,retired)"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.SSHKeyListResponse}
+// @Router /api/v1/rbac/keys [get]
+func (h *SSHKeyHandler) ListKeys(c *gin.Context) {
+	var req request.ListSSHKeyRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	list, err := h.keyService.ListKeys(&req)
+	if err != nil {
+		dtoResponse.Error(c, 500, "获取密钥列表失败", err)
+		return
+	}
+
+	dtoResponse.Success(c, list, "获取成功")
+}
+
+// DeployKey 将公钥分发到主机，可在验证通过后切换主机为密钥登录
+// @Summary 分发公钥
+// @Tags 密钥管理
+// @Accept json
+// @Produce json
+// @Param id path int true "密钥ID"
+// @Param request body request.DeploySSHKeyRequest true "分发参数"
+// @Success 200 {object} dtoResponse.Response
+// @Router /api/v1/rbac/keys/{id}/deploy [post]
+func (h *SSHKeyHandler) DeployKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的密钥ID", err)
+		return
+	}
+	var req request.DeploySSHKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		dtoResponse.Error(c, 400, "请求参数错误", err)
+		return
+	}
+
+	taskID, err := h.keyService.DeployKey(uint(id), &req, taskOperator(c))
+	if err != nil {
+		dtoResponse.Error(c, taskErrorStatus(err, 400), err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, gin.H{"task_id": taskID}, "分发任务已提交")
+}
+
+// RotateKey 立即轮换密钥
+// @Summary 轮换密钥
+// @Tags 密钥管理
+// @Param id path int true "密钥ID"
+// @Success 200 {object} dtoResponse.Response{data=dtoResponse.RotateSSHKeyResponse}
+// @Router /api/v1/rbac/keys/{id}/rotate [post]
+func (h *SSHKeyHandler) RotateKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		dtoResponse.Error(c, 400, "无效的密钥ID", err)
+		return
+	}
+
+	result, err := h.keyService.RotateKey(uint(id), taskOperator(c))
+	if err != nil {
+		dtoResponse.Error(c, taskErrorStatus(err, 400), err.Error(), err)
+		return
+	}
+
+	dtoResponse.Success(c, result, "轮换已开始")
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/middleware"
+	"my-blog-backend/internal/services"
+)
+
+type SysAPITokenHandler struct {
+	tokenService services.SysAPITokenService
+}
+
+func NewSysAPITokenHandler(tokenService services.SysAPITokenService) *SysAPITokenHandler {
+	return &SysAPITokenHandler{
+		tokenService: tokenService,
+	}
+}
+
+// CreateToken 创建 API Token
+// @Summary 创建API Token（明文只返回一次）
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Param request body request.CreateAPITokenRequest true "Token信息"
+// @Success 200 {object} response.Response{data=response.CreateAPITokenResponse}
+// @Router /api/v1/rbac/auth/api-tokens [post]
+func (h *SysAPITokenHandler) CreateToken(c *gin.Context) {
+	var req request.CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "请求参数错误", err)
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	token, err := h.tokenService.CreateToken(userID, &req)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "创建Token失败", err)
+		return
+	}
+
+	response.Success(c, token, "创建成功，请妥善保存Token")
+}
+
+// ListTokens 获取当前用户的 API Token 列表
+// @Summary API Token列表
+// @Tags 认证管理
+// @Produce json
+// @Success 200 {object} response.Response{data=[]models.SysAPIToken}
+// @Router /api/v1/rbac/auth/api-tokens [get]
+func (h *SysAPITokenHandler) ListTokens(c *gin.Context) {
+	userID, _ := middleware.GetCurrentUserID(c)
+	tokens, err := h.tokenService.ListTokens(userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "获取Token列表失败", err)
+		return
+	}
+
+	response.Success(c, tokens, "获取成功")
+}
+
+// DeleteToken 吊销 API Token
+// @Summary 吊销API Token
+// @Tags 认证管理
+// @Param id path int true "Token ID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/rbac/auth/api-tokens/{id} [delete]
+func (h *SysAPITokenHandler) DeleteToken(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "无效的Token ID", err)
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	if err := h.tokenService.DeleteToken(userID, id); err != nil {
+		response.Error(c, http.StatusInternalServerError, "吊销Token失败", err)
+		return
+	}
+
+	response.Success(c, nil, "吊销成功")
+}
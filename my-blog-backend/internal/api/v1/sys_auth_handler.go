@@ -115,6 +115,8 @@ func (h *SysAuthHandler) createSessionAndRespond(c *gin.Context, user *models.Sy
 		response.Error(c, http.StatusInternalServerError, "创建会话失败", err)
 		return
 	}
+	// 旧Session打开的终端一并断开
+	h.userService.RevokeSessions(user.ID)
 
 	// 创建Session
 	sessionInfo := &session.SessionInfo{
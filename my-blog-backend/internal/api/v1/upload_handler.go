@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/pkg/frontmatter"
 	"my-blog-backend/internal/pkg/logger"
 	"my-blog-backend/internal/services"
 
@@ -75,11 +76,25 @@ func (h *UploadHandler) UploadMarkdown(c *gin.Context) {
 		return
 	}
 
-	markdownContent := string(content)
-
-	// 解析 Markdown 文件
-	title := parseTitle(markdownContent, file.Filename)
-	summary := parseSummary(markdownContent)
+	// 解析 front matter，未提供的标题和摘要从正文中提取
+	var matter services.ArchiveFrontMatter
+	markdownContent, err := frontmatter.Parse(content, &matter)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "front matter 格式错误", err)
+		return
+	}
+	title := matter.Title
+	if title == "" {
+		title = parseTitle(markdownContent, file.Filename)
+	}
+	summary := matter.Summary
+	if summary == "" {
+		summary = parseSummary(markdownContent)
+	}
+	tags := make([]string, 0, len(matter.Tags))
+	for _, tag := range matter.Tags {
+		tags = append(tags, tag.Name)
+	}
 
 	// 存储文件到项目根目录下的 markdown_files 目录
 	uploadDir, err := filepath.Abs("../markdown_files")
@@ -113,6 +128,8 @@ func (h *UploadHandler) UploadMarkdown(c *gin.Context) {
 		"title":    title,
 		"content":  markdownContent,
 		"summary":  summary,
+		"slug":     matter.Slug,
+		"tags":     tags,
 		"filename": file.Filename,
 		"path":     uploadPath,
 		"size":     file.Size,
@@ -19,13 +19,16 @@ import (
 	"my-blog-backend/internal/infrastructure/smtp"
 	"my-blog-backend/internal/pkg/logger"
 	"my-blog-backend/internal/pkg/middleware"
+	"my-blog-backend/internal/pkg/search"
 	"my-blog-backend/internal/pkg/session"
 	"my-blog-backend/internal/pkg/token"
+	"my-blog-backend/internal/pkg/utils"
 	impl "my-blog-backend/internal/repository/impl"
 	implMysql "my-blog-backend/internal/repository/impl/mysql"
 	"my-blog-backend/internal/router"
 	"my-blog-backend/internal/services"
 	"my-blog-backend/internal/ssh"
+	"my-blog-backend/internal/websocket"
 
 	"my-blog-backend/internal/api/v1/dto/response"
 )
@@ -45,6 +48,8 @@ type Application struct {
 	httpServer *http.Server
 	router     *gin.Engine
 	handlers   *router.Handlers
+
+	viewService services.ArticleViewService // 停机前写入缓冲的阅读记录
 }
 
 // NewApplication 创建应用实例
@@ -184,29 +189,72 @@ func (app *Application) initServices() {
 	favoriteService := services.NewFavoriteService(favoriteRepo)
 	userActivityService := services.NewUserActivityService(db)
 	authService := services.NewAuthServiceWithCache(userService, favoriteService, redisCacheRepo, app.config.Auth.JWTSecret, app.config.Auth.JWTExpire)
-	articleService := services.NewArticleService(articleRepo, tagRepo, commentRepo, commentLikeRepo, favoriteRepo, articleLikeRepo, userActivityService)
-	categoryService := services.NewCategoryService(categoryRepo)
-	tagService := services.NewTagService(tagRepo)
+	searchCfg := app.config.Search
+	searchEngine, err := search.New(searchCfg.Engine, db, search.Boosts{
+		Title:   searchCfg.TitleBoost,
+		Tags:    searchCfg.TagBoost,
+		Summary: searchCfg.SummaryBoost,
+		Content: searchCfg.ContentBoost,
+	})
+	if err != nil {
+		app.logger.Warn("文章检索引擎配置无效，使用内存索引", logger.Err("error", err))
+		searchEngine = search.NewMemoryEngine(search.DefaultBoosts)
+		searchCfg.Engine = search.EngineMemory
+	}
+	articleRevisionRepo := implMysql.NewArticleRevisionRepositoryImpl(db)
+	articleEvents := services.NewArticleEventBus()
+	// 重定向服务，修改 slug 时记录旧地址
+	redirectService := services.NewRedirectService(implMysql.NewRedirectRepositoryImpl(db), implMysql.NewSlugHistoryRepositoryImpl(db), &app.config.Site)
+	articleService := services.NewArticleService(articleRepo, tagRepo, commentRepo, commentLikeRepo, favoriteRepo, articleLikeRepo, userActivityService,
+		searchEngine, articleRevisionRepo, app.config.Article.MaxRevisions, articleEvents, redirectService)
+	articleEvents.Subscribe(articleService.HandleArticleEvent)
+	go articleService.RunScheduler()
+	// MySQL 全文索引由数据库维护，其余引擎启动时重建
+	if searchCfg.Engine != search.EngineMySQL {
+		go func() {
+			if err := articleService.RebuildSearchIndex(); err != nil {
+				app.logger.Error("重建文章检索索引失败", logger.Err("error", err))
+			}
+		}()
+	}
+	// 阅读量统计，启用 Redis 时在 Redis 中缓冲
+	app.viewService = services.NewArticleViewService(articleRepo, implMysql.NewArticleViewRepositoryImpl(db),
+		impl.NewArticleViewBuffer(app.dbManager.GetRedisClient()), &app.config.View)
+	articleEvents.Subscribe(app.viewService.HandleArticleEvent)
+	go app.viewService.RunFlusher()
+	categoryService := services.NewCategoryService(categoryRepo, redirectService)
+	tagService := services.NewTagService(tagRepo, redirectService)
 
 	// 创建敏感词服务
 	sensitiveWordService := services.NewSensitiveWordService(app.config)
 
 	// 创建评论服务（注入敏感词服务、点赞仓储和用户活动服务）
 	commentService := services.NewCommentService(commentRepo, commentLikeRepo, app.config, sensitiveWordService, userActivityService)
-	seriesService := services.NewSeriesService(seriesRepo, sectionRepo, subchapterRepo)
+	seriesService := services.NewSeriesService(seriesRepo, sectionRepo, subchapterRepo, redirectService)
+	markdownService := services.NewMarkdownService(&app.config.Markdown)
+	feedService := services.NewFeedService(articleRepo, categoryRepo, tagRepo, seriesRepo, markdownService, redirectService, &app.config.Site, &app.config.Feed)
+	articleEvents.Subscribe(feedService.HandleArticleEvent)
+	seoService := services.NewSEOService(implMysql.NewSitemapRepository(db), categoryRepo, tagRepo, &app.config.Site, &app.config.SEO)
+	articleEvents.Subscribe(seoService.HandleArticleEvent)
 
 	// 创建上传服务
 	uploadService := services.NewUploadService(&app.config.Upload)
+	archiveService := services.NewArticleArchiveService(articleRepo, categoryRepo, tagRepo, seriesRepo, sectionRepo, subchapterRepo, commentRepo,
+		articleService, uploadService, redirectService)
 
 	// 创建Handler
 	app.handlers = &router.Handlers{
 		User:         apiV1.NewUserHandler(userService),
 		Auth:         apiV1.NewAuthHandler(authService),
-		Article:      apiV1.NewArticleHandler(articleService),
+		Article:      apiV1.NewArticleHandler(articleService, seoService, markdownService, app.viewService),
+		Archive:      apiV1.NewArticleArchiveHandler(archiveService, app.config.Article.MaxImportSize),
 		Category:     apiV1.NewCategoryHandler(categoryService),
 		Tag:          apiV1.NewTagHandler(tagService),
 		Comment:      apiV1.NewCommentHandler(commentService),
 		Series:       apiV1.NewSeriesHandler(seriesService),
+		Feed:         apiV1.NewFeedHandler(feedService),
+		SEO:          apiV1.NewSEOHandler(seoService),
+		Redirect:     apiV1.NewRedirectHandler(redirectService),
 		Favorite:     apiV1.NewFavoriteHandler(favoriteService),
 		Upload:       apiV1.NewUploadHandler(uploadService),
 		UserActivity: apiV1.NewUserActivityHandler(userActivityService),
@@ -231,7 +279,8 @@ func (app *Application) initRBACServices(db *gorm.DB) {
 
 	// 创建 RBAC Service
 	permissionService := services.NewSysPermissionService(sysUserRepo, sysRoleRepo, sysMenuRepo)
-	sysUserService := services.NewSysUserService(sysUserRepo, sysLogRepo, redisCacheRepo)
+	userEvents := services.NewUserEventBus()
+	sysUserService := services.NewSysUserService(sysUserRepo, sysLogRepo, redisCacheRepo, userEvents)
 	sysRoleService := services.NewSysRoleService(sysRoleRepo)
 	sysMenuService := services.NewSysMenuService(sysMenuRepo)
 	_ = services.NewSysDeptService(sysDeptRepo)
@@ -260,6 +309,8 @@ func (app *Application) initRBACServices(db *gorm.DB) {
 	middleware.InitSessionManager(sessionManager)
 	middleware.InitTokenManager(tokenManager)
 	middleware.InitOperationLogService(nil)
+	apiTokenService := services.NewSysAPITokenService(implMysql.NewSysAPITokenRepositoryImpl(db), sysUserRepo)
+	middleware.InitAPITokenService(apiTokenService)
 
 	// 创建 RBAC Handlers
 	sysAuthHandler := apiV1.NewSysAuthHandler(
@@ -278,12 +329,38 @@ func (app *Application) initRBACServices(db *gorm.DB) {
 	statisticsHandler := apiV1.NewStatisticsHandler(statisticsService)
 
 	// 创建主机管理服务和Handler
+	secretCipher, err := utils.NewSecretCipher(app.config.Ops.SecretKey)
+	if err != nil {
+		app.logger.Warn("未配置 ops.secretKey，主机账号密码和托管密钥无法保存", logger.Err("error", err))
+	}
 	hostRepo := implMysql.NewHostRepository(db)
 	sshPool := ssh.NewPool(5 * time.Minute)
-	hostService := services.NewHostService(hostRepo, sshPool)
+	hostService := services.NewHostService(hostRepo, sshPool, secretCipher)
 	hostHandler := apiV1.NewHostHandler(hostService)
-	sshHandler := apiV1.NewSshHandler(hostService, sshPool)
+	commandPolicy := services.NewCommandPolicy(app.config.Ops.CommandDenyPatterns)
+	outputMasker := services.NewOutputMasker(app.config.Ops.OutputMaskRules)
+	auditLogService := services.NewAuditLogService(implMysql.NewAuditLogRepository(db))
+	containerService := services.NewContainerService(hostService, sshPool)
+	terminalSessionService := services.NewTerminalSessionService(implMysql.NewTerminalSessionRepository(db), &app.config.Ops)
+	terminalSessionService.CloseStale()
+	sshHandler := apiV1.NewSshHandler(hostService, containerService, sshPool, commandPolicy, auditLogService, terminalSessionService, outputMasker, &app.config.Ops)
 	sftpHandler := apiV1.NewSshFileHandler(hostService, sshPool, sshHandler.GetSessions())
+	userEvents.Subscribe(sshHandler.HandleUserEvent)
+
+	// 创建批量任务和脚本库服务
+	batchTaskRepo := implMysql.NewBatchTaskRepository(db)
+	scriptRepo := implMysql.NewScriptRepository(db)
+	taskHub := websocket.NewTaskHub()
+	go taskHub.Run()
+	batchTaskService := services.NewBatchTaskService(batchTaskRepo, hostRepo, hostService, sshPool, taskHub, outputMasker)
+	scriptService := services.NewScriptService(scriptRepo, batchTaskService, commandPolicy)
+	fileTransferService := services.NewFileTransferService(batchTaskRepo, batchTaskService, &app.config.Ops)
+	go fileTransferService.RunCleanup()
+	sshKeyService := services.NewSSHKeyService(implMysql.NewSSHKeyRepository(db), hostRepo, hostService, batchTaskService, secretCipher)
+	go sshKeyService.RunRotation()
+	commandExecService := services.NewCommandExecService(hostService, sshPool, commandPolicy, &app.config.Ops)
+	hostAccountService := services.NewHostAccountService(implMysql.NewHostAccountRepository(db), hostRepo, hostService, sshPool, secretCipher, &app.config.Ops)
+	go hostAccountService.RunRotation()
 
 	// 将 RBAC Handlers 添加到 handlers 结构体
 	app.handlers.SysAuth = sysAuthHandler
@@ -291,12 +368,20 @@ func (app *Application) initRBACServices(db *gorm.DB) {
 	app.handlers.SysMenu = sysMenuHandler
 	app.handlers.Statistics = statisticsHandler
 	app.handlers.SysUser = sysUerHandler
+	app.handlers.APIToken = apiV1.NewSysAPITokenHandler(apiTokenService)
 
 	// 添加主机管理和SSH Handlers
 	app.handlers.Host = hostHandler
 	app.handlers.Ssh = sshHandler
 
 	app.handlers.Sftp = sftpHandler
+	app.handlers.Script = apiV1.NewScriptHandler(scriptService)
+	app.handlers.BatchTask = apiV1.NewBatchTaskHandler(batchTaskService)
+	app.handlers.FileTransfer = apiV1.NewFileTransferHandler(fileTransferService)
+	app.handlers.SSHKey = apiV1.NewSSHKeyHandler(sshKeyService)
+	app.handlers.CommandExec = apiV1.NewCommandExecHandler(hostService, commandExecService)
+	app.handlers.HostAccount = apiV1.NewHostAccountHandler(hostAccountService)
+	app.handlers.Container = apiV1.NewContainerHandler(hostService, containerService)
 
 	app.logger.Info("RBAC services initialized successfully")
 }
@@ -373,6 +458,13 @@ func (app *Application) shutdown(ctx context.Context) error {
 		}
 	}
 
+	// 写入缓冲的阅读记录
+	if app.viewService != nil {
+		if err := app.viewService.Flush(); err != nil {
+			errs = append(errs, fmt.Errorf("flush article views error: %w", err))
+		}
+	}
+
 	// 关闭数据库连接
 	if app.dbManager != nil {
 		if err := app.dbManager.Close(); err != nil {
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 )
 
@@ -17,6 +18,14 @@ type Config struct {
 	EmailServer EmailConfig    `yaml:"emailServer" json:"emailServer"`
 	Comment     CommentConfig  `yaml:"comment" env:"COMMENT"`
 	Upload      UploadConfig   `yaml:"upload" env:"UPLOAD"`
+	Article     ArticleConfig  `yaml:"article" env:"ARTICLE"`
+	View        ViewConfig     `yaml:"view" env:"VIEW"`
+	Search      SearchConfig   `yaml:"search" env:"SEARCH"`
+	Site        SiteConfig     `yaml:"site" env:"SITE"`
+	Feed        FeedConfig     `yaml:"feed" env:"FEED"`
+	SEO         SEOConfig      `yaml:"seo" env:"SEO"`
+	Markdown    MarkdownConfig `yaml:"markdown" env:"MARKDOWN"`
+	Ops         OpsConfig      `yaml:"ops" env:"OPS"`
 }
 
 // AppConfig 应用配置
@@ -297,3 +306,219 @@ type UploadConfig struct {
 	// URL 配置
 	URLPrefix string `yaml:"urlPrefix" env:"URL_PREFIX"` // URL前缀，用于拼接完整URL
 }
+
+// ArticleConfig 文章配置
+type ArticleConfig struct {
+	MaxRevisions  int   `yaml:"maxRevisions" env:"MAX_REVISIONS" env-default:"50"`           // 每篇文章保留的修订数量，小于0不限制
+	MaxImportSize int64 `yaml:"maxImportSize" env:"MAX_IMPORT_SIZE" env-default:"209715200"` // 导入归档的最大大小(200MB)
+}
+
+func (config *ArticleConfig) SetDefault() {
+	if config.MaxRevisions == 0 {
+		config.MaxRevisions = 50
+	}
+	if config.MaxImportSize <= 0 {
+		config.MaxImportSize = 200 * 1024 * 1024
+	}
+}
+
+// ViewConfig 文章阅读量统计配置
+type ViewConfig struct {
+	DedupWindow   time.Duration `yaml:"dedupWindow" env:"DEDUP_WINDOW" env-default:"30m"`     // 同一访客在时间窗口内重复阅读同一篇文章只计一次
+	FlushInterval time.Duration `yaml:"flushInterval" env:"FLUSH_INTERVAL" env-default:"10s"` // 缓冲的阅读记录写入数据库的间隔
+	BatchSize     int           `yaml:"batchSize" env:"BATCH_SIZE" env-default:"500"`         // 每批写入的阅读记录数量
+	BotKeywords   []string      `yaml:"botKeywords" env:"BOT_KEYWORDS" env-separator:","`     // 额外的爬虫 User-Agent 关键字，不区分大小写
+}
+
+func (config *ViewConfig) SetDefault() {
+	if config.DedupWindow <= 0 {
+		config.DedupWindow = 30 * time.Minute
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 10 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 500
+	}
+}
+
+// SearchConfig 文章全文检索配置
+type SearchConfig struct {
+	// 检索引擎: memory（进程内倒排索引，启动时重建）、mysql（FULLTEXT 索引）、postgres（tsvector），
+	// 数据库引擎需要先执行 migrations 中对应的全文索引脚本
+	Engine string `yaml:"engine" env:"ENGINE" env-default:"memory"`
+
+	// 字段权重
+	TitleBoost   float64 `yaml:"titleBoost" env:"TITLE_BOOST" env-default:"3"`
+	TagBoost     float64 `yaml:"tagBoost" env:"TAG_BOOST" env-default:"2.5"`
+	SummaryBoost float64 `yaml:"summaryBoost" env:"SUMMARY_BOOST" env-default:"1.5"`
+	ContentBoost float64 `yaml:"contentBoost" env:"CONTENT_BOOST" env-default:"1"`
+}
+
+func (config *SearchConfig) SetDefault() {
+	if config.Engine == "" {
+		config.Engine = "memory"
+	}
+	if config.TitleBoost == 0 {
+		config.TitleBoost = 3
+	}
+	if config.TagBoost == 0 {
+		config.TagBoost = 2.5
+	}
+	if config.SummaryBoost == 0 {
+		config.SummaryBoost = 1.5
+	}
+	if config.ContentBoost == 0 {
+		config.ContentBoost = 1
+	}
+}
+
+// SiteConfig 博客前台站点配置，用于生成订阅源、站点地图等对外链接
+type SiteConfig struct {
+	URL         string `yaml:"url" env:"URL" env-default:"http://localhost:5173"` // 前台站点地址
+	Title       string `yaml:"title" env:"TITLE" env-default:"my-blog"`            // 站点标题
+	Description string `yaml:"description" env:"DESCRIPTION"`                      // 站点描述
+	Language    string `yaml:"language" env:"LANGUAGE" env-default:"zh-CN"`        // 站点语言
+	Author      string `yaml:"author" env:"AUTHOR"`                                // 默认作者名称
+
+	// 前台页面路径，页面地址为 URL + 路径 + slug
+	ArticlePath  string `yaml:"articlePath" env:"ARTICLE_PATH" env-default:"/articles/"`
+	CategoryPath string `yaml:"categoryPath" env:"CATEGORY_PATH" env-default:"/categories/"`
+	TagPath      string `yaml:"tagPath" env:"TAG_PATH" env-default:"/tags/"`
+	SeriesPath   string `yaml:"seriesPath" env:"SERIES_PATH" env-default:"/series/"`
+}
+
+func (config *SiteConfig) SetDefault() {
+	if config.URL == "" {
+		config.URL = "http://localhost:5173"
+	}
+	config.URL = strings.TrimSuffix(config.URL, "/")
+	if config.Title == "" {
+		config.Title = "my-blog"
+	}
+	if config.Language == "" {
+		config.Language = "zh-CN"
+	}
+	if config.ArticlePath == "" {
+		config.ArticlePath = "/articles/"
+	}
+	if config.CategoryPath == "" {
+		config.CategoryPath = "/categories/"
+	}
+	if config.TagPath == "" {
+		config.TagPath = "/tags/"
+	}
+	if config.SeriesPath == "" {
+		config.SeriesPath = "/series/"
+	}
+}
+
+// FeedConfig 订阅源（RSS、Atom、JSON Feed）配置
+type FeedConfig struct {
+	Limit    int           `yaml:"limit" env:"LIMIT" env-default:"20"`         // 每个订阅源输出的文章数量
+	Mode     string        `yaml:"mode" env:"MODE" env-default:"full"`         // 默认输出方式: full（全文）、summary（摘要），请求可通过 mode 参数覆盖
+	CacheTTL time.Duration `yaml:"cacheTTL" env:"CACHE_TTL" env-default:"10m"` // 生成结果缓存时间，文章变更时立即失效
+}
+
+func (config *FeedConfig) SetDefault() {
+	if config.Limit <= 0 {
+		config.Limit = 20
+	}
+	if config.Mode != "summary" {
+		config.Mode = "full"
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = 10 * time.Minute
+	}
+}
+
+// SEOConfig 站点地图、robots.txt 和页面元数据配置
+type SEOConfig struct {
+	SitemapPageSize int           `yaml:"sitemapPageSize" env:"SITEMAP_PAGE_SIZE" env-default:"50000"` // 单个站点地图的最大地址数，超出时生成站点地图索引，最大 50000
+	CacheTTL        time.Duration `yaml:"cacheTTL" env:"CACHE_TTL" env-default:"1h"`                  // 站点地图缓存时间，文章变更时立即失效
+	RobotsDisallow  []string      `yaml:"robotsDisallow" env:"ROBOTS_DISALLOW" env-separator:","`     // robots.txt 中禁止抓取的路径
+	DefaultImage    string        `yaml:"defaultImage" env:"DEFAULT_IMAGE"`                           // 文章没有分享图片和封面时使用的 Open Graph 图片
+	TwitterSite     string        `yaml:"twitterSite" env:"TWITTER_SITE"`                             // Twitter 账号，如 @myblog
+}
+
+func (config *SEOConfig) SetDefault() {
+	if config.SitemapPageSize <= 0 || config.SitemapPageSize > 50000 {
+		config.SitemapPageSize = 50000
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = time.Hour
+	}
+}
+
+
+// MarkdownConfig 文章 Markdown 服务端渲染配置
+type MarkdownConfig struct {
+	HighlightStyle    string `yaml:"highlightStyle" env:"HIGHLIGHT_STYLE" env-default:"github"`      // 代码高亮样式，见 https://xyproto.github.io/splash/docs/
+	CacheSize         int    `yaml:"cacheSize" env:"CACHE_SIZE" env-default:"500"`                   // 渲染结果缓存条数，按内容哈希缓存
+	WordsPerMinute    int    `yaml:"wordsPerMinute" env:"WORDS_PER_MINUTE" env-default:"200"`        // 英文等按词计数文字的阅读速度
+	CJKCharsPerMinute int    `yaml:"cjkCharsPerMinute" env:"CJK_CHARS_PER_MINUTE" env-default:"400"` // 中日韩文字的阅读速度
+}
+
+func (config *MarkdownConfig) SetDefault() {
+	if config.HighlightStyle == "" {
+		config.HighlightStyle = "github"
+	}
+	if config.CacheSize <= 0 {
+		config.CacheSize = 500
+	}
+	if config.WordsPerMinute <= 0 {
+		config.WordsPerMinute = 200
+	}
+	if config.CJKCharsPerMinute <= 0 {
+		config.CJKCharsPerMinute = 400
+	}
+}
+
+// OpsConfig 运维管理配置
+type OpsConfig struct {
+	DataDir         string `yaml:"dataDir" env:"DATA_DIR" env-default:"./data/ops"`                     // 运维数据目录（分发文件、拉取归档），不能放在公开的静态目录下
+	MaxArtifactSize int64  `yaml:"maxArtifactSize" env:"MAX_ARTIFACT_SIZE" env-default:"1073741824"` // 分发文件最大大小(1GB)
+
+	CollectRetention time.Duration `yaml:"collectRetention" env:"COLLECT_RETENTION" env-default:"168h"` // 拉取结果保留时间，过期后删除
+
+	ExecTimeout         time.Duration `yaml:"execTimeout" env:"EXEC_TIMEOUT" env-default:"60s"`          // 命令执行接口默认超时
+	ExecMaxTimeout      time.Duration `yaml:"execMaxTimeout" env:"EXEC_MAX_TIMEOUT" env-default:"10m"`   // 命令执行接口允许的最大超时
+	ExecMaxOutput       int64         `yaml:"execMaxOutput" env:"EXEC_MAX_OUTPUT" env-default:"1048576"` // 标准输出/标准错误各自保留的最大字节数(1MB)
+	CommandDenyPatterns []string      `yaml:"commandDenyPatterns" env:"COMMAND_DENY_PATTERNS"`           // 禁止执行的命令（正则），终端和命令执行接口共用
+
+	SecretKey      string `yaml:"secretKey" env:"SECRET_KEY"`                              // 主机账号密码和托管私钥的加密密钥，修改后已加密的数据无法解密
+	PasswordLength int    `yaml:"passwordLength" env:"PASSWORD_LENGTH" env-default:"20"` // 自动轮换生成的密码长度
+
+	TerminalMaxTransferSize int64 `yaml:"terminalMaxTransferSize" env:"TERMINAL_MAX_TRANSFER_SIZE" env-default:"104857600"` // 终端 rz/sz 单个文件最大大小(100MB)
+	TerminalRecording       bool  `yaml:"terminalRecording" env:"TERMINAL_RECORDING" env-default:"false"`                   // 录制终端会话输出（asciicast 格式，保存在 DataDir/recordings 下）
+
+	OutputMaskRules    []string `yaml:"outputMaskRules" env:"OUTPUT_MASK_RULES"`                                  // 输出脱敏规则（正则），有捕获组时只遮盖第一个捕获组
+	OutputMaskLiveView bool     `yaml:"outputMaskLiveView" env:"OUTPUT_MASK_LIVE_VIEW" env-default:"false"` // 非超级管理员的终端实时输出也脱敏（日志和任务输出始终脱敏）
+}
+
+func (config *OpsConfig) SetDefault() {
+	if config.DataDir == "" {
+		config.DataDir = "./data/ops"
+	}
+	if config.MaxArtifactSize == 0 {
+		config.MaxArtifactSize = 1 << 30 // 1GB
+	}
+	if config.CollectRetention <= 0 {
+		config.CollectRetention = 7 * 24 * time.Hour
+	}
+	if config.ExecTimeout == 0 {
+		config.ExecTimeout = time.Minute
+	}
+	if config.ExecMaxTimeout == 0 {
+		config.ExecMaxTimeout = 10 * time.Minute
+	}
+	if config.ExecMaxOutput == 0 {
+		config.ExecMaxOutput = 1 << 20 // 1MB
+	}
+	if config.PasswordLength == 0 {
+		config.PasswordLength = 20
+	}
+	if config.TerminalMaxTransferSize == 0 {
+		config.TerminalMaxTransferSize = 100 << 20 // 100MB
+	}
+}
@@ -33,6 +33,14 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 	fmt.Println(cfg)
+	cfg.Article.SetDefault()
+	cfg.View.SetDefault()
+	cfg.Search.SetDefault()
+	cfg.Site.SetDefault()
+	cfg.Feed.SetDefault()
+	cfg.SEO.SetDefault()
+	cfg.Markdown.SetDefault()
+	cfg.Ops.SetDefault()
 	// 从环境变量覆盖敏感配置
 	if jwtSecret := os.Getenv("JWT_SECRET"); jwtSecret != "" {
 		cfg.Auth.JWTSecret = jwtSecret
@@ -16,13 +16,20 @@ type Article struct {
 	Likes        uint32     `gorm:"not null;default:0" json:"likes"`                                    // 文章获得的点赞数量
 	Favorites    uint32     `gorm:"not null;default:0" json:"favorites"`                                // 文章被收藏的次数
 	CommentCount uint32     `gorm:"not null;default:0;column:comment_count" json:"comments"`          // 文章评论数量统计
-	Status       uint8      `gorm:"not null;default:1" json:"status"`                                   // 文章状态：0-草稿，1-已发布
+	Status       uint8      `gorm:"not null;default:1" json:"status"`                                   // 文章状态：0-草稿，1-已发布，2-定时发布
 	IsTop        bool       `gorm:"not null;default:false" json:"is_top"`                               // 是否置顶显示，true表示置顶
 	SortOrder    int        `gorm:"not null;default:0" json:"sort_order"`                               // 文章排序字段，数值越小越靠前
-	PublishedAt  *time.Time `json:"published_at"`                                                       // 文章发布时间，用于按发布时间排序
+	PublishedAt  *time.Time `json:"published_at"`                                                       // 文章发布时间，用于按发布时间排序；定时发布的文章为计划发布时间
+	UnpublishAt  *time.Time `json:"unpublish_at"`                                                       // 定时下线时间，到期后文章转为草稿
 	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`                                   // 文章创建时间，自动记录
 	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`                                   // 文章最后更新时间，自动记录
 
+	// SEO 信息
+	MetaTitle       string `gorm:"size:200" json:"meta_title"`       // SEO 标题，为空时使用文章标题
+	MetaDescription string `gorm:"size:500" json:"meta_description"` // SEO 描述，为空时使用文章摘要
+	CanonicalURL    string `gorm:"size:500" json:"canonical_url"`    // 规范链接，为空时使用文章页面地址
+	OGImage         string `gorm:"size:500" json:"og_image"`         // Open Graph 分享图片，为空时使用封面
+
 	// 关联关系（不使用外键约束）
 	Category         *Category          `gorm:"-" json:"category,omitempty"`                                    // 文章所属分类
 	Author           *User              `gorm:"-" json:"author,omitempty"`                                      // 文章作者信息
@@ -34,6 +41,13 @@ type Article struct {
 	Subchapters      []SeriesSubchapter `gorm:"many2many:subchapter_articles;" json:"subchapters,omitempty"` // 文章所属的系列子章节（保持多对多关系）
 }
 
+// 文章状态
+const (
+	ArticleStatusDraft     uint8 = 0 // 草稿
+	ArticleStatusPublished uint8 = 1 // 已发布
+	ArticleStatusScheduled uint8 = 2 // 定时发布，到达发布时间后转为已发布
+)
+
 func (Article) TableName() string {
 	return "articles"
 }
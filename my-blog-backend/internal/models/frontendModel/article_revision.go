@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// 修订操作类型
+const (
+	RevisionActionCreate  = "create"  // 创建文章（或首次修改时为历史文章补记的基线版本）
+	RevisionActionUpdate  = "update"  // 编辑文章
+	RevisionActionRestore = "restore" // 从历史修订恢复
+	RevisionActionImport  = "import"  // 从归档导入
+)
+
+// ArticleRevision 文章修订历史，每次保存生成一条不可变的修订记录
+type ArticleRevision struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`                         // 修订记录ID
+	ArticleID    uint64    `gorm:"not null;uniqueIndex:uk_article_revision" json:"article_id"` // 文章ID
+	Version      int       `gorm:"not null;uniqueIndex:uk_article_revision" json:"version"`    // 修订版本号，同一文章内递增
+	Title        string    `gorm:"size:200;not null" json:"title"`                             // 文章标题
+	Summary      string    `gorm:"type:text" json:"summary"`                                   // 文章摘要
+	Content      string    `gorm:"type:longtext;not null" json:"content"`                      // 文章正文
+	Tags         string    `gorm:"type:text" json:"tags"`                                      // 标签快照(JSON)，标签被删除后仍可查看
+	EditorID     uint64    `gorm:"not null" json:"editor_id"`                                  // 编辑人ID
+	Action       string    `gorm:"size:20;not null" json:"action"`                             // 操作类型：create、update、restore
+	RestoredFrom int       `gorm:"not null;default:0" json:"restored_from"`                    // 恢复来源版本号，非恢复操作为0
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`                           // 修订时间
+}
+
+// RevisionTag 修订记录中的标签快照
+type RevisionTag struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+func (ArticleRevision) TableName() string {
+	return "article_revisions"
+}
@@ -17,5 +17,5 @@ type ArticleView struct {
 }
 
 func (ArticleView) TableName() string {
-	return "article_view"
+	return "article_views"
 }
@@ -4,16 +4,19 @@ import "time"
 
 // Comment 文章评论模型，存储用户对文章的评论和回复
 type Comment struct {
-	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`                    // 评论唯一标识ID
-	ArticleID uint      `gorm:"not null;index:idx_article_id" json:"article_id"`       // 被评论文章的ID
-	UserID    uint      `gorm:"not null;index:idx_user_id" json:"user_id"`             // 发表评论用户的ID
-	ParentID  *uint     `gorm:"index:idx_parent_id" json:"parent_id"`                  // 父评论ID，用于回复评论，NULL表示顶级评论
-	Content   string    `gorm:"type:text;not null" json:"content" validate:"required"` // 评论内容，必填
-	Status    uint8     `gorm:"not null;default:1" json:"status"`                      // 评论审核状态：0-待审核，1-已通过，2-已拒绝
-	Likes     uint32    `gorm:"not null;default:0" json:"likes"`                       // 评论点赞数统计
-	IPAddress string    `gorm:"size:50" json:"ip_address"`                             // 发表评论的IP地址
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`                      // 评论发表时间
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`                      // 评论最后更新时间
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`                    // 评论唯一标识ID
+	ArticleID   uint      `gorm:"not null;index:idx_article_id" json:"article_id"`       // 被评论文章的ID
+	UserID      uint      `gorm:"not null;index:idx_user_id" json:"user_id"`             // 发表评论用户的ID，从其他博客导入的游客评论为0
+	ParentID    *uint     `gorm:"index:idx_parent_id" json:"parent_id"`                  // 父评论ID，用于回复评论，NULL表示顶级评论
+	Content     string    `gorm:"type:text;not null" json:"content" validate:"required"` // 评论内容，必填
+	Status      uint8     `gorm:"not null;default:1" json:"status"`                      // 评论审核状态：0-待审核，1-已通过，2-已拒绝
+	Likes       uint32    `gorm:"not null;default:0" json:"likes"`                       // 评论点赞数统计
+	IPAddress   string    `gorm:"size:50" json:"ip_address"`                             // 发表评论的IP地址
+	AuthorName  string    `gorm:"size:100" json:"author_name,omitempty"`                 // 游客评论的作者名称，仅导入的评论有值
+	AuthorEmail string    `gorm:"size:100" json:"-"`                                     // 游客评论的作者邮箱，不对外返回
+	AuthorURL   string    `gorm:"size:255" json:"author_url,omitempty"`                  // 游客评论的作者网站
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`                      // 评论发表时间
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`                      // 评论最后更新时间
 
 	// 关联关系（不使用外键约束）
 	Article *Article  `gorm:"-" json:"article,omitempty"` // 被评论的文章，通过手动查询获取
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// 重定向来源
+const (
+	RedirectSourceImport = "import" // 从其他博客系统导入时保留的原文章地址
+	RedirectSourceSlug   = "slug"   // 修改文章、分类、标签、系列的 slug 时自动生成
+	RedirectSourceManual = "manual" // 管理员手动添加
+)
+
+// Redirect 站点路径重定向，旧地址访问时跳转到新地址
+type Redirect struct {
+	ID         uint64     `gorm:"primaryKey;autoIncrement" json:"id"`                              // 重定向ID
+	FromPath   string     `gorm:"size:500;not null;uniqueIndex:uk_redirect_from" json:"from_path"` // 旧路径，不含域名，不以 / 结尾（根路径除外）
+	ToPath     string     `gorm:"size:500;not null" json:"to_path"`                                // 目标路径（相对前台站点地址）或完整URL
+	StatusCode int        `gorm:"not null;default:301" json:"status_code"`                         // 跳转状态码：301、302
+	Source     string     `gorm:"size:20;not null" json:"source"`                                  // 来源：import、slug、manual
+	Hits       uint64     `gorm:"not null;default:0" json:"hits"`                                  // 命中次数
+	LastHitAt  *time.Time `json:"last_hit_at"`                                                     // 最后命中时间
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`                                // 创建时间
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`                                // 更新时间
+}
+
+func (Redirect) TableName() string {
+	return "redirects"
+}
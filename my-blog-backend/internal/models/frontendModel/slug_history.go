@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// slug 历史记录所属的实体类型
+const (
+	SlugEntityArticle  = "article"
+	SlugEntityCategory = "category"
+	SlugEntityTag      = "tag"
+	SlugEntitySeries   = "series"
+)
+
+// SlugHistory 实体修改前使用过的 slug，按旧 slug 查询时找到当前的实体
+type SlugHistory struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`                                         // 记录ID
+	EntityType string    `gorm:"size:20;not null;uniqueIndex:uk_slug_history,priority:1" json:"entity_type"` // 实体类型：article、category、tag、series
+	OldSlug    string    `gorm:"size:200;not null;uniqueIndex:uk_slug_history,priority:2" json:"old_slug"`   // 旧 slug
+	EntityID   uint64    `gorm:"not null;index" json:"entity_id"`                                            // 实体ID
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`                                           // 修改时间
+}
+
+func (SlugHistory) TableName() string {
+	return "slug_histories"
+}
@@ -38,6 +38,7 @@ type AuditLog struct {
 	HostName     string      `gorm:"type:varchar(100);not null;comment:主机名称"`
 	HostAddress   string      `gorm:"type:varchar(100);not null;comment:主机地址"`
 	SessionID    string      `gorm:"type:varchar(100);not null;index;comment:会话ID"`
+	ContainerID  string      `gorm:"type:varchar(64);comment:容器ID(容器终端会话)"`
 	Action       AuditAction `gorm:"type:tinyint(1);not null;comment:操作类型(1:登录,2:执行命令,3:文件上传,4:文件下载,5:会话管理)"`
 	Command      string      `gorm:"type:text;comment:执行的命令"`
 	Status       AuditStatus `gorm:"type:tinyint(1);not null;index:idx_user_time;comment:状态(1:成功,2:失败,3:警告)"`
@@ -21,18 +21,26 @@ const (
 	FileUploadTask   TaskType = 2 // 文件上传
 	FileDownloadTask TaskType = 3 // 文件下载
 	ScriptTask       TaskType = 4 // 脚本任务
+	KeyDeployTask    TaskType = 5 // 密钥分发/轮换
 )
 
 // BatchTask 任务表（用于执行多个主机批量化任务）
 type BatchTask struct {
 	ID           uint       `gorm:"primaryKey;autoIncrement;comment:主键ID"`
 	Name         string     `gorm:"type:varchar(100);not null;comment:任务名称"`
-	Type         TaskType   `gorm:"type:tinyint(1);not null;comment:任务类型(1:命令,2:文件上传,3:文件下载,4:脚本)"`
+	Type         TaskType   `gorm:"type:tinyint(1);not null;comment:任务类型(1:命令,2:文件上传,3:文件下载,4:脚本,5:密钥分发)"`
 	Status       TaskStatus `gorm:"type:tinyint(1);not null;default:1;index;comment:状态(1:待执行,2:执行中,3:成功,4:失败,5:已取消)"`
 	Command      string     `gorm:"type:text;comment:执行的命令或脚本内容"`
 	SourcePath   string     `gorm:"type:varchar(500);comment:源文件路径（文件上传/下载）"`
 	TargetPath   string     `gorm:"type:varchar(500);comment:目标路径"`
+	FileMode     string     `gorm:"type:varchar(10);comment:文件权限(如0644，文件分发)"`
+	FileOwner    string     `gorm:"type:varchar(100);comment:文件属主(user[:group]，文件分发)"`
+	Checksum     string     `gorm:"type:varchar(64);comment:源文件SHA-256(文件分发)"`
+	Backup       bool       `gorm:"type:tinyint(1);default:0;comment:是否备份目标主机上的原文件"`
 	ScriptType   string     `gorm:"type:varchar(20);comment:脚本类型(如:bash,python,shell)"`
+	HostSelector string     `gorm:"type:varchar(500);comment:主机标签选择器(执行时解析)"`
+	ScriptID     uint       `gorm:"type:uint;default:0;comment:脚本库脚本ID(0表示内联脚本)"`
+	ScriptVer    int        `gorm:"type:int;default:0;comment:脚本版本号"`
 	Timeout      int        `gorm:"type:int;default:300;comment:超时时间(秒)"`
 	SuccessCount int        `gorm:"type:int;default:0;comment:成功数量"`
 	FailedCount  int        `gorm:"type:int;default:0;comment:失败数量"`
@@ -78,6 +86,8 @@ func (t TaskType) String() string {
 		return "文件下载"
 	case ScriptTask:
 		return "脚本任务"
+	case KeyDeployTask:
+		return "密钥分发"
 	default:
 		return "未知"
 	}
@@ -92,6 +102,10 @@ type TaskHostRelation struct {
 	HostAddr   string     `gorm:"type:varchar(100);comment:主机地址"`
 	AccountID  uint       `gorm:"type:uint;comment:使用的账号ID"`
 	Status     TaskStatus `gorm:"type:tinyint(1);not null;default:1;comment:执行状态"`
+	ExitCode   int        `gorm:"type:int;default:0;comment:退出码"`
+	Progress   float64    `gorm:"type:decimal(5,2);default:0;comment:传输进度(0-100)"`
+	Checksum   string     `gorm:"type:varchar(64);comment:传输后文件SHA-256"`
+	BackupPath string     `gorm:"type:varchar(500);comment:原文件备份路径"`
 	Output     string     `gorm:"type:text;comment:执行输出"`
 	Error      string     `gorm:"type:text;comment:错误信息"`
 	Duration   int64      `gorm:"type:bigint;comment:执行时长(毫秒)"`
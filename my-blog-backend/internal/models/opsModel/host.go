@@ -19,6 +19,7 @@ type RemoteHost struct {
 	Username  string        `gorm:"type:varchar(50);not null;comment:用户名"`
 	Password  string        `gorm:"type:varchar(255);comment:密码（加密存储）"`
 	SecretKey string        `gorm:"type:text;comment:私钥内容"`
+	KeyID     uint          `gorm:"type:uint;default:0;index;comment:托管密钥ID(0表示手工配置的私钥)"`
 	Port      int64         `gorm:"type:int;not null;default:22;comment:SSH端口"`
 	Address   string        `gorm:"type:varchar(100);not null;comment:主机地址或IP"`
 	Type      SshType       `gorm:"type:tinyint(1);not null;default:1;comment:登录类型(0:密钥,1:密码)"`
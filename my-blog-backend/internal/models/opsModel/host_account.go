@@ -9,24 +9,27 @@ import (
 type AccountType uint
 
 const (
-	RootAccount  AccountType = iota + 1 // 1: root 账号
-	NormalAccount                      // 2: 普通账号
+	RootAccount   AccountType = iota + 1 // 1: root 账号
+	NormalAccount                        // 2: 普通账号
 )
 
 // HostAccount 主机账号表
 type HostAccount struct {
-	ID          uint          `gorm:"primaryKey;autoIncrement;comment:主键ID"`
-	Name        string        `gorm:"type:varchar(50);not null;comment:账号名称"`
-	Username    string        `gorm:"type:varchar(50);not null;comment:用户名"`
-	Password    string        `gorm:"type:varchar(255);comment:密码（加密存储）"`
-	SecretKey   string        `gorm:"type:text;comment:私钥内容"`
-	Type        AccountType    `gorm:"type:tinyint(1);not null;default:2;comment:账号类型(1:root,2:普通)"`
-	HostID      uint          `gorm:"type:uint;not null;comment:关联主机ID"`
-	Status      models.Status `gorm:"type:tinyint(1);not null;default:1;comment:状态(0:禁用,1:启用)"`
-	Remark      string        `gorm:"type:varchar(255);comment:备注"`
-	CreatedBy   uint          `gorm:"type:uint;not null;comment:创建人ID"`
-	CreatedAt   time.Time     `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
-	UpdatedAt   time.Time     `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;comment:更新时间"`
+	ID            uint          `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	Name          string        `gorm:"type:varchar(50);not null;comment:账号名称"`
+	Username      string        `gorm:"type:varchar(50);not null;comment:用户名"`
+	Password      string        `gorm:"type:varchar(255);comment:密码（加密存储）"`
+	SecretKey     string        `gorm:"type:text;comment:私钥内容"`
+	Type          AccountType   `gorm:"type:tinyint(1);not null;default:2;comment:账号类型(1:root,2:普通)"`
+	HostID        uint          `gorm:"type:uint;not null;comment:关联主机ID"`
+	Status        models.Status `gorm:"type:tinyint(1);not null;default:1;comment:状态(0:禁用,1:启用)"`
+	Remark        string        `gorm:"type:varchar(255);comment:备注"`
+	RotateDays    int           `gorm:"type:int;default:0;comment:密码自动轮换周期(天，0表示不自动轮换)"`
+	NextRotateAt  *time.Time    `gorm:"type:datetime;index;comment:下次轮换时间"`
+	LastRotatedAt *time.Time    `gorm:"type:datetime;comment:最后轮换时间"`
+	CreatedBy     uint          `gorm:"type:uint;not null;comment:创建人ID"`
+	CreatedAt     time.Time     `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt     time.Time     `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;comment:更新时间"`
 }
 
 // TableName 设置表名
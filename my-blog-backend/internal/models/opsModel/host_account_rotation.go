@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type RotationStatus string
+
+const (
+	RotationSuccess    RotationStatus = "success"     // 轮换成功
+	RotationFailed     RotationStatus = "failed"      // 修改密码失败，远端未变更
+	RotationRolledBack RotationStatus = "rolled_back" // 验证或保存失败，已恢复旧密码
+	RotationBroken     RotationStatus = "broken"      // 回滚失败，远端密码与记录不一致，需人工处理
+)
+
+// HostAccountRotation 主机账号密码轮换记录
+type HostAccountRotation struct {
+	ID         uint           `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	AccountID  uint           `gorm:"type:uint;not null;index;comment:主机账号ID"`
+	HostID     uint           `gorm:"type:uint;not null;comment:主机ID"`
+	Method     string         `gorm:"type:varchar(20);not null;comment:修改方式(chpasswd,passwd)"`
+	Status     RotationStatus `gorm:"type:varchar(20);not null;comment:状态(success,failed,rolled_back,broken)"`
+	Message    string         `gorm:"type:text;comment:失败原因"`
+	Source     string         `gorm:"type:varchar(20);not null;comment:触发来源(manual,schedule)"`
+	CreatedBy  uint           `gorm:"type:uint;not null;comment:操作人ID"`
+	StartedAt  time.Time      `gorm:"type:datetime;not null;comment:开始时间"`
+	FinishedAt time.Time      `gorm:"type:datetime;not null;comment:结束时间"`
+}
+
+// TableName 设置表名
+func (HostAccountRotation) TableName() string {
+	return "host_account_rotations"
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// HostLabel 主机标签表（键值对，如 env=prod、role=db），供标签选择器动态选择主机
+type HostLabel struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	HostID    uint      `gorm:"type:uint;not null;uniqueIndex:uk_host_label_key;comment:主机ID"`
+	Key       string    `gorm:"column:label_key;type:varchar(63);not null;uniqueIndex:uk_host_label_key;index:idx_label_key_value;comment:标签键"`
+	Value     string    `gorm:"column:label_value;type:varchar(63);not null;default:'';index:idx_label_key_value;comment:标签值"`
+	CreatedAt time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+}
+
+// TableName 设置表名
+func (HostLabel) TableName() string {
+	return "host_labels"
+}
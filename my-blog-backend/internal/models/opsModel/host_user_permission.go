@@ -2,14 +2,16 @@ package models
 
 import "time"
 
-// HostUserPermission 主机用户权限关联表（用户组 <-> 主机组）
-// 实现RBAC：用户组对主机组拥有访问权限
+// HostUserPermission 主机用户权限关联表（用户组 <-> 主机组 / 标签选择器）
+// 实现RBAC：用户组对主机组或匹配标签选择器的主机拥有访问权限
 type HostUserPermission struct {
-	ID          uint      `gorm:"primaryKey;autoIncrement;comment:主键ID"`
-	UserGroupID uint      `gorm:"type:uint;not null;comment:用户组ID"`
-	HostGroupID uint      `gorm:"type:uint;not null;comment:主机组ID"`
-	CreatedBy   uint      `gorm:"type:uint;not null;comment:创建人ID"`
-	CreatedAt   time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	ID               uint      `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	UserGroupID      uint      `gorm:"type:uint;not null;comment:用户组ID"`
+	HostGroupID      uint      `gorm:"type:uint;not null;default:0;comment:主机组ID(0表示按标签选择器授权)"`
+	HostSelector     string    `gorm:"type:varchar(500);not null;default:'';comment:主机标签选择器(校验权限时解析，按主机组授权时为空)"`
+	ContainerPattern string    `gorm:"type:varchar(500);comment:可进入的容器(名称或ID通配符，逗号分隔，空表示不允许)"`
+	CreatedBy        uint      `gorm:"type:uint;not null;comment:创建人ID"`
+	CreatedAt        time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
 }
 
 // TableName 设置表名
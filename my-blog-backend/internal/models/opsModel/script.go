@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"my-blog-backend/internal/models"
+)
+
+type ScriptLanguage string
+
+const (
+	BashScript   ScriptLanguage = "bash"
+	ShellScript  ScriptLanguage = "shell"
+	PythonScript ScriptLanguage = "python"
+	PerlScript   ScriptLanguage = "perl"
+)
+
+type ScriptParamType string
+
+const (
+	StringParam ScriptParamType = "string"
+	IntParam    ScriptParamType = "int"
+	BoolParam   ScriptParamType = "bool"
+	EnumParam   ScriptParamType = "enum"
+)
+
+// Script 脚本库表（脚本元信息，内容按版本存储在 ScriptVersion 中）
+type Script struct {
+	ID            uint           `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	Name          string         `gorm:"type:varchar(100);not null;uniqueIndex;comment:脚本名称"`
+	Language      ScriptLanguage `gorm:"type:varchar(20);not null;comment:脚本语言(bash,shell,python,perl)"`
+	Description   string         `gorm:"type:varchar(500);comment:描述"`
+	LatestVersion int            `gorm:"type:int;not null;default:1;comment:最新版本号"`
+	Owner         uint           `gorm:"type:uint;not null;index;comment:所有者ID"`
+	Status        models.Status  `gorm:"type:tinyint(1);not null;default:1;comment:状态(0:禁用,1:启用)"`
+	CreatedAt     time.Time      `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt     time.Time      `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;comment:更新时间"`
+}
+
+// TableName 设置表名
+func (Script) TableName() string {
+	return "scripts"
+}
+
+// ScriptVersion 脚本版本表（每次修改生成新版本，历史版本不可变）
+type ScriptVersion struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	ScriptID   uint      `gorm:"type:uint;not null;uniqueIndex:uk_script_version;comment:脚本ID"`
+	Version    int       `gorm:"type:int;not null;uniqueIndex:uk_script_version;comment:版本号"`
+	Content    string    `gorm:"type:longtext;not null;comment:脚本内容"`
+	Parameters string    `gorm:"type:text;comment:参数定义(JSON)"`
+	ChangeLog  string    `gorm:"type:varchar(255);comment:变更说明"`
+	CreatedBy  uint      `gorm:"type:uint;not null;comment:创建人ID"`
+	CreatedAt  time.Time `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+}
+
+// TableName 设置表名
+func (ScriptVersion) TableName() string {
+	return "script_versions"
+}
+
+// ScriptParameter 脚本参数定义（序列化后存入 ScriptVersion.Parameters）
+type ScriptParameter struct {
+	Name        string          `json:"name"`
+	Type        ScriptParamType `json:"type"`
+	Default     string          `json:"default"`
+	Required    bool            `json:"required"`
+	Options     []string        `json:"options,omitempty"` // 枚举类型的可选值
+	Raw         bool            `json:"raw,omitempty"`     // 不按脚本语言转义，原样渲染（仅限整数和枚举类型）
+	Description string          `json:"description"`
+}
+
+// Interpreter 返回执行脚本所用的解释器
+func (l ScriptLanguage) Interpreter() string {
+	switch l {
+	case BashScript:
+		return "bash"
+	case ShellScript:
+		return "sh"
+	case PythonScript:
+		return "python3"
+	case PerlScript:
+		return "perl"
+	default:
+		return ""
+	}
+}
+
+// Extension 返回脚本文件扩展名
+func (l ScriptLanguage) Extension() string {
+	switch l {
+	case BashScript, ShellScript:
+		return ".sh"
+	case PythonScript:
+		return ".py"
+	case PerlScript:
+		return ".pl"
+	default:
+		return ""
+	}
+}
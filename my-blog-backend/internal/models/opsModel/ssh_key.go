@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type SSHKeyStatus string
+
+const (
+	SSHKeyActive  SSHKeyStatus = "active"  // 使用中
+	SSHKeyRetired SSHKeyStatus = "retired" // 已轮换下线
+)
+
+// SSHKey 托管密钥表（服务端生成的密钥对，用于分发到主机并替代密码登录）
+type SSHKey struct {
+	ID           uint         `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	Name         string       `gorm:"type:varchar(100);not null;comment:密钥名称"`
+	KeyType      string       `gorm:"type:varchar(20);not null;comment:密钥类型(ed25519,rsa)"`
+	Bits         int          `gorm:"type:int;default:0;comment:密钥长度(RSA)"`
+	PublicKey    string       `gorm:"type:text;not null;comment:公钥(authorized_keys格式)"`
+	PrivateKey   string       `gorm:"type:text;not null;comment:私钥内容(加密)"`
+	Fingerprint  string       `gorm:"type:varchar(100);not null;uniqueIndex;comment:SHA256指纹"`
+	Status       SSHKeyStatus `gorm:"type:varchar(20);not null;default:'active';index;comment:状态(active,retired)"`
+	RotateDays   int          `gorm:"type:int;default:0;comment:自动轮换周期(天，0表示不自动轮换)"`
+	NextRotateAt *time.Time   `gorm:"type:datetime;index;comment:下次轮换时间"`
+	ReplacedBy   uint         `gorm:"type:uint;default:0;comment:轮换后的新密钥ID"`
+	CreatedBy    uint         `gorm:"type:uint;not null;comment:创建人ID"`
+	CreatedAt    time.Time    `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt    time.Time    `gorm:"type:datetime;not null;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;comment:更新时间"`
+}
+
+// TableName 设置表名
+func (SSHKey) TableName() string {
+	return "ssh_keys"
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+type TerminalSessionStatus string
+
+const (
+	TerminalSessionActive TerminalSessionStatus = "active" // 会话进行中
+	TerminalSessionClosed TerminalSessionStatus = "closed" // 会话已结束
+)
+
+// TerminalSession 终端会话记录，会话建立时写入，结束时更新流量和结束时间
+type TerminalSession struct {
+	ID          uint                  `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	SessionID   string                `gorm:"type:varchar(100);not null;index;comment:会话ID"`
+	UserID      uint                  `gorm:"type:uint;not null;index:idx_user_started;comment:用户ID"`
+	UserName    string                `gorm:"type:varchar(50);not null;comment:用户名"`
+	HostID      uint                  `gorm:"type:uint;not null;index;comment:主机ID"`
+	HostName    string                `gorm:"type:varchar(100);not null;comment:主机名称"`
+	HostAddress string                `gorm:"type:varchar(100);not null;comment:主机地址"`
+	HostAccount string                `gorm:"type:varchar(50);not null;comment:登录主机使用的账号"`
+	ContainerID string                `gorm:"type:varchar(64);comment:容器ID(容器终端会话)"`
+	ClientIP    string                `gorm:"type:varchar(50);comment:客户端IP"`
+	ClientAgent string                `gorm:"type:varchar(255);comment:客户端User-Agent"`
+	RecordingID string                `gorm:"type:varchar(100);comment:会话录像ID"`
+	Status      TerminalSessionStatus `gorm:"type:varchar(20);not null;index;comment:状态(active,closed)"`
+	CloseReason string                `gorm:"type:varchar(255);comment:断开原因(超时或强制断开)"`
+	BytesIn     int64                 `gorm:"type:bigint;not null;default:0;comment:输入字节数"`
+	BytesOut    int64                 `gorm:"type:bigint;not null;default:0;comment:输出字节数"`
+	StartedAt   time.Time             `gorm:"type:datetime;not null;index:idx_user_started;comment:开始时间"`
+	LastInputAt *time.Time            `gorm:"type:datetime;comment:最后输入时间"`
+	EndedAt     *time.Time            `gorm:"type:datetime;comment:结束时间"`
+}
+
+// TableName 设置表名
+func (TerminalSession) TableName() string {
+	return "terminal_sessions"
+}
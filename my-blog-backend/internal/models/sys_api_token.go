@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// SysAPIToken 用户 API Token（供 CI 等机器调用方使用，只保存哈希）
+type SysAPIToken struct {
+	ID         uint64     `gorm:"primaryKey;autoIncrement;comment:主键ID" json:"id"`
+	UserID     uint64     `gorm:"column:user_id;not null;index:idx_user_id;comment:所属用户ID" json:"userId"`
+	Name       string     `gorm:"column:name;type:varchar(100);not null;comment:名称" json:"name"`
+	TokenHash  string     `gorm:"column:token_hash;type:char(64);not null;uniqueIndex:uk_token_hash;comment:Token的SHA-256哈希" json:"-"`
+	Prefix     string     `gorm:"column:prefix;type:varchar(20);not null;comment:Token前缀(用于识别)" json:"prefix"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at;comment:过期时间(空表示永不过期)" json:"expiresAt"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at;comment:最后使用时间" json:"lastUsedAt"`
+	LastUsedIP string     `gorm:"column:last_used_ip;type:varchar(50);comment:最后使用IP" json:"lastUsedIp"`
+	CreateTime *time.Time `gorm:"autoCreateTime;column:create_time;comment:创建时间" json:"createTime"`
+}
+
+// TableName 指定表名
+func (SysAPIToken) TableName() string {
+	return "sys_api_token"
+}
@@ -0,0 +1,135 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+// generator 订阅源生成器名称
+const generator = "my-blog"
+
+// Format 订阅源格式
+type Format string
+
+const (
+	FormatRSS  Format = "rss"  // RSS 2.0
+	FormatAtom Format = "atom" // Atom 1.0
+	FormatJSON Format = "json" // JSON Feed 1.1
+)
+
+// ParseFormat 解析订阅源格式，空值默认为 RSS
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(value))) {
+	case "", FormatRSS:
+		return FormatRSS, nil
+	case FormatAtom:
+		return FormatAtom, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("不支持的订阅源格式: %s", value)
+}
+
+// ContentType 返回订阅源格式对应的响应类型
+func (f Format) ContentType() string {
+	return f.mimeType() + "; charset=utf-8"
+}
+
+func (f Format) mimeType() string {
+	switch f {
+	case FormatAtom:
+		return "application/atom+xml"
+	case FormatJSON:
+		return "application/feed+json"
+	}
+	return "application/rss+xml"
+}
+
+// Feed 订阅源
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // 对应的页面地址
+	FeedLink    string // 订阅源自身地址
+	Language    string
+	Author      string
+	Updated     time.Time // 最近一篇文章的更新时间
+	Items       []*Item
+}
+
+// Item 订阅源条目
+type Item struct {
+	ID          string // 全局唯一标识，使用文章页面地址
+	Title       string
+	Link        string
+	Summary     string
+	ContentHTML string // 正文 HTML，为空时使用 ContentText
+	ContentText string // 正文纯文本，摘要模式下为空
+	Author      string
+	Image       string
+	Categories  []string
+	Published   time.Time
+	Updated     time.Time
+}
+
+// Render 按格式生成订阅源
+func Render(feed *Feed, format Format) ([]byte, error) {
+	switch format {
+	case FormatAtom:
+		return renderAtom(feed)
+	case FormatJSON:
+		return renderJSON(feed)
+	}
+	return renderRSS(feed)
+}
+
+// contentHTML 返回条目正文 HTML，纯文本正文按空行分段转义
+func (item *Item) contentHTML() string {
+	if item.ContentHTML != "" {
+		return item.ContentHTML
+	}
+	if item.ContentText == "" {
+		return ""
+	}
+	var builder strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(item.ContentText, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		builder.WriteString("<p>")
+		builder.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		builder.WriteString("</p>")
+	}
+	return builder.String()
+}
+
+// marshalXML 生成带 XML 声明的文档
+func marshalXML(document interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// imageType 按扩展名推断图片类型
+func imageType(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	if contentType := mime.TypeByExtension(path.Ext(url)); strings.HasPrefix(contentType, "image/") {
+		return contentType
+	}
+	return "image/jpeg"
+}
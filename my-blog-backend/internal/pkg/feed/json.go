@@ -0,0 +1,83 @@
+package feed
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// jsonFeed JSON Feed 1.1，见 https://www.jsonfeed.org/version/1.1/
+type jsonFeed struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url,omitempty"`
+	FeedURL     string       `json:"feed_url,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Language    string       `json:"language,omitempty"`
+	Authors     []jsonAuthor `json:"authors,omitempty"`
+	Items       []jsonItem   `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonItem struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url,omitempty"`
+	Title         string       `json:"title,omitempty"`
+	ContentHTML   string       `json:"content_html,omitempty"`
+	ContentText   string       `json:"content_text,omitempty"`
+	Summary       string       `json:"summary,omitempty"`
+	Image         string       `json:"image,omitempty"`
+	DatePublished string       `json:"date_published,omitempty"`
+	DateModified  string       `json:"date_modified,omitempty"`
+	Authors       []jsonAuthor `json:"authors,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+}
+
+func renderJSON(feed *Feed) ([]byte, error) {
+	document := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		FeedURL:     feed.FeedLink,
+		Description: feed.Description,
+		Language:    feed.Language,
+		Items:       make([]jsonItem, 0, len(feed.Items)),
+	}
+	if feed.Author != "" {
+		document.Authors = []jsonAuthor{{Name: feed.Author}}
+	}
+
+	for _, item := range feed.Items {
+		entry := jsonItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentHTML:   item.ContentHTML,
+			ContentText:   item.ContentText,
+			Summary:       item.Summary,
+			Image:         item.Image,
+			DatePublished: jsonTime(item.Published),
+			DateModified:  jsonTime(item.Updated),
+			Tags:          item.Categories,
+		}
+		// content_html 和 content_text 至少需要一个，摘要模式下使用摘要
+		if entry.ContentHTML == "" && entry.ContentText == "" {
+			entry.ContentText = item.Summary
+		}
+		if item.Author != "" {
+			entry.Authors = []jsonAuthor{{Name: item.Author}}
+		}
+		document.Items = append(document.Items, entry)
+	}
+
+	return json.MarshalIndent(document, "", "  ")
+}
+
+func jsonTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
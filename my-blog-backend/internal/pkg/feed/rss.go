@@ -0,0 +1,105 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type rssDocument struct {
+	XMLName      xml.Name   `xml:"rss"`
+	Version      string     `xml:"version,attr"`
+	ContentNS    string     `xml:"xmlns:content,attr"`
+	AtomNS       string     `xml:"xmlns:atom,attr"`
+	DublinCoreNS string     `xml:"xmlns:dc,attr"`
+	Channel      rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Generator     string    `xml:"generator"`
+	AtomLink      rssLink   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	Description rssCDATA      `xml:"description"`
+	Content     *rssCDATA     `xml:"content:encoded,omitempty"`
+	Creator     string        `xml:"dc:creator,omitempty"`
+	Categories  []string      `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssCDATA struct {
+	Value string `xml:",cdata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int    `xml:"length,attr"`
+}
+
+func renderRSS(feed *Feed) ([]byte, error) {
+	channel := rssChannel{
+		Title:       feed.Title,
+		Link:        feed.Link,
+		Description: feed.Description,
+		Language:    feed.Language,
+		Generator:   generator,
+		AtomLink:    rssLink{Href: feed.FeedLink, Rel: "self", Type: FormatRSS.mimeType()},
+	}
+	if !feed.Updated.IsZero() {
+		channel.LastBuildDate = feed.Updated.Format(time.RFC1123Z)
+	}
+	if channel.Description == "" {
+		channel.Description = feed.Title
+	}
+
+	for _, item := range feed.Items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{Value: item.ID, IsPermaLink: item.ID == item.Link},
+			Description: rssCDATA{Value: item.Summary},
+			Creator:     item.Author,
+			Categories:  item.Categories,
+		}
+		if content := item.contentHTML(); content != "" {
+			entry.Content = &rssCDATA{Value: content}
+		}
+		if item.Image != "" {
+			entry.Enclosure = &rssEnclosure{URL: item.Image, Type: imageType(item.Image)}
+		}
+		if !item.Published.IsZero() {
+			entry.PubDate = item.Published.Format(time.RFC1123Z)
+		}
+		channel.Items = append(channel.Items, entry)
+	}
+
+	return marshalXML(rssDocument{
+		Version:      "2.0",
+		ContentNS:    "http://purl.org/rss/1.0/modules/content/",
+		AtomNS:       "http://www.w3.org/2005/Atom",
+		DublinCoreNS: "http://purl.org/dc/elements/1.1/",
+		Channel:      channel,
+	})
+}
@@ -0,0 +1,97 @@
+package frontmatter
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	delimiter     = "---"
+	tomlDelimiter = "+++" // Hugo 的 TOML front matter
+)
+
+// Split 拆分 Markdown 开头以 --- 包围的 YAML front matter，没有 front matter 时 ok 为 false，body 为原文
+func Split(content []byte) (matter []byte, body []byte, ok bool) {
+	return split(content, delimiter, "...")
+}
+
+// split 拆分以 open 开始、以 closes 之一结束的 front matter
+func split(content []byte, open string, closes ...string) (matter []byte, body []byte, ok bool) {
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+	first, rest, found := cutLine(content)
+	if !found || strings.TrimSpace(string(first)) != open {
+		return nil, content, false
+	}
+
+	for offset := 0; offset < len(rest); {
+		line, next, _ := cutLine(rest[offset:])
+		if trimmed := strings.TrimSpace(string(line)); trimmed == open || slices.Contains(closes, trimmed) {
+			return rest[:offset], bytes.TrimLeft(next, "\r\n"), true
+		}
+		offset = len(rest) - len(next)
+	}
+	return nil, content, false
+}
+
+// Parse 解析 front matter 到 v，返回去掉 front matter 的正文；没有 front matter 时 v 不变
+func Parse(content []byte, v interface{}) (string, error) {
+	matter, body, ok := Split(content)
+	if !ok {
+		return string(body), nil
+	}
+	if err := yaml.Unmarshal(matter, v); err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// ParseMap 解析 YAML（---）或 TOML（+++）front matter，返回字段和去掉 front matter 的正文；
+// 用于导入其他博客系统的文章，字段名和类型因系统而异，没有 front matter 时返回空 map
+func ParseMap(content []byte) (map[string]interface{}, string, error) {
+	fields := make(map[string]interface{})
+	if matter, body, ok := Split(content); ok {
+		if err := yaml.Unmarshal(matter, &fields); err != nil {
+			return nil, "", err
+		}
+		return fields, string(body), nil
+	}
+	matter, body, ok := split(content, tomlDelimiter)
+	if ok {
+		if err := toml.Unmarshal(matter, &fields); err != nil {
+			return nil, "", err
+		}
+	}
+	return fields, string(body), nil
+}
+
+// Marshal 生成带 YAML front matter 的 Markdown
+func Marshal(v interface{}, body string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(delimiter + "\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString(delimiter + "\n\n")
+	buf.WriteString(body)
+	if !strings.HasSuffix(body, "\n") {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// cutLine 返回第一行（不含换行符）和剩余内容
+func cutLine(content []byte) (line, rest []byte, found bool) {
+	if i := bytes.IndexByte(content, '\n'); i >= 0 {
+		return bytes.TrimSuffix(content[:i], []byte("\r")), content[i+1:], true
+	}
+	return content, nil, false
+}
@@ -0,0 +1,135 @@
+package importer
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"my-blog-backend/internal/pkg/frontmatter"
+)
+
+// Archive 上传的站点目录 zip 归档，文件名统一为 / 分隔的相对路径
+type Archive struct {
+	files map[string]*zip.File
+	names []string // 按名称排序
+}
+
+// OpenArchive 读取 zip 归档，忽略目录、隐藏文件和 macOS 生成的 __MACOSX 目录
+func OpenArchive(r io.ReaderAt, size int64) (*Archive, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 zip 文件: %w", err)
+	}
+
+	archive := &Archive{files: make(map[string]*zip.File, len(reader.File))}
+	for _, file := range reader.File {
+		name := path.Clean(strings.ReplaceAll(file.Name, "\\", "/"))
+		if file.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".") {
+			continue
+		}
+		archive.files[name] = file
+		archive.names = append(archive.names, name)
+	}
+	sort.Strings(archive.names)
+	return archive, nil
+}
+
+// Exists 判断归档中是否有该文件
+func (a *Archive) Exists(name string) bool {
+	_, ok := a.files[name]
+	return ok
+}
+
+// Open 读取归档中的文件，不存在时返回 fs.ErrNotExist，超过 maxSize 时返回错误
+func (a *Archive) Open(name string, maxSize int64) ([]byte, error) {
+	file, ok := a.files[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return ReadZipFile(file, maxSize)
+}
+
+// ReadZipFile 读取 zip 中的文件，超过 maxSize 时返回错误
+// 先检查声明的解压大小，读取时再限制实际读取量，防止声明大小与实际不符的压缩炸弹
+func ReadZipFile(file *zip.File, maxSize int64) ([]byte, error) {
+	if file.UncompressedSize64 > uint64(maxSize) {
+		return nil, fmt.Errorf("文件超过 %d MB", maxSize/(1024*1024))
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	// 多读一个字节判断是否超过大小限制
+	content, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > maxSize {
+		return nil, fmt.Errorf("文件超过 %d MB", maxSize/(1024*1024))
+	}
+	return content, nil
+}
+
+// root 查找包含 marker（如 source/_posts/）的最短目录前缀，站点目录在归档中可能套了一层文件夹
+func (a *Archive) root(marker string) (string, bool) {
+	root, found := "", false
+	for _, name := range a.names {
+		i := strings.Index("/"+name, "/"+marker)
+		if i < 0 || (found && i >= len(root)) {
+			continue
+		}
+		root, found = name[:i], true
+	}
+	return root, found
+}
+
+// documents 返回 dir 下（含子目录）的全部 Markdown 文件
+func (a *Archive) documents(dir string) []string {
+	var names []string
+	for _, name := range a.names {
+		if !strings.HasPrefix(name, dir) {
+			continue
+		}
+		if ext := strings.ToLower(path.Ext(name)); ext == ".md" || ext == ".markdown" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// config 读取第一个存在的站点配置文件，都不存在时返回空配置
+func (a *Archive) config(names ...string) (fields, error) {
+	for _, name := range names {
+		if !a.Exists(name) {
+			continue
+		}
+		content, err := a.Open(name, maxDocumentSize)
+		if err != nil {
+			return nil, err
+		}
+		config, err := parseConfig(name, content)
+		if err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %w", name, err)
+		}
+		return config, nil
+	}
+	return fields{}, nil
+}
+
+// document 读取 Markdown 文件，返回 front matter 字段和正文
+func (a *Archive) document(name string) (fields, string, error) {
+	content, err := a.Open(name, maxDocumentSize)
+	if err != nil {
+		return nil, "", err
+	}
+	matter, body, err := frontmatter.ParseMap(content)
+	if err != nil {
+		return nil, "", fmt.Errorf("front matter 格式错误: %w", err)
+	}
+	return matter, body, nil
+}
@@ -0,0 +1,37 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestArchiveOpenLimit(t *testing.T) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"small.png": "png",
+		"large.png": strings.Repeat("x", 2048),
+	} {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("创建归档失败: %v", err)
+		}
+		_, _ = w.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("创建归档失败: %v", err)
+	}
+
+	archive, err := OpenArchive(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("读取归档失败: %v", err)
+	}
+	if content, err := archive.Open("small.png", 1024); err != nil || string(content) != "png" {
+		t.Fatalf("读取小文件失败: %q %v", content, err)
+	}
+	if _, err := archive.Open("large.png", 1024); err == nil {
+		t.Fatal("超过大小限制的文件应返回错误")
+	}
+}
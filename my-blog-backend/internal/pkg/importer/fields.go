@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// fields front matter 或站点配置的字段，不同系统、不同主题的字段类型不统一，统一通过访问方法转换
+type fields map[string]interface{}
+
+// parseConfig 按扩展名解析站点配置文件
+func parseConfig(name string, content []byte) (fields, error) {
+	config := fields{}
+	var err error
+	switch strings.ToLower(path.Ext(name)) {
+	case ".toml":
+		err = toml.Unmarshal(content, &config)
+	case ".json":
+		err = json.Unmarshal(content, &config)
+	default:
+		err = yaml.Unmarshal(content, &config)
+	}
+	return config, err
+}
+
+// string 返回第一个非空的字符串字段
+func (f fields) string(keys ...string) string {
+	for _, key := range keys {
+		switch value := f[key].(type) {
+		case string:
+			if value = strings.TrimSpace(value); value != "" {
+				return value
+			}
+		case int, int64, uint64, float64:
+			return fmt.Sprint(value)
+		}
+	}
+	return ""
+}
+
+// list 返回第一个非空的列表字段，字符串视为只有一项的列表，嵌套列表按顺序展开
+func (f fields) list(keys ...string) []string {
+	for _, key := range keys {
+		if values := flatten(f[key]); len(values) > 0 {
+			return values
+		}
+	}
+	return nil
+}
+
+func flatten(value interface{}) []string {
+	switch value := value.(type) {
+	case string:
+		if value = strings.TrimSpace(value); value != "" {
+			return []string{value}
+		}
+	case int, int64, uint64, float64:
+		return []string{fmt.Sprint(value)}
+	case []interface{}:
+		var values []string
+		for _, item := range value {
+			values = append(values, flatten(item)...)
+		}
+		return values
+	}
+	return nil
+}
+
+// bool 返回布尔字段，字段不存在或不是布尔值时 ok 为 false
+func (f fields) bool(key string) (value bool, ok bool) {
+	switch v := f[key].(type) {
+	case bool:
+		return v, true
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+		return parsed, err == nil
+	}
+	return false, false
+}
+
+// time 返回第一个有效的时间字段，没有时区的时间按 location 解析
+func (f fields) time(location *time.Location, keys ...string) time.Time {
+	for _, key := range keys {
+		if t := parseTime(f[key], location); !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// field 返回嵌套的字段，如 Hugo 主题常用的 cover.image
+func (f fields) field(key string) fields {
+	switch value := f[key].(type) {
+	case map[string]interface{}:
+		return value
+	case fields:
+		return value
+	}
+	return fields{}
+}
+
+// timeLayouts 各系统常见的时间格式
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+func parseTime(value interface{}, location *time.Location) time.Time {
+	var text string
+	switch value := value.(type) {
+	case time.Time:
+		// YAML 将没有时区的时间解析为 UTC，而 Hexo、Jekyll 按站点时区解释，这里同样按 location 处理
+		if value.Location() == time.UTC {
+			return time.Date(value.Year(), value.Month(), value.Day(), value.Hour(), value.Minute(), value.Second(), value.Nanosecond(), location)
+		}
+		return value
+	case string:
+		text = value
+	case fmt.Stringer:
+		// TOML 的本地日期时间（toml.LocalDateTime 等）
+		text = value.String()
+	default:
+		return time.Time{}
+	}
+
+	text = strings.TrimSpace(text)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, text, location); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,190 @@
+package importer
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+
+	"my-blog-backend/internal/pkg/utils"
+)
+
+// hexoDefaultPermalink Hexo 默认的文章链接格式
+const hexoDefaultPermalink = ":year/:month/:day/:title/"
+
+var (
+	// hexoTagPattern Hexo 标签插件 {% name args %}
+	hexoTagPattern = regexp.MustCompile(`\{%\s*(asset_img|asset_path|asset_link|img)\s+(.*?)\s*%\}`)
+	// hexoDatePrefix new_post_name 带日期时文件名的日期前缀
+	hexoDatePrefix = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}-`)
+)
+
+// ReadHexo 读取 Hexo 站点目录：source/_posts 下为文章，source/_drafts 下为草稿，
+// 链接格式取自 _config.yml 的 permalink，source 目录对应站点根路径
+func ReadHexo(archive *Archive, options Options) (*Site, error) {
+	root, ok := archive.root("source/_posts/")
+	sourceDir := root + "source/"
+	if !ok {
+		// 只上传了 source 目录
+		if sourceDir, ok = archive.root("_posts/"); !ok {
+			return nil, errors.New("未找到 Hexo 的 source/_posts 目录")
+		}
+		root = sourceDir
+	}
+
+	config, err := archive.config(root + "_config.yml")
+	if err != nil {
+		return nil, err
+	}
+	site := &Site{URL: config.string("url"), Archive: archive, StaticDirs: []string{sourceDir}}
+
+	permalink := options.Permalink
+	if permalink == "" {
+		permalink = config.string("permalink")
+	}
+	if permalink == "" {
+		permalink = hexoDefaultPermalink
+	}
+	siteRoot := config.string("root")
+	if siteRoot == "" {
+		siteRoot = basePath(site.URL)
+	}
+	defaultCategory := config.string("default_category")
+	if defaultCategory == "" {
+		defaultCategory = "uncategorized"
+	}
+	datePrefix := strings.Contains(config.string("new_post_name"), ":year-:month-:day-")
+
+	for _, draft := range []bool{false, true} {
+		dir := sourceDir + "_posts/"
+		if draft {
+			dir = sourceDir + "_drafts/"
+		}
+		for _, name := range archive.documents(dir) {
+			post := &Post{File: name, AssetDirs: []string{trimExt(name), path.Dir(name)}}
+			site.Posts = append(site.Posts, post)
+			matter, body, err := archive.document(name)
+			if err != nil {
+				post.Err = err
+				continue
+			}
+
+			// :title 为相对 _posts 的路径（不含扩展名）
+			title := trimExt(strings.TrimPrefix(name, dir))
+			if datePrefix {
+				title = path.Join(path.Dir(title), hexoDatePrefix.ReplaceAllString(path.Base(title), ""))
+			}
+
+			post.Title = matter.string("title")
+			post.Slug = utils.Slugify(path.Base(title))
+			post.Summary = matter.string("excerpt", "description", "summary")
+			post.Cover = matter.string("cover", "thumbnail", "index_img", "banner_img", "top_img", "banner")
+			if post.Cover == "" {
+				if photos := matter.list("photos"); len(photos) > 0 {
+					post.Cover = photos[0]
+				}
+			}
+			// Hexo 的多个分类表示层级，第一个为顶级分类
+			post.Categories = matter.list("categories", "category")
+			post.Tags = matter.list("tags", "tag")
+			post.Date = matter.time(options.Location, "date")
+			post.Updated = matter.time(options.Location, "updated", "lastmod")
+			post.Content = convertHexoTags(body)
+			published, ok := matter.bool("published")
+			post.Draft = draft || (ok && !published)
+			if post.Draft {
+				continue
+			}
+
+			if link := matter.string("permalink"); link != "" {
+				post.URLs = append(post.URLs, sitePath(siteRoot, link))
+				continue
+			}
+			values := scalarValues(matter)
+			dateValues(post.Date, values)
+			values["title"] = title
+			values["name"] = path.Base(title)
+			values["post_title"] = slugize(post.Title)
+			categories := make([]string, 0, len(post.Categories))
+			for _, category := range post.Categories {
+				categories = append(categories, slugize(category))
+			}
+			if len(categories) == 0 {
+				categories = append(categories, slugize(defaultCategory))
+			}
+			values["category"] = strings.Join(categories, "/")
+			if link := permalinkPath(siteRoot, permalink, values); link != "" {
+				post.URLs = append(post.URLs, link)
+			}
+		}
+	}
+	return site, nil
+}
+
+// convertHexoTags 将 Hexo 的图片标签插件转换为 Markdown 图片和链接
+func convertHexoTags(content string) string {
+	return hexoTagPattern.ReplaceAllStringFunc(content, func(match string) string {
+		groups := hexoTagPattern.FindStringSubmatch(match)
+		args := splitTagArgs(groups[2])
+		if len(args) == 0 {
+			return match
+		}
+		switch groups[1] {
+		case "asset_path":
+			return args[0]
+		case "asset_link":
+			return "[" + strings.Join(args[1:], " ") + "](" + args[0] + ")"
+		case "asset_img":
+			return "![" + strings.Join(args[1:], " ") + "](" + args[0] + ")"
+		default:
+			// {% img [class] src [width] [height] [title] %}，第一个像链接的参数为图片地址
+			for i, arg := range args {
+				if strings.ContainsAny(arg, "/.") {
+					var alt []string
+					for _, rest := range args[i+1:] {
+						if !isNumber(rest) {
+							alt = append(alt, rest)
+						}
+					}
+					return "![" + strings.Join(alt, " ") + "](" + arg + ")"
+				}
+			}
+			return match
+		}
+	})
+}
+
+// splitTagArgs 按空白拆分标签参数，引号内的空白不拆分
+func splitTagArgs(text string) []string {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	for _, r := range text {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && (r == ' ' || r == '\t'):
+			if current.Len() > 0 {
+				args = append(args, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+func isNumber(text string) bool {
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return text != ""
+}
@@ -0,0 +1,165 @@
+package importer
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+
+	"my-blog-backend/internal/pkg/utils"
+)
+
+var (
+	// hugoFigurePattern Hugo 的 figure 短代码 {{< figure src="..." >}}
+	hugoFigurePattern = regexp.MustCompile(`\{\{[<%]\s*figure\s+(.*?)\s*/?[>%]\}\}`)
+	// hugoAttrPattern 短代码参数 name="value"
+	hugoAttrPattern = regexp.MustCompile(`(\w+)\s*=\s*"([^"]*)"`)
+)
+
+// hugoConfigFiles Hugo 站点配置文件，按 Hugo 的查找顺序
+var hugoConfigFiles = []string{
+	"hugo.toml", "hugo.yaml", "hugo.yml", "hugo.json",
+	"config.toml", "config.yaml", "config.yml", "config.json",
+	"config/_default/hugo.toml", "config/_default/hugo.yaml", "config/_default/config.toml", "config/_default/config.yaml",
+}
+
+// ReadHugo 读取 Hugo 站点目录：content 下的页面（_index.md 除外）为文章，支持 YAML 和 TOML front matter；
+// 链接取自 url 字段、站点配置的 permalinks 或按目录生成，aliases 一并保留
+func ReadHugo(archive *Archive, options Options) (*Site, error) {
+	root, ok := archive.root("content/")
+	if !ok {
+		return nil, errors.New("未找到 Hugo 的 content 目录")
+	}
+	configFiles := make([]string, len(hugoConfigFiles))
+	for i, name := range hugoConfigFiles {
+		configFiles[i] = root + name
+	}
+	config, err := archive.config(configFiles...)
+	if err != nil {
+		return nil, err
+	}
+
+	site := &Site{URL: config.string("baseURL", "baseurl"), Archive: archive, StaticDirs: []string{root + "static/", root + "assets/"}}
+	siteRoot := basePath(site.URL)
+	// 新版 Hugo 的 permalinks 按页面类型分组
+	permalinks := config.field("permalinks")
+	if page := permalinks.field("page"); len(page) > 0 {
+		permalinks = page
+	}
+
+	contentDir := root + "content/"
+	for _, name := range archive.documents(contentDir) {
+		relative := strings.TrimPrefix(name, contentDir)
+		if strings.HasPrefix(path.Base(relative), "_index.") {
+			continue
+		}
+		// 页面包 posts/hello/index.md 的文件名为目录名
+		dir, filename := path.Dir(relative), trimExt(path.Base(relative))
+		if filename == "index" {
+			if dir == "." {
+				continue
+			}
+			dir, filename = path.Dir(dir), path.Base(dir)
+		}
+		if dir == "." {
+			dir = ""
+		}
+		section := strings.SplitN(dir, "/", 2)[0]
+
+		post := &Post{File: name, AssetDirs: []string{path.Dir(name)}}
+		site.Posts = append(site.Posts, post)
+		matter, body, err := archive.document(name)
+		if err != nil {
+			post.Err = err
+			continue
+		}
+
+		post.Title = matter.string("title")
+		slug := matter.string("slug")
+		post.Slug = utils.Slugify(slug)
+		if post.Slug == "" {
+			post.Slug = utils.Slugify(filename)
+		}
+		post.Summary = matter.string("summary", "description")
+		post.Cover = matter.string("cover", "featured_image", "featureImage", "image")
+		if post.Cover == "" {
+			post.Cover = matter.field("cover").string("image")
+		}
+		if images := matter.list("images"); post.Cover == "" && len(images) > 0 {
+			post.Cover = images[0]
+		}
+		post.Categories = matter.list("categories")
+		post.Tags = matter.list("tags")
+		post.Date = matter.time(options.Location, "date", "publishDate", "pubdate", "published")
+		post.Updated = matter.time(options.Location, "lastmod", "modified")
+		post.Draft, _ = matter.bool("draft")
+		post.Content = convertHugoShortcodes(body)
+		if post.Draft {
+			continue
+		}
+
+		link := matter.string("url")
+		if link != "" {
+			link = sitePath("", link)
+		} else {
+			urlized := strings.ToLower(slugize(post.Title))
+			slugOrFilename := slug
+			if slugOrFilename == "" {
+				slugOrFilename = filename
+			}
+			if pattern := options.Permalink; pattern != "" || permalinks.string(section) != "" {
+				if pattern == "" {
+					pattern = permalinks.string(section)
+				}
+				values := make(map[string]string)
+				dateValues(post.Date, values)
+				values["title"] = urlized
+				values["slug"] = slug
+				if slug == "" {
+					values["slug"] = urlized
+				}
+				values["filename"] = filename
+				values["contentbasename"] = filename
+				values["slugorfilename"] = slugOrFilename
+				values["slugorcontentbasename"] = slugOrFilename
+				values["section"] = section
+				values["sections"] = dir
+				link = strings.ToLower(permalinkPath(siteRoot, pattern, values))
+			} else {
+				link = sitePath(siteRoot, strings.ToLower(path.Join(dir, strings.ReplaceAll(slugOrFilename, " ", "-")))+"/")
+			}
+		}
+		if link != "" {
+			post.URLs = append(post.URLs, link)
+		}
+		// 相对路径的别名相对于页面所在目录
+		for _, alias := range matter.list("aliases") {
+			if !strings.HasPrefix(alias, "/") {
+				alias = path.Join(path.Dir(strings.TrimSuffix(link, "/")), alias)
+			}
+			post.URLs = append(post.URLs, sitePath("", alias))
+		}
+	}
+	return site, nil
+}
+
+// convertHugoShortcodes 将 Hugo 的 figure 短代码转换为 Markdown 图片
+func convertHugoShortcodes(content string) string {
+	return hugoFigurePattern.ReplaceAllStringFunc(content, func(match string) string {
+		attrs := make(map[string]string)
+		for _, attr := range hugoAttrPattern.FindAllStringSubmatch(hugoFigurePattern.FindStringSubmatch(match)[1], -1) {
+			attrs[attr[1]] = attr[2]
+		}
+		if attrs["src"] == "" {
+			return match
+		}
+		alt := attrs["alt"]
+		if alt == "" {
+			alt = attrs["caption"]
+		}
+		if title := attrs["title"]; title != "" {
+			return "![" + alt + "](" + attrs["src"] + ` "` + title + `")`
+		}
+		return "![" + alt + "](" + attrs["src"] + ")"
+	})
+}
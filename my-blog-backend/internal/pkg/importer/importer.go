@@ -0,0 +1,91 @@
+// Package importer 读取 Hexo、Hugo、Jekyll 站点目录和 WordPress WXR 导出文件，
+// 转换为统一的文章结构，由文章导入服务写入数据库
+package importer
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// 支持的博客系统
+const (
+	FormatHexo      = "hexo"
+	FormatHugo      = "hugo"
+	FormatJekyll    = "jekyll"
+	FormatWordPress = "wordpress"
+)
+
+// maxDocumentSize 单个 Markdown 文件的最大大小
+const maxDocumentSize = 10 * 1024 * 1024
+
+// Post 从其他博客系统读取的文章
+type Post struct {
+	File       string   // 文章在归档中的路径，WordPress 为 wordpress/{post_id}
+	AssetDirs  []string // 相对图片链接的查找目录（归档内路径），依次查找，如 Hexo 的文章资源目录
+	Title      string
+	Slug       string
+	Summary    string
+	Content    string
+	Cover      string
+	Categories []string // 第一个作为文章分类
+	Tags       []string
+	Draft      bool
+	Date       time.Time // 发布时间
+	Updated    time.Time // 最后修改时间，原系统没有记录时为零值
+	URLs       []string  // 文章在原站的访问路径（不含域名），导入后重定向到新地址
+	Comments   []*Comment
+	Err        error // 读取或解析失败的原因
+}
+
+// Comment 文章评论，只有 WordPress 导出文件包含评论
+type Comment struct {
+	ID       string
+	ParentID string // 父评论ID，顶级评论为空
+	Author   string
+	Email    string
+	URL      string
+	IP       string
+	Content  string
+	Date     time.Time
+	Approved bool
+}
+
+// Site 读取结果
+type Site struct {
+	URL        string   // 原站地址，来自站点配置，用于识别需要下载的站内图片，没有配置时为空
+	Posts      []*Post  // 按文件路径排序
+	Archive    *Archive // 上传的 zip 归档，WordPress 导入时为 nil
+	StaticDirs []string // 站点根路径对应的归档目录，以 / 开头的图片链接依次在其中查找
+}
+
+// Options 读取选项
+type Options struct {
+	Permalink string // 覆盖站点配置中的文章链接格式，使用原系统的占位符
+	Location  *time.Location
+}
+
+// Read 读取指定格式的站点，Hexo、Hugo、Jekyll 为站点目录的 zip 归档，WordPress 为 WXR 文件
+func Read(format string, r io.ReaderAt, size int64, options Options) (*Site, error) {
+	if options.Location == nil {
+		options.Location = time.Local
+	}
+	if format == FormatWordPress {
+		return ReadWordPress(io.NewSectionReader(r, 0, size), options)
+	}
+
+	archive, err := OpenArchive(r, size)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatHexo:
+		return ReadHexo(archive, options)
+	case FormatHugo:
+		return ReadHugo(archive, options)
+	case FormatJekyll:
+		return ReadJekyll(archive, options)
+	default:
+		return nil, fmt.Errorf("不支持的格式: %s", format)
+	}
+}
@@ -0,0 +1,153 @@
+package importer
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"my-blog-backend/internal/pkg/utils"
+)
+
+var (
+	// jekyllPostName _posts 下的文件名 YYYY-MM-DD-title.md
+	jekyllPostName = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+	// jekyllURLFilter {{ "/assets/a.png" | relative_url }} 等地址过滤器
+	jekyllURLFilter = regexp.MustCompile(`\{\{\s*["']([^"']*)["']\s*\|\s*(?:relative_url|absolute_url|prepend:\s*site\.baseurl)\s*\}\}`)
+	// jekyllSiteVariable {{ site.baseurl }}、{{ site.url }}
+	jekyllSiteVariable = regexp.MustCompile(`\{\{\s*site\.(?:baseurl|url)\s*\}\}`)
+)
+
+// jekyllPermalinkStyles Jekyll 内置的链接格式
+var jekyllPermalinkStyles = map[string]string{
+	"date":    "/:categories/:year/:month/:day/:title:output_ext",
+	"pretty":  "/:categories/:year/:month/:day/:title/",
+	"ordinal": "/:categories/:year/:y_day/:title:output_ext",
+	"none":    "/:categories/:title:output_ext",
+}
+
+// ReadJekyll 读取 Jekyll 站点目录：_posts 下为文章（文件名带日期），_drafts 下为草稿，
+// _posts 所在的上级目录作为文章分类；链接格式取自 _config.yml 的 permalink（默认 date）
+func ReadJekyll(archive *Archive, options Options) (*Site, error) {
+	root, ok := archive.root("_config.yml")
+	if !ok {
+		if root, ok = archive.root("_posts/"); !ok {
+			return nil, errors.New("未找到 Jekyll 的 _posts 目录")
+		}
+	}
+	config, err := archive.config(root + "_config.yml")
+	if err != nil {
+		return nil, err
+	}
+
+	site := &Site{URL: config.string("url"), Archive: archive, StaticDirs: []string{root}}
+	siteRoot := config.string("baseurl")
+	permalink := options.Permalink
+	if permalink == "" {
+		permalink = config.string("permalink")
+	}
+	if style, ok := jekyllPermalinkStyles[permalink]; ok || permalink == "" {
+		if permalink == "" {
+			style = jekyllPermalinkStyles["date"]
+		}
+		permalink = style
+	}
+
+	for _, name := range archive.documents(root) {
+		relative := strings.TrimPrefix(name, root)
+		var draft bool
+		var dirCategories []string
+		switch {
+		case strings.HasPrefix(relative, "_drafts/"):
+			draft = true
+		case strings.HasPrefix(relative, "_posts/"):
+		case strings.Contains(relative, "/_posts/"):
+			dirCategories = strings.Split(relative[:strings.Index(relative, "/_posts/")], "/")
+		default:
+			continue
+		}
+
+		post := &Post{File: name, AssetDirs: []string{path.Dir(name)}}
+		site.Posts = append(site.Posts, post)
+		matter, body, err := archive.document(name)
+		if err != nil {
+			post.Err = err
+			continue
+		}
+
+		// 文件名中的日期和标题，草稿没有日期
+		title := trimExt(path.Base(name))
+		var fileDate time.Time
+		if groups := jekyllPostName.FindStringSubmatch(title); groups != nil {
+			fileDate, _ = time.ParseInLocation(time.DateOnly, groups[1], options.Location)
+			title = groups[2]
+		}
+		if slug := matter.string("slug"); slug != "" {
+			title = slug
+		}
+
+		post.Title = matter.string("title")
+		post.Slug = utils.Slugify(title)
+		post.Summary = matter.string("excerpt", "description", "summary")
+		post.Cover = matter.string("image", "cover", "feature_image", "thumbnail")
+		if post.Cover == "" {
+			post.Cover = matter.field("image").string("path", "feature")
+		}
+		// categories 和 tags 可以写成空格分隔的字符串
+		post.Categories = append(dirCategories, jekyllWords(matter, "categories", "category")...)
+		post.Tags = jekyllWords(matter, "tags", "tag")
+		post.Date = matter.time(options.Location, "date")
+		if post.Date.IsZero() {
+			post.Date = fileDate
+		}
+		post.Updated = matter.time(options.Location, "last_modified_at", "updated")
+		post.Content = convertJekyllLiquid(body)
+		published, ok := matter.bool("published")
+		post.Draft = draft || (ok && !published)
+		if post.Draft {
+			continue
+		}
+
+		values := make(map[string]string)
+		dateValues(post.Date, values)
+		categories := make([]string, 0, len(post.Categories))
+		for _, category := range post.Categories {
+			categories = append(categories, strings.ToLower(category))
+		}
+		values["categories"] = strings.Join(categories, "/")
+		values["title"] = title
+		values["slug"] = title
+		values["output_ext"] = ".html"
+		link := matter.string("permalink")
+		if link == "" {
+			link = permalink
+		}
+		if link = permalinkPath(siteRoot, link, values); link != "" {
+			post.URLs = append(post.URLs, link)
+		}
+	}
+	return site, nil
+}
+
+// jekyllWords 读取列表字段，字符串按空格拆分
+func jekyllWords(matter fields, keys ...string) []string {
+	for _, key := range keys {
+		if text, ok := matter[key].(string); ok {
+			if words := strings.Fields(text); len(words) > 0 {
+				return words
+			}
+			continue
+		}
+		if values := matter.list(key); len(values) > 0 {
+			return values
+		}
+	}
+	return nil
+}
+
+// convertJekyllLiquid 去掉图片链接中的站点地址变量，使链接变为以 / 开头的站内路径
+func convertJekyllLiquid(content string) string {
+	content = jekyllURLFilter.ReplaceAllString(content, "$1")
+	return jekyllSiteVariable.ReplaceAllString(content, "")
+}
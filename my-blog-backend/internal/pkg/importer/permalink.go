@@ -0,0 +1,131 @@
+package importer
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// expandPermalink 替换链接格式中的 :name 占位符，未知的占位符原样保留
+func expandPermalink(pattern string, values map[string]string) string {
+	var builder strings.Builder
+	for i := 0; i < len(pattern); {
+		if pattern[i] != ':' {
+			builder.WriteByte(pattern[i])
+			i++
+			continue
+		}
+		end := i + 1
+		for end < len(pattern) && (pattern[end] == '_' || isASCIILetter(pattern[end])) {
+			end++
+		}
+		// 取最长的已知占位符，如 :title.html 中的 :title
+		matched := false
+		for ; end > i+1; end-- {
+			if value, ok := values[pattern[i+1:end]]; ok {
+				builder.WriteString(value)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			builder.WriteByte(':')
+			end = i + 1
+		}
+		i = end
+	}
+	return builder.String()
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// sitePath 拼接站点根路径和文章路径，合并重复的 /，保留末尾的 /
+func sitePath(root, link string) string {
+	if link == "" {
+		return ""
+	}
+	joined := "/" + strings.Trim(root, "/") + "/" + strings.TrimPrefix(link, "/")
+	cleaned := path.Clean(joined)
+	if strings.HasSuffix(link, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// basePath 返回站点地址中的路径部分，如 https://example.com/blog/ 的 /blog
+func basePath(siteURL string) string {
+	parsed, err := url.Parse(siteURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(parsed.Path, "/")
+}
+
+// slugize 按静态博客生成器的规则生成链接片段：空白和标点替换为 -，保留字母（含中文）、数字和 _
+func slugize(text string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range strings.TrimSpace(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			if dash && builder.Len() > 0 {
+				builder.WriteByte('-')
+			}
+			builder.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return builder.String()
+}
+
+// trimExt 去掉文件扩展名
+func trimExt(name string) string {
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// dateValues 链接格式中的日期占位符，时间为零值时不提供，含日期占位符的链接无法生成
+func dateValues(t time.Time, values map[string]string) {
+	if t.IsZero() {
+		return
+	}
+	values["year"] = t.Format("2006")
+	values["short_year"] = t.Format("06")
+	values["month"] = t.Format("01")
+	values["i_month"] = strconv.Itoa(int(t.Month()))
+	values["monthname"] = strings.ToLower(t.Month().String())
+	values["day"] = t.Format("02")
+	values["i_day"] = strconv.Itoa(t.Day())
+	values["y_day"] = fmt.Sprintf("%03d", t.YearDay())
+	values["hour"] = t.Format("15")
+	values["minute"] = t.Format("04")
+	values["second"] = t.Format("05")
+}
+
+// permalinkPath 按链接格式生成文章路径，有无法替换的占位符时返回空字符串
+func permalinkPath(root, pattern string, values map[string]string) string {
+	link := expandPermalink(pattern, values)
+	for i := 0; i+1 < len(link); i++ {
+		if link[i] == ':' && isASCIILetter(link[i+1]) {
+			return ""
+		}
+	}
+	return sitePath(root, link)
+}
+
+// scalarValues 返回 front matter 中的字符串和数字字段，Hexo 等系统允许在链接格式中引用自定义字段
+func scalarValues(matter fields) map[string]string {
+	values := make(map[string]string, len(matter))
+	for key := range matter {
+		if value := matter.string(key); value != "" {
+			values[key] = value
+		}
+	}
+	return values
+}
@@ -0,0 +1,202 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"my-blog-backend/internal/pkg/utils"
+)
+
+// wordpressCaptionPattern [caption] 短代码，转换后只保留其中的图片和说明文字
+var wordpressCaptionPattern = regexp.MustCompile(`\[/?caption[^\]]*\]`)
+
+// wxr WordPress 导出文件（WXR），只解析导入需要的字段；
+// 字段名不带命名空间，兼容不同版本的 wp 命名空间（export/1.0 ~ 1.2）
+type wxr struct {
+	Channel struct {
+		BaseBlogURL string    `xml:"base_blog_url"`
+		BaseSiteURL string    `xml:"base_site_url"`
+		Items       []wxrItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrItem struct {
+	Title         string        `xml:"title"`
+	Link          string        `xml:"link"`
+	Encoded       []wxrEncoded  `xml:"encoded"` // content:encoded 为正文，excerpt:encoded 为摘要
+	PostID        string        `xml:"post_id"`
+	PostDate      string        `xml:"post_date"`
+	PostDateGMT   string        `xml:"post_date_gmt"`
+	PostModified  string        `xml:"post_modified"`
+	PostName      string        `xml:"post_name"`
+	Status        string        `xml:"status"`
+	PostType      string        `xml:"post_type"`
+	AttachmentURL string        `xml:"attachment_url"`
+	Categories    []wxrCategory `xml:"category"`
+	PostMeta      []struct {
+		Key   string `xml:"meta_key"`
+		Value string `xml:"meta_value"`
+	} `xml:"postmeta"`
+	Comments []wxrComment `xml:"comment"`
+}
+
+type wxrEncoded struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type wxrCategory struct {
+	Domain string `xml:"domain,attr"` // category 或 post_tag
+	Value  string `xml:",chardata"`
+}
+
+type wxrComment struct {
+	ID       string `xml:"comment_id"`
+	Author   string `xml:"comment_author"`
+	Email    string `xml:"comment_author_email"`
+	URL      string `xml:"comment_author_url"`
+	IP       string `xml:"comment_author_IP"`
+	Date     string `xml:"comment_date"`
+	DateGMT  string `xml:"comment_date_gmt"`
+	Content  string `xml:"comment_content"`
+	Approved string `xml:"comment_approved"` // 1 已通过、0 待审核、spam、trash
+	Type     string `xml:"comment_type"`     // 空或 comment 为普通评论，pingback、trackback 不导入
+	Parent   string `xml:"comment_parent"`   // 0 表示顶级评论
+}
+
+// ReadWordPress 读取 WordPress 导出的 WXR 文件，只导入文章（post），不导入页面和附件；
+// 正文保留为 HTML（Markdown 兼容 HTML），评论一并导入，pingback 和垃圾评论除外
+func ReadWordPress(r io.Reader, options Options) (*Site, error) {
+	var export wxr
+	if err := xml.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("无效的 WXR 文件: %w", err)
+	}
+
+	site := &Site{URL: export.Channel.BaseBlogURL}
+	if site.URL == "" {
+		site.URL = export.Channel.BaseSiteURL
+	}
+	// 特色图片通过 _thumbnail_id 引用附件
+	attachments := make(map[string]string)
+	for _, item := range export.Channel.Items {
+		if item.PostType == "attachment" && item.AttachmentURL != "" {
+			attachments[item.PostID] = item.AttachmentURL
+		}
+	}
+
+	for _, item := range export.Channel.Items {
+		if item.PostType != "post" || item.Status == "trash" || item.Status == "auto-draft" {
+			continue
+		}
+		post := &Post{
+			File:    "wordpress/" + item.PostID,
+			Title:   strings.TrimSpace(item.Title),
+			Draft:   item.Status != "publish" && item.Status != "future",
+			Date:    wordpressTime(item.PostDateGMT, item.PostDate, options.Location),
+			Updated: wordpressTime("", item.PostModified, options.Location),
+		}
+		site.Posts = append(site.Posts, post)
+
+		for _, encoded := range item.Encoded {
+			switch {
+			case strings.Contains(encoded.XMLName.Space, "excerpt"):
+				post.Summary = strings.TrimSpace(encoded.Value)
+			default:
+				post.Content = wordpressCaptionPattern.ReplaceAllString(encoded.Value, "")
+			}
+		}
+
+		// post_name 中的中文等字符经过百分号编码
+		if name, err := url.PathUnescape(item.PostName); err == nil {
+			post.Slug = utils.Slugify(name)
+		}
+		if post.Slug == "" {
+			post.Slug = utils.Slugify(post.Title)
+		}
+		if post.Slug == "" {
+			post.Slug = "post-" + item.PostID
+		}
+
+		for _, category := range item.Categories {
+			name := strings.TrimSpace(category.Value)
+			switch {
+			case name == "":
+			case category.Domain == "category":
+				post.Categories = append(post.Categories, name)
+			case category.Domain == "post_tag":
+				post.Tags = append(post.Tags, name)
+			}
+		}
+		// WordPress 未分类的文章归入默认分类 Uncategorized，不作为分类导入
+		if len(post.Categories) == 1 && strings.EqualFold(post.Categories[0], "uncategorized") {
+			post.Categories = nil
+		}
+
+		for _, meta := range item.PostMeta {
+			if meta.Key == "_thumbnail_id" {
+				post.Cover = attachments[meta.Value]
+			}
+		}
+
+		if !post.Draft {
+			if link, err := url.Parse(item.Link); err == nil && link.Path != "" && link.Path != "/" {
+				post.URLs = append(post.URLs, link.Path)
+			}
+		}
+
+		for _, comment := range item.Comments {
+			if (comment.Type != "" && comment.Type != "comment") || (comment.Approved != "1" && comment.Approved != "0") {
+				continue
+			}
+			parentID := comment.Parent
+			if parentID == "0" {
+				parentID = ""
+			}
+			post.Comments = append(post.Comments, &Comment{
+				ID:       comment.ID,
+				ParentID: parentID,
+				Author:   strings.TrimSpace(comment.Author),
+				Email:    strings.TrimSpace(comment.Email),
+				URL:      strings.TrimSpace(comment.URL),
+				IP:       strings.TrimSpace(comment.IP),
+				Content:  strings.TrimSpace(comment.Content),
+				Date:     wordpressTime(comment.DateGMT, comment.Date, options.Location),
+				Approved: comment.Approved == "1",
+			})
+		}
+		// 父评论在前，导入时才能找到父评论
+		sort.SliceStable(post.Comments, func(i, j int) bool {
+			return commentOrder(post.Comments[i]) < commentOrder(post.Comments[j])
+		})
+	}
+
+	sort.SliceStable(site.Posts, func(i, j int) bool { return site.Posts[i].Date.Before(site.Posts[j].Date) })
+	return site, nil
+}
+
+// wordpressTime 优先使用 UTC 时间，草稿的 UTC 时间为 0000-00-00 00:00:00，此时使用站点时间
+func wordpressTime(gmt, local string, location *time.Location) time.Time {
+	if t, err := time.Parse(time.DateTime, gmt); err == nil && t.Year() > 1 {
+		return t.In(location)
+	}
+	if t, err := time.ParseInLocation(time.DateTime, local, location); err == nil && t.Year() > 1 {
+		return t
+	}
+	return time.Time{}
+}
+
+// commentOrder 评论 ID 递增，按 ID 排序即可保证父评论在前
+func commentOrder(comment *Comment) int {
+	id, err := strconv.Atoi(comment.ID)
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,106 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// Version 渲染规则版本，修改渲染规则或扩展后递增，使按内容缓存的结果失效
+const Version = 1
+
+// Options 渲染选项
+type Options struct {
+	HighlightStyle    string // 代码高亮样式名称，见 chroma styles
+	WordsPerMinute    int    // 英文等按词计数文字的阅读速度
+	CJKCharsPerMinute int    // 中日韩文字的阅读速度
+}
+
+// Result 渲染结果
+type Result struct {
+	HTML        string     `json:"html"`         // 经过过滤的 HTML
+	TOC         []*TOCItem `json:"toc"`          // 标题目录，按层级嵌套
+	WordCount   int        `json:"word_count"`   // 字数，中日韩文字按字计数，其他按词计数，不含代码块和公式
+	ReadingTime int        `json:"reading_time"` // 预计阅读时间（分钟）
+}
+
+// Renderer Markdown 渲染器，支持 GFM（表格、任务列表、删除线、自动链接）、脚注、
+// 数学公式和 mermaid 透传、代码高亮，可并发使用
+type Renderer struct {
+	md      goldmark.Markdown
+	policy  *bluemonday.Policy
+	style   *chroma.Style
+	options Options
+}
+
+// New 创建渲染器
+func New(options Options) *Renderer {
+	if options.WordsPerMinute <= 0 {
+		options.WordsPerMinute = 200
+	}
+	if options.CJKCharsPerMinute <= 0 {
+		options.CJKCharsPerMinute = 400
+	}
+	style := styles.Get(options.HighlightStyle)
+
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			// 即 extension.GFM，表格对齐使用 align 属性，过滤时无需放行 style
+			extension.Linkify,
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+			extension.Strikethrough,
+			extension.TaskList,
+			extension.Footnote,
+			Math,
+			Mermaid,
+			highlighting.NewHighlighting(
+				highlighting.WithCustomStyle(style),
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		// 允许正文中的原始 HTML，输出前统一过滤
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	return &Renderer{md: md, policy: newPolicy(), style: style, options: options}
+}
+
+// Render 将 Markdown 渲染为 HTML，并生成目录、字数和阅读时间
+func (r *Renderer) Render(source string) (*Result, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := r.md.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := r.md.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, err
+	}
+
+	words := countWords(doc, src)
+	return &Result{
+		HTML:        r.policy.Sanitize(buf.String()),
+		TOC:         buildTOC(doc, src),
+		WordCount:   words.total(),
+		ReadingTime: words.minutes(r.options.WordsPerMinute, r.options.CJKCharsPerMinute),
+	}, nil
+}
+
+// HighlightCSS 返回代码高亮样式表，高亮结果使用 CSS 类名输出
+func (r *Renderer) HighlightCSS() (string, error) {
+	var builder strings.Builder
+	if err := chromahtml.New(chromahtml.WithClasses(true)).WriteCSS(&builder, r.style); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
@@ -0,0 +1,135 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"my-blog-backend/internal/pkg/markdown"
+)
+
+func render(t *testing.T, source string) *markdown.Result {
+	t.Helper()
+	result, err := markdown.New(markdown.Options{HighlightStyle: "github"}).Render(source)
+	if err != nil {
+		t.Fatalf("渲染失败: %v", err)
+	}
+	return result
+}
+
+func TestRenderSanitizesHTML(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		contains  []string
+		forbidden []string
+	}{
+		{
+			name:      "script 标签",
+			source:    "<script>alert(1)</script>\n\n正文",
+			contains:  []string{"<p>正文</p>"},
+			forbidden: []string{"<script", "alert(1)"},
+		},
+		{
+			name:      "javascript 链接",
+			source:    "[点击](javascript:alert(1)) <a href=\"JavaScript:alert(2)\">链接</a>",
+			contains:  []string{"点击", "链接"},
+			forbidden: []string{"javascript:", "JavaScript:", "href"},
+		},
+		{
+			name:      "允许标签上的事件属性",
+			source:    "<div class=\"note\" onmouseover=\"x()\">提示</div>\n\n<img src=\"/a.png\" onerror=\"alert(1)\"> <a href=\"/b\" onclick=\"x()\">b</a>",
+			contains:  []string{`<div class="note">提示</div>`, `<img src="/a.png">`, `href="/b"`},
+			forbidden: []string{"onmouseover", "onerror", "onclick"},
+		},
+		{
+			name:      "style 属性",
+			source:    "<span style=\"background:url(javascript:alert(1))\">文字</span>",
+			contains:  []string{"文字"},
+			forbidden: []string{"style", "javascript:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := render(t, tt.source).HTML
+			for _, want := range tt.contains {
+				if !strings.Contains(html, want) {
+					t.Errorf("输出应包含 %q: %s", want, html)
+				}
+			}
+			for _, bad := range tt.forbidden {
+				if strings.Contains(html, bad) {
+					t.Errorf("输出不应包含 %q: %s", bad, html)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderTOC(t *testing.T) {
+	result := render(t, "# Hello World\n\n## Hello World\n\n### 中文 标题\n\n## `code` title\n\n#### 跳级标题\n\n# 第二章")
+
+	for _, want := range []string{
+		`<h1 id="hello-world">`,
+		`<h2 id="hello-world-1">`,
+		`<h3 id="中文-标题">`,
+		`<h2 id="code-title">`,
+		`<h4 id="跳级标题">`,
+		`<h1 id="第二章">`,
+	} {
+		if !strings.Contains(result.HTML, want) {
+			t.Errorf("标题锚点应包含 %q: %s", want, result.HTML)
+		}
+	}
+
+	if len(result.TOC) != 2 {
+		t.Fatalf("顶层目录应有 2 项, 实际 %d", len(result.TOC))
+	}
+	first := result.TOC[0]
+	if first.ID != "hello-world" || len(first.Children) != 2 {
+		t.Fatalf("第一章 = %+v", first)
+	}
+	tests := []struct {
+		item  *markdown.TOCItem
+		level int
+		text  string
+		id    string
+	}{
+		{first.Children[0], 2, "Hello World", "hello-world-1"},
+		{first.Children[0].Children[0], 3, "中文 标题", "中文-标题"},
+		{first.Children[1], 2, "code title", "code-title"},
+		// 跳级的标题挂在最近的上级标题下
+		{first.Children[1].Children[0], 4, "跳级标题", "跳级标题"},
+		{result.TOC[1], 1, "第二章", "第二章"},
+	}
+	for _, tt := range tests {
+		if tt.item.Level != tt.level || tt.item.Text != tt.text || tt.item.ID != tt.id {
+			t.Errorf("目录项 = %+v, 期望 level=%d text=%q id=%q", tt.item, tt.level, tt.text, tt.id)
+		}
+	}
+}
+
+func TestRenderPassthroughBlocks(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"mermaid 代码块", "```mermaid\ngraph TD\n  A-->B <script>\n```", "<pre class=\"mermaid\">graph TD\n  A--&gt;B &lt;script&gt;\n</pre>"},
+		{"公式块", "$$\n\\frac{a}{b} < c\n$$", `<div class="math math-display">\[\frac{a}{b} &lt; c\]</div>`},
+		{"单行公式块", "$$ E = mc^2 $$", `<div class="math math-display">\[E = mc^2\]</div>`},
+		{"math 代码块", "```math\na<b\n```", `<div class="math math-display">\[a&lt;b\]</div>`},
+		{"行内公式", "面积 $\\pi r^2$ 和 $$x$$", `<span class="math math-inline">\(\pi r^2\)</span> 和 <span class="math math-display">\[x\]</span>`},
+		{"金额不是公式", "价格 $5 和 $10", "<p>价格 $5 和 $10</p>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := render(t, tt.source).HTML
+			if !strings.Contains(html, tt.want) {
+				t.Errorf("输出应包含 %q: %s", tt.want, html)
+			}
+			if strings.Contains(html, "chroma") {
+				t.Errorf("透传内容不应经过代码高亮: %s", html)
+			}
+		})
+	}
+}
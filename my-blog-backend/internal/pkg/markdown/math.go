@@ -0,0 +1,184 @@
+package markdown
+
+import (
+	"bytes"
+	"html"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// 数学公式只做透传：服务端不排版，输出带 math 类名和 \( \) 、\[ \] 定界符的元素，由前端 KaTeX/MathJax 渲染。
+// 支持行内 $...$、行内 $$...$$、独立成行的 $$ 公式块和 ```math 代码块
+
+var (
+	KindMath      = ast.NewNodeKind("Math")
+	KindMathBlock = ast.NewNodeKind("MathBlock")
+)
+
+// MathInline 行内公式
+type MathInline struct {
+	ast.BaseInline
+	Display bool
+	Value   []byte
+}
+
+func (n *MathInline) Kind() ast.NodeKind { return KindMath }
+
+func (n *MathInline) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Value": string(n.Value)}, nil)
+}
+
+// MathBlock 公式块
+type MathBlock struct {
+	ast.BaseBlock
+	closed bool // 单行公式块在 Open 时已经结束
+}
+
+func (n *MathBlock) Kind() ast.NodeKind { return KindMathBlock }
+
+func (n *MathBlock) IsRaw() bool { return true }
+
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+type mathBlockParser struct{}
+
+func (p *mathBlockParser) Trigger() []byte { return []byte{'$'} }
+
+func (p *mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], []byte("$$")) {
+		return nil, parser.NoChildren
+	}
+	node := &MathBlock{}
+	start := segment.Start + pos + 2
+	rest := util.TrimRightSpace(line[pos+2:])
+	// $$ 公式 $$ 写在同一行
+	if len(rest) >= 2 && bytes.HasSuffix(rest, []byte("$$")) {
+		node.Lines().Append(text.NewSegment(start, start+len(rest)-2))
+		node.closed = true
+		return node, parser.NoChildren
+	}
+	if len(util.TrimLeftSpace(rest)) > 0 {
+		node.Lines().Append(text.NewSegment(start, segment.Stop))
+	}
+	return node, parser.NoChildren
+}
+
+func (p *mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	if node.(*MathBlock).closed {
+		return parser.Close
+	}
+	line, segment := reader.PeekLine()
+	trimmed := util.TrimRightSpace(util.TrimLeftSpace(line))
+	if bytes.HasSuffix(trimmed, []byte("$$")) {
+		if content := trimmed[:len(trimmed)-2]; len(content) > 0 {
+			start := segment.Start + bytes.Index(line, content)
+			node.Lines().Append(text.NewSegment(start, start+len(content)))
+		}
+		reader.Advance(segment.Len() - 1)
+		return parser.Close
+	}
+	node.Lines().Append(segment)
+	reader.Advance(segment.Len() - 1)
+	return parser.Continue | parser.NoChildren
+}
+
+func (p *mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (p *mathBlockParser) CanInterruptParagraph() bool { return true }
+
+func (p *mathBlockParser) CanAcceptIndentedLine() bool { return false }
+
+type mathInlineParser struct{}
+
+func (p *mathInlineParser) Trigger() []byte { return []byte{'$'} }
+
+func (p *mathInlineParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	if len(line) < 3 {
+		return nil
+	}
+
+	if line[1] == '$' {
+		end := bytes.Index(line[2:], []byte("$$"))
+		if end <= 0 {
+			return nil
+		}
+		block.Advance(end + 4)
+		return &MathInline{Display: true, Value: line[2 : end+2]}
+	}
+
+	// $ 后不能是空白，结束的 $ 前不能是空白、后面不能紧跟数字，避免把 "$5 和 $10" 当作公式
+	if isSpace(line[1]) {
+		return nil
+	}
+	for i := 2; i < len(line); i++ {
+		switch {
+		case line[i] == '\\', line[i] == '$' && i+1 < len(line) && line[i+1] == '$':
+			i++
+		case line[i] == '$' && !isSpace(line[i-1]) && (i+1 >= len(line) || !isDigit(line[i+1])):
+			block.Advance(i + 1)
+			return &MathInline{Value: line[1:i]}
+		}
+	}
+	return nil
+}
+
+type mathRenderer struct{}
+
+func (r *mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMath, r.renderInline)
+	reg.Register(KindMathBlock, r.renderBlock)
+}
+
+func (r *mathRenderer) renderInline(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*MathInline)
+	value := html.EscapeString(string(n.Value))
+	if n.Display {
+		_, _ = w.WriteString(`<span class="math math-display">\[` + value + `\]</span>`)
+	} else {
+		_, _ = w.WriteString(`<span class="math math-inline">\(` + value + `\)</span>`)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *mathRenderer) renderBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	value := bytes.TrimSpace(node.Lines().Value(source))
+	_, _ = w.WriteString(`<div class="math math-display">\[` + html.EscapeString(string(value)) + "\\]</div>\n")
+	return ast.WalkSkipChildren, nil
+}
+
+type mathExtension struct{}
+
+// Math 数学公式透传扩展
+var Math goldmark.Extender = &mathExtension{}
+
+func (e *mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&mathBlockParser{}, 750)),
+		parser.WithInlineParsers(util.Prioritized(&mathInlineParser{}, 150)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&mathRenderer{}, 500)))
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
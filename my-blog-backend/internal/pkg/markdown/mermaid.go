@@ -0,0 +1,80 @@
+package markdown
+
+import (
+	"html"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// KindMermaid mermaid 图表节点
+var KindMermaid = ast.NewNodeKind("Mermaid")
+
+// MermaidBlock mermaid 图表，原样输出为 <pre class="mermaid">，由前端渲染
+type MermaidBlock struct {
+	ast.BaseBlock
+}
+
+func (n *MermaidBlock) Kind() ast.NodeKind { return KindMermaid }
+
+func (n *MermaidBlock) IsRaw() bool { return true }
+
+func (n *MermaidBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// fencedBlockTransformer 将语言为 mermaid、math 的代码块替换为对应节点，避免被代码高亮处理
+type fencedBlockTransformer struct{}
+
+func (t *fencedBlockTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var blocks []*ast.FencedCodeBlock
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if block, ok := node.(*ast.FencedCodeBlock); ok && entering {
+			if lang := string(block.Language(source)); lang == "mermaid" || lang == "math" {
+				blocks = append(blocks, block)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, block := range blocks {
+		var replacement ast.Node
+		if string(block.Language(source)) == "mermaid" {
+			replacement = &MermaidBlock{}
+		} else {
+			replacement = &MathBlock{}
+		}
+		replacement.SetLines(block.Lines())
+		block.Parent().ReplaceChild(block.Parent(), block, replacement)
+	}
+}
+
+type mermaidRenderer struct{}
+
+func (r *mermaidRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMermaid, r.render)
+}
+
+func (r *mermaidRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(`<pre class="mermaid">` + html.EscapeString(string(node.Lines().Value(source))) + "</pre>\n")
+	return ast.WalkSkipChildren, nil
+}
+
+type mermaidExtension struct{}
+
+// Mermaid mermaid 图表与 ```math 代码块透传扩展
+var Mermaid goldmark.Extender = &mermaidExtension{}
+
+func (e *mermaidExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(util.Prioritized(&fencedBlockTransformer{}, 100)))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&mermaidRenderer{}, 500)))
+}
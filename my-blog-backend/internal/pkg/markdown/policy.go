@@ -0,0 +1,27 @@
+package markdown
+
+import (
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+var (
+	// classPattern 代码高亮、公式、图表、脚注使用的类名
+	classPattern = regexp.MustCompile(`^[\w\- ]+$`)
+	// idPattern 标题锚点可能包含中文等 Unicode 字母
+	idPattern = regexp.MustCompile(`^[\p{L}\p{N}_:.\-]+$`)
+)
+
+// newPolicy 在 UGC 策略的基础上放行渲染器自身输出需要的属性
+func newPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(classPattern).OnElements("pre", "code", "span", "div", "a", "sup", "li", "ol", "ul", "input")
+	policy.AllowAttrs("id").Matching(idPattern).OnElements("h1", "h2", "h3", "h4", "h5", "h6", "sup", "li")
+	policy.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-[a-z]+$`)).OnElements("a", "div", "sup")
+	policy.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	// 任务列表
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	return policy
+}
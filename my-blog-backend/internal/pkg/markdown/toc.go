@@ -0,0 +1,177 @@
+package markdown
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// TOCItem 目录项
+type TOCItem struct {
+	Level    int        `json:"level"`
+	Text     string     `json:"text"`
+	ID       string     `json:"id"` // 标题锚点，对应 HTML 中标题元素的 id
+	Children []*TOCItem `json:"children,omitempty"`
+}
+
+// buildTOC 按标题层级生成嵌套目录，跳级的标题挂在最近的上级标题下
+func buildTOC(doc ast.Node, source []byte) []*TOCItem {
+	toc := make([]*TOCItem, 0)
+	var stack []*TOCItem
+	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
+		heading, ok := node.(*ast.Heading)
+		if !ok {
+			continue
+		}
+		item := &TOCItem{Level: heading.Level, Text: nodeText(heading, source)}
+		if id, ok := heading.AttributeString("id"); ok {
+			if value, ok := id.([]byte); ok {
+				item.ID = string(value)
+			}
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= item.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			toc = append(toc, item)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, item)
+		}
+		stack = append(stack, item)
+	}
+	return toc
+}
+
+// nodeText 返回节点的纯文本内容
+func nodeText(node ast.Node, source []byte) string {
+	var buf bytes.Buffer
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Text:
+			buf.Write(n.Value(source))
+			if n.SoftLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(n.Value)
+		case *MathInline:
+			buf.Write(n.Value)
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(buf.String())
+}
+
+// wordStats 字数统计
+type wordStats struct {
+	words int // 按空白和标点分隔的词数
+	cjk   int // 中日韩文字数
+}
+
+func (s wordStats) total() int {
+	return s.words + s.cjk
+}
+
+// minutes 预计阅读时间，有内容时至少为 1 分钟
+func (s wordStats) minutes(wordsPerMinute, cjkPerMinute int) int {
+	if s.total() == 0 {
+		return 0
+	}
+	minutes := float64(s.words)/float64(wordsPerMinute) + float64(s.cjk)/float64(cjkPerMinute)
+	return max(1, int(math.Ceil(minutes)))
+}
+
+// countWords 统计正文字数，跳过代码块、公式、图表和原始 HTML
+func countWords(doc ast.Node, source []byte) wordStats {
+	var stats wordStats
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock, *ast.RawHTML, *MathBlock, *MathInline, *MermaidBlock:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			stats.add(n.Value(source))
+		case *ast.String:
+			stats.add(n.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return stats
+}
+
+func (s *wordStats) add(text []byte) {
+	inWord := false
+	for _, r := range string(text) {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			s.cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				s.words++
+				inWord = true
+			}
+		case inWord && (r == '\'' || r == '’' || r == '-'):
+			// don't、well-known 算作一个词
+		default:
+			inWord = false
+		}
+	}
+}
+
+// headingIDs 生成标题锚点，保留中日韩等 Unicode 字母，重复时追加 -1、-2 后缀
+type headingIDs struct {
+	used map[string]struct{}
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: make(map[string]struct{})}
+}
+
+func (s *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	var builder strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(string(value)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			if dash && builder.Len() > 0 {
+				builder.WriteByte('-')
+			}
+			builder.WriteRune(r)
+			dash = false
+		case unicode.IsSpace(r) || r == '-':
+			dash = true
+		}
+	}
+	id := builder.String()
+	if id == "" {
+		id = "heading"
+	}
+
+	unique := id
+	for i := 1; ; i++ {
+		if _, ok := s.used[unique]; !ok {
+			break
+		}
+		unique = id + "-" + strconv.Itoa(i)
+	}
+	s.used[unique] = struct{}{}
+	return []byte(unique)
+}
+
+func (s *headingIDs) Put(value []byte) {
+	s.used[string(value)] = struct{}{}
+}
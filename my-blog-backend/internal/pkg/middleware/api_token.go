@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"my-blog-backend/internal/pkg/session"
+)
+
+var apiTokenService APITokenService
+
+// APITokenService API Token 校验接口
+type APITokenService interface {
+	Authenticate(rawToken, clientIP string) (*session.SessionInfo, error)
+}
+
+// InitAPITokenService 初始化API Token服务
+func InitAPITokenService(service APITokenService) {
+	apiTokenService = service
+}
+
+// APITokenAuth 机器调用方认证中间件（Authorization: Bearer <token>）
+// 未携带 Token 时放行，由后续的 Session + Once-Token 中间件完成认证
+func APITokenAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.Next()
+			return
+		}
+
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" || apiTokenService == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    401,
+				"message": "缺少有效的API Token",
+			})
+			c.Abort()
+			return
+		}
+
+		info, err := apiTokenService.Authenticate(strings.TrimSpace(parts[1]), c.ClientIP())
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    401,
+				"message": "API Token无效或已过期",
+			})
+			c.Abort()
+			return
+		}
+
+		// 与 Session 认证写入相同的用户信息，下游 handler 无需区分
+		c.Set("user_id", info.UserID)
+		c.Set("username", info.Username)
+		c.Set("nickname", info.Nickname)
+		c.Set("role_ids", info.RoleIDs)
+		c.Set("dept_id", info.DeptID)
+		c.Set("session_info", info)
+		c.Set("api_token_auth", true)
+
+		c.Next()
+	}
+}
+
+// IsAPITokenAuth 当前请求是否已通过API Token认证
+func IsAPITokenAuth(c *gin.Context) bool {
+	return c.GetBool("api_token_auth")
+}
@@ -188,6 +188,23 @@ func AuthRequired() gin.HandlerFunc {
 	}
 }
 
+// OptionalAuth 博客前台可选认证，携带有效 token 时设置用户信息，未携带或无效时按游客继续处理
+func OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if userClaims, err := validateToken(parts[1]); err == nil {
+				c.Set("user_claims", userClaims)
+				c.Set("user_id", userClaims.UserID)
+				c.Set("username", userClaims.Username)
+				c.Set("email", userClaims.Email)
+				c.Set("role", userClaims.Role)
+			}
+		}
+		c.Next()
+	}
+}
+
 // 前台用户验证token
 func validateToken(tokenString string) (*claims.UserClaims, error) {
 	// 使用ParseWithClaims来解析到UserClaims结构
@@ -44,8 +44,14 @@ func GetTokenManager() token.Manager {
 }
 
 // OnceTokenMiddleware 一次性Token中间件
+// 机器调用方无法交互获取一次性Token，已通过 APITokenAuth 认证的请求直接放行
 func OnceTokenMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if IsAPITokenAuth(c) {
+			c.Next()
+			return
+		}
+
 		tokenID := c.Query("once_token")
 		if tokenID == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
@@ -76,8 +76,14 @@ func RequireAuth() gin.HandlerFunc {
 }
 
 // RBACAuth RBAC管理后台认证中间件（基于cookie session）
+// 已通过 APITokenAuth 认证的请求直接放行
 func RBACAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if IsAPITokenAuth(c) {
+			c.Next()
+			return
+		}
+
 		sessionID := sessionManager.GetCookie(c)
 
 		if sessionID == "" {
@@ -0,0 +1,60 @@
+package search
+
+import (
+	"gorm.io/gorm"
+)
+
+// publishedStatus 已发布文章的状态值
+const publishedStatus = 1
+
+// scoredRow 数据库引擎查询结果
+type scoredRow struct {
+	ID    uint64
+	Score float64
+}
+
+// filtered 构造数据库引擎的公共查询：只检索已发布文章，并应用分类、标签和发布时间过滤
+func filtered(db *gorm.DB, query *Query) *gorm.DB {
+	tx := db.Table("articles AS a").Where("a.status = ?", publishedStatus)
+	if query.CategoryID != 0 {
+		tx = tx.Where("a.category_id = ?", query.CategoryID)
+	}
+	if query.TagID != 0 {
+		tx = tx.Where("EXISTS (SELECT 1 FROM article_tag at WHERE at.article_id = a.id AND at.tag_id = ?)", query.TagID)
+	}
+	if query.From != nil {
+		tx = tx.Where("COALESCE(a.published_at, a.created_at) >= ?", *query.From)
+	}
+	if query.To != nil {
+		tx = tx.Where("COALESCE(a.published_at, a.created_at) < ?", *query.To)
+	}
+	return tx
+}
+
+// fetch 统计总数并按得分分页查询命中的文章
+func fetch(tx *gorm.DB, query *Query, score string, args ...interface{}) (*Result, error) {
+	tx = tx.Session(&gorm.Session{})
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	result := &Result{Total: total}
+	if total == 0 {
+		return result, nil
+	}
+
+	var rows []scoredRow
+	offset, size := query.offset()
+	err := tx.Select("a.id AS id, "+score+" AS score", args...).
+		Order("score DESC, COALESCE(a.published_at, a.created_at) DESC, a.id DESC").
+		Offset(offset).Limit(size).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result.Hits = append(result.Hits, Hit{ID: row.ID, Score: row.Score})
+	}
+	return result, nil
+}
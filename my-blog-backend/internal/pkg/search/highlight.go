@@ -0,0 +1,146 @@
+package search
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// 高亮标签
+const (
+	highlightOpen  = "<mark>"
+	highlightClose = "</mark>"
+)
+
+var (
+	mdCodeFence = regexp.MustCompile("(?m)^```.*$")
+	mdImage     = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink      = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	mdHTMLTag   = regexp.MustCompile(`<[^>]+>`)
+	mdSyntax    = regexp.MustCompile("(?m)^\\s*(#{1,6}|>|[-*+]|\\d+\\.)\\s+|[*_`~]{1,3}")
+	spaces      = regexp.MustCompile(`\s+`)
+)
+
+// Highlighter 根据查询关键词生成高亮文本
+type Highlighter struct {
+	terms [][]rune
+}
+
+// NewHighlighter 创建高亮器，优先匹配完整短语，其次匹配检索词
+func NewHighlighter(keyword string) *Highlighter {
+	h := &Highlighter{}
+	for _, term := range append(phrases(keyword), QueryTerms(keyword)...) {
+		h.terms = append(h.terms, []rune(term))
+	}
+	return h
+}
+
+// Highlight 高亮整段文本，结果已做 HTML 转义
+func (h *Highlighter) Highlight(text string) string {
+	runes := []rune(text)
+	return h.render(runes, h.mark(runes), 0, len(runes))
+}
+
+// Snippet 截取包含匹配词的片段并高亮，maxLen 为片段最大字符数
+// 正文为 Markdown 时先去除标记语法；没有匹配时返回开头部分
+func (h *Highlighter) Snippet(content string, maxLen int) string {
+	runes := []rune(PlainText(content))
+	marks := h.mark(runes)
+
+	start := 0
+	for i, marked := range marks {
+		if marked {
+			// 匹配词前保留少量上下文
+			start = i - maxLen/4
+			break
+		}
+	}
+	if start < 0 || len(runes) <= maxLen {
+		start = 0
+	}
+	end := start + maxLen
+	if end > len(runes) {
+		end = len(runes)
+		if start = end - maxLen; start < 0 {
+			start = 0
+		}
+	}
+
+	snippet := h.render(runes, marks, start, end)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// mark 标记文本中与检索词匹配的字符，忽略大小写，英文词需要在词边界上
+func (h *Highlighter) mark(runes []rune) []bool {
+	marks := make([]bool, len(runes))
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	for _, term := range h.terms {
+		wordTerm := !isCJK(term[0])
+		for i := 0; i+len(term) <= len(lower); i++ {
+			if !equalRunes(lower[i:i+len(term)], term) {
+				continue
+			}
+			if wordTerm && (i > 0 && isWordRune(lower[i-1]) || i+len(term) < len(lower) && isWordRune(lower[i+len(term)])) {
+				continue
+			}
+			for j := i; j < i+len(term); j++ {
+				marks[j] = true
+			}
+		}
+	}
+	return marks
+}
+
+// render 输出 [start, end) 区间的文本，连续标记的字符合并为一个高亮
+func (h *Highlighter) render(runes []rune, marks []bool, start, end int) string {
+	var b strings.Builder
+	for i := start; i < end; {
+		j := i
+		for j < end && marks[j] == marks[i] {
+			j++
+		}
+		segment := html.EscapeString(string(runes[i:j]))
+		if marks[i] {
+			b.WriteString(highlightOpen)
+			b.WriteString(segment)
+			b.WriteString(highlightClose)
+		} else {
+			b.WriteString(segment)
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// PlainText 去除 Markdown 和 HTML 标记，合并空白，用于生成摘要片段
+func PlainText(content string) string {
+	text := mdCodeFence.ReplaceAllString(content, "")
+	text = mdImage.ReplaceAllString(text, "$1")
+	text = mdLink.ReplaceAllString(text, "$1")
+	text = mdHTMLTag.ReplaceAllString(text, "")
+	text = mdSyntax.ReplaceAllString(text, "")
+	return strings.TrimSpace(spaces.ReplaceAllString(text, " "))
+}
+
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return !isCJK(r) && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,249 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// 字段编号
+const (
+	fieldTitle = iota
+	fieldTags
+	fieldSummary
+	fieldContent
+	fieldCount
+)
+
+// BM25 参数
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// memoryEngine 进程内倒排索引，使用 BM25F 计算相关度
+// 索引只保存词频和过滤字段，不保存正文，重启后需要调用 Rebuild 重建
+type memoryEngine struct {
+	mu       sync.RWMutex
+	boosts   [fieldCount]float64
+	docs     map[uint64]*memoryDoc
+	postings map[string]map[uint64]*[fieldCount]int // 词 -> 文章ID -> 各字段词频
+	totalLen [fieldCount]int                        // 各字段总词数，用于计算平均长度
+}
+
+type memoryDoc struct {
+	terms       []string // 文章包含的词，删除时清理倒排表
+	length      [fieldCount]int
+	tagIDs      []uint64
+	categoryID  uint64
+	publishedAt int64
+}
+
+// NewMemoryEngine 创建进程内检索引擎
+func NewMemoryEngine(boosts Boosts) Engine {
+	return &memoryEngine{
+		boosts:   [fieldCount]float64{boosts.Title, boosts.Tags, boosts.Summary, boosts.Content},
+		docs:     make(map[uint64]*memoryDoc),
+		postings: make(map[string]map[uint64]*[fieldCount]int),
+	}
+}
+
+func (e *memoryEngine) Index(doc *Document) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.remove(doc.ID)
+	e.add(doc)
+	return nil
+}
+
+func (e *memoryEngine) Delete(id uint64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.remove(id)
+	return nil
+}
+
+func (e *memoryEngine) Rebuild(docs []*Document) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.docs = make(map[uint64]*memoryDoc, len(docs))
+	e.postings = make(map[string]map[uint64]*[fieldCount]int)
+	e.totalLen = [fieldCount]int{}
+	for _, doc := range docs {
+		e.add(doc)
+	}
+	return nil
+}
+
+// add 写入文章索引，调用方需持有写锁
+func (e *memoryEngine) add(doc *Document) {
+	var tags string
+	for i, tag := range doc.Tags {
+		if i > 0 {
+			tags += " "
+		}
+		tags += tag
+	}
+	fields := [fieldCount][]string{
+		fieldTitle:   Tokenize(doc.Title),
+		fieldTags:    Tokenize(tags),
+		fieldSummary: Tokenize(doc.Summary),
+		fieldContent: Tokenize(doc.Content),
+	}
+
+	entry := &memoryDoc{
+		tagIDs:      append([]uint64(nil), doc.TagIDs...),
+		categoryID:  doc.CategoryID,
+		publishedAt: doc.PublishedAt.Unix(),
+	}
+	for field, tokens := range fields {
+		entry.length[field] = len(tokens)
+		e.totalLen[field] += len(tokens)
+		for _, token := range tokens {
+			docs, ok := e.postings[token]
+			if !ok {
+				docs = make(map[uint64]*[fieldCount]int)
+				e.postings[token] = docs
+			}
+			freq, ok := docs[doc.ID]
+			if !ok {
+				freq = &[fieldCount]int{}
+				docs[doc.ID] = freq
+				entry.terms = append(entry.terms, token)
+			}
+			freq[field]++
+		}
+	}
+	e.docs[doc.ID] = entry
+}
+
+// remove 删除文章索引，调用方需持有写锁
+func (e *memoryEngine) remove(id uint64) {
+	entry, ok := e.docs[id]
+	if !ok {
+		return
+	}
+	for _, term := range entry.terms {
+		docs := e.postings[term]
+		delete(docs, id)
+		if len(docs) == 0 {
+			delete(e.postings, term)
+		}
+	}
+	for field := range entry.length {
+		e.totalLen[field] -= entry.length[field]
+	}
+	delete(e.docs, id)
+}
+
+func (e *memoryEngine) Search(query *Query) (*Result, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	terms := QueryTerms(query.Keyword)
+	var hits []Hit
+	if len(terms) == 0 {
+		for id, doc := range e.docs {
+			if e.match(doc, query) {
+				hits = append(hits, Hit{ID: id})
+			}
+		}
+	} else {
+		hits = e.score(terms, query)
+	}
+
+	// 得分相同时较新的文章在前
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		a, b := e.docs[hits[i].ID].publishedAt, e.docs[hits[j].ID].publishedAt
+		if a != b {
+			return a > b
+		}
+		return hits[i].ID > hits[j].ID
+	})
+
+	result := &Result{Total: int64(len(hits))}
+	offset, size := query.offset()
+	if offset < len(hits) {
+		end := offset + size
+		if end > len(hits) {
+			end = len(hits)
+		}
+		result.Hits = hits[offset:end]
+	}
+	return result, nil
+}
+
+// score 计算同时包含全部检索词的文章得分
+func (e *memoryEngine) score(terms []string, query *Query) []Hit {
+	// 从文档数最少的词开始求交集
+	lists := make([]map[uint64]*[fieldCount]int, 0, len(terms))
+	for _, term := range terms {
+		docs, ok := e.postings[term]
+		if !ok {
+			return nil
+		}
+		lists = append(lists, docs)
+	}
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+
+	total := float64(len(e.docs))
+	var avgLen [fieldCount]float64
+	for field := range avgLen {
+		avgLen[field] = math.Max(float64(e.totalLen[field])/total, 1)
+	}
+
+	var hits []Hit
+candidates:
+	for id := range lists[0] {
+		doc := e.docs[id]
+		if !e.match(doc, query) {
+			continue
+		}
+		var score float64
+		for _, docs := range lists {
+			freq, ok := docs[id]
+			if !ok {
+				continue candidates
+			}
+			// BM25F：各字段按权重和长度归一化后累加词频
+			var tf float64
+			for field := 0; field < fieldCount; field++ {
+				if freq[field] == 0 {
+					continue
+				}
+				norm := 1 - bm25B + bm25B*float64(doc.length[field])/avgLen[field]
+				tf += e.boosts[field] * float64(freq[field]) / norm
+			}
+			n := float64(len(docs))
+			idf := math.Log(1 + (total-n+0.5)/(n+0.5))
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1)
+		}
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	return hits
+}
+
+// match 检查分类、标签和发布时间过滤条件
+func (e *memoryEngine) match(doc *memoryDoc, query *Query) bool {
+	if query.CategoryID != 0 && doc.categoryID != query.CategoryID {
+		return false
+	}
+	if query.From != nil && doc.publishedAt < query.From.Unix() {
+		return false
+	}
+	if query.To != nil && doc.publishedAt >= query.To.Unix() {
+		return false
+	}
+	if query.TagID != 0 {
+		for _, id := range doc.tagIDs {
+			if id == query.TagID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// mysqlEngine 基于 MySQL FULLTEXT 索引检索
+// 需要在 title、summary、content 上分别建立 ngram 全文索引（见 migrations），索引由 InnoDB 自动维护
+type mysqlEngine struct {
+	db     *gorm.DB
+	boosts Boosts
+}
+
+// NewMySQLEngine 创建 MySQL 全文检索引擎
+func NewMySQLEngine(db *gorm.DB, boosts Boosts) Engine {
+	return &mysqlEngine{db: db, boosts: boosts}
+}
+
+func (e *mysqlEngine) Index(doc *Document) error { return nil }
+
+func (e *mysqlEngine) Delete(id uint64) error { return nil }
+
+func (e *mysqlEngine) Rebuild(docs []*Document) error { return nil }
+
+func (e *mysqlEngine) Search(query *Query) (*Result, error) {
+	tx := filtered(e.db, query)
+	keyword := strings.TrimSpace(query.Keyword)
+	if keyword == "" {
+		return fetch(tx, query, "0")
+	}
+
+	// 标签名称较短，按关键词中的词精确匹配
+	tagNames := strings.Fields(keyword)
+	if len(tagNames) > 1 {
+		tagNames = append(tagNames, keyword)
+	}
+	const (
+		matchTitle   = "MATCH(a.title) AGAINST (? IN NATURAL LANGUAGE MODE)"
+		matchSummary = "MATCH(a.summary) AGAINST (? IN NATURAL LANGUAGE MODE)"
+		matchContent = "MATCH(a.content) AGAINST (? IN NATURAL LANGUAGE MODE)"
+		matchTags    = "EXISTS (SELECT 1 FROM article_tag at JOIN tag t ON t.id = at.tag_id WHERE at.article_id = a.id AND t.name IN ?)"
+	)
+	tx = tx.Where(matchTitle+" OR "+matchSummary+" OR "+matchContent+" OR "+matchTags,
+		keyword, keyword, keyword, tagNames)
+
+	score := fmt.Sprintf("%g * %s + %g * %s + %g * %s + %g * %s",
+		e.boosts.Title, matchTitle,
+		e.boosts.Summary, matchSummary,
+		e.boosts.Content, matchContent,
+		e.boosts.Tags, matchTags)
+	return fetch(tx, query, score, keyword, keyword, keyword, tagNames)
+}
@@ -0,0 +1,77 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// postgresEngine 基于 PostgreSQL tsvector 检索
+// 内置的 simple 分词配置不能切分中文，因此由本包分词后写入 articles.search_vector，
+// 标题、标签、摘要、正文分别使用 A、B、C、D 权重（见 migrations/postgresql）
+type postgresEngine struct {
+	db      *gorm.DB
+	weights string // ts_rank 权重数组，顺序为 {D,C,B,A}
+}
+
+const postgresVector = "setweight(to_tsvector('simple', ?), 'A') || " +
+	"setweight(to_tsvector('simple', ?), 'B') || " +
+	"setweight(to_tsvector('simple', ?), 'C') || " +
+	"setweight(to_tsvector('simple', ?), 'D')"
+
+// NewPostgresEngine 创建 PostgreSQL 全文检索引擎
+func NewPostgresEngine(db *gorm.DB, boosts Boosts) Engine {
+	// ts_rank 的权重不能大于 1，按最大值归一化
+	max := math.Max(math.Max(boosts.Title, boosts.Tags), math.Max(boosts.Summary, boosts.Content))
+	if max <= 0 {
+		max = 1
+	}
+	weights := fmt.Sprintf("{%g,%g,%g,%g}", boosts.Content/max, boosts.Summary/max, boosts.Tags/max, boosts.Title/max)
+	return &postgresEngine{db: db, weights: weights}
+}
+
+func (e *postgresEngine) Index(doc *Document) error {
+	return e.index(e.db, doc)
+}
+
+func (e *postgresEngine) index(db *gorm.DB, doc *Document) error {
+	return db.Exec("UPDATE articles SET search_vector = "+postgresVector+" WHERE id = ?",
+		strings.Join(Tokenize(doc.Title), " "),
+		strings.Join(Tokenize(strings.Join(doc.Tags, " ")), " "),
+		strings.Join(Tokenize(doc.Summary), " "),
+		strings.Join(Tokenize(doc.Content), " "),
+		doc.ID).Error
+}
+
+func (e *postgresEngine) Delete(id uint64) error {
+	return e.db.Exec("UPDATE articles SET search_vector = NULL WHERE id = ?", id).Error
+}
+
+func (e *postgresEngine) Rebuild(docs []*Document) error {
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("UPDATE articles SET search_vector = NULL").Error; err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			if err := e.index(tx, doc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (e *postgresEngine) Search(query *Query) (*Result, error) {
+	tx := filtered(e.db, query)
+	terms := QueryTerms(query.Keyword)
+	if len(terms) == 0 {
+		return fetch(tx, query, "0")
+	}
+
+	// 检索词只包含字母、数字和中日韩文字，可以直接拼接为 tsquery
+	tsquery := strings.Join(terms, " & ")
+	tx = tx.Where("a.search_vector @@ to_tsquery('simple', ?)", tsquery)
+	return fetch(tx, query, "ts_rank(?::float4[], a.search_vector, to_tsquery('simple', ?))", e.weights, tsquery)
+}
@@ -0,0 +1,101 @@
+// Package search 文章全文检索
+// 检索引擎可替换：默认使用进程内倒排索引，也可以使用 MySQL FULLTEXT 或 PostgreSQL tsvector
+// 引擎只负责返回命中的文章ID和得分，文章内容由调用方查询后再生成高亮摘要
+package search
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 引擎类型
+const (
+	EngineMemory   = "memory"
+	EngineMySQL    = "mysql"
+	EnginePostgres = "postgres"
+)
+
+// Engine 全文检索引擎
+type Engine interface {
+	// Index 新增或更新文章索引
+	Index(doc *Document) error
+	// Delete 删除文章索引，文章不存在时忽略
+	Delete(id uint64) error
+	// Rebuild 使用全部文章重建索引
+	Rebuild(docs []*Document) error
+	// Search 检索文章，按相关度排序
+	Search(query *Query) (*Result, error)
+}
+
+// Document 被索引的文章
+type Document struct {
+	ID          uint64
+	Title       string
+	Summary     string
+	Content     string
+	Tags        []string // 标签名称
+	TagIDs      []uint64
+	CategoryID  uint64
+	PublishedAt time.Time // 未发布时间时使用创建时间
+}
+
+// Query 检索条件，Keyword 为空时只按过滤条件查询并按发布时间排序
+type Query struct {
+	Keyword    string
+	CategoryID uint64
+	TagID      uint64
+	From       *time.Time // 发布时间下限（含）
+	To         *time.Time // 发布时间上限（不含）
+	Page       int
+	PageSize   int
+}
+
+// Hit 命中的文章
+type Hit struct {
+	ID    uint64
+	Score float64
+}
+
+// Result 检索结果
+type Result struct {
+	Total int64
+	Hits  []Hit
+}
+
+// Boosts 各字段的权重
+type Boosts struct {
+	Title   float64
+	Tags    float64
+	Summary float64
+	Content float64
+}
+
+// DefaultBoosts 默认字段权重：标题 > 标签 > 摘要 > 正文
+var DefaultBoosts = Boosts{Title: 3, Tags: 2.5, Summary: 1.5, Content: 1}
+
+// New 按类型创建检索引擎，数据库引擎需要先执行对应的迁移脚本创建索引
+func New(engine string, db *gorm.DB, boosts Boosts) (Engine, error) {
+	switch engine {
+	case "", EngineMemory:
+		return NewMemoryEngine(boosts), nil
+	case EngineMySQL:
+		return NewMySQLEngine(db, boosts), nil
+	case EnginePostgres, "postgresql":
+		return NewPostgresEngine(db, boosts), nil
+	}
+	return nil, fmt.Errorf("不支持的检索引擎: %s", engine)
+}
+
+// offset 计算分页偏移，页码和每页数量无效时使用默认值
+func (q *Query) offset() (int, int) {
+	page, size := q.Page, q.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+	return (page - 1) * size, size
+}
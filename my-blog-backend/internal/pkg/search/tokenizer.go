@@ -0,0 +1,98 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxWordLength 英文、数字词的最大长度，超出部分截断（如长哈希值、Base64）
+const maxWordLength = 64
+
+// Tokenize 将文本切分为索引词
+// 字母和数字连续组成一个词并转为小写；中日韩文字没有词边界，按二元组（bigram）切分，
+// 同时保留单字，使单字查询也能命中
+func Tokenize(text string) []string {
+	return tokenize(text, true)
+}
+
+// QueryTerms 将查询关键词切分为检索词，中日韩文字只在单字时使用单字，否则使用二元组
+func QueryTerms(keyword string) []string {
+	terms := tokenize(keyword, false)
+	seen := make(map[string]bool, len(terms))
+	unique := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
+
+func tokenize(text string, unigrams bool) []string {
+	var tokens []string
+	var word, cjk []rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			if len(word) > maxWordLength {
+				word = word[:maxWordLength]
+			}
+			tokens = append(tokens, string(word))
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		switch {
+		case len(cjk) == 1:
+			tokens = append(tokens, string(cjk))
+		case len(cjk) > 1:
+			for i := 0; i < len(cjk)-1; i++ {
+				if unigrams {
+					tokens = append(tokens, string(cjk[i]))
+				}
+				tokens = append(tokens, string(cjk[i:i+2]))
+			}
+			if unigrams {
+				tokens = append(tokens, string(cjk[len(cjk)-1]))
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, unicode.ToLower(r))
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return tokens
+}
+
+// isCJK 是否为没有词边界的中日韩文字
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// phrases 查询关键词中按空白分隔的短语（小写），用于高亮连续匹配
+func phrases(keyword string) []string {
+	var result []string
+	for _, field := range strings.Fields(strings.ToLower(keyword)) {
+		if len([]rune(field)) > 1 {
+			result = append(result, field)
+		}
+	}
+	return result
+}
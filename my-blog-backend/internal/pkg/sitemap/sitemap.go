@@ -0,0 +1,97 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"math"
+	"strconv"
+	"time"
+)
+
+// MaxURLs 单个站点地图允许的最大地址数，见 https://www.sitemaps.org/protocol.html
+const MaxURLs = 50000
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL 站点地图中的页面
+type URL struct {
+	Loc        string
+	LastMod    time.Time
+	ChangeFreq string  // always、hourly、daily、weekly、monthly、yearly、never，为空时不输出
+	Priority   float64 // 0.0 ~ 1.0，为 0 时不输出
+}
+
+// Index 站点地图索引中的子站点地图
+type Index struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name  `xml:"urlset"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	URLs    []urlNode `xml:"url"`
+}
+
+type urlNode struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name      `xml:"sitemapindex"`
+	Xmlns    string        `xml:"xmlns,attr"`
+	Sitemaps []sitemapNode `xml:"sitemap"`
+}
+
+type sitemapNode struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// RenderURLSet 生成站点地图
+func RenderURLSet(urls []URL) ([]byte, error) {
+	document := urlSet{Xmlns: namespace, URLs: make([]urlNode, 0, len(urls))}
+	for _, u := range urls {
+		node := urlNode{Loc: u.Loc, LastMod: lastMod(u.LastMod), ChangeFreq: u.ChangeFreq}
+		if u.Priority > 0 {
+			node.Priority = formatPriority(u.Priority)
+		}
+		document.URLs = append(document.URLs, node)
+	}
+	return marshal(document)
+}
+
+// RenderIndex 生成站点地图索引
+func RenderIndex(sitemaps []Index) ([]byte, error) {
+	document := sitemapIndex{Xmlns: namespace, Sitemaps: make([]sitemapNode, 0, len(sitemaps))}
+	for _, s := range sitemaps {
+		document.Sitemaps = append(document.Sitemaps, sitemapNode{Loc: s.Loc, LastMod: lastMod(s.LastMod)})
+	}
+	return marshal(document)
+}
+
+func marshal(document interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func lastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatPriority(priority float64) string {
+	return strconv.FormatFloat(math.Min(priority, 1), 'f', 1, 64)
+}
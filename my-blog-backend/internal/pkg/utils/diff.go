@@ -0,0 +1,269 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffLine 行级差异
+type DiffLine struct {
+	Op      DiffOp `json:"op"`
+	OldLine int    `json:"old_line,omitempty"` // 旧文本中的行号（从1开始，插入行为0）
+	NewLine int    `json:"new_line,omitempty"` // 新文本中的行号（从1开始，删除行为0）
+	Text    string `json:"text"`
+}
+
+// DiffLines 计算两段文本的行级差异（Myers 算法）
+func DiffLines(oldText, newText string) []DiffLine {
+	ops := diffTokens(splitLines(oldText), splitLines(newText))
+
+	lines := make([]DiffLine, 0, len(ops))
+	oldNo, newNo := 0, 0
+	for _, op := range ops {
+		line := DiffLine{Op: op.op, Text: op.text}
+		switch op.op {
+		case DiffEqual:
+			oldNo++
+			newNo++
+			line.OldLine, line.NewLine = oldNo, newNo
+		case DiffDelete:
+			oldNo++
+			line.OldLine = oldNo
+		case DiffInsert:
+			newNo++
+			line.NewLine = newNo
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// DiffStat 统计新增和删除的行数
+func DiffStat(lines []DiffLine) (added, removed int) {
+	for _, line := range lines {
+		switch line.Op {
+		case DiffInsert:
+			added++
+		case DiffDelete:
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// UnifiedDiff 以 +/- 前缀的文本形式输出差异，便于直接展示或下载
+func UnifiedDiff(oldName, newName string, lines []DiffLine) string {
+	var sb strings.Builder
+	sb.WriteString("--- " + oldName + "\n")
+	sb.WriteString("+++ " + newName + "\n")
+	for _, line := range lines {
+		switch line.Op {
+		case DiffEqual:
+			sb.WriteString(" ")
+		case DiffInsert:
+			sb.WriteString("+")
+		case DiffDelete:
+			sb.WriteString("-")
+		}
+		sb.WriteString(line.Text)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// DiffSegment 词级差异片段，连续的相同操作合并为一段
+type DiffSegment struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// MaxDiffWords 词级对比允许的最大词数（新旧文本合计），超出时返回 ErrDiffTooLarge
+const MaxDiffWords = 20000
+
+// ErrDiffTooLarge 文本过大，无法按词对比
+var ErrDiffTooLarge = errors.New("diff too large")
+
+// DiffWords 计算两段文本的词级差异
+// 英文、数字按单词切分，中日韩文字按单字切分，空白和标点单独成词
+// 新旧文本合计超过 MaxDiffWords 个词时返回 ErrDiffTooLarge，调用方可退回按行对比
+func DiffWords(oldText, newText string) ([]DiffSegment, error) {
+	oldWords, newWords := splitWords(oldText), splitWords(newText)
+	if len(oldWords)+len(newWords) > MaxDiffWords {
+		return nil, ErrDiffTooLarge
+	}
+	ops := diffTokens(oldWords, newWords)
+
+	var segments []DiffSegment
+	for _, op := range ops {
+		if n := len(segments); n > 0 && segments[n-1].Op == op.op {
+			segments[n-1].Text += op.text
+			continue
+		}
+		segments = append(segments, DiffSegment{Op: op.op, Text: op.text})
+	}
+	return segments, nil
+}
+
+func splitWords(text string) []string {
+	var words []string
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Han, r) || unicode.IsDigit(r) || r == '_' {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			words = append(words, text[start:i])
+			start = -1
+		}
+		words = append(words, string(r))
+	}
+	if start >= 0 {
+		words = append(words, text[start:])
+	}
+	return words
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+type diffOp struct {
+	op   DiffOp
+	text string
+}
+
+// diffTokens Myers O(ND) 差分算法（线性空间版本），返回按顺序排列的编辑操作
+// 通过中间蛇（middle snake）分治求解，内存占用与输入长度成正比，不随编辑距离增长
+func diffTokens(a, b []string) []diffOp {
+	if len(a)+len(b) == 0 {
+		return nil
+	}
+	size := len(a) + len(b) + 2
+	d := &differ{
+		a:  a,
+		b:  b,
+		vf: make([]int, 2*size+1),
+		vb: make([]int, 2*size+1),
+		o:  size,
+	}
+	d.ops = make([]diffOp, 0, len(a)+len(b))
+	d.diff(0, len(a), 0, len(b))
+	return d.ops
+}
+
+// differ 保存分治过程中共享的前向/反向搜索数组，避免每层递归重复分配
+type differ struct {
+	a, b   []string
+	vf, vb []int
+	o      int // 对角线 k 在 vf/vb 中的偏移
+	ops    []diffOp
+}
+
+// diff 计算 a[aLo:aHi] 与 b[bLo:bHi] 的差异并按顺序追加到 ops
+func (d *differ) diff(aLo, aHi, bLo, bHi int) {
+	// 去掉公共前缀
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.ops = append(d.ops, diffOp{op: DiffEqual, text: d.a[aLo]})
+		aLo++
+		bLo++
+	}
+	// 公共后缀在子问题处理完后追加
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+
+	switch {
+	case aLo == aHi:
+		for _, text := range d.b[bLo:bHi] {
+			d.ops = append(d.ops, diffOp{op: DiffInsert, text: text})
+		}
+	case bLo == bHi:
+		for _, text := range d.a[aLo:aHi] {
+			d.ops = append(d.ops, diffOp{op: DiffDelete, text: text})
+		}
+	default:
+		// 首尾均不相同且两侧非空时编辑距离至少为 2，两半的子问题编辑距离严格变小
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.diff(aLo, x, bLo, y)
+		for i := x; i < u; i++ {
+			d.ops = append(d.ops, diffOp{op: DiffEqual, text: d.a[i]})
+		}
+		d.diff(u, aHi, v, bHi)
+	}
+
+	for i := aHi; i < aHi+suffix; i++ {
+		d.ops = append(d.ops, diffOp{op: DiffEqual, text: d.a[i]})
+	}
+}
+
+// middleSnake 同时从两端搜索最短编辑路径，返回路径中间那段蛇的起点 (x, y) 和终点 (u, v)
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	a, b := d.a[aLo:aHi], d.b[bLo:bHi]
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta&1 != 0
+	vf, vb, o := d.vf, d.vb, d.o
+	vf[o+1], vb[o+1] = 0, 0
+
+	for step := 0; step <= (n+m+1)/2; step++ {
+		// 前向搜索，vf[k] 为对角线 k 上能到达的最远 x
+		for k := -step; k <= step; k += 2 {
+			var x0 int
+			if k == -step || (k != step && vf[o+k-1] < vf[o+k+1]) {
+				x0 = vf[o+k+1]
+			} else {
+				x0 = vf[o+k-1] + 1
+			}
+			y0 := x0 - k
+			x1, y1 := x0, y0
+			for x1 < n && y1 < m && a[x1] == b[y1] {
+				x1++
+				y1++
+			}
+			vf[o+k] = x1
+			if c := delta - k; odd && c >= -(step-1) && c <= step-1 && x1+vb[o+c] >= n {
+				return aLo + x0, bLo + y0, aLo + x1, bLo + y1
+			}
+		}
+		// 反向搜索，vb[c] 为反向对角线 c 上从末尾回退的最远距离
+		for c := -step; c <= step; c += 2 {
+			var x0 int
+			if c == -step || (c != step && vb[o+c-1] < vb[o+c+1]) {
+				x0 = vb[o+c+1]
+			} else {
+				x0 = vb[o+c-1] + 1
+			}
+			y0 := x0 - c
+			x1, y1 := x0, y0
+			for x1 < n && y1 < m && a[n-x1-1] == b[m-y1-1] {
+				x1++
+				y1++
+			}
+			vb[o+c] = x1
+			if k := delta - c; !odd && k >= -step && k <= step && vf[o+k]+x1 >= n {
+				return aLo + n - x1, bLo + m - y1, aLo + n - x0, bLo + m - y0
+			}
+		}
+	}
+	// 两侧非空时必然在上面的循环中相遇
+	return aLo, bLo, aLo, bLo
+}
@@ -0,0 +1,96 @@
+package utils_test
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"my-blog-backend/internal/pkg/utils"
+)
+
+// lcsLength 动态规划求最长公共子序列长度，用于校验差异是否最短
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func TestDiffLinesMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "b", "c", "d"}
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		return lines
+	}
+
+	for round := 0; round < 500; round++ {
+		oldLines, newLines := randomLines(), randomLines()
+		diff := utils.DiffLines(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))
+
+		var gotOld, gotNew []string
+		equal := 0
+		for _, line := range diff {
+			switch line.Op {
+			case utils.DiffEqual:
+				gotOld = append(gotOld, line.Text)
+				gotNew = append(gotNew, line.Text)
+				equal++
+			case utils.DiffDelete:
+				gotOld = append(gotOld, line.Text)
+			case utils.DiffInsert:
+				gotNew = append(gotNew, line.Text)
+			}
+		}
+		if strings.Join(gotOld, "\n") != strings.Join(oldLines, "\n") || strings.Join(gotNew, "\n") != strings.Join(newLines, "\n") {
+			t.Fatalf("差异无法还原原文: old=%v new=%v diff=%v", oldLines, newLines, diff)
+		}
+		if want := lcsLength(oldLines, newLines); equal != want {
+			t.Fatalf("差异不是最短的: 相同行 %d, 期望 %d, old=%v new=%v", equal, want, oldLines, newLines)
+		}
+	}
+}
+
+func TestDiffWordsTooLarge(t *testing.T) {
+	// 每个汉字单独成词，两段文本合计超过上限
+	oldText := strings.Repeat("旧", utils.MaxDiffWords/2)
+	newText := strings.Repeat("新", utils.MaxDiffWords/2+1)
+	if _, err := utils.DiffWords(oldText, newText); !errors.Is(err, utils.ErrDiffTooLarge) {
+		t.Fatalf("期望 ErrDiffTooLarge, 实际 %v", err)
+	}
+
+	segments, err := utils.DiffWords(oldText, oldText[:len(oldText)-len("旧")]+"新")
+	if err != nil {
+		t.Fatalf("上限内的对比失败: %v", err)
+	}
+	if len(segments) != 3 || segments[1].Text != "旧" || segments[2].Text != "新" {
+		t.Fatalf("差异结果不正确: %+v", segments[1:])
+	}
+}
+
+func TestDiffLinesLargeRewrite(t *testing.T) {
+	// 完全改写的大文本，编辑距离接近输入长度
+	oldLines := make([]string, 5000)
+	newLines := make([]string, 5000)
+	for i := range oldLines {
+		oldLines[i] = "old"
+		newLines[i] = "new"
+	}
+	added, removed := utils.DiffStat(utils.DiffLines(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n")))
+	if added != 5000 || removed != 5000 {
+		t.Fatalf("期望新增/删除 5000 行, 实际 %d/%d", added, removed)
+	}
+}
@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type SelectorOp string
+
+const (
+	SelectorEquals       SelectorOp = "="
+	SelectorNotEquals    SelectorOp = "!="
+	SelectorIn           SelectorOp = "in"
+	SelectorNotIn        SelectorOp = "notin"
+	SelectorExists       SelectorOp = "exists"
+	SelectorDoesNotExist SelectorOp = "!"
+)
+
+// 标签键和值允许的字符
+var labelTokenPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?$`)
+
+// LabelRequirement 标签选择器中的单个条件
+type LabelRequirement struct {
+	Key    string
+	Op     SelectorOp
+	Values []string
+}
+
+// LabelSelector 标签选择器，所有条件同时满足才算匹配；空选择器匹配所有对象
+type LabelSelector []LabelRequirement
+
+// ParseLabelSelector 解析标签选择器表达式，条件之间用逗号分隔，支持：
+//
+//	env=prod  env==prod  env!=prod  role in (db,cache)  role notin (web)  gpu  !gpu
+func ParseLabelSelector(expr string) (LabelSelector, error) {
+	var selector LabelSelector
+	for _, part := range splitSelector(expr) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		req, err := parseRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, req)
+	}
+	return selector, nil
+}
+
+// ValidateLabel 校验标签键值是否合法，值允许为空
+func ValidateLabel(key, value string) error {
+	if len(key) > 63 || !labelTokenPattern.MatchString(key) {
+		return fmt.Errorf("无效的标签键: %q", key)
+	}
+	if value != "" && (len(value) > 63 || !labelTokenPattern.MatchString(value)) {
+		return fmt.Errorf("无效的标签值: %q", value)
+	}
+	return nil
+}
+
+// Empty 是否为空选择器
+func (s LabelSelector) Empty() bool {
+	return len(s) == 0
+}
+
+// Matches 判断标签集合是否满足选择器
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, req := range s {
+		value, ok := labels[req.Key]
+		switch req.Op {
+		case SelectorEquals:
+			if !ok || value != req.Values[0] {
+				return false
+			}
+		case SelectorNotEquals:
+			if ok && value == req.Values[0] {
+				return false
+			}
+		case SelectorIn:
+			if !ok || !containsString(req.Values, value) {
+				return false
+			}
+		case SelectorNotIn:
+			if ok && containsString(req.Values, value) {
+				return false
+			}
+		case SelectorExists:
+			if !ok {
+				return false
+			}
+		case SelectorDoesNotExist:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String 规范化后的表达式
+func (s LabelSelector) String() string {
+	parts := make([]string, len(s))
+	for i, req := range s {
+		switch req.Op {
+		case SelectorEquals, SelectorNotEquals:
+			parts[i] = req.Key + string(req.Op) + req.Values[0]
+		case SelectorIn, SelectorNotIn:
+			parts[i] = fmt.Sprintf("%s %s (%s)", req.Key, req.Op, strings.Join(req.Values, ","))
+		case SelectorExists:
+			parts[i] = req.Key
+		case SelectorDoesNotExist:
+			parts[i] = "!" + req.Key
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// splitSelector 按顶层逗号切分，括号内的逗号不切分
+func splitSelector(expr string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range expr {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, expr[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, expr[start:])
+}
+
+func parseRequirement(part string) (LabelRequirement, error) {
+	// !key
+	if strings.HasPrefix(part, "!") && !strings.Contains(part, "=") {
+		key := strings.TrimSpace(part[1:])
+		if err := ValidateLabel(key, ""); err != nil {
+			return LabelRequirement{}, err
+		}
+		return LabelRequirement{Key: key, Op: SelectorDoesNotExist}, nil
+	}
+
+	// key!=value / key==value / key=value
+	for _, op := range []string{"!=", "==", "="} {
+		if i := strings.Index(part, op); i >= 0 {
+			key := strings.TrimSpace(part[:i])
+			value := strings.TrimSpace(part[i+len(op):])
+			if err := ValidateLabel(key, value); err != nil {
+				return LabelRequirement{}, err
+			}
+			reqOp := SelectorEquals
+			if op == "!=" {
+				reqOp = SelectorNotEquals
+			}
+			return LabelRequirement{Key: key, Op: reqOp, Values: []string{value}}, nil
+		}
+	}
+
+	// key in (v1,v2) / key notin (v1,v2)
+	if open := strings.Index(part, "("); open >= 0 {
+		if !strings.HasSuffix(part, ")") {
+			return LabelRequirement{}, fmt.Errorf("选择器缺少右括号: %q", part)
+		}
+		fields := strings.Fields(part[:open])
+		if len(fields) != 2 {
+			return LabelRequirement{}, fmt.Errorf("无效的选择器条件: %q", part)
+		}
+		key, op := fields[0], SelectorOp(strings.ToLower(fields[1]))
+		if op != SelectorIn && op != SelectorNotIn {
+			return LabelRequirement{}, fmt.Errorf("不支持的选择器操作符: %q", fields[1])
+		}
+		if err := ValidateLabel(key, ""); err != nil {
+			return LabelRequirement{}, err
+		}
+		var values []string
+		for _, v := range strings.Split(part[open+1:len(part)-1], ",") {
+			v = strings.TrimSpace(v)
+			if err := ValidateLabel(key, v); err != nil {
+				return LabelRequirement{}, err
+			}
+			values = append(values, v)
+		}
+		return LabelRequirement{Key: key, Op: op, Values: values}, nil
+	}
+
+	// key
+	if err := ValidateLabel(part, ""); err != nil {
+		return LabelRequirement{}, fmt.Errorf("无效的选择器条件: %q", part)
+	}
+	return LabelRequirement{Key: part, Op: SelectorExists}, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
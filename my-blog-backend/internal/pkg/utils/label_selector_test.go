@@ -0,0 +1,99 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"my-blog-backend/internal/pkg/utils"
+)
+
+func TestParseLabelSelectorErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"缺少右括号", "role in (db,cache"},
+		{"in 缺少括号", "role in db"},
+		{"不支持的操作符", "role like (db)"},
+		{"in 缺少键", "in (db)"},
+		{"非法的键", "env!=prod,-env=prod"},
+		{"非法的值", "env=prod!"},
+		{"非法的集合值", "role in (db,ca che)"},
+		{"非法的 !key", "!env-"},
+		{"键过长", strings.Repeat("a", 64) + "=x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if selector, err := utils.ParseLabelSelector(tt.expr); err == nil {
+				t.Fatalf("%q 应解析失败，实际 %v", tt.expr, selector)
+			}
+		})
+	}
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	labels := map[string]string{"env": "prod", "role": "db", "gpu": ""}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"", true},
+		{"env=prod", true},
+		{"env==prod", true},
+		{"env=dev", false},
+		{"env!=dev", true},
+		{"region!=cn", true},
+		{"role in (db,cache)", true},
+		{"role in (web)", false},
+		{"region in (cn)", false},
+		{"role notin (web,cache)", true},
+		{"role notin (db)", false},
+		{"region notin (cn)", true},
+		{"gpu", true},
+		{"region", false},
+		{"!region", true},
+		{"!gpu", false},
+		{"env=prod,role IN (db),!region", true},
+		{"env=prod,role in (cache)", false},
+	}
+	for _, tt := range tests {
+		selector, err := utils.ParseLabelSelector(tt.expr)
+		if err != nil {
+			t.Fatalf("解析 %q 失败: %v", tt.expr, err)
+		}
+		if got := selector.Matches(labels); got != tt.want {
+			t.Errorf("%q 匹配结果 = %v，期望 %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestLabelSelectorStringRoundTrip(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", ""},
+		{" env = prod ", "env=prod"},
+		{"env==prod", "env=prod"},
+		{"env != prod", "env!=prod"},
+		{"role  IN ( db , cache )", "role in (db,cache)"},
+		{"role notin (web)", "role notin (web)"},
+		{" gpu , ! ssd ", "gpu,!ssd"},
+		{"env=prod,role in (db,cache),!gpu", "env=prod,role in (db,cache),!gpu"},
+	}
+	for _, tt := range tests {
+		selector, err := utils.ParseLabelSelector(tt.expr)
+		if err != nil {
+			t.Fatalf("解析 %q 失败: %v", tt.expr, err)
+		}
+		got := selector.String()
+		if got != tt.want {
+			t.Errorf("%q 规范化为 %q，期望 %q", tt.expr, got, tt.want)
+		}
+		// 规范形式再次解析后不变
+		again, err := utils.ParseLabelSelector(got)
+		if err != nil || again.String() != got {
+			t.Errorf("%q 再次解析得到 %q, err=%v", got, again.String(), err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// 加密值的前缀，没有前缀的视为历史明文数据
+const encryptedPrefix = "enc:v1:"
+
+// SecretCipher 使用 AES-256-GCM 加密存储敏感字段（如主机账号密码）
+type SecretCipher struct {
+	aead cipher.AEAD
+}
+
+// NewSecretCipher 由配置的密钥派生 AES-256 密钥
+func NewSecretCipher(secret string) (*SecretCipher, error) {
+	if secret == "" {
+		return nil, errors.New("加密密钥不能为空")
+	}
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretCipher{aead: aead}, nil
+}
+
+// Encrypt 加密明文，空字符串原样返回
+func (c *SecretCipher) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密密文；没有加密前缀的值视为历史明文直接返回
+// c 为 nil（未配置加密密钥）时只能读取明文
+func (c *SecretCipher) Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+	if c == nil {
+		return "", errors.New("未配置加密密钥，无法解密")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("密文格式错误: %v", err)
+	}
+	size := c.aead.NonceSize()
+	if len(sealed) < size {
+		return "", errors.New("密文格式错误")
+	}
+	plaintext, err := c.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return "", errors.New("解密失败，加密密钥可能已变更")
+	}
+	return string(plaintext), nil
+}
+
+// 生成密码使用的字符集，符号排除引号、反斜杠、冒号等在 shell 和 chpasswd 中有特殊含义的字符
+var passwordCharsets = []string{
+	"ABCDEFGHJKLMNPQRSTUVWXYZ",
+	"abcdefghijkmnopqrstuvwxyz",
+	"23456789",
+	"!@#%^*_+-=.,",
+}
+
+// GeneratePassword 生成包含大小写字母、数字和符号的随机密码，长度不小于 12
+func GeneratePassword(length int) (string, error) {
+	if length < 12 {
+		length = 12
+	}
+	all := strings.Join(passwordCharsets, "")
+	password := make([]byte, length)
+	for i := range password {
+		// 前几位保证每类字符至少出现一次，之后打乱顺序
+		charset := all
+		if i < len(passwordCharsets) {
+			charset = passwordCharsets[i]
+		}
+		ch, err := randomChar(charset)
+		if err != nil {
+			return "", err
+		}
+		password[i] = ch
+	}
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+	return string(password), nil
+}
+
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, err
+	}
+	return charset[n.Int64()], nil
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slugify 根据名称生成 URL 别名：转为小写，保留字母（含中文）、数字和下划线，其余字符合并为单个 -
+func Slugify(name string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			if dash && builder.Len() > 0 {
+				builder.WriteByte('-')
+			}
+			builder.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return builder.String()
+}
@@ -1,18 +1,36 @@
 package repository
 
 import (
+	"time"
+
 	models "my-blog-backend/internal/models/frontendModel"
 )
 
+// ArticleFeedFilter 订阅源文章过滤条件，为 0 的条件不生效
+type ArticleFeedFilter struct {
+	CategoryID uint64
+	TagID      uint64
+	SeriesID   uint64
+	Limit      int
+}
+
 // ArticleRepository 文章仓储接口
 type ArticleRepository interface {
 	Create(article *models.Article) error
 	Update(article *models.Article) error
 	Delete(id uint) error
+	SetTags(articleID uint64, tagIDs []uint) error // 替换文章关联的标签
 	GetByID(id uint) (*models.Article, error)
+	GetBySlug(slug string) (*models.Article, error)
 	List(page, pageSize int, status *int, categoryID *uint) ([]*models.Article, int64, error)
-	Search(keyword string, page, pageSize int) ([]*models.Article, int64, error)
-	IncrementViewCount(id uint) error
+	GetByIDs(ids []uint64) ([]*models.Article, error)                         // 批量获取文章（不含正文），不保证顺序
+	GetContentsByIDs(ids []uint64) (map[uint64]string, error)                 // 批量获取文章正文
+	ListDueForPublish(now time.Time) ([]*models.Article, error)               // 获取到达发布时间的定时发布文章
+	ListDueForUnpublish(now time.Time) ([]*models.Article, error)             // 获取到达下线时间的已发布文章
+	ChangeStatus(id uint64, from, to uint8) (bool, error)                     // 状态为 from 时改为 to，返回是否修改；转为草稿时清除下线时间
+	ListPublishedWithTags() ([]*models.Article, error)                        // 获取全部已发布文章（含正文和标签），用于重建检索索引
+	ListAllWithTags() ([]*models.Article, error)                              // 获取全部文章（含草稿、正文和标签），用于导出
+	ListLatestPublished(filter *ArticleFeedFilter) ([]*models.Article, error) // 按发布时间倒序获取已发布文章（含正文和标签），用于生成订阅源
 	IncrementLikeCount(id uint) error
 	DecrementLikeCount(id uint) error
 	GetHotArticles(limit int) ([]*models.Article, error)
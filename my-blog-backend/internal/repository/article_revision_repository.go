@@ -0,0 +1,14 @@
+package repository
+
+import (
+	models "my-blog-backend/internal/models/frontendModel"
+)
+
+// ArticleRevisionRepository 文章修订历史仓储接口
+type ArticleRevisionRepository interface {
+	Create(revision *models.ArticleRevision, keep int) error // 新增修订并只保留最近 keep 条，keep<=0 不清理
+	Get(articleID uint, version int) (*models.ArticleRevision, error)
+	GetLatest(articleID uint) (*models.ArticleRevision, error)
+	List(articleID uint, page, pageSize int) ([]*models.ArticleRevision, int64, error) // 不含正文，按版本倒序
+	DeleteByArticleID(articleID uint) error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	models "my-blog-backend/internal/models/frontendModel"
+)
+
+// ArticleViewRepository 文章浏览记录仓储接口
+type ArticleViewRepository interface {
+	// CreateBatch 在同一事务中写入浏览记录并按文章累加阅读量，已删除文章的记录被丢弃
+	CreateBatch(views []*models.ArticleView) error
+}
+
+// ArticleViewBuffer 浏览记录缓冲区，用于访客去重和暂存待批量写入数据库的浏览记录
+type ArticleViewBuffer interface {
+	// MarkSeen 标记访客在时间窗口内已阅读过，首次标记返回 true
+	MarkSeen(ctx context.Context, key string, window time.Duration) (bool, error)
+	Push(ctx context.Context, view *models.ArticleView) error
+	// Pop 取出最多 limit 条浏览记录
+	Pop(ctx context.Context, limit int) ([]*models.ArticleView, error)
+}
@@ -0,0 +1,10 @@
+package repository
+
+import (
+	models "my-blog-backend/internal/models/opsModel"
+)
+
+// AuditLogRepository 终端审计日志仓储接口
+type AuditLogRepository interface {
+	Create(log *models.AuditLog) error
+}
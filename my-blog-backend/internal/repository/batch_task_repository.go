@@ -0,0 +1,17 @@
+package repository
+
+import (
+	models "my-blog-backend/internal/models/opsModel"
+)
+
+// BatchTaskRepository 批量任务仓储接口
+type BatchTaskRepository interface {
+	Create(task *models.BatchTask, relations []*models.TaskHostRelation) error
+	CreateRelations(taskID uint, relations []*models.TaskHostRelation) error
+	Update(task *models.BatchTask) error
+	GetByID(id uint) (*models.BatchTask, error)
+	List(page, pageSize int, taskType, status uint, createdBy uint) ([]*models.BatchTask, int64, error)
+	ListRelations(taskID uint) ([]*models.TaskHostRelation, error)
+	GetRelation(taskID, hostID uint) (*models.TaskHostRelation, error)
+	UpdateRelation(relation *models.TaskHostRelation) error
+}
@@ -7,6 +7,7 @@ import (
 // CommentRepository 评论仓储接口
 type CommentRepository interface {
 	Create(comment *models.Comment) error
+	CreateImported(comment *models.Comment) error // 创建从其他博客导入的游客评论，不关联用户
 	Update(comment *models.Comment) error
 	Delete(id uint) error
 	DeleteWithChildren(id uint) error // 删除评论及其所有子评论
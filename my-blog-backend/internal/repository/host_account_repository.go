@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"time"
+
+	models "my-blog-backend/internal/models/opsModel"
+)
+
+// HostAccountRepository 主机账号仓储接口
+type HostAccountRepository interface {
+	Create(account *models.HostAccount) error
+	Update(account *models.HostAccount) error
+	Delete(id uint) error
+	GetByID(id uint) (*models.HostAccount, error)
+	List(page, pageSize int, hostID uint, name string) ([]*models.HostAccount, int64, error)
+	ListDueForRotation(now time.Time) ([]*models.HostAccount, error)
+	// SaveRotation 在同一事务中保存轮换后的账号密码和轮换记录，host 不为空时同时更新主机登录密码
+	SaveRotation(account *models.HostAccount, host *models.RemoteHost, rotation *models.HostAccountRotation) error
+	CreateRotation(rotation *models.HostAccountRotation) error
+	ListRotations(accountID uint, page, pageSize int) ([]*models.HostAccountRotation, int64, error)
+}
@@ -0,0 +1,121 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/repository"
+)
+
+const (
+	articleViewSeenPrefix = "article:view:seen:" // 访客去重标记
+	articleViewQueueKey   = "article:view:queue" // 待写入数据库的浏览记录
+)
+
+// NewArticleViewBuffer 创建浏览记录缓冲区，未启用 Redis 时缓冲在进程内
+func NewArticleViewBuffer(client *redis.Client) repository.ArticleViewBuffer {
+	if client == nil {
+		return &memoryArticleViewBuffer{seen: make(map[string]time.Time)}
+	}
+	return &redisArticleViewBuffer{client: client}
+}
+
+// redisArticleViewBuffer 基于 Redis 的缓冲区，多个实例共享去重标记和待写入队列
+type redisArticleViewBuffer struct {
+	client *redis.Client
+}
+
+func (b *redisArticleViewBuffer) MarkSeen(ctx context.Context, key string, window time.Duration) (bool, error) {
+	ok, err := b.client.SetNX(ctx, articleViewSeenPrefix+key, 1, window).Result()
+	if err != nil {
+		return false, fmt.Errorf("标记阅读记录失败: %w", err)
+	}
+	return ok, nil
+}
+
+func (b *redisArticleViewBuffer) Push(ctx context.Context, view *models.ArticleView) error {
+	data, err := json.Marshal(view)
+	if err != nil {
+		return err
+	}
+	if err := b.client.RPush(ctx, articleViewQueueKey, data).Err(); err != nil {
+		return fmt.Errorf("缓冲阅读记录失败: %w", err)
+	}
+	return nil
+}
+
+// Pop 在事务中读取并删除队首的记录，多个实例同时写入数据库时不会重复
+func (b *redisArticleViewBuffer) Pop(ctx context.Context, limit int) ([]*models.ArticleView, error) {
+	var items *redis.StringSliceCmd
+	_, err := b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		items = pipe.LRange(ctx, articleViewQueueKey, 0, int64(limit)-1)
+		pipe.LTrim(ctx, articleViewQueueKey, int64(limit), -1)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取阅读记录失败: %w", err)
+	}
+
+	views := make([]*models.ArticleView, 0, len(items.Val()))
+	for _, item := range items.Val() {
+		var view models.ArticleView
+		// 无法解析的记录直接丢弃，避免阻塞队列
+		if err := json.Unmarshal([]byte(item), &view); err == nil {
+			views = append(views, &view)
+		}
+	}
+	return views, nil
+}
+
+// memoryArticleViewBuffer 进程内缓冲区，重启时未写入的记录会丢失
+type memoryArticleViewBuffer struct {
+	mu    sync.Mutex
+	seen  map[string]time.Time // 去重标记的过期时间
+	queue []*models.ArticleView
+}
+
+func (b *memoryArticleViewBuffer) MarkSeen(_ context.Context, key string, window time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if expireAt, ok := b.seen[key]; ok && now.Before(expireAt) {
+		return false, nil
+	}
+	b.seen[key] = now.Add(window)
+	return true, nil
+}
+
+func (b *memoryArticleViewBuffer) Push(_ context.Context, view *models.ArticleView) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queue = append(b.queue, view)
+	return nil
+}
+
+// Pop 取出记录，同时清理过期的去重标记
+func (b *memoryArticleViewBuffer) Pop(_ context.Context, limit int) ([]*models.ArticleView, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	for key, expireAt := range b.seen {
+		if !now.Before(expireAt) {
+			delete(b.seen, key)
+		}
+	}
+
+	if limit > len(b.queue) {
+		limit = len(b.queue)
+	}
+	views := b.queue[:limit:limit]
+	b.queue = b.queue[limit:]
+	if len(b.queue) == 0 {
+		b.queue = nil
+	}
+	return views, nil
+}
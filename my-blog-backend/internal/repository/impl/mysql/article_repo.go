@@ -1,6 +1,8 @@
 package mysql
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 
 	models "my-blog-backend/internal/models/frontendModel"
@@ -19,12 +21,24 @@ func NewArticleRepositoryImpl(db *gorm.DB) repository.ArticleRepository {
 
 // Create 创建文章
 func (r *ArticleRepositoryImpl) Create(article *models.Article) error {
-	return r.db.Create(article).Error
+	// status 列默认值为已发布，GORM 创建时会忽略草稿的零值，需要单独写入
+	status := article.Status
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(article).Error; err != nil {
+			return err
+		}
+		if status == models.ArticleStatusDraft {
+			article.Status = status
+			return tx.Model(article).Update("status", status).Error
+		}
+		return nil
+	})
 }
 
-// Update 更新文章
+// Update 更新文章的可编辑字段
+// 阅读、点赞、收藏和评论数由各自的统计逻辑累加，不随文章内容写回，避免覆盖编辑期间产生的计数
 func (r *ArticleRepositoryImpl) Update(article *models.Article) error {
-	return r.db.Save(article).Error
+	return r.db.Omit("views", "likes", "favorites", "comment_count").Save(article).Error
 }
 
 // Delete 删除文章
@@ -33,6 +47,28 @@ func (r *ArticleRepositoryImpl) Delete(id uint) error {
 
 }
 
+// SetTags 替换文章关联的标签
+func (r *ArticleRepositoryImpl) SetTags(articleID uint64, tagIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("article_id = ?", articleID).Delete(&models.ArticleTag{}).Error; err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		relations := make([]models.ArticleTag, 0, len(tagIDs))
+		seen := make(map[uint]bool, len(tagIDs))
+		for _, tagID := range tagIDs {
+			if seen[tagID] {
+				continue
+			}
+			seen[tagID] = true
+			relations = append(relations, models.ArticleTag{ArticleID: articleID, TagID: uint64(tagID)})
+		}
+		return tx.Create(&relations).Error
+	})
+}
+
 // GetByID 根据ID获取文章
 func (r *ArticleRepositoryImpl) GetByID(id uint) (*models.Article, error) {
 	var article models.Article
@@ -43,6 +79,16 @@ func (r *ArticleRepositoryImpl) GetByID(id uint) (*models.Article, error) {
 	return &article, nil
 }
 
+// GetBySlug 根据slug获取文章
+func (r *ArticleRepositoryImpl) GetBySlug(slug string) (*models.Article, error) {
+	var article models.Article
+	err := r.db.Where("slug = ?", slug).First(&article).Error
+	if err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
 // List 分页获取文章列表
 func (r *ArticleRepositoryImpl) List(page, pageSize int, status *int, categoryID *uint) ([]*models.Article, int64, error) {
 	var articles []*models.Article
@@ -96,24 +142,99 @@ func (r *ArticleRepositoryImpl) GetByTagID(tagID uint, page, pageSize int) ([]*m
 	return articles, total, err
 }
 
-// Search 搜索文章
-func (r *ArticleRepositoryImpl) Search(keyword string, page, pageSize int) ([]*models.Article, int64, error) {
+// GetByIDs 批量获取文章
+func (r *ArticleRepositoryImpl) GetByIDs(ids []uint64) ([]*models.Article, error) {
 	var articles []*models.Article
-	var total int64
-
-	offset := (page - 1) * pageSize
-	query := r.db.Model(&models.Article{}).
+	if len(ids) == 0 {
+		return articles, nil
+	}
+	err := r.db.Model(&models.Article{}).
 		Select("id, title, slug, summary, cover, category_id, author_id, views, likes, favorites, comment_count, status, is_top, sort_order, published_at, created_at, updated_at").
-		Where("title LIKE ? OR content LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
-	query.Count(&total)
-	err := query.Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&articles).Error
+		Where("id IN ?", ids).
+		Find(&articles).Error
+	return articles, err
+}
 
-	return articles, total, err
+// GetContentsByIDs 批量获取文章正文
+func (r *ArticleRepositoryImpl) GetContentsByIDs(ids []uint64) (map[uint64]string, error) {
+	contents := make(map[uint64]string, len(ids))
+	if len(ids) == 0 {
+		return contents, nil
+	}
+	var rows []struct {
+		ID      uint64
+		Content string
+	}
+	if err := r.db.Model(&models.Article{}).Select("id, content").Where("id IN ?", ids).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		contents[row.ID] = row.Content
+	}
+	return contents, nil
 }
 
-// IncrementViewCount 增加浏览次数
-func (r *ArticleRepositoryImpl) IncrementViewCount(id uint) error {
-	return r.db.Model(&models.Article{}).Where("id = ?", id).UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
+// ListDueForPublish 获取到达发布时间的定时发布文章
+func (r *ArticleRepositoryImpl) ListDueForPublish(now time.Time) ([]*models.Article, error) {
+	var articles []*models.Article
+	err := r.db.Where("status = ? AND published_at <= ?", models.ArticleStatusScheduled, now).
+		Order("published_at").Find(&articles).Error
+	return articles, err
+}
+
+// ListDueForUnpublish 获取到达下线时间的已发布文章
+func (r *ArticleRepositoryImpl) ListDueForUnpublish(now time.Time) ([]*models.Article, error) {
+	var articles []*models.Article
+	err := r.db.Where("status = ? AND unpublish_at IS NOT NULL AND unpublish_at <= ?", models.ArticleStatusPublished, now).
+		Order("unpublish_at").Find(&articles).Error
+	return articles, err
+}
+
+// ChangeStatus 按原状态条件更新文章状态，避免覆盖编辑人同时做的修改
+func (r *ArticleRepositoryImpl) ChangeStatus(id uint64, from, to uint8) (bool, error) {
+	updates := map[string]interface{}{
+		"status":     to,
+		"updated_at": time.Now(),
+	}
+	if to == models.ArticleStatusDraft {
+		updates["unpublish_at"] = nil
+	}
+	result := r.db.Model(&models.Article{}).Where("id = ? AND status = ?", id, from).Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListPublishedWithTags 获取全部已发布文章及其标签
+func (r *ArticleRepositoryImpl) ListPublishedWithTags() ([]*models.Article, error) {
+	var articles []*models.Article
+	err := r.db.Preload("Tags").Where("status = ?", 1).Find(&articles).Error
+	return articles, err
+}
+
+// ListAllWithTags 获取全部文章及其标签
+func (r *ArticleRepositoryImpl) ListAllWithTags() ([]*models.Article, error) {
+	var articles []*models.Article
+	err := r.db.Preload("Tags").Order("id ASC").Find(&articles).Error
+	return articles, err
+}
+
+// ListLatestPublished 按发布时间倒序获取已发布文章
+func (r *ArticleRepositoryImpl) ListLatestPublished(filter *repository.ArticleFeedFilter) ([]*models.Article, error) {
+	var articles []*models.Article
+	query := r.db.Preload("Tags").Where("status = ?", models.ArticleStatusPublished)
+	if filter.CategoryID != 0 {
+		query = query.Where("category_id = ?", filter.CategoryID)
+	}
+	if filter.TagID != 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM article_tag at WHERE at.article_id = articles.id AND at.tag_id = ?)", filter.TagID)
+	}
+	if filter.SeriesID != 0 {
+		query = query.Where(`EXISTS (SELECT 1 FROM subchapter_articles sa
+			JOIN series_subchapters sc ON sc.id = sa.subchapter_id
+			JOIN series_sections ss ON ss.id = sc.section_id
+			WHERE sa.article_id = articles.id AND ss.series_id = ?)`, filter.SeriesID)
+	}
+	err := query.Order("COALESCE(published_at, created_at) DESC, id DESC").Limit(filter.Limit).Find(&articles).Error
+	return articles, err
 }
 
 // IncrementLikeCount 增加点赞数
@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"gorm.io/gorm"
+
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/repository"
+)
+
+// ArticleRevisionRepositoryImpl 文章修订历史仓储实现
+type ArticleRevisionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewArticleRevisionRepositoryImpl 创建文章修订历史仓储实例
+func NewArticleRevisionRepositoryImpl(db *gorm.DB) repository.ArticleRevisionRepository {
+	return &ArticleRevisionRepositoryImpl{db: db}
+}
+
+// Create 新增修订，版本号取当前最大版本号加一，超出保留数量的旧修订被删除
+func (r *ArticleRevisionRepositoryImpl) Create(revision *models.ArticleRevision, keep int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&models.ArticleRevision{}).
+			Where("article_id = ?", revision.ArticleID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		revision.Version = latest + 1
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+
+		if keep <= 0 {
+			return nil
+		}
+		return tx.Where("article_id = ? AND version <= ?", revision.ArticleID, revision.Version-keep).
+			Delete(&models.ArticleRevision{}).Error
+	})
+}
+
+// Get 获取指定版本的修订
+func (r *ArticleRevisionRepositoryImpl) Get(articleID uint, version int) (*models.ArticleRevision, error) {
+	var revision models.ArticleRevision
+	err := r.db.Where("article_id = ? AND version = ?", articleID, version).First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetLatest 获取最新修订
+func (r *ArticleRevisionRepositoryImpl) GetLatest(articleID uint) (*models.ArticleRevision, error) {
+	var revision models.ArticleRevision
+	err := r.db.Where("article_id = ?", articleID).Order("version DESC").First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// List 分页获取修订列表
+func (r *ArticleRevisionRepositoryImpl) List(articleID uint, page, pageSize int) ([]*models.ArticleRevision, int64, error) {
+	var revisions []*models.ArticleRevision
+	var total int64
+
+	offset := (page - 1) * pageSize
+	query := r.db.Model(&models.ArticleRevision{}).
+		Select("id, article_id, version, title, summary, tags, editor_id, action, restored_from, created_at").
+		Where("article_id = ?", articleID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Offset(offset).Limit(pageSize).Order("version DESC").Find(&revisions).Error
+
+	return revisions, total, err
+}
+
+// DeleteByArticleID 删除文章的全部修订
+func (r *ArticleRevisionRepositoryImpl) DeleteByArticleID(articleID uint) error {
+	return r.db.Where("article_id = ?", articleID).Delete(&models.ArticleRevision{}).Error
+}
@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"gorm.io/gorm"
+
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/repository"
+)
+
+// ArticleViewRepositoryImpl 文章浏览记录仓储实现
+type ArticleViewRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewArticleViewRepositoryImpl 创建文章浏览记录仓储实例
+func NewArticleViewRepositoryImpl(db *gorm.DB) repository.ArticleViewRepository {
+	return &ArticleViewRepositoryImpl{db: db}
+}
+
+// CreateBatch 批量写入浏览记录，并按写入的记录数累加文章阅读量，保证两者一致
+func (r *ArticleViewRepositoryImpl) CreateBatch(views []*models.ArticleView) error {
+	if len(views) == 0 {
+		return nil
+	}
+	articleIDs := make([]uint64, 0, len(views))
+	seen := make(map[uint64]bool, len(views))
+	for _, view := range views {
+		if !seen[view.ArticleID] {
+			seen[view.ArticleID] = true
+			articleIDs = append(articleIDs, view.ArticleID)
+		}
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 缓冲期间文章可能已被删除，只写入仍存在的文章
+		var existing []uint64
+		if err := tx.Model(&models.Article{}).Where("id IN ?", articleIDs).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		exists := make(map[uint64]bool, len(existing))
+		for _, id := range existing {
+			exists[id] = true
+		}
+
+		rows := make([]*models.ArticleView, 0, len(views))
+		counts := make(map[uint64]int)
+		for _, view := range views {
+			if exists[view.ArticleID] {
+				rows = append(rows, view)
+				counts[view.ArticleID]++
+			}
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := tx.Omit("Article", "User").CreateInBatches(rows, 200).Error; err != nil {
+			return err
+		}
+		for articleID, count := range counts {
+			if err := tx.Model(&models.Article{}).Where("id = ?", articleID).
+				UpdateColumn("views", gorm.Expr("views + ?", count)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
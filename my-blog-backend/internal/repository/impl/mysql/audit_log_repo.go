@@ -0,0 +1,20 @@
+package mysql
+
+import (
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) repository.AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+func (r *AuditLogRepository) Create(log *opsModel.AuditLog) error {
+	return r.db.Create(log).Error
+}
@@ -0,0 +1,106 @@
+package mysql
+
+import (
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type BatchTaskRepository struct {
+	db *gorm.DB
+}
+
+func NewBatchTaskRepository(db *gorm.DB) repository.BatchTaskRepository {
+	return &BatchTaskRepository{db: db}
+}
+
+// Create 创建任务及其主机关联记录
+func (r *BatchTaskRepository) Create(task *opsModel.BatchTask, relations []*opsModel.TaskHostRelation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		if len(relations) == 0 {
+			return nil
+		}
+		for _, rel := range relations {
+			rel.TaskID = task.ID
+		}
+		return tx.Create(&relations).Error
+	})
+}
+
+// CreateRelations 为已创建的任务添加主机关联记录
+func (r *BatchTaskRepository) CreateRelations(taskID uint, relations []*opsModel.TaskHostRelation) error {
+	if len(relations) == 0 {
+		return nil
+	}
+	for _, rel := range relations {
+		rel.TaskID = taskID
+	}
+	return r.db.Create(&relations).Error
+}
+
+func (r *BatchTaskRepository) Update(task *opsModel.BatchTask) error {
+	return r.db.Save(task).Error
+}
+
+func (r *BatchTaskRepository) GetByID(id uint) (*opsModel.BatchTask, error) {
+	var task opsModel.BatchTask
+	err := r.db.First(&task, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (r *BatchTaskRepository) List(page, pageSize int, taskType, status uint, createdBy uint) ([]*opsModel.BatchTask, int64, error) {
+	var tasks []*opsModel.BatchTask
+	var total int64
+
+	// 列表不返回命令/脚本内容，避免大字段拖慢查询
+	query := r.db.Model(&opsModel.BatchTask{}).Omit("command")
+	if taskType > 0 {
+		query = query.Where("type = ?", taskType)
+	}
+	if status > 0 {
+		query = query.Where("status = ?", status)
+	}
+	if createdBy > 0 {
+		query = query.Where("created_by = ?", createdBy)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&tasks).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return tasks, total, nil
+}
+
+func (r *BatchTaskRepository) ListRelations(taskID uint) ([]*opsModel.TaskHostRelation, error) {
+	var relations []*opsModel.TaskHostRelation
+	err := r.db.Where("task_id = ?", taskID).Order("id ASC").Find(&relations).Error
+	if err != nil {
+		return nil, err
+	}
+	return relations, nil
+}
+
+func (r *BatchTaskRepository) GetRelation(taskID, hostID uint) (*opsModel.TaskHostRelation, error) {
+	var relation opsModel.TaskHostRelation
+	err := r.db.Where("task_id = ? AND host_id = ?", taskID, hostID).First(&relation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+func (r *BatchTaskRepository) UpdateRelation(relation *opsModel.TaskHostRelation) error {
+	return r.db.Save(relation).Error
+}
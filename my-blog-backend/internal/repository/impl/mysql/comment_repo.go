@@ -56,6 +56,22 @@ func (r *CommentRepositoryImpl) Create(comment *models.Comment) error {
 	return tx.Commit().Error
 }
 
+// CreateImported 创建导入的游客评论，游客没有用户ID，不写入用户评论文章中间表
+func (r *CommentRepositoryImpl) CreateImported(comment *models.Comment) error {
+	// status 有默认值 1，待审核（零值）创建时会被替换为默认值，需要单独更新
+	status := comment.Status
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		if comment.Status == status {
+			return nil
+		}
+		comment.Status = status
+		return tx.Model(comment).UpdateColumn("status", status).Error
+	})
+}
+
 // Update 更新评论
 func (r *CommentRepositoryImpl) Update(comment *models.Comment) error {
 	return r.db.Save(comment).Error
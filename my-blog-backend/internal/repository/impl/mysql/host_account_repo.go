@@ -0,0 +1,116 @@
+package mysql
+
+import (
+	"time"
+
+	"my-blog-backend/internal/models"
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type HostAccountRepository struct {
+	db *gorm.DB
+}
+
+func NewHostAccountRepository(db *gorm.DB) repository.HostAccountRepository {
+	return &HostAccountRepository{db: db}
+}
+
+func (r *HostAccountRepository) Create(account *opsModel.HostAccount) error {
+	return r.db.Create(account).Error
+}
+
+func (r *HostAccountRepository) Update(account *opsModel.HostAccount) error {
+	return r.db.Save(account).Error
+}
+
+func (r *HostAccountRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("account_id = ?", id).Delete(&opsModel.HostAccountRotation{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&opsModel.HostAccount{}, id).Error
+	})
+}
+
+func (r *HostAccountRepository) GetByID(id uint) (*opsModel.HostAccount, error) {
+	var account opsModel.HostAccount
+	err := r.db.First(&account, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *HostAccountRepository) List(page, pageSize int, hostID uint, name string) ([]*opsModel.HostAccount, int64, error) {
+	var accounts []*opsModel.HostAccount
+	var total int64
+
+	// 列表不返回密码和私钥
+	query := r.db.Model(&opsModel.HostAccount{}).Omit("password", "secret_key")
+	if hostID > 0 {
+		query = query.Where("host_id = ?", hostID)
+	}
+	if name != "" {
+		query = query.Where("name LIKE ? OR username LIKE ?", "%"+name+"%", "%"+name+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&accounts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return accounts, total, nil
+}
+
+// ListDueForRotation 获取已到轮换时间的启用账号
+func (r *HostAccountRepository) ListDueForRotation(now time.Time) ([]*opsModel.HostAccount, error) {
+	var accounts []*opsModel.HostAccount
+	err := r.db.Where("status = ? AND rotate_days > 0 AND next_rotate_at <= ?", models.StatusEnabled, now).
+		Order("id ASC").Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *HostAccountRepository) SaveRotation(account *opsModel.HostAccount, host *opsModel.RemoteHost, rotation *opsModel.HostAccountRotation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(account).Error; err != nil {
+			return err
+		}
+		if host != nil {
+			if err := tx.Model(host).Update("password", host.Password).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(rotation).Error
+	})
+}
+
+func (r *HostAccountRepository) CreateRotation(rotation *opsModel.HostAccountRotation) error {
+	return r.db.Create(rotation).Error
+}
+
+func (r *HostAccountRepository) ListRotations(accountID uint, page, pageSize int) ([]*opsModel.HostAccountRotation, int64, error) {
+	var rotations []*opsModel.HostAccountRotation
+	var total int64
+
+	query := r.db.Model(&opsModel.HostAccountRotation{}).Where("account_id = ?", accountID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&rotations).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return rotations, total, nil
+}
@@ -1,7 +1,9 @@
 package mysql
 
 import (
+	"my-blog-backend/internal/models"
 	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/utils"
 	"my-blog-backend/internal/repository"
 
 	"gorm.io/gorm"
@@ -24,7 +26,12 @@ func (r *HostRepository) Update(host *opsModel.RemoteHost) error {
 }
 
 func (r *HostRepository) Delete(id uint) error {
-	return r.db.Delete(&opsModel.RemoteHost{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("host_id = ?", id).Delete(&opsModel.HostLabel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&opsModel.RemoteHost{}, id).Error
+	})
 }
 
 func (r *HostRepository) GetByID(id uint) (*opsModel.RemoteHost, error) {
@@ -36,7 +43,7 @@ func (r *HostRepository) GetByID(id uint) (*opsModel.RemoteHost, error) {
 	return &host, nil
 }
 
-func (r *HostRepository) List(page, pageSize int, name, address, hostType, status string) ([]*opsModel.RemoteHost, int64, error) {
+func (r *HostRepository) List(page, pageSize int, name, address, hostType, status string, selector utils.LabelSelector) ([]*opsModel.RemoteHost, int64, error) {
 	var hosts []*opsModel.RemoteHost
 	var total int64
 
@@ -66,6 +73,7 @@ func (r *HostRepository) List(page, pageSize int, name, address, hostType, statu
 	if status != "" {
 		query = query.Where("status = ?", st)
 	}
+	query = r.applySelector(query, selector)
 
 	// 获取总数
 	if err := query.Count(&total).Error; err != nil {
@@ -89,3 +97,141 @@ func (r *HostRepository) GetAll() ([]*opsModel.RemoteHost, error) {
 	}
 	return hosts, nil
 }
+
+// ListBySelector 获取匹配标签选择器的所有主机
+func (r *HostRepository) ListBySelector(selector utils.LabelSelector) ([]*opsModel.RemoteHost, error) {
+	var hosts []*opsModel.RemoteHost
+	err := r.applySelector(r.db.Model(&opsModel.RemoteHost{}), selector).Order("id ASC").Find(&hosts).Error
+	if err != nil {
+		return nil, err
+	}
+	return hosts, nil
+}
+
+// ListByKeyID 获取使用指定托管密钥登录的主机
+func (r *HostRepository) ListByKeyID(keyID uint) ([]*opsModel.RemoteHost, error) {
+	var hosts []*opsModel.RemoteHost
+	err := r.db.Where("key_id = ?", keyID).Order("id ASC").Find(&hosts).Error
+	if err != nil {
+		return nil, err
+	}
+	return hosts, nil
+}
+
+// SetLabels 覆盖主机的全部标签
+func (r *HostRepository) SetLabels(hostID uint, labels map[string]string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("host_id = ?", hostID).Delete(&opsModel.HostLabel{}).Error; err != nil {
+			return err
+		}
+		if len(labels) == 0 {
+			return nil
+		}
+		rows := make([]*opsModel.HostLabel, 0, len(labels))
+		for key, value := range labels {
+			rows = append(rows, &opsModel.HostLabel{HostID: hostID, Key: key, Value: value})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// GetLabels 批量获取主机标签，返回 hostID -> 标签集合
+func (r *HostRepository) GetLabels(hostIDs []uint) (map[uint]map[string]string, error) {
+	result := make(map[uint]map[string]string, len(hostIDs))
+	if len(hostIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []*opsModel.HostLabel
+	if err := r.db.Where("host_id IN ?", hostIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if result[row.HostID] == nil {
+			result[row.HostID] = make(map[string]string)
+		}
+		result[row.HostID][row.Key] = row.Value
+	}
+	return result, nil
+}
+
+// ListLabels 获取所有已使用的标签键值（去重）
+func (r *HostRepository) ListLabels() ([]*opsModel.HostLabel, error) {
+	var labels []*opsModel.HostLabel
+	err := r.db.Model(&opsModel.HostLabel{}).
+		Select("label_key, label_value").
+		Group("label_key, label_value").
+		Order("label_key ASC, label_value ASC").
+		Find(&labels).Error
+	if err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// ListUserPermissions 获取用户所在的已启用用户组的主机授权
+func (r *HostRepository) ListUserPermissions(userID uint) ([]*opsModel.HostUserPermission, error) {
+	var permissions []*opsModel.HostUserPermission
+	err := r.db.Table("host_user_permissions AS p").
+		Select("p.*").
+		Joins("JOIN user_group_relations AS ugr ON ugr.user_group_id = p.user_group_id").
+		Joins("JOIN user_groups AS ug ON ug.id = p.user_group_id").
+		Where("ugr.user_id = ? AND ug.status = ?", userID, models.StatusEnabled).
+		Find(&permissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// ListPermissions 获取用户组的主机授权，userGroupID 为 0 时返回全部授权
+func (r *HostRepository) ListPermissions(userGroupID uint) ([]*opsModel.HostUserPermission, error) {
+	var permissions []*opsModel.HostUserPermission
+	query := r.db.Model(&opsModel.HostUserPermission{})
+	if userGroupID > 0 {
+		query = query.Where("user_group_id = ?", userGroupID)
+	}
+	if err := query.Order("id ASC").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *HostRepository) CreatePermission(permission *opsModel.HostUserPermission) error {
+	return r.db.Create(permission).Error
+}
+
+func (r *HostRepository) DeletePermission(id uint) error {
+	return r.db.Delete(&opsModel.HostUserPermission{}, id).Error
+}
+
+// ListHostGroupIDs 获取主机所属的主机组ID
+func (r *HostRepository) ListHostGroupIDs(hostID uint) ([]uint, error) {
+	var groupIDs []uint
+	err := r.db.Model(&opsModel.HostGroupRelation{}).
+		Where("host_id = ?", hostID).
+		Pluck("host_group_id", &groupIDs).Error
+	return groupIDs, err
+}
+
+// applySelector 将标签选择器转换为 host_labels 子查询条件
+func (r *HostRepository) applySelector(query *gorm.DB, selector utils.LabelSelector) *gorm.DB {
+	for _, req := range selector {
+		sub := r.db.Model(&opsModel.HostLabel{}).Select("host_id").Where("label_key = ?", req.Key)
+		switch req.Op {
+		case utils.SelectorEquals:
+			query = query.Where("id IN (?)", sub.Where("label_value = ?", req.Values[0]))
+		case utils.SelectorNotEquals:
+			query = query.Where("id NOT IN (?)", sub.Where("label_value = ?", req.Values[0]))
+		case utils.SelectorIn:
+			query = query.Where("id IN (?)", sub.Where("label_value IN ?", req.Values))
+		case utils.SelectorNotIn:
+			query = query.Where("id NOT IN (?)", sub.Where("label_value IN ?", req.Values))
+		case utils.SelectorExists:
+			query = query.Where("id IN (?)", sub)
+		case utils.SelectorDoesNotExist:
+			query = query.Where("id NOT IN (?)", sub)
+		}
+	}
+	return query
+}
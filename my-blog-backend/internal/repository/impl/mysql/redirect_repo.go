@@ -0,0 +1,108 @@
+package mysql
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/repository"
+)
+
+// RedirectRepositoryImpl 重定向仓储实现
+type RedirectRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRedirectRepositoryImpl 创建重定向仓储实例
+func NewRedirectRepositoryImpl(db *gorm.DB) repository.RedirectRepository {
+	return &RedirectRepositoryImpl{db: db}
+}
+
+// GetByID 根据ID获取重定向
+func (r *RedirectRepositoryImpl) GetByID(id uint64) (*models.Redirect, error) {
+	var redirect models.Redirect
+	if err := r.db.First(&redirect, id).Error; err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// GetByFromPath 根据来源路径获取重定向
+func (r *RedirectRepositoryImpl) GetByFromPath(fromPath string) (*models.Redirect, error) {
+	var redirect models.Redirect
+	if err := r.db.Where("from_path = ?", fromPath).First(&redirect).Error; err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// List 分页获取重定向列表，keyword 匹配来源路径和目标路径
+func (r *RedirectRepositoryImpl) List(page, pageSize int, source, keyword string) ([]*models.Redirect, int64, error) {
+	var redirects []*models.Redirect
+	var total int64
+
+	query := r.db.Model(&models.Redirect{})
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+	if keyword != "" {
+		query = query.Where("from_path LIKE ? OR to_path LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&redirects).Error; err != nil {
+		return nil, 0, err
+	}
+	return redirects, total, nil
+}
+
+// Create 创建重定向
+func (r *RedirectRepositoryImpl) Create(redirect *models.Redirect) error {
+	return r.db.Create(redirect).Error
+}
+
+// Update 更新重定向的路径和状态码，不修改命中次数
+func (r *RedirectRepositoryImpl) Update(redirect *models.Redirect) error {
+	return r.db.Model(redirect).Select("from_path", "to_path", "status_code", "source").Updates(redirect).Error
+}
+
+// Delete 删除重定向
+func (r *RedirectRepositoryImpl) Delete(id uint64) error {
+	return r.db.Delete(&models.Redirect{}, id).Error
+}
+
+// DeleteByFromPath 删除来源路径的重定向
+func (r *RedirectRepositoryImpl) DeleteByFromPath(fromPath string) error {
+	return r.db.Where("from_path = ?", fromPath).Delete(&models.Redirect{}).Error
+}
+
+// Save 新增重定向，来源路径已存在时覆盖目标、状态码和来源，保留命中次数
+func (r *RedirectRepositoryImpl) Save(redirect *models.Redirect) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "from_path"}},
+		DoUpdates: clause.AssignmentColumns([]string{"to_path", "status_code", "source", "updated_at"}),
+	}).Create(redirect).Error
+}
+
+// Retarget 将指向旧目标的重定向改为指向新目标
+func (r *RedirectRepositoryImpl) Retarget(oldToPath, newToPath string) error {
+	return r.db.Model(&models.Redirect{}).Where("to_path = ?", oldToPath).
+		Updates(map[string]interface{}{
+			"to_path":    newToPath,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// IncrementHits 命中次数加一
+func (r *RedirectRepositoryImpl) IncrementHits(id uint64) error {
+	return r.db.Model(&models.Redirect{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"hits":        gorm.Expr("hits + 1"),
+			"last_hit_at": time.Now(),
+		}).Error
+}
@@ -0,0 +1,127 @@
+package mysql
+
+import (
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type ScriptRepository struct {
+	db *gorm.DB
+}
+
+func NewScriptRepository(db *gorm.DB) repository.ScriptRepository {
+	return &ScriptRepository{db: db}
+}
+
+// Create 创建脚本及其第一个版本
+func (r *ScriptRepository) Create(script *opsModel.Script, version *opsModel.ScriptVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(script).Error; err != nil {
+			return err
+		}
+		version.ScriptID = script.ID
+		version.Version = script.LatestVersion
+		return tx.Create(version).Error
+	})
+}
+
+func (r *ScriptRepository) Update(script *opsModel.Script) error {
+	return r.db.Save(script).Error
+}
+
+// Delete 删除脚本及其所有版本
+func (r *ScriptRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("script_id = ?", id).Delete(&opsModel.ScriptVersion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&opsModel.Script{}, id).Error
+	})
+}
+
+func (r *ScriptRepository) GetByID(id uint) (*opsModel.Script, error) {
+	var script opsModel.Script
+	err := r.db.First(&script, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &script, nil
+}
+
+func (r *ScriptRepository) GetByName(name string) (*opsModel.Script, error) {
+	var script opsModel.Script
+	err := r.db.Where("name = ?", name).First(&script).Error
+	if err != nil {
+		return nil, err
+	}
+	return &script, nil
+}
+
+func (r *ScriptRepository) List(page, pageSize int, name, language string, owner uint) ([]*opsModel.Script, int64, error) {
+	var scripts []*opsModel.Script
+	var total int64
+
+	query := r.db.Model(&opsModel.Script{})
+	if name != "" {
+		query = query.Where("name LIKE ?", "%"+name+"%")
+	}
+	if language != "" {
+		query = query.Where("language = ?", language)
+	}
+	if owner > 0 {
+		query = query.Where("owner = ?", owner)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&scripts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return scripts, total, nil
+}
+
+// AddVersion 新增脚本版本并更新脚本的最新版本号
+func (r *ScriptRepository) AddVersion(script *opsModel.Script, version *opsModel.ScriptVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&opsModel.ScriptVersion{}).
+			Where("script_id = ?", script.ID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		version.ScriptID = script.ID
+		version.Version = latest + 1
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+
+		script.LatestVersion = version.Version
+		return tx.Save(script).Error
+	})
+}
+
+func (r *ScriptRepository) GetVersion(scriptID uint, version int) (*opsModel.ScriptVersion, error) {
+	var v opsModel.ScriptVersion
+	err := r.db.Where("script_id = ? AND version = ?", scriptID, version).First(&v).Error
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *ScriptRepository) ListVersions(scriptID uint) ([]*opsModel.ScriptVersion, error) {
+	var versions []*opsModel.ScriptVersion
+	err := r.db.Where("script_id = ?", scriptID).Order("version DESC").Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
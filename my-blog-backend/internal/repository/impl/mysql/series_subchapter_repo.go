@@ -95,3 +95,16 @@ func (r *SeriesSubchapterRepositoryImpl) GetArticles(subchapterID uint) ([]*mode
 		Find(&articles).Error
 	return articles, err
 }
+
+// ListPlacements 获取全部文章在系列中的位置
+func (r *SeriesSubchapterRepositoryImpl) ListPlacements() ([]*repository.SeriesPlacement, error) {
+	var placements []*repository.SeriesPlacement
+	err := r.db.Table("subchapter_articles sa").
+		Select("sa.article_id, s.slug AS series_slug, ss.name AS section, sc.name AS subchapter, sa.sort_order").
+		Joins("JOIN series_subchapters sc ON sc.id = sa.subchapter_id").
+		Joins("JOIN series_sections ss ON ss.id = sc.section_id").
+		Joins("JOIN series s ON s.id = ss.series_id").
+		Order("sa.article_id ASC, s.sort_order ASC, ss.sort_order ASC, sc.sort_order ASC").
+		Scan(&placements).Error
+	return placements, err
+}
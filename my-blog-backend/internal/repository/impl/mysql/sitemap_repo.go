@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"fmt"
+	"time"
+
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type SitemapRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSitemapRepository(db *gorm.DB) repository.SitemapRepository {
+	return &SitemapRepositoryImpl{db: db}
+}
+
+// query 构造各类型的公开内容查询，返回查询和更新时间字段；标签没有更新时间，使用创建时间
+func (r *SitemapRepositoryImpl) query(kind repository.SitemapKind) (*gorm.DB, string, error) {
+	switch kind {
+	case repository.SitemapArticles:
+		return r.db.Model(&models.Article{}).Where("status = ?", models.ArticleStatusPublished), "updated_at", nil
+	case repository.SitemapCategories:
+		return r.db.Model(&models.Category{}).Where("status = ?", 1), "updated_at", nil
+	case repository.SitemapTags:
+		return r.db.Model(&models.Tag{}), "created_at", nil
+	case repository.SitemapSeries:
+		return r.db.Model(&models.Series{}).Where("status = ?", 1), "updated_at", nil
+	}
+	return nil, "", fmt.Errorf("unknown sitemap kind: %s", kind)
+}
+
+// Count 统计公开内容数量
+func (r *SitemapRepositoryImpl) Count(kind repository.SitemapKind) (int64, error) {
+	query, _, err := r.query(kind)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	err = query.Count(&count).Error
+	return count, err
+}
+
+// List 分页获取公开内容的 slug 和更新时间
+func (r *SitemapRepositoryImpl) List(kind repository.SitemapKind, offset, limit int) ([]repository.SitemapEntry, error) {
+	query, updatedColumn, err := r.query(kind)
+	if err != nil {
+		return nil, err
+	}
+	var entries []repository.SitemapEntry
+	err = query.Select("slug, " + updatedColumn + " AS updated_at").
+		Order("id").Offset(offset).Limit(limit).
+		Scan(&entries).Error
+	return entries, err
+}
+
+// LastModified 获取公开内容的最近更新时间
+func (r *SitemapRepositoryImpl) LastModified(kind repository.SitemapKind) (time.Time, error) {
+	query, updatedColumn, err := r.query(kind)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var latest struct {
+		UpdatedAt time.Time
+	}
+	err = query.Select(updatedColumn + " AS updated_at").Order(updatedColumn + " DESC").Limit(1).Scan(&latest).Error
+	return latest.UpdatedAt, err
+}
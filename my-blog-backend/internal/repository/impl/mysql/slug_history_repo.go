@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/repository"
+)
+
+// SlugHistoryRepositoryImpl slug 历史记录仓储实现
+type SlugHistoryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSlugHistoryRepositoryImpl 创建 slug 历史记录仓储实例
+func NewSlugHistoryRepositoryImpl(db *gorm.DB) repository.SlugHistoryRepository {
+	return &SlugHistoryRepositoryImpl{db: db}
+}
+
+// GetEntityID 根据旧 slug 获取实体ID
+func (r *SlugHistoryRepositoryImpl) GetEntityID(entityType, oldSlug string) (uint64, error) {
+	var history models.SlugHistory
+	if err := r.db.Where("entity_type = ? AND old_slug = ?", entityType, oldSlug).First(&history).Error; err != nil {
+		return 0, err
+	}
+	return history.EntityID, nil
+}
+
+// Save 保存旧 slug，同类型的旧 slug 已存在时改为指向新的实体
+func (r *SlugHistoryRepositoryImpl) Save(history *models.SlugHistory) error {
+	if history.CreatedAt.IsZero() {
+		history.CreatedAt = time.Now()
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity_type"}, {Name: "old_slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"entity_id", "created_at"}),
+	}).Create(history).Error
+}
+
+// Delete 删除旧 slug 记录
+func (r *SlugHistoryRepositoryImpl) Delete(entityType, oldSlug string) error {
+	return r.db.Where("entity_type = ? AND old_slug = ?", entityType, oldSlug).Delete(&models.SlugHistory{}).Error
+}
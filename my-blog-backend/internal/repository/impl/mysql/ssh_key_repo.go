@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"time"
+
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type SSHKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewSSHKeyRepository(db *gorm.DB) repository.SSHKeyRepository {
+	return &SSHKeyRepository{db: db}
+}
+
+func (r *SSHKeyRepository) Create(key *opsModel.SSHKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *SSHKeyRepository) Update(key *opsModel.SSHKey) error {
+	return r.db.Save(key).Error
+}
+
+func (r *SSHKeyRepository) Delete(id uint) error {
+	return r.db.Delete(&opsModel.SSHKey{}, id).Error
+}
+
+func (r *SSHKeyRepository) GetByID(id uint) (*opsModel.SSHKey, error) {
+	var key opsModel.SSHKey
+	err := r.db.First(&key, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *SSHKeyRepository) List(page, pageSize int, name, status string) ([]*opsModel.SSHKey, int64, error) {
+	var keys []*opsModel.SSHKey
+	var total int64
+
+	// 列表不返回私钥
+	query := r.db.Model(&opsModel.SSHKey{}).Omit("private_key")
+	if name != "" {
+		query = query.Where("name LIKE ?", "%"+name+"%")
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&keys).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return keys, total, nil
+}
+
+// ListDueForRotation 获取已到轮换时间的使用中密钥
+func (r *SSHKeyRepository) ListDueForRotation(now time.Time) ([]*opsModel.SSHKey, error) {
+	var keys []*opsModel.SSHKey
+	err := r.db.Where("status = ? AND rotate_days > 0 AND next_rotate_at <= ?", opsModel.SSHKeyActive, now).
+		Order("id ASC").Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"my-blog-backend/internal/models"
+	"my-blog-backend/internal/repository"
+)
+
+type SysAPITokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSysAPITokenRepositoryImpl(db *gorm.DB) repository.SysAPITokenRepository {
+	return &SysAPITokenRepositoryImpl{db: db}
+}
+
+func (r *SysAPITokenRepositoryImpl) Create(token *models.SysAPIToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *SysAPITokenRepositoryImpl) FindByHash(tokenHash string) (*models.SysAPIToken, error) {
+	var token models.SysAPIToken
+	err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *SysAPITokenRepositoryImpl) ListByUserID(userID uint64) ([]*models.SysAPIToken, error) {
+	var tokens []*models.SysAPIToken
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *SysAPITokenRepositoryImpl) Delete(userID, id uint64) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.SysAPIToken{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *SysAPITokenRepositoryImpl) UpdateLastUsed(id uint64, usedAt time.Time, ip string) error {
+	return r.db.Model(&models.SysAPIToken{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": usedAt, "last_used_ip": ip}).Error
+}
@@ -42,6 +42,16 @@ func (r *TagRepositoryImpl) GetByID(id uint) (*models.Tag, error) {
 	return &tag, nil
 }
 
+// GetByName 根据名称获取标签
+func (r *TagRepositoryImpl) GetByName(name string) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Where("name = ?", name).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
 // GetBySlug 根据slug获取标签
 func (r *TagRepositoryImpl) GetBySlug(slug string) (*models.Tag, error) {
 	var tag models.Tag
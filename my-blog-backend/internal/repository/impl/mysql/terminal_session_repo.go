@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"time"
+
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type TerminalSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewTerminalSessionRepository(db *gorm.DB) repository.TerminalSessionRepository {
+	return &TerminalSessionRepository{db: db}
+}
+
+func (r *TerminalSessionRepository) Create(session *opsModel.TerminalSession) error {
+	return r.db.Create(session).Error
+}
+
+func (r *TerminalSessionRepository) Update(session *opsModel.TerminalSession) error {
+	return r.db.Save(session).Error
+}
+
+func (r *TerminalSessionRepository) GetByID(id uint) (*opsModel.TerminalSession, error) {
+	var session opsModel.TerminalSession
+	err := r.db.First(&session, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *TerminalSessionRepository) List(page, pageSize int, userID, hostID uint, keyword string) ([]*opsModel.TerminalSession, int64, error) {
+	var sessions []*opsModel.TerminalSession
+	var total int64
+
+	query := r.db.Model(&opsModel.TerminalSession{})
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if hostID > 0 {
+		query = query.Where("host_id = ?", hostID)
+	}
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		query = query.Where("user_name LIKE ? OR host_name LIKE ? OR host_address LIKE ? OR client_ip LIKE ? OR session_id = ?",
+			like, like, like, like, keyword)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&sessions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return sessions, total, nil
+}
+
+func (r *TerminalSessionRepository) CloseActive(endedAt time.Time, reason string) (int64, error) {
+	result := r.db.Model(&opsModel.TerminalSession{}).
+		Where("status = ?", opsModel.TerminalSessionActive).
+		Updates(map[string]interface{}{
+			"status":       opsModel.TerminalSessionClosed,
+			"close_reason": reason,
+			"ended_at":     endedAt,
+		})
+	return result.RowsAffected, result.Error
+}
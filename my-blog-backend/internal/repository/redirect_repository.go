@@ -0,0 +1,26 @@
+package repository
+
+import (
+	models "my-blog-backend/internal/models/frontendModel"
+)
+
+// RedirectRepository 重定向仓储接口
+type RedirectRepository interface {
+	GetByID(id uint64) (*models.Redirect, error)
+	GetByFromPath(fromPath string) (*models.Redirect, error)
+	List(page, pageSize int, source, keyword string) ([]*models.Redirect, int64, error)
+	Create(redirect *models.Redirect) error
+	Update(redirect *models.Redirect) error
+	Delete(id uint64) error
+	DeleteByFromPath(fromPath string) error
+	Save(redirect *models.Redirect) error       // 按来源路径新增或覆盖目标
+	Retarget(oldToPath, newToPath string) error // 指向旧目标的重定向改为指向新目标，避免多次跳转
+	IncrementHits(id uint64) error              // 命中次数加一并记录命中时间
+}
+
+// SlugHistoryRepository slug 历史记录仓储接口
+type SlugHistoryRepository interface {
+	GetEntityID(entityType, oldSlug string) (uint64, error)
+	Save(history *models.SlugHistory) error // 旧 slug 已有记录时改为指向新的实体
+	Delete(entityType, oldSlug string) error
+}
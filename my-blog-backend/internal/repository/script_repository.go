@@ -0,0 +1,18 @@
+package repository
+
+import (
+	models "my-blog-backend/internal/models/opsModel"
+)
+
+// ScriptRepository 脚本库仓储接口
+type ScriptRepository interface {
+	Create(script *models.Script, version *models.ScriptVersion) error
+	Update(script *models.Script) error
+	Delete(id uint) error
+	GetByID(id uint) (*models.Script, error)
+	GetByName(name string) (*models.Script, error)
+	List(page, pageSize int, name, language string, owner uint) ([]*models.Script, int64, error)
+	AddVersion(script *models.Script, version *models.ScriptVersion) error
+	GetVersion(scriptID uint, version int) (*models.ScriptVersion, error)
+	ListVersions(scriptID uint) ([]*models.ScriptVersion, error)
+}
@@ -2,6 +2,15 @@ package repository
 
 import models "my-blog-backend/internal/models/frontendModel"
 
+// SeriesPlacement 文章在系列中的位置
+type SeriesPlacement struct {
+	ArticleID  uint64
+	SeriesSlug string
+	Section    string // 章节名称
+	Subchapter string // 子章节名称
+	SortOrder  int
+}
+
 // SeriesRepository 系列仓储接口
 type SeriesRepository interface {
 	Create(series *models.Series) error
@@ -31,4 +40,5 @@ type SeriesSubchapterRepository interface {
 	AddArticle(subchapterID, articleID uint, sortOrder int) error
 	RemoveArticle(subchapterID, articleID uint) error
 	GetArticles(subchapterID uint) ([]*models.Article, error)
+	ListPlacements() ([]*SeriesPlacement, error) // 获取全部文章在系列中的位置，用于导出
 }
@@ -0,0 +1,29 @@
+package repository
+
+import "time"
+
+// SitemapKind 站点地图内容类型
+type SitemapKind string
+
+const (
+	SitemapArticles   SitemapKind = "articles"
+	SitemapCategories SitemapKind = "categories"
+	SitemapTags       SitemapKind = "tags"
+	SitemapSeries     SitemapKind = "series"
+)
+
+// SitemapKinds 站点地图中内容类型的输出顺序
+var SitemapKinds = []SitemapKind{SitemapArticles, SitemapCategories, SitemapTags, SitemapSeries}
+
+// SitemapEntry 站点地图条目
+type SitemapEntry struct {
+	Slug      string
+	UpdatedAt time.Time
+}
+
+// SitemapRepository 站点地图仓储接口，只返回公开可访问的内容
+type SitemapRepository interface {
+	Count(kind SitemapKind) (int64, error)
+	List(kind SitemapKind, offset, limit int) ([]SitemapEntry, error) // 按ID顺序分页，保证分页结果稳定
+	LastModified(kind SitemapKind) (time.Time, error)                 // 最近更新时间，没有内容时为零值
+}
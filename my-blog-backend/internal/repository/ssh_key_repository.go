@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+
+	models "my-blog-backend/internal/models/opsModel"
+)
+
+// SSHKeyRepository 托管密钥仓储接口
+type SSHKeyRepository interface {
+	Create(key *models.SSHKey) error
+	Update(key *models.SSHKey) error
+	Delete(id uint) error
+	GetByID(id uint) (*models.SSHKey, error)
+	List(page, pageSize int, name, status string) ([]*models.SSHKey, int64, error)
+	ListDueForRotation(now time.Time) ([]*models.SSHKey, error)
+}
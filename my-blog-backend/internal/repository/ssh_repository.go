@@ -2,6 +2,7 @@ package repository
 
 import (
 	models "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/utils"
 )
 
 type HostRepository interface {
@@ -9,6 +10,16 @@ type HostRepository interface {
 	Update(host *models.RemoteHost) error
 	Delete(id uint) error
 	GetByID(id uint) (*models.RemoteHost, error)
-	List(page, pageSize int, name, address, hostType, status string) ([]*models.RemoteHost, int64, error)
+	List(page, pageSize int, name, address, hostType, status string, selector utils.LabelSelector) ([]*models.RemoteHost, int64, error)
 	GetAll() ([]*models.RemoteHost, error)
+	ListBySelector(selector utils.LabelSelector) ([]*models.RemoteHost, error)
+	ListByKeyID(keyID uint) ([]*models.RemoteHost, error)
+	SetLabels(hostID uint, labels map[string]string) error
+	GetLabels(hostIDs []uint) (map[uint]map[string]string, error)
+	ListLabels() ([]*models.HostLabel, error)
+	ListUserPermissions(userID uint) ([]*models.HostUserPermission, error)
+	ListPermissions(userGroupID uint) ([]*models.HostUserPermission, error) // userGroupID 为 0 时返回全部授权
+	CreatePermission(permission *models.HostUserPermission) error
+	DeletePermission(id uint) error
+	ListHostGroupIDs(hostID uint) ([]uint, error)
 }
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"time"
+
+	"my-blog-backend/internal/models"
+)
+
+// SysAPITokenRepository API Token 仓储接口
+type SysAPITokenRepository interface {
+	// Create 创建 Token
+	Create(token *models.SysAPIToken) error
+	// FindByHash 根据 Token 哈希查找
+	FindByHash(tokenHash string) (*models.SysAPIToken, error)
+	// ListByUserID 获取用户的所有 Token
+	ListByUserID(userID uint64) ([]*models.SysAPIToken, error)
+	// Delete 删除用户的指定 Token，返回是否存在
+	Delete(userID, id uint64) (bool, error)
+	// UpdateLastUsed 更新最后使用时间和 IP
+	UpdateLastUsed(id uint64, usedAt time.Time, ip string) error
+}
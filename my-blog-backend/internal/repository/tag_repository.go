@@ -11,6 +11,7 @@ type TagRepository interface {
 	Delete(id uint) error
 	GetByID(id uint) (*models.Tag, error)
 	GetBySlug(slug string) (*models.Tag, error)
+	GetByName(name string) (*models.Tag, error)
 	List(page, pageSize int) ([]*models.Tag, int64, error)
 	GetByArticleID(articleID uint) ([]*models.Tag, error)
 	GetTagArticles(tagID uint, page, pageSize int) ([]*models.Article, int64, error)
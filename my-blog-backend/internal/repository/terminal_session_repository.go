@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+
+	models "my-blog-backend/internal/models/opsModel"
+)
+
+// TerminalSessionRepository 终端会话记录仓储接口
+type TerminalSessionRepository interface {
+	Create(session *models.TerminalSession) error
+	Update(session *models.TerminalSession) error
+	GetByID(id uint) (*models.TerminalSession, error)
+	List(page, pageSize int, userID, hostID uint, keyword string) ([]*models.TerminalSession, int64, error)
+	// CloseActive 把仍处于进行中的记录标记为结束（服务重启后内存中的会话已不存在）
+	CloseActive(endedAt time.Time, reason string) (int64, error)
+}
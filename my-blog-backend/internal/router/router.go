@@ -16,10 +16,14 @@ type Handlers struct {
 	Auth         *apiv1.AuthHandler
 	User         *apiv1.UserHandler
 	Article      *apiv1.ArticleHandler
+	Archive      *apiv1.ArticleArchiveHandler
 	Category     *apiv1.CategoryHandler
 	Tag          *apiv1.TagHandler
 	Comment      *apiv1.CommentHandler
 	Series       *apiv1.SeriesHandler
+	Feed         *apiv1.FeedHandler
+	SEO          *apiv1.SEOHandler
+	Redirect     *apiv1.RedirectHandler
 	Favorite     *apiv1.FavoriteHandler
 	Upload       *apiv1.UploadHandler
 	UserActivity *apiv1.UserActivityHandler
@@ -31,6 +35,14 @@ type Handlers struct {
 	Host         *apiv1.HostHandler
 	Ssh          *apiv1.SshHandler
 	Sftp         *apiv1.SshFileHandler
+	Script       *apiv1.ScriptHandler
+	BatchTask    *apiv1.BatchTaskHandler
+	FileTransfer *apiv1.FileTransferHandler
+	SSHKey       *apiv1.SSHKeyHandler
+	CommandExec  *apiv1.CommandExecHandler
+	APIToken     *apiv1.SysAPITokenHandler
+	HostAccount  *apiv1.HostAccountHandler
+	Container    *apiv1.ContainerHandler
 }
 
 // SetupRouter 设置路由
@@ -46,6 +58,10 @@ func SetupRouter(engine *gin.Engine, handlers *Handlers) {
 	// swagger文档路由
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// 订阅源、站点地图
+	setupFeedRoutes(engine, handlers)
+	setupSEORoutes(engine, handlers)
+
 	// API v1 路由
 	v1 := engine.Group("/api/v1")
 	{
@@ -59,6 +75,26 @@ func SetupRouter(engine *gin.Engine, handlers *Handlers) {
 		setupAdminRoutes(v1, handlers)
 
 	}
+
+	// 未匹配的路径按重定向表跳转（旧文章地址等）
+	engine.NoRoute(handlers.Redirect.NoRoute)
+}
+
+// 订阅源路由 - 无需认证，挂在根路径下便于阅读器发现
+func setupFeedRoutes(engine *gin.Engine, handlers *Handlers) {
+	engine.GET("/feed.xml", handlers.Feed.RSS)                       // 全站 RSS
+	engine.GET("/atom.xml", handlers.Feed.Atom)                      // 全站 Atom
+	engine.GET("/feed.json", handlers.Feed.JSON)                     // 全站 JSON Feed
+	engine.GET("/categories/:slug/feed", handlers.Feed.CategoryFeed) // 分类订阅源
+	engine.GET("/tags/:slug/feed", handlers.Feed.TagFeed)            // 标签订阅源
+	engine.GET("/series/:slug/feed", handlers.Feed.SeriesFeed)       // 系列订阅源
+}
+
+// 站点地图和 robots.txt 路由 - 无需认证
+func setupSEORoutes(engine *gin.Engine, handlers *Handlers) {
+	engine.GET("/robots.txt", handlers.SEO.Robots)         // robots.txt
+	engine.GET("/sitemap.xml", handlers.SEO.Sitemap)       // 站点地图或站点地图索引
+	engine.GET("/sitemap/:name", handlers.SEO.SitemapPage) // 子站点地图，如 articles-1.xml
 }
 
 // 公开路由 - 无需认证，前台和后台都可以访问
@@ -80,11 +116,16 @@ func setupPublicRoutes(router *gin.RouterGroup, handlers *Handlers) {
 		// 文章公开查询
 		articles := public.Group("/articles")
 		{
-			articles.GET("", handlers.Article.ListArticles)             // 获取文章列表
-			articles.GET("/search", handlers.Article.SearchArticles)    // 搜索文章
-			articles.GET("/hot", handlers.Article.GetHotArticles)       // 获取热门文章
-			articles.GET("/recent", handlers.Article.GetRecentArticles) // 获取最新文章
-			articles.GET("/:id", handlers.Article.GetArticle)           // 获取文章详情
+			articles.GET("", handlers.Article.ListArticles)                // 获取文章列表
+			articles.GET("/search", handlers.Article.SearchArticles)       // 搜索文章
+			articles.GET("/hot", handlers.Article.GetHotArticles)          // 获取热门文章
+			articles.GET("/recent", handlers.Article.GetRecentArticles)    // 获取最新文章
+			articles.GET("/highlight.css", handlers.Article.HighlightCSS)  // 代码高亮样式表
+			articles.GET("/slug/:slug", handlers.Article.GetArticleBySlug) // 根据slug获取文章详情
+			articles.GET("/:id", handlers.Article.GetArticle)              // 获取文章详情
+
+			// 记录阅读量，游客也计入；携带 token 时按用户去重
+			articles.POST("/:id/view", middleware.OptionalAuth(), handlers.Article.ViewArticle)
 		}
 
 		// 分类公开查询
@@ -122,6 +163,9 @@ func setupPublicRoutes(router *gin.RouterGroup, handlers *Handlers) {
 			series.GET("/subchapters/:id", handlers.Series.GetSubchapter)                      // 获取子章节详情
 			series.GET("/subchapters/:id/articles", handlers.Series.GetArticlesBySubchapterID) // 获取子章节文章列表
 		}
+
+		// 重定向查询
+		public.GET("/redirects/resolve", handlers.Redirect.Resolve) // 查询旧地址的跳转目标
 	}
 }
 
@@ -205,6 +249,9 @@ func setupAdminRoutes(router *gin.RouterGroup, handlers *Handlers) {
 		rbacSecure.GET("/auth/info", handlers.SysAuth.GetInfo)
 		rbacSecure.GET("/auth/menu", handlers.SysAuth.GetMenu)
 		rbacSecure.GET("/auth/permission", handlers.SysAuth.CheckPermission)
+		rbacSecure.GET("/auth/api-tokens", handlers.APIToken.ListTokens)
+		rbacSecure.POST("/auth/api-tokens", handlers.APIToken.CreateToken)
+		rbacSecure.DELETE("/auth/api-tokens/:id", handlers.APIToken.DeleteToken)
 
 		// 用户管理
 		rbacSecure.DELETE("/users/:id", handlers.SysUser.DeleteUser)
@@ -234,10 +281,17 @@ func setupAdminRoutes(router *gin.RouterGroup, handlers *Handlers) {
 
 		// 文章管理
 		rbacSecure.POST("/articles/import", handlers.Article.ImportMarkdownArticle)
+		rbacSecure.POST("/articles/import/archive", handlers.Archive.Import)
+		rbacSecure.POST("/articles/import/blog", handlers.Archive.ImportBlog)
+		rbacSecure.GET("/articles/export", handlers.Archive.Export)
 		rbacSecure.PUT("/articles/:id/status", handlers.Article.UpdateArticleStatus)
 		rbacSecure.POST("/articles", handlers.Article.CreateArticle)
 		rbacSecure.PUT("/articles/:id", handlers.Article.UpdateArticle)
 		rbacSecure.DELETE("/articles/:id", handlers.Article.DeleteArticle)
+		rbacSecure.GET("/articles/:id/revisions", handlers.Article.ListRevisions)
+		rbacSecure.GET("/articles/:id/revisions/:version", handlers.Article.GetRevision)
+		rbacSecure.POST("/articles/:id/revisions/:version/restore", handlers.Article.RestoreRevision)
+		rbacSecure.GET("/articles/:id/diff", handlers.Article.DiffRevisions)
 
 		// 评论管理
 		rbacSecure.GET("/comments", handlers.Comment.ListAllComments)
@@ -269,6 +323,12 @@ func setupAdminRoutes(router *gin.RouterGroup, handlers *Handlers) {
 		rbacSecure.POST("/series/:id/sections", handlers.Series.CreateSection)
 		rbacSecure.POST("/series/subchapters/:id/articles", handlers.Series.AddArticleToSubchapter)
 
+		// 重定向管理
+		rbacSecure.GET("/redirects", handlers.Redirect.ListRedirects)
+		rbacSecure.POST("/redirects", handlers.Redirect.CreateRedirect)
+		rbacSecure.PUT("/redirects/:id", handlers.Redirect.UpdateRedirect)
+		rbacSecure.DELETE("/redirects/:id", handlers.Redirect.DeleteRedirect)
+
 		// 文件上传
 		rbacSecure.POST("/upload/markdown", handlers.Upload.UploadMarkdown)
 		rbacSecure.POST("/upload/word", handlers.Upload.UploadWord)
@@ -292,19 +352,79 @@ func setupAdminRoutes(router *gin.RouterGroup, handlers *Handlers) {
 		// 主机管理
 		rbacSecure.GET("/hosts", handlers.Host.ListHosts)
 		rbacSecure.GET("/hosts/all", handlers.Host.GetAllHosts)
+		rbacSecure.GET("/hosts/labels", handlers.Host.ListLabels)
 		rbacSecure.GET("/hosts/:id", handlers.Host.GetHost)
 		rbacSecure.POST("/hosts", handlers.Host.CreateHost)
 		rbacSecure.PUT("/hosts", handlers.Host.UpdateHost)
 		rbacSecure.DELETE("/hosts/:id", handlers.Host.DeleteHost)
+		rbacSecure.POST("/hosts/test", handlers.Host.DiagnoseHosts)
 		rbacSecure.POST("/hosts/:id/test", handlers.Host.TestConnection)
+		rbacSecure.GET("/hosts/:id/containers", handlers.Container.ListContainers)
+
+		// 主机授权（用户组 <-> 主机组 / 标签选择器）
+		rbacSecure.GET("/host-permissions", handlers.Host.ListPermissions)
+		rbacSecure.POST("/host-permissions", handlers.Host.CreatePermission)
+		rbacSecure.DELETE("/host-permissions/:id", handlers.Host.DeletePermission)
 
 		// SSH 终端（只需要 RBAC 认证，WebSocket 无法携带 Once-Token）
 		rbacAuth.GET("/ssh/connect/:host_id", handlers.Ssh.WebSocketConnect)
+		rbacAuth.GET("/ssh/connect/:host_id/containers/:container", handlers.Ssh.ContainerConnect)
+		rbacAuth.GET("/ssh/broadcast", handlers.Ssh.BroadcastConnect)
 		rbacSecure.GET("/ssh/sessions", handlers.Ssh.ListSessions)
+		rbacSecure.GET("/ssh/sessions/history", handlers.Ssh.ListSessionHistory)
+		rbacSecure.GET("/ssh/sessions/history/:id/recording", handlers.Ssh.DownloadRecording)
 		rbacSecure.DELETE("/ssh/sessions/:session_id", handlers.Ssh.CloseSession)
+		rbacSecure.DELETE("/ssh/users/:user_id/sessions", handlers.Ssh.KickUser)
 
 		//sftp终端
 		rbacSecure.POST("/sftp/uploadFile", handlers.Sftp.UploadFile)
 		rbacSecure.GET("/sftp/list", handlers.Sftp.List)
+
+		// 脚本库
+		rbacSecure.GET("/scripts", handlers.Script.ListScripts)
+		rbacSecure.POST("/scripts", handlers.Script.CreateScript)
+		rbacSecure.PUT("/scripts", handlers.Script.UpdateScript)
+		rbacSecure.POST("/scripts/run", handlers.Script.RunScript)
+		rbacSecure.GET("/scripts/:id", handlers.Script.GetScript)
+		rbacSecure.DELETE("/scripts/:id", handlers.Script.DeleteScript)
+		rbacSecure.GET("/scripts/:id/versions", handlers.Script.ListVersions)
+		rbacSecure.GET("/scripts/:id/diff", handlers.Script.DiffVersions)
+
+		// 批量任务
+		rbacSecure.GET("/tasks", handlers.BatchTask.ListTasks)
+		rbacSecure.GET("/tasks/:id", handlers.BatchTask.GetTask)
+		rbacSecure.POST("/tasks/:id/cancel", handlers.BatchTask.CancelTask)
+		rbacSecure.GET("/tasks/:id/archive", handlers.FileTransfer.DownloadArchive)
+		// 任务实时输出（WebSocket 无法携带 Once-Token，只需要 RBAC 认证）
+		rbacAuth.GET("/tasks/:id/stream", handlers.BatchTask.StreamTask)
+
+		// 文件分发与拉取
+		rbacSecure.POST("/files/distribute", handlers.FileTransfer.Distribute)
+		rbacSecure.POST("/files/collect", handlers.FileTransfer.Collect)
+
+		// 密钥管理
+		rbacSecure.GET("/keys", handlers.SSHKey.ListKeys)
+		rbacSecure.POST("/keys", handlers.SSHKey.GenerateKey)
+		rbacSecure.PUT("/keys", handlers.SSHKey.UpdateKey)
+		rbacSecure.GET("/keys/:id", handlers.SSHKey.GetKey)
+		rbacSecure.DELETE("/keys/:id", handlers.SSHKey.DeleteKey)
+		rbacSecure.POST("/keys/:id/deploy", handlers.SSHKey.DeployKey)
+		rbacSecure.POST("/keys/:id/rotate", handlers.SSHKey.RotateKey)
+
+		// 主机账号
+		rbacSecure.GET("/host-accounts", handlers.HostAccount.ListAccounts)
+		rbacSecure.POST("/host-accounts", handlers.HostAccount.CreateAccount)
+		rbacSecure.PUT("/host-accounts", handlers.HostAccount.UpdateAccount)
+		rbacSecure.GET("/host-accounts/:id", handlers.HostAccount.GetAccount)
+		rbacSecure.DELETE("/host-accounts/:id", handlers.HostAccount.DeleteAccount)
+		rbacSecure.POST("/host-accounts/:id/rotate", handlers.HostAccount.RotatePassword)
+		rbacSecure.GET("/host-accounts/:id/rotations", handlers.HostAccount.ListRotations)
+	}
+
+	// 自动化接口（支持 API Token；未携带 Token 时仍需 Session + Once-Token）
+	rbacAPI := router.Group("/rbac")
+	rbacAPI.Use(middleware.APITokenAuth(), middleware.RBACAuth(), middleware.OnceTokenMiddleware())
+	{
+		rbacAPI.POST("/hosts/:id/exec", handlers.CommandExec.ExecCommand)
 	}
 }
@@ -0,0 +1,865 @@
+package services
+
+import (
+	"archive/zip"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"my-blog-backend/internal/api/v1/dto/response"
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/pkg/frontmatter"
+	"my-blog-backend/internal/pkg/importer"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/pkg/utils"
+	"my-blog-backend/internal/repository"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// 归档目录结构：articles/{slug}.md 为带 front matter 的文章，images/ 为文章引用的本地图片，
+// 文章中的图片链接改写为相对于文章文件的路径（../images/xxx.png）
+const (
+	archiveArticleDir = "articles/"
+	archiveImageDir   = "images/"
+	// archiveMaxFileSize 归档中单个 Markdown 文件的最大大小
+	archiveMaxFileSize = 10 * 1024 * 1024
+)
+
+var (
+	// markdownImagePattern Markdown 图片 ![alt](url "title")，第一个分组为链接
+	markdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+["'(][^)]*)?\)`)
+	// htmlImagePattern HTML 图片 <img src="url">，第一个分组为链接
+	htmlImagePattern = regexp.MustCompile(`(?i)<img\s[^>]*?src\s*=\s*["']([^"']+)["']`)
+)
+
+// ArchiveTerm 分类或标签，front matter 中可以写成名称字符串，也可以写成 {name, slug}
+type ArchiveTerm struct {
+	Name string `yaml:"name"`
+	Slug string `yaml:"slug,omitempty"`
+}
+
+func (t *ArchiveTerm) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		t.Name = strings.TrimSpace(node.Value)
+		return nil
+	}
+	type plain ArchiveTerm
+	return node.Decode((*plain)(t))
+}
+
+// ArchiveSeriesPlacement 文章在系列中的位置
+type ArchiveSeriesPlacement struct {
+	Series     string `yaml:"series"` // 系列 slug
+	Section    string `yaml:"section"`
+	Subchapter string `yaml:"subchapter"`
+	Order      int    `yaml:"order,omitempty"`
+}
+
+// ArchiveFrontMatter 归档中文章的 front matter
+type ArchiveFrontMatter struct {
+	Title           string                   `yaml:"title"`
+	Slug            string                   `yaml:"slug"`
+	Summary         string                   `yaml:"summary,omitempty"`
+	Cover           string                   `yaml:"cover,omitempty"`
+	Category        *ArchiveTerm             `yaml:"category,omitempty"`
+	Tags            []ArchiveTerm            `yaml:"tags,omitempty"`
+	Series          []ArchiveSeriesPlacement `yaml:"series,omitempty"`
+	Status          string                   `yaml:"status"` // draft、published、scheduled
+	IsTop           bool                     `yaml:"is_top,omitempty"`
+	PublishedAt     *time.Time               `yaml:"published_at,omitempty"`
+	UnpublishAt     *time.Time               `yaml:"unpublish_at,omitempty"`
+	CreatedAt       *time.Time               `yaml:"created_at,omitempty"`
+	UpdatedAt       *time.Time               `yaml:"updated_at,omitempty"` // 导出时文章的更新时间，导入时用于判断站内文章是否在导出后被修改
+	MetaTitle       string                   `yaml:"meta_title,omitempty"`
+	MetaDescription string                   `yaml:"meta_description,omitempty"`
+	CanonicalURL    string                   `yaml:"canonical_url,omitempty"`
+	OGImage         string                   `yaml:"og_image,omitempty"`
+}
+
+var archiveStatusNames = map[uint8]string{
+	models.ArticleStatusDraft:     "draft",
+	models.ArticleStatusPublished: "published",
+	models.ArticleStatusScheduled: "scheduled",
+}
+
+// ArticleArchiveImportOptions 导入选项
+type ArticleArchiveImportOptions struct {
+	DryRun    bool // 只检查不写入
+	Overwrite bool // 站内文章在导出后被修改时仍然覆盖
+	EditorID  uint // 导入人，作为新文章的作者和修订的编辑人
+}
+
+// ArticleArchiveService 文章归档导入导出服务
+type ArticleArchiveService interface {
+	// Export 将全部文章（含草稿）及其引用的本地图片导出为 zip 归档
+	Export(w io.Writer) error
+	// Import 导入 zip 归档，按 slug 新建或覆盖文章，自动新建缺少的分类和标签，上传引用的图片并改写链接
+	Import(archive io.ReaderAt, size int64, options *ArticleArchiveImportOptions) (*response.ArticleArchiveImportReport, error)
+	// ImportBlog 导入 Hexo、Hugo、Jekyll 站点目录（zip）或 WordPress 导出文件，规则与 Import 相同，
+	// 另外导入 WordPress 评论，并将文章在原站的地址重定向到新地址
+	ImportBlog(format string, source io.ReaderAt, size int64, options *BlogImportOptions) (*response.ArticleArchiveImportReport, error)
+}
+
+type articleArchiveService struct {
+	articleRepo     repository.ArticleRepository
+	categoryRepo    repository.CategoryRepository
+	tagRepo         repository.TagRepository
+	seriesRepo      repository.SeriesRepository
+	sectionRepo     repository.SeriesSectionRepository
+	subchapterRepo  repository.SeriesSubchapterRepository
+	commentRepo     repository.CommentRepository
+	articleService  ArticleService
+	uploadService   UploadService
+	redirectService RedirectService
+}
+
+func NewArticleArchiveService(
+	articleRepo repository.ArticleRepository,
+	categoryRepo repository.CategoryRepository,
+	tagRepo repository.TagRepository,
+	seriesRepo repository.SeriesRepository,
+	sectionRepo repository.SeriesSectionRepository,
+	subchapterRepo repository.SeriesSubchapterRepository,
+	commentRepo repository.CommentRepository,
+	articleService ArticleService,
+	uploadService UploadService,
+	redirectService RedirectService,
+) ArticleArchiveService {
+	return &articleArchiveService{
+		articleRepo:     articleRepo,
+		categoryRepo:    categoryRepo,
+		tagRepo:         tagRepo,
+		seriesRepo:      seriesRepo,
+		sectionRepo:     sectionRepo,
+		subchapterRepo:  subchapterRepo,
+		commentRepo:     commentRepo,
+		articleService:  articleService,
+		uploadService:   uploadService,
+		redirectService: redirectService,
+	}
+}
+
+func (s *articleArchiveService) Export(w io.Writer) error {
+	articles, err := s.articleRepo.ListAllWithTags()
+	if err != nil {
+		return err
+	}
+	placements, err := s.subchapterRepo.ListPlacements()
+	if err != nil {
+		return err
+	}
+	articlePlacements := make(map[uint64][]ArchiveSeriesPlacement)
+	for _, p := range placements {
+		articlePlacements[p.ArticleID] = append(articlePlacements[p.ArticleID], ArchiveSeriesPlacement{
+			Series:     p.SeriesSlug,
+			Section:    p.Section,
+			Subchapter: p.Subchapter,
+			Order:      p.SortOrder,
+		})
+	}
+
+	archive := zip.NewWriter(w)
+	images := &archiveImageWriter{archive: archive, uploadService: s.uploadService, names: make(map[string]string), used: make(map[string]bool)}
+	categories := make(map[uint64]*ArchiveTerm)
+	usedFiles := make(map[string]bool)
+
+	for _, article := range articles {
+		matter := &ArchiveFrontMatter{
+			Title:           article.Title,
+			Slug:            article.Slug,
+			Summary:         article.Summary,
+			Cover:           images.rewrite(article.Cover),
+			Series:          articlePlacements[article.ID],
+			Status:          archiveStatusNames[article.Status],
+			IsTop:           article.IsTop,
+			PublishedAt:     article.PublishedAt,
+			UnpublishAt:     article.UnpublishAt,
+			CreatedAt:       &article.CreatedAt,
+			UpdatedAt:       &article.UpdatedAt,
+			MetaTitle:       article.MetaTitle,
+			MetaDescription: article.MetaDescription,
+			CanonicalURL:    article.CanonicalURL,
+			OGImage:         images.rewrite(article.OGImage),
+		}
+		if article.CategoryID != 0 {
+			category, ok := categories[article.CategoryID]
+			if !ok {
+				if c, err := s.categoryRepo.GetByID(uint(article.CategoryID)); err == nil {
+					category = &ArchiveTerm{Name: c.Name, Slug: c.Slug}
+				}
+				categories[article.CategoryID] = category
+			}
+			matter.Category = category
+		}
+		for _, tag := range article.Tags {
+			matter.Tags = append(matter.Tags, ArchiveTerm{Name: tag.Name, Slug: tag.Slug})
+		}
+
+		body := rewriteImageLinks(article.Content, images.rewrite)
+		if images.err != nil {
+			return images.err
+		}
+		content, err := frontmatter.Marshal(matter, body)
+		if err != nil {
+			return fmt.Errorf("导出文章 %s 失败: %w", article.Slug, err)
+		}
+
+		name := archiveFileName(article.Slug, article.ID, usedFiles)
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: archiveArticleDir + name, Method: zip.Deflate, Modified: article.UpdatedAt})
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// archiveFileName 由 slug 生成不重复的文件名
+func archiveFileName(slug string, id uint64, used map[string]bool) string {
+	name := strings.NewReplacer("/", "-", "\\", "-").Replace(slug)
+	if name == "" || used[name+".md"] {
+		name = fmt.Sprintf("%s-%d", name, id)
+	}
+	used[name+".md"] = true
+	return name + ".md"
+}
+
+// archiveImageWriter 导出时将本地图片写入归档，同一图片只写入一次
+type archiveImageWriter struct {
+	archive       *zip.Writer
+	uploadService UploadService
+	names         map[string]string // 图片路径 -> 归档中的文件名，空字符串表示不是本地图片
+	used          map[string]bool   // 已使用的归档文件名
+	err           error
+}
+
+// rewrite 返回图片在归档中相对于文章文件的路径，不是本地图片或读取失败时返回原链接
+func (w *archiveImageWriter) rewrite(link string) string {
+	if link == "" || w.err != nil {
+		return link
+	}
+	// 同一图片可能以完整URL和相对路径两种形式出现
+	key := link
+	if i := strings.Index(link, "/uploads/"); i >= 0 {
+		key = link[i:]
+	}
+	if name, ok := w.names[key]; ok {
+		if name == "" {
+			return link
+		}
+		return "../" + archiveImageDir + name
+	}
+
+	content, err := w.uploadService.ReadImage(link)
+	if err != nil {
+		if !errors.Is(err, ErrNotLocalImage) {
+			logger.Warn("导出时读取图片失败，保留原链接", logger.String("url", link), logger.Err("error", err))
+		}
+		w.names[key] = ""
+		return link
+	}
+
+	base := linkBase(link)
+	name := base
+	for i := 1; w.used[name]; i++ {
+		name = fmt.Sprintf("%d-%s", i, base)
+	}
+	w.used[name] = true
+	w.names[key] = name
+
+	file, err := w.archive.Create(archiveImageDir + name)
+	if err == nil {
+		_, err = file.Write(content)
+	}
+	if err != nil {
+		w.err = err
+	}
+	return "../" + archiveImageDir + name
+}
+
+// rewriteImageLinks 改写正文中 Markdown 和 HTML 图片的链接
+func rewriteImageLinks(content string, rewrite func(link string) string) string {
+	for _, pattern := range []*regexp.Regexp{markdownImagePattern, htmlImagePattern} {
+		content = pattern.ReplaceAllStringFunc(content, func(match string) string {
+			group := pattern.FindStringSubmatchIndex(match)
+			return match[:group[2]] + rewrite(match[group[2]:group[3]]) + match[group[3]:]
+		})
+	}
+	return content
+}
+
+// archiveDocument 待导入的文章
+type archiveDocument struct {
+	File      string   // 文件在归档中的路径，相对图片链接以此为基准
+	AssetDirs []string // 相对图片链接的查找目录，为空时为文件所在目录
+	Matter    *ArchiveFrontMatter
+	Body      string
+	Redirects []string            // 文章在原站的访问路径，导入后重定向到新地址
+	Comments  []*importer.Comment // 新建文章时一并导入的评论
+	Err       error               // 解析失败的原因
+}
+
+// archiveImageSource 导入时查找文章引用的图片
+type archiveImageSource interface {
+	// Locate 返回图片链接对应的归档内路径或需要下载的远程地址，不需要导入的链接返回空字符串
+	Locate(document *archiveDocument, link string) string
+	// Open 读取归档中的图片，不存在时返回 fs.ErrNotExist
+	Open(name string) ([]byte, error)
+}
+
+// zipImageSource 从 zip 归档读取相对链接引用的图片
+type zipImageSource struct {
+	files   map[string]*zip.File
+	maxSize int64 // 单张图片的大小上限，与上传限制一致
+}
+
+func (z *zipImageSource) Locate(document *archiveDocument, link string) string {
+	if link == "" || strings.HasPrefix(link, "/") || strings.HasPrefix(link, "#") || strings.Contains(link, ":") {
+		return ""
+	}
+	return path.Join(path.Dir(document.File), strings.SplitN(strings.SplitN(link, "?", 2)[0], "#", 2)[0])
+}
+
+func (z *zipImageSource) Open(name string) ([]byte, error) {
+	file, ok := z.files[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return importer.ReadZipFile(file, z.maxSize)
+}
+
+func (s *articleArchiveService) Import(archive io.ReaderAt, size int64, options *ArticleArchiveImportOptions) (*response.ArticleArchiveImportReport, error) {
+	reader, err := zip.NewReader(archive, size)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 zip 文件: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(reader.File))
+	var documents []*archiveDocument
+	for _, file := range reader.File {
+		name := path.Clean(strings.ReplaceAll(file.Name, "\\", "/"))
+		if file.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".") {
+			continue
+		}
+		files[name] = file
+		if ext := strings.ToLower(path.Ext(name)); ext == ".md" || ext == ".markdown" {
+			documents = append(documents, readArchiveDocument(name, file))
+		}
+	}
+	sort.Slice(documents, func(i, j int) bool { return documents[i].File < documents[j].File })
+
+	return s.importDocuments(documents, &zipImageSource{files: files, maxSize: s.uploadService.MaxSize()}, options), nil
+}
+
+// readArchiveDocument 读取并解析归档中的 Markdown 文件
+func readArchiveDocument(name string, file *zip.File) *archiveDocument {
+	document := &archiveDocument{File: name, Matter: &ArchiveFrontMatter{}}
+	if file.UncompressedSize64 > archiveMaxFileSize {
+		document.Err = fmt.Errorf("文件超过 %d MB", archiveMaxFileSize/(1024*1024))
+		return document
+	}
+	reader, err := file.Open()
+	if err != nil {
+		document.Err = err
+		return document
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(io.LimitReader(reader, archiveMaxFileSize))
+	if err != nil {
+		document.Err = err
+		return document
+	}
+	if document.Body, err = frontmatter.Parse(content, document.Matter); err != nil {
+		document.Err = fmt.Errorf("front matter 格式错误: %w", err)
+	}
+	return document
+}
+
+// archiveImport 一次导入过程中的状态
+type archiveImport struct {
+	options    *ArticleArchiveImportOptions
+	images     archiveImageSource
+	report     *response.ArticleArchiveImportReport
+	slugs      map[string]string // 已处理的文章 slug -> 文件
+	categories map[string]uint64 // 分类 slug -> ID，预检查时新分类的 ID 为 0
+	tags       map[string]uint   // 标签 slug 或名称 -> ID
+	uploaded   map[string]string // 归档内图片路径或远程地址 -> 上传后的链接
+}
+
+// importDocuments 依次导入文章，单篇失败不影响其他文章
+func (s *articleArchiveService) importDocuments(documents []*archiveDocument, images archiveImageSource, options *ArticleArchiveImportOptions) *response.ArticleArchiveImportReport {
+	state := &archiveImport{
+		options: options,
+		images:  images,
+		report: &response.ArticleArchiveImportReport{
+			DryRun:            options.DryRun,
+			CreatedCategories: []string{},
+			CreatedTags:       []string{},
+			Items:             make([]response.ArticleArchiveImportItem, 0, len(documents)),
+		},
+		slugs:      make(map[string]string),
+		categories: make(map[string]uint64),
+		tags:       make(map[string]uint),
+		uploaded:   make(map[string]string),
+	}
+
+	for _, document := range documents {
+		item := s.importDocument(state, document)
+		if item.Action != response.ArchiveActionError {
+			s.importExtras(state, document, item)
+		}
+		switch item.Action {
+		case response.ArchiveActionCreate:
+			state.report.Created++
+		case response.ArchiveActionUpdate:
+			state.report.Updated++
+		case response.ArchiveActionUnchanged:
+			state.report.Unchanged++
+		case response.ArchiveActionSkip:
+			state.report.Skipped++
+		default:
+			state.report.Failed++
+		}
+		state.report.Items = append(state.report.Items, *item)
+	}
+	return state.report
+}
+
+func (s *articleArchiveService) importDocument(state *archiveImport, document *archiveDocument) *response.ArticleArchiveImportItem {
+	matter := document.Matter
+	item := &response.ArticleArchiveImportItem{File: document.File, Slug: matter.Slug, Title: matter.Title}
+	fail := func(err error) *response.ArticleArchiveImportItem {
+		item.Action = response.ArchiveActionError
+		item.Error = err.Error()
+		return item
+	}
+	if document.Err != nil {
+		return fail(document.Err)
+	}
+
+	if matter.Title == "" {
+		matter.Title = markdownTitle(document.Body, document.File)
+		item.Title = matter.Title
+	}
+	if matter.Slug == "" {
+		matter.Slug = utils.Slugify(strings.TrimSuffix(path.Base(document.File), path.Ext(document.File)))
+		item.Slug = matter.Slug
+	}
+	if matter.Slug == "" {
+		return fail(errors.New("缺少 slug"))
+	}
+	if strings.TrimSpace(document.Body) == "" {
+		return fail(errors.New("正文为空"))
+	}
+	if previous, ok := state.slugs[matter.Slug]; ok {
+		return fail(fmt.Errorf("与 %s 的 slug 重复", previous))
+	}
+	state.slugs[matter.Slug] = document.File
+
+	article, err := buildArchiveArticle(document, item)
+	if err != nil {
+		return fail(err)
+	}
+
+	existing, err := s.articleRepo.GetBySlug(matter.Slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fail(err)
+	}
+	if existing != nil {
+		item.ArticleID = existing.ID
+		if sameArticleContent(existing, article) && s.sameArticleTerms(existing, matter) {
+			item.Action = response.ArchiveActionUnchanged
+			return item
+		}
+		if matter.UpdatedAt == nil {
+			item.Conflicts = append(item.Conflicts, "站内已有相同 slug 的文章，归档未记录更新时间")
+		} else if existing.UpdatedAt.Truncate(time.Second).After(matter.UpdatedAt.Truncate(time.Second)) {
+			item.Conflicts = append(item.Conflicts, fmt.Sprintf("站内文章在 %s 修改过，晚于归档中的更新时间 %s",
+				existing.UpdatedAt.Format(time.DateTime), matter.UpdatedAt.Format(time.DateTime)))
+		}
+		if len(item.Conflicts) > 0 && !state.options.Overwrite {
+			item.Action = response.ArchiveActionSkip
+			return item
+		}
+		article.ID = existing.ID
+		item.Action = response.ArchiveActionUpdate
+	} else {
+		article.AuthorID = uint64(state.options.EditorID)
+		item.Action = response.ArchiveActionCreate
+	}
+
+	// 分类、标签和图片在确定导入后才创建和上传，跳过的文章不产生副作用
+	tagIDs, err := s.resolveReferences(state, document, article, item)
+	if err != nil {
+		return fail(err)
+	}
+	if state.options.DryRun {
+		return item
+	}
+
+	if err := s.articleService.ImportArticle(article, tagIDs, state.options.EditorID); err != nil {
+		return fail(err)
+	}
+	item.ArticleID = article.ID
+	s.placeInSeries(article.ID, matter.Series, item)
+	return item
+}
+
+// buildArchiveArticle 根据 front matter 生成文章，分类、标签和图片链接由 resolveReferences 处理
+func buildArchiveArticle(document *archiveDocument, item *response.ArticleArchiveImportItem) (*models.Article, error) {
+	matter := document.Matter
+	article := &models.Article{
+		Title:           matter.Title,
+		Slug:            matter.Slug,
+		Summary:         matter.Summary,
+		Content:         document.Body,
+		Cover:           matter.Cover,
+		IsTop:           matter.IsTop,
+		PublishedAt:     matter.PublishedAt,
+		UnpublishAt:     matter.UnpublishAt,
+		MetaTitle:       matter.MetaTitle,
+		MetaDescription: matter.MetaDescription,
+		CanonicalURL:    matter.CanonicalURL,
+		OGImage:         matter.OGImage,
+	}
+	if matter.CreatedAt != nil {
+		article.CreatedAt = *matter.CreatedAt
+	}
+
+	now := time.Now()
+	switch matter.Status {
+	case "", "published":
+		article.Status = models.ArticleStatusPublished
+	case "draft":
+		article.Status = models.ArticleStatusDraft
+		article.UnpublishAt = nil
+	case "scheduled":
+		article.Status = models.ArticleStatusScheduled
+		if article.PublishedAt != nil && !article.PublishedAt.After(now) {
+			article.Status = models.ArticleStatusPublished
+			item.Warnings = append(item.Warnings, "定时发布时间已过，按已发布导入")
+		}
+	default:
+		return nil, fmt.Errorf("无效的状态: %s", matter.Status)
+	}
+	if article.UnpublishAt != nil && !article.UnpublishAt.After(now) {
+		article.UnpublishAt = nil
+		item.Warnings = append(item.Warnings, "定时下线时间已过，已忽略")
+	}
+	return article, nil
+}
+
+// resolveReferences 查找或新建分类、标签，上传相对链接引用的图片并改写链接
+func (s *articleArchiveService) resolveReferences(state *archiveImport, document *archiveDocument, article *models.Article, item *response.ArticleArchiveImportItem) ([]uint, error) {
+	matter := document.Matter
+	if matter.Category != nil && (matter.Category.Name != "" || matter.Category.Slug != "") {
+		categoryID, err := s.resolveCategory(state, matter.Category)
+		if err != nil {
+			return nil, fmt.Errorf("处理分类失败: %w", err)
+		}
+		article.CategoryID = categoryID
+	}
+
+	tagIDs := make([]uint, 0, len(matter.Tags))
+	for i := range matter.Tags {
+		if matter.Tags[i].Name == "" && matter.Tags[i].Slug == "" {
+			continue
+		}
+		tagID, err := s.resolveTag(state, &matter.Tags[i])
+		if err != nil {
+			return nil, fmt.Errorf("处理标签 %s 失败: %w", matter.Tags[i].Name, err)
+		}
+		if tagID != 0 {
+			tagIDs = append(tagIDs, tagID)
+		}
+	}
+
+	var uploadErr error
+	upload := func(link string) string {
+		if uploadErr != nil {
+			return link
+		}
+		uploaded, err := s.uploadImage(state, document, link, item)
+		if err != nil {
+			uploadErr = err
+		}
+		return uploaded
+	}
+	article.Content = rewriteImageLinks(article.Content, upload)
+	article.Cover = upload(article.Cover)
+	article.OGImage = upload(article.OGImage)
+	if uploadErr != nil {
+		return nil, uploadErr
+	}
+	return tagIDs, nil
+}
+
+func (s *articleArchiveService) resolveCategory(state *archiveImport, term *ArchiveTerm) (uint64, error) {
+	slug := term.Slug
+	if slug == "" {
+		slug = termSlug(term.Name)
+	}
+	if id, ok := state.categories[slug]; ok {
+		return id, nil
+	}
+
+	category, err := s.categoryRepo.GetBySlug(slug)
+	if err == nil {
+		state.categories[slug] = uint64(category.ID)
+		return uint64(category.ID), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	state.report.CreatedCategories = append(state.report.CreatedCategories, slug)
+	if state.options.DryRun {
+		state.categories[slug] = 0
+		return 0, nil
+	}
+	name := term.Name
+	if name == "" {
+		name = slug
+	}
+	category = &models.Category{Name: name, Slug: slug, Status: 1}
+	if err := s.categoryRepo.Create(category); err != nil {
+		return 0, err
+	}
+	state.categories[slug] = uint64(category.ID)
+	return uint64(category.ID), nil
+}
+
+// resolveTag 依次按 slug、名称查找标签，都不存在时新建
+func (s *articleArchiveService) resolveTag(state *archiveImport, term *ArchiveTerm) (uint, error) {
+	key := term.Slug + "\x00" + term.Name
+	if id, ok := state.tags[key]; ok {
+		return id, nil
+	}
+
+	var tag *models.Tag
+	var err error
+	if term.Slug != "" {
+		tag, err = s.tagRepo.GetBySlug(term.Slug)
+	}
+	if tag == nil && term.Name != "" {
+		tag, err = s.tagRepo.GetByName(term.Name)
+	}
+	if tag == nil && term.Slug == "" {
+		tag, err = s.tagRepo.GetBySlug(termSlug(term.Name))
+	}
+	if tag != nil {
+		state.tags[key] = uint(tag.ID)
+		return uint(tag.ID), nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	tag = &models.Tag{Name: term.Name, Slug: term.Slug}
+	if tag.Name == "" {
+		tag.Name = tag.Slug
+	}
+	if tag.Slug == "" {
+		tag.Slug = termSlug(tag.Name)
+	}
+	state.report.CreatedTags = append(state.report.CreatedTags, tag.Name)
+	if state.options.DryRun {
+		state.tags[key] = 0
+		return 0, nil
+	}
+	if err := s.tagRepo.Create(tag); err != nil {
+		return 0, err
+	}
+	state.tags[key] = uint(tag.ID)
+	return uint(tag.ID), nil
+}
+
+// termSlug 根据名称生成分类或标签的 slug，名称中没有可用字符时使用名称的哈希
+func termSlug(name string) string {
+	if slug := utils.Slugify(name); slug != "" {
+		return slug
+	}
+	sum := md5.Sum([]byte(name))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+// uploadImage 上传文章引用的归档内图片或下载远程图片，返回新链接；不需要导入的链接原样返回
+func (s *articleArchiveService) uploadImage(state *archiveImport, document *archiveDocument, link string, item *response.ArticleArchiveImportItem) (string, error) {
+	name := state.images.Locate(document, link)
+	if name == "" {
+		return link, nil
+	}
+	if uploaded, ok := state.uploaded[name]; ok {
+		return uploaded, nil
+	}
+
+	// 远程图片下载失败不影响导入，保留原链接
+	if remote := strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://"); remote {
+		state.report.UploadedImages++
+		if state.options.DryRun {
+			state.uploaded[name] = link
+			return link, nil
+		}
+		uploaded, err := s.uploadService.DownloadImage(name)
+		if err != nil {
+			state.report.UploadedImages--
+			item.Warnings = append(item.Warnings, fmt.Sprintf("下载图片失败，保留原链接: %s（%v）", link, err))
+			uploaded = link
+		}
+		state.uploaded[name] = uploaded
+		return uploaded, nil
+	}
+
+	content, err := state.images.Open(name)
+	if err != nil {
+		item.Warnings = append(item.Warnings, "图片不存在，保留原链接: "+link)
+		state.uploaded[name] = link
+		return link, nil
+	}
+	state.report.UploadedImages++
+	if state.options.DryRun {
+		state.uploaded[name] = link
+		return link, nil
+	}
+	uploaded, err := s.uploadService.SaveImage(path.Base(name), content)
+	if err != nil {
+		return "", fmt.Errorf("上传图片 %s 失败: %w", link, err)
+	}
+	state.uploaded[name] = uploaded
+	return uploaded, nil
+}
+
+// placeInSeries 将文章加入系列，系列不存在时记录警告，章节和子章节不存在时新建
+func (s *articleArchiveService) placeInSeries(articleID uint64, placements []ArchiveSeriesPlacement, item *response.ArticleArchiveImportItem) {
+	for _, placement := range placements {
+		if placement.Series == "" || placement.Section == "" || placement.Subchapter == "" {
+			item.Warnings = append(item.Warnings, "系列位置不完整，已忽略")
+			continue
+		}
+		series, err := s.seriesRepo.GetBySlug(placement.Series)
+		if err != nil {
+			item.Warnings = append(item.Warnings, "系列不存在，未加入: "+placement.Series)
+			continue
+		}
+		if err := s.addToSubchapter(series.ID, articleID, &placement); err != nil {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("加入系列 %s 失败: %v", placement.Series, err))
+		}
+	}
+}
+
+func (s *articleArchiveService) addToSubchapter(seriesID, articleID uint64, placement *ArchiveSeriesPlacement) error {
+	sections, _, err := s.sectionRepo.GetBySeriesID(uint(seriesID))
+	if err != nil {
+		return err
+	}
+	var section *models.SeriesSection
+	for _, candidate := range sections {
+		if candidate.Name == placement.Section {
+			section = candidate
+			break
+		}
+	}
+	if section == nil {
+		section = &models.SeriesSection{SeriesID: seriesID, Name: placement.Section, SortOrder: len(sections)}
+		if err := s.sectionRepo.Create(section); err != nil {
+			return err
+		}
+	}
+
+	subchapters, _, err := s.subchapterRepo.GetBySectionID(uint(section.ID))
+	if err != nil {
+		return err
+	}
+	var subchapter *models.SeriesSubchapter
+	for _, candidate := range subchapters {
+		if candidate.Name == placement.Subchapter {
+			subchapter = candidate
+			break
+		}
+	}
+	if subchapter == nil {
+		subchapter = &models.SeriesSubchapter{SectionID: section.ID, Name: placement.Subchapter, SortOrder: len(subchapters)}
+		if err := s.subchapterRepo.Create(subchapter); err != nil {
+			return err
+		}
+	}
+	return s.subchapterRepo.AddArticle(uint(subchapter.ID), uint(articleID), placement.Order)
+}
+
+// sameArticleContent 判断导入的文章与站内文章内容是否相同，图片链接只比较文件名
+func sameArticleContent(existing, imported *models.Article) bool {
+	return existing.Title == imported.Title &&
+		existing.Summary == imported.Summary &&
+		existing.Status == imported.Status &&
+		linkBase(existing.Cover) == linkBase(imported.Cover) &&
+		strings.TrimSpace(rewriteImageLinks(existing.Content, linkBase)) == strings.TrimSpace(rewriteImageLinks(imported.Content, linkBase))
+}
+
+// sameArticleTerms 判断导入的分类和标签与站内文章是否相同，查询失败时视为不同
+func (s *articleArchiveService) sameArticleTerms(existing *models.Article, matter *ArchiveFrontMatter) bool {
+	categorySlug := ""
+	if matter.Category != nil && (matter.Category.Name != "" || matter.Category.Slug != "") {
+		categorySlug = matter.Category.Slug
+		if categorySlug == "" {
+			categorySlug = termSlug(matter.Category.Name)
+		}
+	}
+	if existing.CategoryID == 0 {
+		if categorySlug != "" {
+			return false
+		}
+	} else if category, err := s.categoryRepo.GetByID(uint(existing.CategoryID)); err != nil || category.Slug != categorySlug {
+		return false
+	}
+
+	tags, err := s.tagRepo.GetByArticleID(uint(existing.ID))
+	if err != nil {
+		return false
+	}
+	names := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		names[tag.Name] = true
+		names[tag.Slug] = true
+	}
+	count := 0
+	for _, term := range matter.Tags {
+		if term.Name == "" && term.Slug == "" {
+			continue
+		}
+		if !names[term.Name] && !names[term.Slug] {
+			return false
+		}
+		count++
+	}
+	return count == len(tags)
+}
+
+// linkBase 返回链接的文件名，去掉查询参数和锚点
+func linkBase(link string) string {
+	if link == "" {
+		return ""
+	}
+	return path.Base(strings.SplitN(strings.SplitN(link, "?", 2)[0], "#", 2)[0])
+}
+
+// markdownTitle 没有 front matter 标题时，使用第一个一级标题，否则使用文件名
+func markdownTitle(body, file string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if title, ok := strings.CutPrefix(strings.TrimSpace(line), "# "); ok && strings.TrimSpace(title) != "" {
+			return strings.TrimSpace(title)
+		}
+	}
+	return strings.TrimSuffix(path.Base(file), path.Ext(file))
+}
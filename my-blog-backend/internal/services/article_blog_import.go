@@ -0,0 +1,290 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"my-blog-backend/internal/api/v1/dto/response"
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/pkg/importer"
+	"my-blog-backend/internal/pkg/logger"
+)
+
+// 远程图片的下载范围
+const (
+	ImageDownloadNone = "none" // 不下载，保留原链接
+	ImageDownloadSite = "site" // 只下载原站的图片
+	ImageDownloadAll  = "all"  // 下载全部外部图片
+)
+
+// BlogImportOptions 导入其他博客系统的选项
+type BlogImportOptions struct {
+	ArticleArchiveImportOptions
+	SiteURL        string // 原站地址，用于识别原站图片，为空时取站点配置中的地址
+	Permalink      string // 覆盖原站配置中的文章链接格式
+	DownloadImages string // none、site、all
+}
+
+func (s *articleArchiveService) ImportBlog(format string, source io.ReaderAt, size int64, options *BlogImportOptions) (*response.ArticleArchiveImportReport, error) {
+	site, err := importer.Read(format, source, size, importer.Options{Permalink: options.Permalink})
+	if err != nil {
+		return nil, err
+	}
+
+	images := &siteImageSource{archive: site.Archive, staticDirs: site.StaticDirs, download: options.DownloadImages, maxSize: s.uploadService.MaxSize()}
+	siteURL := options.SiteURL
+	if siteURL == "" {
+		siteURL = site.URL
+	}
+	if parsed, err := url.Parse(siteURL); err == nil && parsed.Host != "" {
+		images.siteURL = parsed
+	}
+
+	documents := make([]*archiveDocument, 0, len(site.Posts))
+	for _, post := range site.Posts {
+		documents = append(documents, blogDocument(post))
+	}
+	logger.Info("读取待导入的博客",
+		logger.String("format", format),
+		logger.String("site_url", siteURL),
+		logger.Int("posts", len(documents)),
+	)
+	return s.importDocuments(documents, images, &options.ArticleArchiveImportOptions), nil
+}
+
+// blogDocument 将其他系统的文章转换为归档文章，第一个分类作为文章分类，其余分类作为标签
+func blogDocument(post *importer.Post) *archiveDocument {
+	document := &archiveDocument{
+		File:      post.File,
+		AssetDirs: post.AssetDirs,
+		Matter:    &ArchiveFrontMatter{},
+		Body:      post.Content,
+		Redirects: post.URLs,
+		Comments:  post.Comments,
+		Err:       post.Err,
+	}
+	if post.Err != nil {
+		return document
+	}
+
+	matter := document.Matter
+	matter.Title = post.Title
+	matter.Slug = post.Slug
+	matter.Summary = post.Summary
+	matter.Cover = post.Cover
+
+	terms := post.Tags
+	if len(post.Categories) > 0 {
+		matter.Category = &ArchiveTerm{Name: post.Categories[0]}
+		terms = append(post.Categories[1:len(post.Categories):len(post.Categories)], post.Tags...)
+	}
+	seen := make(map[string]bool, len(terms))
+	for _, name := range terms {
+		if key := strings.ToLower(name); !seen[key] {
+			seen[key] = true
+			matter.Tags = append(matter.Tags, ArchiveTerm{Name: name})
+		}
+	}
+
+	switch {
+	case post.Draft:
+		matter.Status = "draft"
+	case post.Date.After(time.Now()):
+		matter.Status = "scheduled"
+	default:
+		matter.Status = "published"
+	}
+	if !post.Date.IsZero() {
+		matter.CreatedAt = &post.Date
+		if !post.Draft {
+			matter.PublishedAt = &post.Date
+		}
+	}
+	// 用于判断站内同 slug 的文章是否在原站最后修改后又被修改过
+	if !post.Updated.IsZero() {
+		matter.UpdatedAt = &post.Updated
+	} else if !post.Date.IsZero() {
+		matter.UpdatedAt = &post.Date
+	}
+	return document
+}
+
+// siteImageSource 查找其他博客系统文章引用的图片：相对链接在文章目录和资源目录中查找，
+// 以 / 开头的链接在站点静态目录中查找，原站或外部的图片按下载范围返回远程地址
+type siteImageSource struct {
+	archive    *importer.Archive // WordPress 导入时为 nil
+	staticDirs []string
+	siteURL    *url.URL // 原站地址，未知时为 nil
+	download   string
+	maxSize    int64 // 单张图片的大小上限，与上传限制一致
+}
+
+func (i *siteImageSource) Locate(document *archiveDocument, link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" || strings.HasPrefix(link, "#") || strings.HasPrefix(link, "data:") {
+		return ""
+	}
+	if strings.HasPrefix(link, "//") {
+		link = "https:" + link
+	}
+
+	if strings.Contains(link, "://") {
+		parsed, err := url.Parse(link)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return ""
+		}
+		if i.isSiteHost(parsed.Host) {
+			// 原站图片优先使用归档中的文件
+			if name := i.staticFile(parsed.Path); name != "" {
+				return name
+			}
+		} else if i.download != ImageDownloadAll {
+			return ""
+		}
+		if i.download == ImageDownloadNone {
+			return ""
+		}
+		return link
+	}
+	if strings.Contains(link, ":") {
+		return ""
+	}
+
+	name := strings.SplitN(strings.SplitN(link, "?", 2)[0], "#", 2)[0]
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	if strings.HasPrefix(name, "/") {
+		if file := i.staticFile(name); file != "" {
+			return file
+		}
+		if i.siteURL != nil && i.download != ImageDownloadNone {
+			return i.siteURL.Scheme + "://" + i.siteURL.Host + link
+		}
+		if len(i.staticDirs) > 0 {
+			return path.Join(i.staticDirs[0], name)
+		}
+		return ""
+	}
+
+	dirs := document.AssetDirs
+	if len(dirs) == 0 {
+		dirs = []string{path.Dir(document.File)}
+	}
+	for _, dir := range dirs {
+		if file := path.Join(dir, name); i.archive != nil && i.archive.Exists(file) {
+			return file
+		}
+	}
+	return path.Join(dirs[0], name)
+}
+
+func (i *siteImageSource) Open(name string) ([]byte, error) {
+	if i.archive == nil {
+		return nil, fs.ErrNotExist
+	}
+	return i.archive.Open(name, i.maxSize)
+}
+
+// staticFile 在静态目录中查找站内路径对应的文件，站点部署在子路径时同时尝试去掉子路径
+func (i *siteImageSource) staticFile(sitePath string) string {
+	if i.archive == nil {
+		return ""
+	}
+	if unescaped, err := url.PathUnescape(sitePath); err == nil {
+		sitePath = unescaped
+	}
+	candidates := []string{sitePath}
+	if i.siteURL != nil {
+		if base := strings.TrimSuffix(i.siteURL.Path, "/"); base != "" && strings.HasPrefix(sitePath, base+"/") {
+			candidates = append(candidates, strings.TrimPrefix(sitePath, base))
+		}
+	}
+	for _, candidate := range candidates {
+		for _, dir := range i.staticDirs {
+			if file := path.Join(dir, candidate); i.archive.Exists(file) {
+				return file
+			}
+		}
+	}
+	return ""
+}
+
+// isSiteHost 判断是否为原站域名，忽略 www 前缀
+func (i *siteImageSource) isSiteHost(host string) bool {
+	if i.siteURL == nil {
+		return false
+	}
+	trim := func(h string) string { return strings.TrimPrefix(strings.ToLower(h), "www.") }
+	return trim(host) == trim(i.siteURL.Host)
+}
+
+// importExtras 保存文章原地址的重定向，新建文章时导入评论；预检查时只统计数量
+func (s *articleArchiveService) importExtras(state *archiveImport, document *archiveDocument, item *response.ArticleArchiveImportItem) {
+	if len(document.Redirects) > 0 {
+		item.Redirects = document.Redirects
+		state.report.Redirects += len(document.Redirects)
+		if !state.options.DryRun {
+			if err := s.redirectService.SaveArticleRedirects(document.Redirects, document.Matter.Slug, models.RedirectSourceImport); err != nil {
+				item.Warnings = append(item.Warnings, "保存重定向失败: "+err.Error())
+			}
+		}
+	}
+
+	// 覆盖已有文章时不导入评论，避免重复
+	if len(document.Comments) == 0 || item.Action != response.ArchiveActionCreate {
+		return
+	}
+	if state.options.DryRun {
+		item.Comments = len(document.Comments)
+	} else {
+		item.Comments = s.importComments(uint(item.ArticleID), document.Comments, item)
+	}
+	state.report.Comments += item.Comments
+}
+
+// importComments 按顺序导入评论，父评论不存在时作为顶级评论，返回导入的数量
+func (s *articleArchiveService) importComments(articleID uint, comments []*importer.Comment, item *response.ArticleArchiveImportItem) int {
+	ids := make(map[string]uint, len(comments))
+	count := 0
+	for _, imported := range comments {
+		if imported.Content == "" {
+			continue
+		}
+		comment := &models.Comment{
+			ArticleID:   articleID,
+			Content:     imported.Content,
+			Status:      0, // 待审核
+			IPAddress:   imported.IP,
+			AuthorName:  imported.Author,
+			AuthorEmail: imported.Email,
+			AuthorURL:   imported.URL,
+			CreatedAt:   imported.Date,
+		}
+		if imported.Approved {
+			comment.Status = 1
+		}
+		if comment.AuthorName == "" {
+			comment.AuthorName = "匿名"
+		}
+		if parentID, ok := ids[imported.ParentID]; ok {
+			comment.ParentID = &parentID
+		}
+
+		if err := s.commentRepo.CreateImported(comment); err != nil {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("导入评论失败，已导入 %d 条: %v", count, err))
+			break
+		}
+		ids[imported.ID] = comment.ID
+		count++
+		if err := s.commentRepo.IncrementArticleCommentCount(articleID); err != nil {
+			logger.Warn("更新文章评论数失败", logger.Uint("article_id", articleID), logger.Err("error", err))
+		}
+	}
+	return count
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"sync"
+
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/pkg/logger"
+)
+
+// ArticleEventType 文章事件类型
+type ArticleEventType string
+
+const (
+	ArticleEventPublished   ArticleEventType = "published"   // 文章发布（包括定时发布到期）
+	ArticleEventUnpublished ArticleEventType = "unpublished" // 文章下线（转为草稿或定时发布，包括定时下线到期）
+	ArticleEventUpdated     ArticleEventType = "updated"     // 文章内容修改
+	ArticleEventDeleted     ArticleEventType = "deleted"     // 文章删除，Article 只包含ID
+)
+
+// ArticleEvent 文章变更事件，用于通知依赖文章内容的缓存失效
+type ArticleEvent struct {
+	Type    ArticleEventType
+	Article *models.Article
+}
+
+// ArticleEventBus 文章事件分发，订阅方同步调用，耗时操作需自行异步处理
+type ArticleEventBus struct {
+	mu       sync.RWMutex
+	handlers []func(ArticleEvent)
+}
+
+func NewArticleEventBus() *ArticleEventBus {
+	return &ArticleEventBus{}
+}
+
+// Subscribe 订阅文章事件
+func (b *ArticleEventBus) Subscribe(handler func(ArticleEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish 发布文章事件，未初始化时忽略
+func (b *ArticleEventBus) Publish(event ArticleEvent) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	logger.Info("文章事件", logger.String("type", string(event.Type)), logger.Uint("article_id", uint(event.Article.ID)))
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
@@ -1,20 +1,39 @@
 package services
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/api/v1/dto/response"
 	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/pkg/search"
+	"my-blog-backend/internal/pkg/utils"
 	"my-blog-backend/internal/repository"
+	"sort"
+	"strings"
 	"time"
 )
 
+const (
+	searchSnippetLength     = 160              // 搜索结果正文片段的最大字符数
+	articleScheduleInterval = 30 * time.Second // 定时发布/下线检查间隔
+)
+
 type ArticleService interface {
 	CreateArticle(article *models.Article, tagIDs []uint) error
-	UpdateArticle(id uint, article *models.Article, tagIDs []uint) error
+	UpdateArticle(id uint, article *models.Article, tagIDs []uint, editorID uint) error
+	UpdateArticleStatus(id uint, status uint8, publishAt, unpublishAt *time.Time) error
+	ImportArticle(article *models.Article, tagIDs []uint, editorID uint) error
+	RunScheduler()
 	DeleteArticle(id uint) error
 	GetArticle(id uint) (*models.Article, error)
+	GetArticleBySlug(slug string) (*models.Article, error)
 	ListArticles(page, pageSize int, status *int, categoryID *uint) ([]*models.Article, int64, error)
-	SearchArticles(keyword string, page, pageSize int) ([]*models.Article, int64, error)
-	ViewArticle(id uint) error
+	SearchArticles(req *request.SearchRequest) (*response.SearchResponse, error)
+	RebuildSearchIndex() error
+	HandleArticleEvent(event ArticleEvent) // 文章变更时同步检索索引
 	GetHotArticles(limit int) ([]*models.Article, error)
 	GetRecentArticles(limit int) ([]*models.Article, error)
 	LikeArticle(articleID, userID uint) error
@@ -22,6 +41,10 @@ type ArticleService interface {
 	CheckArticleLiked(articleID, userID uint) (bool, error)
 	FavoriteArticle(articleID, userID uint) error
 	UnfavoriteArticle(articleID, userID uint) error
+	ListRevisions(articleID uint, page, pageSize int) (*response.ArticleRevisionListResponse, error)
+	GetRevision(articleID uint, version int) (*response.ArticleRevisionResponse, error)
+	DiffRevisions(articleID uint, from, to int, mode string) (*response.ArticleRevisionDiffResponse, error)
+	RestoreRevision(articleID uint, version int, editorID uint) (*models.Article, error)
 }
 
 type articleService struct {
@@ -32,6 +55,11 @@ type articleService struct {
 	favoriteRepo    repository.FavoriteRepository
 	articleLikeRepo repository.ArticleLikeRepository
 	activityService *UserActivityService
+	searchEngine    search.Engine
+	revisionRepo    repository.ArticleRevisionRepository
+	maxRevisions    int // 每篇文章保留的修订数量，小于等于0不限制
+	events          *ArticleEventBus
+	redirectService RedirectService
 }
 
 func NewArticleService(articleRepo repository.ArticleRepository,
@@ -40,7 +68,12 @@ func NewArticleService(articleRepo repository.ArticleRepository,
 	commentLikeRepo repository.CommentLikeRepository,
 	favoriteRepo repository.FavoriteRepository,
 	articleLikeRepo repository.ArticleLikeRepository,
-	activityService *UserActivityService) ArticleService {
+	activityService *UserActivityService,
+	searchEngine search.Engine,
+	revisionRepo repository.ArticleRevisionRepository,
+	maxRevisions int,
+	events *ArticleEventBus,
+	redirectService RedirectService) ArticleService {
 	return &articleService{
 		articleRepo:     articleRepo,
 		tagRepo:         tagRepo,
@@ -49,54 +82,290 @@ func NewArticleService(articleRepo repository.ArticleRepository,
 		favoriteRepo:    favoriteRepo,
 		articleLikeRepo: articleLikeRepo,
 		activityService: activityService,
+		searchEngine:    searchEngine,
+		revisionRepo:    revisionRepo,
+		maxRevisions:    maxRevisions,
+		events:          events,
+		redirectService: redirectService,
 	}
 }
 
+// CreateArticle 创建文章
+// 定时发布时 article.PublishedAt 为计划发布时间，article.UnpublishAt 为可选的定时下线时间
 func (s *articleService) CreateArticle(article *models.Article, tagIDs []uint) error {
 	now := time.Now()
 	article.CreatedAt = now
 	article.UpdatedAt = now
 
-	if article.Status == 1 {
-		article.PublishedAt = &now
+	if err := applySchedule(article, nil); err != nil {
+		return err
 	}
 
 	if err := s.articleRepo.Create(article); err != nil {
 		return err
 	}
+	if len(tagIDs) > 0 {
+		if err := s.articleRepo.SetTags(article.ID, tagIDs); err != nil {
+			return err
+		}
+	}
 
-	// TODO: 关联标签
+	s.saveRevision(article, uint(article.AuthorID), models.RevisionActionCreate, 0)
+	s.notifyChange(models.ArticleStatusDraft, article)
 	return nil
 }
 
-func (s *articleService) UpdateArticle(id uint, article *models.Article, tagIDs []uint) error {
+// UpdateArticle 更新文章，tagIDs 为 nil 时不修改标签，发布时间规则同 CreateArticle
+// 每次保存生成一条修订记录，内容未变化时不生成
+func (s *articleService) UpdateArticle(id uint, article *models.Article, tagIDs []uint, editorID uint) error {
 	existing, err := s.articleRepo.GetByID(id)
 	if err != nil {
 		return errors.New("article not found")
 	}
+	if err := applySchedule(article, existing); err != nil {
+		return err
+	}
+	// 修订功能上线前创建的文章没有修订，先补记修改前的版本，避免本次保存覆盖后无法找回
+	s.ensureBaselineRevision(existing)
 
 	article.ID = uint64(id)
 	article.CreatedAt = existing.CreatedAt
 	article.UpdatedAt = time.Now()
+	// 编辑表单不包含的字段沿用原值
+	article.AuthorID = existing.AuthorID
+	article.Views = existing.Views
+	article.Likes = existing.Likes
+	article.Favorites = existing.Favorites
+	article.CommentCount = existing.CommentCount
+	article.SortOrder = existing.SortOrder
+	if article.Slug == "" {
+		article.Slug = existing.Slug
+	}
+
+	if err := s.articleRepo.Update(article); err != nil {
+		return err
+	}
+	if tagIDs != nil {
+		if err := s.articleRepo.SetTags(article.ID, tagIDs); err != nil {
+			return err
+		}
+	}
 
-	if article.Status == 1 && existing.Status != 1 {
-		now := time.Now()
-		article.PublishedAt = &now
+	// 修改 slug 后旧地址跳转到新地址
+	recordSlugChange(s.redirectService, models.SlugEntityArticle, article.ID, existing.Slug, article.Slug)
+
+	s.saveRevision(article, editorID, models.RevisionActionUpdate, 0)
+	s.notifyChange(existing.Status, article)
+	return nil
+}
+
+// ImportArticle 导入文章，article.ID 为 0 时创建，否则覆盖该文章
+// 与 CreateArticle、UpdateArticle 不同，保留导入的创建时间和发布时间；已发布但没有发布时间的文章以当前时间发布
+func (s *articleService) ImportArticle(article *models.Article, tagIDs []uint, editorID uint) error {
+	now := time.Now()
+	start := now
+	switch article.Status {
+	case models.ArticleStatusPublished:
+		if article.PublishedAt == nil {
+			article.PublishedAt = &now
+		}
+	case models.ArticleStatusScheduled:
+		if article.PublishedAt == nil || !article.PublishedAt.After(now) {
+			return errors.New("定时发布时间必须晚于当前时间")
+		}
+		start = *article.PublishedAt
+	case models.ArticleStatusDraft:
+		if article.UnpublishAt != nil {
+			return errors.New("草稿不能设置下线时间")
+		}
+	default:
+		return fmt.Errorf("无效的文章状态: %d", article.Status)
+	}
+	if article.UnpublishAt != nil && !article.UnpublishAt.After(start) {
+		return errors.New("下线时间必须晚于发布时间")
+	}
+	article.UpdatedAt = now
+
+	previous := models.ArticleStatusDraft
+	if article.ID == 0 {
+		if article.CreatedAt.IsZero() {
+			article.CreatedAt = now
+		}
+		if err := s.articleRepo.Create(article); err != nil {
+			return err
+		}
+	} else {
+		existing, err := s.articleRepo.GetByID(uint(article.ID))
+		if err != nil {
+			return errors.New("article not found")
+		}
+		s.ensureBaselineRevision(existing)
+		previous = existing.Status
+
+		// 归档不包含的字段沿用原值
+		if article.CreatedAt.IsZero() {
+			article.CreatedAt = existing.CreatedAt
+		}
+		article.AuthorID = existing.AuthorID
+		article.Views = existing.Views
+		article.Likes = existing.Likes
+		article.Favorites = existing.Favorites
+		article.CommentCount = existing.CommentCount
+		article.SortOrder = existing.SortOrder
+		if err := s.articleRepo.Update(article); err != nil {
+			return err
+		}
+	}
+	if err := s.articleRepo.SetTags(article.ID, tagIDs); err != nil {
+		return err
 	}
 
+	s.saveRevision(article, editorID, models.RevisionActionImport, 0)
+	s.notifyChange(previous, article)
+	return nil
+}
+
+// UpdateArticleStatus 更新文章状态，不生成修订
+// 定时发布需要 publishAt 且晚于当前时间；unpublishAt 可选，需晚于发布时间
+func (s *articleService) UpdateArticleStatus(id uint, status uint8, publishAt, unpublishAt *time.Time) error {
+	article, err := s.articleRepo.GetByID(id)
+	if err != nil {
+		return errors.New("article not found")
+	}
+	previous := *article
+
+	article.Status = status
+	article.PublishedAt = publishAt
+	article.UnpublishAt = unpublishAt
+	if err := applySchedule(article, &previous); err != nil {
+		return err
+	}
+	article.UpdatedAt = time.Now()
+
 	if err := s.articleRepo.Update(article); err != nil {
 		return err
 	}
+	s.notifyChange(previous.Status, article)
+	return nil
+}
 
-	// TODO: 更新标签关联
+// RunScheduler 定期执行到期的定时发布和定时下线，需在独立协程中运行
+func (s *articleService) RunScheduler() {
+	ticker := time.NewTicker(articleScheduleInterval)
+	defer ticker.Stop()
+
+	// 启动时先处理停机期间到期的文章
+	s.runDueSchedules(time.Now())
+	for now := range ticker.C {
+		s.runDueSchedules(now)
+	}
+}
+
+// runDueSchedules 发布到期的定时文章，下线到期的已发布文章
+func (s *articleService) runDueSchedules(now time.Time) {
+	articles, err := s.articleRepo.ListDueForPublish(now)
+	if err != nil {
+		logger.Error("查询待发布文章失败", logger.Err("error", err))
+	}
+	for _, article := range articles {
+		s.changeStatus(article, models.ArticleStatusPublished, "定时发布文章")
+	}
+
+	articles, err = s.articleRepo.ListDueForUnpublish(now)
+	if err != nil {
+		logger.Error("查询待下线文章失败", logger.Err("error", err))
+	}
+	for _, article := range articles {
+		s.changeStatus(article, models.ArticleStatusDraft, "定时下线文章")
+	}
+}
+
+// changeStatus 定时任务修改文章状态，文章状态已被编辑人修改时跳过
+func (s *articleService) changeStatus(article *models.Article, status uint8, action string) {
+	previous := article.Status
+	changed, err := s.articleRepo.ChangeStatus(article.ID, previous, status)
+	if err != nil {
+		logger.Error(action+"失败", logger.Uint("article_id", uint(article.ID)), logger.Err("error", err))
+		return
+	}
+	if !changed {
+		return
+	}
+
+	article.Status = status
+	if status == models.ArticleStatusDraft {
+		article.UnpublishAt = nil
+	}
+	s.notifyChange(previous, article)
+	logger.Info(action, logger.Uint("article_id", uint(article.ID)), logger.String("title", article.Title))
+}
+
+// applySchedule 按文章状态整理发布时间和下线时间
+// 定时发布使用 article.PublishedAt 作为计划发布时间；立即发布时沿用已发布文章的原发布时间，否则取当前时间；
+// 草稿保留曾经发布过的发布时间。previous 为修改前的文章，新建时为 nil
+func applySchedule(article *models.Article, previous *models.Article) error {
+	now := time.Now()
+	switch article.Status {
+	case models.ArticleStatusScheduled:
+		if article.PublishedAt == nil {
+			return errors.New("定时发布需要设置发布时间")
+		}
+		if !article.PublishedAt.After(now) {
+			return errors.New("定时发布时间必须晚于当前时间")
+		}
+	case models.ArticleStatusPublished:
+		if previous != nil && previous.Status == models.ArticleStatusPublished && previous.PublishedAt != nil {
+			article.PublishedAt = previous.PublishedAt
+		} else {
+			article.PublishedAt = &now
+		}
+	case models.ArticleStatusDraft:
+		article.PublishedAt = nil
+		if previous != nil && previous.Status != models.ArticleStatusScheduled {
+			article.PublishedAt = previous.PublishedAt
+		}
+		if article.UnpublishAt != nil {
+			return errors.New("草稿不能设置下线时间")
+		}
+	default:
+		return fmt.Errorf("无效的文章状态: %d", article.Status)
+	}
+
+	if article.UnpublishAt != nil {
+		start := now
+		if article.Status == models.ArticleStatusScheduled {
+			start = *article.PublishedAt
+		}
+		if !article.UnpublishAt.After(start) {
+			return errors.New("下线时间必须晚于发布时间")
+		}
+	}
 	return nil
 }
 
+// notifyChange 根据状态变化发布文章事件，草稿之间的修改不影响已发布内容，不发布事件
+func (s *articleService) notifyChange(previous uint8, article *models.Article) {
+	published := article.Status == models.ArticleStatusPublished
+	wasPublished := previous == models.ArticleStatusPublished
+	switch {
+	case published && !wasPublished:
+		s.events.Publish(ArticleEvent{Type: ArticleEventPublished, Article: article})
+	case !published && wasPublished:
+		s.events.Publish(ArticleEvent{Type: ArticleEventUnpublished, Article: article})
+	case published:
+		s.events.Publish(ArticleEvent{Type: ArticleEventUpdated, Article: article})
+	}
+}
+
 func (s *articleService) DeleteArticle(id uint) error {
 	// TODO: 删除标签关联
 	if err := s.articleRepo.Delete(id); err != nil {
 		return err
 	}
+	if err := s.revisionRepo.DeleteByArticleID(id); err != nil {
+		return err
+	}
+	s.events.Publish(ArticleEvent{Type: ArticleEventDeleted, Article: &models.Article{ID: uint64(id)}})
 	// TODO: 删除文章所有评论
 	if err := s.commentRepo.DeleteCommentByArticleID(id); err != nil {
 		return err
@@ -109,20 +378,183 @@ func (s *articleService) DeleteArticle(id uint) error {
 	return s.articleRepo.Delete(id)
 }
 
+// GetArticle 获取已发布文章详情，草稿和定时文章按不存在处理
 func (s *articleService) GetArticle(id uint) (*models.Article, error) {
-	return s.articleRepo.GetByID(id)
+	return publishedOnly(s.articleRepo.GetByID(id))
+}
+
+// GetArticleBySlug 根据 slug 获取已发布文章，slug 已修改时按旧 slug 找到文章；草稿和定时文章按不存在处理
+func (s *articleService) GetArticleBySlug(slug string) (*models.Article, error) {
+	return publishedOnly(findBySlug(s.redirectService, models.SlugEntityArticle, slug, s.articleRepo.GetBySlug, s.articleRepo.GetByID))
+}
+
+// publishedOnly 前台详情只返回已发布文章，其余状态返回 ErrArticleNotFound
+func publishedOnly(article *models.Article, err error) (*models.Article, error) {
+	if err != nil {
+		return nil, err
+	}
+	if article.Status != models.ArticleStatusPublished {
+		return nil, ErrArticleNotFound
+	}
+	return article, nil
 }
 
 func (s *articleService) ListArticles(page, pageSize int, status *int, categoryID *uint) ([]*models.Article, int64, error) {
 	return s.articleRepo.List(page, pageSize, status, categoryID)
 }
 
-func (s *articleService) SearchArticles(keyword string, page, pageSize int) ([]*models.Article, int64, error) {
-	return s.articleRepo.Search(keyword, page, pageSize)
+func (s *articleService) SearchArticles(req *request.SearchRequest) (*response.SearchResponse, error) {
+	keyword := strings.TrimSpace(req.Keyword)
+	query := &search.Query{
+		Keyword:    keyword,
+		CategoryID: uint64(req.CategoryID),
+		TagID:      uint64(req.TagID),
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+	}
+	if req.StartDate != "" {
+		from, err := time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+		if err != nil {
+			return nil, errors.New("开始日期格式错误")
+		}
+		query.From = &from
+	}
+	if req.EndDate != "" {
+		to, err := time.ParseInLocation("2006-01-02", req.EndDate, time.Local)
+		if err != nil {
+			return nil, errors.New("结束日期格式错误")
+		}
+		// 截止日期包含当天
+		to = to.AddDate(0, 0, 1)
+		query.To = &to
+	}
+
+	result, err := s.searchEngine.Search(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ID)
+	}
+	articles, err := s.articleRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	contents, err := s.articleRepo.GetContentsByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	articleMap := make(map[uint64]*models.Article, len(articles))
+	for _, article := range articles {
+		articleMap[article.ID] = article
+	}
+
+	highlighter := search.NewHighlighter(keyword)
+	items := make([]response.SearchResultItem, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		article, ok := articleMap[hit.ID]
+		if !ok {
+			// 索引尚未同步的已删除文章
+			continue
+		}
+		items = append(items, response.SearchResultItem{
+			ID:           article.ID,
+			Title:        article.Title,
+			Slug:         article.Slug,
+			Summary:      article.Summary,
+			Cover:        article.Cover,
+			CategoryID:   article.CategoryID,
+			AuthorID:     article.AuthorID,
+			Views:        article.Views,
+			Likes:        article.Likes,
+			Favorites:    article.Favorites,
+			CommentCount: article.CommentCount,
+			IsTop:        article.IsTop,
+			PublishedAt:  article.PublishedAt,
+			CreatedAt:    article.CreatedAt,
+			Score:        hit.Score,
+			Highlight: response.SearchHighlight{
+				Title:   highlighter.Highlight(article.Title),
+				Summary: highlighter.Highlight(article.Summary),
+				Content: highlighter.Snippet(contents[hit.ID], searchSnippetLength),
+			},
+		})
+	}
+
+	return &response.SearchResponse{
+		Items:    items,
+		Total:    result.Total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Keyword:  keyword,
+	}, nil
+}
+
+// RebuildSearchIndex 使用全部已发布文章重建检索索引
+func (s *articleService) RebuildSearchIndex() error {
+	articles, err := s.articleRepo.ListPublishedWithTags()
+	if err != nil {
+		return err
+	}
+	docs := make([]*search.Document, 0, len(articles))
+	for _, article := range articles {
+		tags := make([]*models.Tag, 0, len(article.Tags))
+		for i := range article.Tags {
+			tags = append(tags, &article.Tags[i])
+		}
+		docs = append(docs, searchDocument(article, tags))
+	}
+	if err := s.searchEngine.Rebuild(docs); err != nil {
+		return err
+	}
+	logger.Info("文章检索索引重建完成", logger.Int("count", len(docs)))
+	return nil
+}
+
+// HandleArticleEvent 文章发布、修改后写入检索索引，下线或删除后从索引中移除
+func (s *articleService) HandleArticleEvent(event ArticleEvent) {
+	s.indexArticle(event.Article, event.Type == ArticleEventDeleted)
+}
+
+// indexArticle 同步文章检索索引：已发布的文章写入索引，其余从索引中删除
+// 索引失败只记录日志，不影响文章保存
+func (s *articleService) indexArticle(article *models.Article, deleted bool) {
+	if deleted || article.Status != models.ArticleStatusPublished {
+		if err := s.searchEngine.Delete(article.ID); err != nil {
+			logger.Warn("删除文章检索索引失败", logger.Uint("article_id", uint(article.ID)), logger.Err("error", err))
+		}
+		return
+	}
+
+	tags, err := s.tagRepo.GetByArticleID(uint(article.ID))
+	if err != nil {
+		logger.Warn("获取文章标签失败", logger.Uint("article_id", uint(article.ID)), logger.Err("error", err))
+	}
+	if err := s.searchEngine.Index(searchDocument(article, tags)); err != nil {
+		logger.Warn("更新文章检索索引失败", logger.Uint("article_id", uint(article.ID)), logger.Err("error", err))
+	}
 }
 
-func (s *articleService) ViewArticle(id uint) error {
-	return s.articleRepo.IncrementViewCount(id)
+// searchDocument 将文章转换为检索文档
+func searchDocument(article *models.Article, tags []*models.Tag) *search.Document {
+	doc := &search.Document{
+		ID:          article.ID,
+		Title:       article.Title,
+		Summary:     article.Summary,
+		Content:     article.Content,
+		CategoryID:  article.CategoryID,
+		PublishedAt: article.CreatedAt,
+	}
+	if article.PublishedAt != nil {
+		doc.PublishedAt = *article.PublishedAt
+	}
+	for _, tag := range tags {
+		doc.Tags = append(doc.Tags, tag.Name)
+		doc.TagIDs = append(doc.TagIDs, tag.ID)
+	}
+	return doc
 }
 
 func (s *articleService) GetHotArticles(limit int) ([]*models.Article, error) {
@@ -203,3 +635,244 @@ func (s *articleService) UnfavoriteArticle(articleID, userID uint) error {
 	// 更新文章收藏数
 	return s.favoriteRepo.DecrementFavoriteCount(articleID)
 }
+
+// ListRevisions 分页获取文章修订列表（不含正文）
+func (s *articleService) ListRevisions(articleID uint, page, pageSize int) (*response.ArticleRevisionListResponse, error) {
+	if _, err := s.articleRepo.GetByID(articleID); err != nil {
+		return nil, errors.New("文章不存在")
+	}
+	revisions, total, err := s.revisionRepo.List(articleID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.ArticleRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		items[i] = toRevisionResponse(revision)
+	}
+	return &response.ArticleRevisionListResponse{
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// GetRevision 获取文章指定版本的修订
+func (s *articleService) GetRevision(articleID uint, version int) (*response.ArticleRevisionResponse, error) {
+	revision, err := s.revisionRepo.Get(articleID, version)
+	if err != nil {
+		return nil, fmt.Errorf("修订版本 %d 不存在", version)
+	}
+	result := toRevisionResponse(revision)
+	return &result, nil
+}
+
+// DiffRevisions 对比文章两个修订，正文按行或按词对比，标题和摘要按词对比
+func (s *articleService) DiffRevisions(articleID uint, from, to int, mode string) (*response.ArticleRevisionDiffResponse, error) {
+	fromRevision, err := s.revisionRepo.Get(articleID, from)
+	if err != nil {
+		return nil, fmt.Errorf("修订版本 %d 不存在", from)
+	}
+	toRevision, err := s.revisionRepo.Get(articleID, to)
+	if err != nil {
+		return nil, fmt.Errorf("修订版本 %d 不存在", to)
+	}
+	if mode == "" {
+		mode = "line"
+	}
+
+	title, err := utils.DiffWords(fromRevision.Title, toRevision.Title)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := utils.DiffWords(fromRevision.Summary, toRevision.Summary)
+	if err != nil {
+		return nil, err
+	}
+	result := &response.ArticleRevisionDiffResponse{
+		ArticleID: uint64(articleID),
+		From:      from,
+		To:        to,
+		Mode:      mode,
+		Title:     title,
+		Summary:   summary,
+	}
+	result.TagsAdded, result.TagsRemoved = diffRevisionTags(decodeRevisionTags(fromRevision.Tags), decodeRevisionTags(toRevision.Tags))
+
+	lines := utils.DiffLines(fromRevision.Content, toRevision.Content)
+	result.Added, result.Removed = utils.DiffStat(lines)
+	if mode == "word" {
+		// 正文过大时退回按行对比
+		result.Words, err = utils.DiffWords(fromRevision.Content, toRevision.Content)
+		if errors.Is(err, utils.ErrDiffTooLarge) {
+			result.Mode = "line"
+			result.TooLarge = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if result.Mode != "word" {
+		result.Lines = lines
+		result.Unified = utils.UnifiedDiff(fmt.Sprintf("v%d", from), fmt.Sprintf("v%d", to), lines)
+	}
+	return result, nil
+}
+
+// RestoreRevision 将文章恢复到指定修订的标题、摘要、正文和标签，恢复结果作为新的修订保存
+// 已删除的标签不会恢复
+func (s *articleService) RestoreRevision(articleID uint, version int, editorID uint) (*models.Article, error) {
+	revision, err := s.revisionRepo.Get(articleID, version)
+	if err != nil {
+		return nil, fmt.Errorf("修订版本 %d 不存在", version)
+	}
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, errors.New("文章不存在")
+	}
+
+	article.Title = revision.Title
+	article.Summary = revision.Summary
+	article.Content = revision.Content
+	article.UpdatedAt = time.Now()
+	if err := s.articleRepo.Update(article); err != nil {
+		return nil, err
+	}
+
+	tagIDs := make([]uint, 0)
+	for _, tag := range decodeRevisionTags(revision.Tags) {
+		if _, err := s.tagRepo.GetByID(uint(tag.ID)); err == nil {
+			tagIDs = append(tagIDs, uint(tag.ID))
+		}
+	}
+	if err := s.articleRepo.SetTags(article.ID, tagIDs); err != nil {
+		return nil, err
+	}
+
+	s.saveRevision(article, editorID, models.RevisionActionRestore, version)
+	s.notifyChange(article.Status, article)
+	return article, nil
+}
+
+// saveRevision 保存文章当前内容为新修订，与最新修订相同时跳过
+// 修订失败只记录日志，不影响文章保存
+func (s *articleService) saveRevision(article *models.Article, editorID uint, action string, restoredFrom int) {
+	tags, err := s.revisionTags(article.ID)
+	if err != nil {
+		logger.Warn("获取文章标签失败", logger.Uint("article_id", uint(article.ID)), logger.Err("error", err))
+	}
+
+	if latest, err := s.revisionRepo.GetLatest(uint(article.ID)); err == nil &&
+		latest.Title == article.Title && latest.Summary == article.Summary &&
+		latest.Content == article.Content && latest.Tags == tags {
+		return
+	}
+
+	revision := &models.ArticleRevision{
+		ArticleID:    article.ID,
+		Title:        article.Title,
+		Summary:      article.Summary,
+		Content:      article.Content,
+		Tags:         tags,
+		EditorID:     uint64(editorID),
+		Action:       action,
+		RestoredFrom: restoredFrom,
+	}
+	if err := s.revisionRepo.Create(revision, s.maxRevisions); err != nil {
+		logger.Warn("保存文章修订失败", logger.Uint("article_id", uint(article.ID)), logger.Err("error", err))
+	}
+}
+
+// ensureBaselineRevision 文章没有任何修订时，将当前内容补记为第一个修订
+func (s *articleService) ensureBaselineRevision(article *models.Article) {
+	if _, err := s.revisionRepo.GetLatest(uint(article.ID)); err == nil {
+		return
+	}
+	tags, err := s.revisionTags(article.ID)
+	if err != nil {
+		logger.Warn("获取文章标签失败", logger.Uint("article_id", uint(article.ID)), logger.Err("error", err))
+	}
+	revision := &models.ArticleRevision{
+		ArticleID: article.ID,
+		Title:     article.Title,
+		Summary:   article.Summary,
+		Content:   article.Content,
+		Tags:      tags,
+		EditorID:  article.AuthorID,
+		Action:    models.RevisionActionCreate,
+		CreatedAt: article.UpdatedAt,
+	}
+	if err := s.revisionRepo.Create(revision, s.maxRevisions); err != nil {
+		logger.Warn("保存文章修订失败", logger.Uint("article_id", uint(article.ID)), logger.Err("error", err))
+	}
+}
+
+// revisionTags 序列化文章当前标签，按ID排序保证相同标签序列化结果一致
+func (s *articleService) revisionTags(articleID uint64) (string, error) {
+	tags, err := s.tagRepo.GetByArticleID(uint(articleID))
+	if err != nil {
+		return "[]", err
+	}
+	snapshot := make([]models.RevisionTag, 0, len(tags))
+	for _, tag := range tags {
+		snapshot = append(snapshot, models.RevisionTag{ID: tag.ID, Name: tag.Name})
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].ID < snapshot[j].ID })
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return "[]", err
+	}
+	return string(data), nil
+}
+
+func decodeRevisionTags(data string) []models.RevisionTag {
+	var tags []models.RevisionTag
+	if data == "" {
+		return tags
+	}
+	if err := json.Unmarshal([]byte(data), &tags); err != nil {
+		logger.Warn("解析修订标签失败", logger.Err("error", err))
+	}
+	return tags
+}
+
+// diffRevisionTags 计算新增和移除的标签名称
+func diffRevisionTags(from, to []models.RevisionTag) (added, removed []string) {
+	fromIDs := make(map[uint64]bool, len(from))
+	for _, tag := range from {
+		fromIDs[tag.ID] = true
+	}
+	toIDs := make(map[uint64]bool, len(to))
+	for _, tag := range to {
+		toIDs[tag.ID] = true
+		if !fromIDs[tag.ID] {
+			added = append(added, tag.Name)
+		}
+	}
+	for _, tag := range from {
+		if !toIDs[tag.ID] {
+			removed = append(removed, tag.Name)
+		}
+	}
+	return added, removed
+}
+
+func toRevisionResponse(revision *models.ArticleRevision) response.ArticleRevisionResponse {
+	tags := decodeRevisionTags(revision.Tags)
+	result := response.ArticleRevisionResponse{
+		ArticleID:    revision.ArticleID,
+		Version:      revision.Version,
+		Title:        revision.Title,
+		Summary:      revision.Summary,
+		Content:      revision.Content,
+		Tags:         make([]response.ArticleRevisionTag, len(tags)),
+		EditorID:     revision.EditorID,
+		Action:       revision.Action,
+		RestoredFrom: revision.RestoredFrom,
+		CreatedAt:    revision.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	for i, tag := range tags {
+		result.Tags[i] = response.ArticleRevisionTag{ID: tag.ID, Name: tag.Name}
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	models "my-blog-backend/internal/models/frontendModel"
+)
+
+func (r *fakeArticleRepo) GetByID(id uint) (*models.Article, error) {
+	if article, ok := r.articles[uint64(id)]; ok {
+		return article, nil
+	}
+	return nil, ErrArticleNotFound
+}
+
+func (r *fakeArticleRepo) GetBySlug(slug string) (*models.Article, error) {
+	for _, article := range r.articles {
+		if article.Slug == slug {
+			return article, nil
+		}
+	}
+	return nil, ErrArticleNotFound
+}
+
+func (r *fakeArticleRepo) ListDueForPublish(now time.Time) ([]*models.Article, error) {
+	var articles []*models.Article
+	for _, article := range r.articles {
+		if article.Status == models.ArticleStatusScheduled && article.PublishedAt != nil && !article.PublishedAt.After(now) {
+			articles = append(articles, article)
+		}
+	}
+	return articles, nil
+}
+
+func (r *fakeArticleRepo) ListDueForUnpublish(now time.Time) ([]*models.Article, error) {
+	return nil, nil
+}
+
+func (r *fakeArticleRepo) ChangeStatus(id uint64, from, to uint8) (bool, error) {
+	article, ok := r.articles[id]
+	if !ok || article.Status != from {
+		return false, nil
+	}
+	// 与数据库实现一致，只改库中的状态，内存中的文章由调用方更新
+	r.articles[id] = &models.Article{ID: article.ID, Slug: article.Slug, Status: to, PublishedAt: article.PublishedAt}
+	return true, nil
+}
+
+func TestScheduledArticleHiddenUntilPublished(t *testing.T) {
+	publishAt := time.Now().Add(time.Hour)
+	articleRepo := &fakeArticleRepo{articles: map[uint64]*models.Article{
+		1: {ID: 1, Slug: "scheduled", Status: models.ArticleStatusScheduled, PublishedAt: &publishAt},
+	}}
+	service := &articleService{articleRepo: articleRepo}
+
+	service.runDueSchedules(publishAt.Add(-time.Minute))
+	if _, err := service.GetArticle(1); !errors.Is(err, ErrArticleNotFound) {
+		t.Fatalf("未到发布时间的定时文章不应公开, 实际 %v", err)
+	}
+	if _, err := service.GetArticleBySlug("scheduled"); !errors.Is(err, ErrArticleNotFound) {
+		t.Fatalf("未到发布时间的定时文章不应按 slug 公开, 实际 %v", err)
+	}
+
+	service.runDueSchedules(publishAt)
+	if article, err := service.GetArticle(1); err != nil || article.Status != models.ArticleStatusPublished {
+		t.Fatalf("到期后定时文章应已发布: article=%v err=%v", article, err)
+	}
+	if _, err := service.GetArticleBySlug("scheduled"); err != nil {
+		t.Fatalf("到期后定时文章应可按 slug 访问: %v", err)
+	}
+}
+
+func TestGetArticleBySlugPublishedOnly(t *testing.T) {
+	articleRepo := &fakeArticleRepo{articles: map[uint64]*models.Article{
+		1: {ID: 1, Slug: "published", Status: models.ArticleStatusPublished},
+		2: {ID: 2, Slug: "draft", Status: models.ArticleStatusDraft},
+		3: {ID: 3, Slug: "scheduled", Status: models.ArticleStatusScheduled},
+	}}
+	service := &articleService{articleRepo: articleRepo}
+
+	for _, slug := range []string{"draft", "scheduled"} {
+		if _, err := service.GetArticleBySlug(slug); !errors.Is(err, ErrArticleNotFound) {
+			t.Fatalf("slug %s: 未发布文章应返回 ErrArticleNotFound, 实际 %v", slug, err)
+		}
+	}
+	article, err := service.GetArticleBySlug("published")
+	if err != nil || article.ID != 1 {
+		t.Fatalf("已发布文章应正常返回: article=%v err=%v", article, err)
+	}
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"my-blog-backend/internal/config"
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/repository"
+)
+
+// botUserAgentKeywords 爬虫、监控和命令行工具 User-Agent 中常见的关键字
+var botUserAgentKeywords = []string{
+	"bot", "spider", "crawl", "slurp", "bingpreview", "facebookexternalhit", "embedly",
+	"headless", "lighthouse", "pingdom", "uptime", "monitor", "curl", "wget",
+	"python-requests", "python-urllib", "go-http-client", "okhttp", "java/", "httpclient", "axios", "node-fetch",
+}
+
+const (
+	articleStatusCacheTTL  = time.Minute // 文章是否可统计的缓存时间，多实例部署时其他实例的状态变更最多延迟这么久生效
+	articleStatusCacheSize = 10000       // 缓存的文章数量上限，超过后清空，避免任意ID撑大缓存
+)
+
+// ErrArticleNotFound 文章不存在或未发布
+var ErrArticleNotFound = errors.New("文章不存在")
+
+// ArticleVisitor 阅读文章的访客
+type ArticleVisitor struct {
+	UserID    uint64 // 未登录时为 0
+	IP        string
+	UserAgent string
+}
+
+// ArticleViewService 文章阅读量统计服务
+// 阅读记录先在缓冲区去重和暂存，定时批量写入浏览记录表并累加文章阅读量
+type ArticleViewService interface {
+	// RecordView 记录一次阅读，爬虫和时间窗口内的重复阅读不计入，返回是否计入
+	// 文章不存在或未发布时返回 ErrArticleNotFound
+	RecordView(articleID uint, visitor *ArticleVisitor) (bool, error)
+	// RunFlusher 定时将缓冲的阅读记录写入数据库
+	RunFlusher()
+	// Flush 立即写入全部缓冲的阅读记录，停机前调用
+	Flush() error
+	// HandleArticleEvent 文章发布、下线或删除后清除该文章的状态缓存
+	HandleArticleEvent(event ArticleEvent)
+}
+
+type articleViewService struct {
+	articleRepo repository.ArticleRepository
+	viewRepo    repository.ArticleViewRepository
+	buffer      repository.ArticleViewBuffer
+	config      *config.ViewConfig
+	botKeywords []string
+
+	mu        sync.RWMutex
+	published map[uint64]*articleStatusEntry // 文章是否已发布，包括不存在的文章
+}
+
+type articleStatusEntry struct {
+	published bool
+	expiresAt time.Time
+}
+
+func NewArticleViewService(articleRepo repository.ArticleRepository, viewRepo repository.ArticleViewRepository, buffer repository.ArticleViewBuffer, viewConfig *config.ViewConfig) ArticleViewService {
+	keywords := append([]string(nil), botUserAgentKeywords...)
+	for _, keyword := range viewConfig.BotKeywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return &articleViewService{
+		articleRepo: articleRepo,
+		viewRepo:    viewRepo,
+		buffer:      buffer,
+		config:      viewConfig,
+		botKeywords: keywords,
+		published:   make(map[uint64]*articleStatusEntry),
+	}
+}
+
+func (s *articleViewService) RecordView(articleID uint, visitor *ArticleVisitor) (bool, error) {
+	if s.isBot(visitor.UserAgent) {
+		return false, nil
+	}
+	// 只统计已发布的文章，避免任意ID占用去重和缓冲空间
+	published, err := s.isPublished(uint64(articleID))
+	if err != nil {
+		return false, err
+	}
+	if !published {
+		return false, ErrArticleNotFound
+	}
+
+	ctx := context.Background()
+	first, err := s.buffer.MarkSeen(ctx, fmt.Sprintf("%d:%s", articleID, visitorKey(visitor)), s.config.DedupWindow)
+	if err != nil || !first {
+		return false, err
+	}
+
+	// user_agent 字段长度为 500
+	if runes := []rune(visitor.UserAgent); len(runes) > 500 {
+		visitor.UserAgent = string(runes[:500])
+	}
+	view := &models.ArticleView{
+		ArticleID: uint64(articleID),
+		IPAddress: visitor.IP,
+		UserAgent: visitor.UserAgent,
+		CreatedAt: time.Now(),
+	}
+	if visitor.UserID != 0 {
+		userID := visitor.UserID
+		view.UserID = &userID
+	}
+	if err := s.buffer.Push(ctx, view); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *articleViewService) RunFlusher() {
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.Flush(); err != nil {
+			logger.Error("写入文章阅读记录失败", logger.Err("error", err))
+		}
+	}
+}
+
+// Flush 按批次写入，直到缓冲区为空；写入失败的批次放回缓冲区，下次重试
+func (s *articleViewService) Flush() error {
+	ctx := context.Background()
+	for {
+		views, err := s.buffer.Pop(ctx, s.config.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(views) == 0 {
+			return nil
+		}
+		if err := s.viewRepo.CreateBatch(views); err != nil {
+			for _, view := range views {
+				if pushErr := s.buffer.Push(ctx, view); pushErr != nil {
+					logger.Warn("阅读记录放回缓冲区失败", logger.Uint("article_id", uint(view.ArticleID)), logger.Err("error", pushErr))
+				}
+			}
+			return err
+		}
+		if len(views) < s.config.BatchSize {
+			return nil
+		}
+	}
+}
+
+// HandleArticleEvent 文章状态变化后清除缓存，下次阅读时重新查询
+func (s *articleViewService) HandleArticleEvent(event ArticleEvent) {
+	s.mu.Lock()
+	delete(s.published, event.Article.ID)
+	s.mu.Unlock()
+}
+
+// isPublished 查询文章是否已发布，结果缓存 articleStatusCacheTTL，阅读请求不必每次查询数据库
+func (s *articleViewService) isPublished(articleID uint64) (bool, error) {
+	s.mu.RLock()
+	entry, ok := s.published[articleID]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.published, nil
+	}
+
+	articles, err := s.articleRepo.GetByIDs([]uint64{articleID})
+	if err != nil {
+		return false, err
+	}
+	published := len(articles) > 0 && articles[0].Status == models.ArticleStatusPublished
+
+	s.mu.Lock()
+	if len(s.published) >= articleStatusCacheSize {
+		s.published = make(map[uint64]*articleStatusEntry)
+	}
+	s.published[articleID] = &articleStatusEntry{published: published, expiresAt: time.Now().Add(articleStatusCacheTTL)}
+	s.mu.Unlock()
+	return published, nil
+}
+
+// isBot 根据 User-Agent 判断是否为爬虫，没有 User-Agent 的请求也视为爬虫
+func (s *articleViewService) isBot(userAgent string) bool {
+	userAgent = strings.ToLower(strings.TrimSpace(userAgent))
+	if userAgent == "" {
+		return true
+	}
+	for _, keyword := range s.botKeywords {
+		if strings.Contains(userAgent, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// visitorKey 登录用户按用户ID去重，游客按 IP 和 User-Agent 去重，避免同一出口 IP 下的不同设备被合并
+func visitorKey(visitor *ArticleVisitor) string {
+	if visitor.UserID != 0 {
+		return fmt.Sprintf("u%d", visitor.UserID)
+	}
+	sum := sha1.Sum([]byte(visitor.IP + "|" + visitor.UserAgent))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"my-blog-backend/internal/config"
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/repository"
+	"my-blog-backend/internal/repository/impl"
+)
+
+// fakeArticleRepo 只实现测试用到的查询，queries 记录 GetByIDs 的调用次数
+type fakeArticleRepo struct {
+	repository.ArticleRepository
+	articles map[uint64]*models.Article
+	queries  int
+}
+
+func (r *fakeArticleRepo) GetByIDs(ids []uint64) ([]*models.Article, error) {
+	r.queries++
+	var articles []*models.Article
+	for _, id := range ids {
+		if article, ok := r.articles[id]; ok {
+			articles = append(articles, article)
+		}
+	}
+	return articles, nil
+}
+
+// fakeViewBuffer 记录去重标记和缓冲的阅读记录
+type fakeViewBuffer struct {
+	seen  map[string]bool
+	views []*models.ArticleView
+}
+
+func (b *fakeViewBuffer) MarkSeen(ctx context.Context, key string, window time.Duration) (bool, error) {
+	if b.seen[key] {
+		return false, nil
+	}
+	b.seen[key] = true
+	return true, nil
+}
+
+func (b *fakeViewBuffer) Push(ctx context.Context, view *models.ArticleView) error {
+	b.views = append(b.views, view)
+	return nil
+}
+
+func (b *fakeViewBuffer) Pop(ctx context.Context, limit int) ([]*models.ArticleView, error) {
+	n := min(limit, len(b.views))
+	views := b.views[:n]
+	b.views = b.views[n:]
+	return views, nil
+}
+
+func newTestViewService() (ArticleViewService, *fakeViewBuffer) {
+	articleRepo := &fakeArticleRepo{articles: map[uint64]*models.Article{
+		1: {ID: 1, Status: models.ArticleStatusPublished},
+		2: {ID: 2, Status: models.ArticleStatusDraft},
+	}}
+	buffer := &fakeViewBuffer{seen: make(map[string]bool)}
+	service := NewArticleViewService(articleRepo, nil, buffer, &config.ViewConfig{
+		DedupWindow:   time.Hour,
+		FlushInterval: time.Minute,
+		BatchSize:     100,
+	})
+	return service, buffer
+}
+
+func TestRecordViewUnknownArticle(t *testing.T) {
+	service, buffer := newTestViewService()
+	visitor := &ArticleVisitor{IP: "203.0.113.1", UserAgent: "Mozilla/5.0"}
+
+	for _, id := range []uint{2, 404} {
+		counted, err := service.RecordView(id, visitor)
+		if !errors.Is(err, ErrArticleNotFound) || counted {
+			t.Fatalf("文章 %d: 期望 ErrArticleNotFound, 实际 counted=%v err=%v", id, counted, err)
+		}
+	}
+	if len(buffer.seen) != 0 || len(buffer.views) != 0 {
+		t.Fatalf("不存在或未发布的文章不应写入缓冲区: seen=%d views=%d", len(buffer.seen), len(buffer.views))
+	}
+
+	counted, err := service.RecordView(1, visitor)
+	if err != nil || !counted {
+		t.Fatalf("已发布文章应计入阅读量: counted=%v err=%v", counted, err)
+	}
+	if counted, _ := service.RecordView(1, visitor); counted {
+		t.Fatal("时间窗口内的重复阅读不应计入")
+	}
+}
+
+func TestRecordViewMemoryBuffer(t *testing.T) {
+	articleRepo := &fakeArticleRepo{articles: map[uint64]*models.Article{
+		1: {ID: 1, Status: models.ArticleStatusPublished},
+	}}
+	buffer := impl.NewArticleViewBuffer(nil)
+	service := NewArticleViewService(articleRepo, nil, buffer, &config.ViewConfig{
+		DedupWindow: time.Hour,
+		BatchSize:   100,
+		BotKeywords: []string{"MyCrawler"},
+	})
+
+	tests := []struct {
+		name    string
+		visitor ArticleVisitor
+		counted bool
+	}{
+		{"游客首次阅读", ArticleVisitor{IP: "203.0.113.1", UserAgent: "Mozilla/5.0"}, true},
+		{"同一游客重复阅读", ArticleVisitor{IP: "203.0.113.1", UserAgent: "Mozilla/5.0"}, false},
+		{"同一IP的其他设备", ArticleVisitor{IP: "203.0.113.1", UserAgent: "Mozilla/5.0 (iPhone)"}, true},
+		{"登录用户首次阅读", ArticleVisitor{UserID: 7, IP: "203.0.113.2", UserAgent: "Mozilla/5.0"}, true},
+		{"登录用户换IP重复阅读", ArticleVisitor{UserID: 7, IP: "198.51.100.9", UserAgent: "Mozilla/5.0"}, false},
+		{"内置爬虫关键字", ArticleVisitor{IP: "203.0.113.3", UserAgent: "Googlebot/2.1"}, false},
+		{"配置的爬虫关键字", ArticleVisitor{IP: "203.0.113.4", UserAgent: "mycrawler/1.0"}, false},
+		{"没有User-Agent", ArticleVisitor{IP: "203.0.113.5"}, false},
+	}
+	for _, tt := range tests {
+		counted, err := service.RecordView(1, &tt.visitor)
+		if err != nil || counted != tt.counted {
+			t.Fatalf("%s: 期望 counted=%v, 实际 counted=%v err=%v", tt.name, tt.counted, counted, err)
+		}
+	}
+
+	views, err := buffer.Pop(context.Background(), 100)
+	if err != nil || len(views) != 3 {
+		t.Fatalf("缓冲区应有 3 条阅读记录: views=%d err=%v", len(views), err)
+	}
+	if articleRepo.queries != 1 {
+		t.Fatalf("文章状态应被缓存, 实际查询 %d 次", articleRepo.queries)
+	}
+
+	// 文章下线后清除缓存，不再计入阅读
+	articleRepo.articles[1].Status = models.ArticleStatusDraft
+	service.HandleArticleEvent(ArticleEvent{Type: ArticleEventUnpublished, Article: articleRepo.articles[1]})
+	visitor := &ArticleVisitor{IP: "203.0.113.6", UserAgent: "Mozilla/5.0"}
+	if _, err := service.RecordView(1, visitor); !errors.Is(err, ErrArticleNotFound) {
+		t.Fatalf("下线的文章应返回 ErrArticleNotFound, 实际 %v", err)
+	}
+}
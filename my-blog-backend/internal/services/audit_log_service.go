@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/repository"
+	"my-blog-backend/internal/ssh"
+)
+
+// TerminalAuditContext 终端会话的审计上下文
+type TerminalAuditContext struct {
+	UserID      uint
+	UserName    string
+	HostID      uint
+	HostName    string
+	HostAddress string
+	SessionID   string
+	ContainerID string // 容器终端会话的容器ID
+	ClientIP    string
+	ClientAgent string
+}
+
+// AuditLogService 终端审计日志
+type AuditLogService struct {
+	auditRepo repository.AuditLogRepository
+}
+
+func NewAuditLogService(auditRepo repository.AuditLogRepository) *AuditLogService {
+	return &AuditLogService{auditRepo: auditRepo}
+}
+
+// RecordFileTransfer 记录终端内 rz/sz 文件传输，写入失败只记录日志
+func (s *AuditLogService) RecordFileTransfer(ctx *TerminalAuditContext, file ssh.ZmodemFile) {
+	action, command := opsModel.FileDownloadAction, "sz"
+	if file.Direction == ssh.ZmodemUpload {
+		action, command = opsModel.FileUploadAction, "rz"
+	}
+
+	status, risk := opsModel.AuditSuccess, opsModel.LowRisk
+	switch file.Status {
+	case ssh.ZmodemRejected:
+		status, risk = opsModel.AuditWarning, opsModel.MediumRisk
+	case ssh.ZmodemSkipped:
+		status = opsModel.AuditWarning
+	case ssh.ZmodemFailed:
+		status = opsModel.AuditFailed
+	}
+
+	endTime := file.EndTime
+	record := &opsModel.AuditLog{
+		UserID:       ctx.UserID,
+		UserName:     ctx.UserName,
+		HostID:       ctx.HostID,
+		HostName:     ctx.HostName,
+		HostAddress:  ctx.HostAddress,
+		SessionID:    ctx.SessionID,
+		ContainerID:  ctx.ContainerID,
+		Action:       action,
+		Command:      fmt.Sprintf("%s %s (%d bytes)", command, file.Name, file.Size),
+		Status:       status,
+		RiskLevel:    risk,
+		ClientIP:     ctx.ClientIP,
+		ClientAgent:  truncateAgent(ctx.ClientAgent),
+		ErrorMessage: file.Message,
+		Duration:     file.EndTime.Sub(file.StartTime).Milliseconds(),
+		StartTime:    file.StartTime,
+		EndTime:      &endTime,
+	}
+	if err := s.auditRepo.Create(record); err != nil {
+		logger.Error("记录文件传输审计失败", logger.String("session", ctx.SessionID), logger.String("file", file.Name), logger.Err("error", err))
+	}
+}
+
+// RecordSession 记录终端会话（如进入容器），sessionErr 为会话启动失败的原因
+func (s *AuditLogService) RecordSession(ctx *TerminalAuditContext, command string, startTime time.Time, sessionErr error) {
+	endTime := time.Now()
+	record := &opsModel.AuditLog{
+		UserID:      ctx.UserID,
+		UserName:    ctx.UserName,
+		HostID:      ctx.HostID,
+		HostName:    ctx.HostName,
+		HostAddress: ctx.HostAddress,
+		SessionID:   ctx.SessionID,
+		ContainerID: ctx.ContainerID,
+		Action:      opsModel.SessionAction,
+		Command:     command,
+		Status:      opsModel.AuditSuccess,
+		RiskLevel:   opsModel.LowRisk,
+		ClientIP:    ctx.ClientIP,
+		ClientAgent: truncateAgent(ctx.ClientAgent),
+		Duration:    endTime.Sub(startTime).Milliseconds(),
+		StartTime:   startTime,
+		EndTime:     &endTime,
+	}
+	if sessionErr != nil {
+		record.Status = opsModel.AuditFailed
+		record.ErrorMessage = sessionErr.Error()
+	}
+	if err := s.auditRepo.Create(record); err != nil {
+		logger.Error("记录会话审计失败", logger.String("session", ctx.SessionID), logger.Err("error", err))
+	}
+}
+
+// RecordCommand 记录终端中提交的命令行，policyErr 不为空表示命令被策略拒绝
+func (s *AuditLogService) RecordCommand(ctx *TerminalAuditContext, command string, policyErr error) {
+	now := time.Now()
+	record := &opsModel.AuditLog{
+		UserID:      ctx.UserID,
+		UserName:    ctx.UserName,
+		HostID:      ctx.HostID,
+		HostName:    ctx.HostName,
+		HostAddress: ctx.HostAddress,
+		SessionID:   ctx.SessionID,
+		ContainerID: ctx.ContainerID,
+		Action:      opsModel.ExecuteAction,
+		Command:     command,
+		Status:      opsModel.AuditSuccess,
+		RiskLevel:   opsModel.LowRisk,
+		ClientIP:    ctx.ClientIP,
+		ClientAgent: truncateAgent(ctx.ClientAgent),
+		StartTime:   now,
+		EndTime:     &now,
+	}
+	if policyErr != nil {
+		record.Status = opsModel.AuditWarning
+		record.RiskLevel = opsModel.HighRisk
+		record.ErrorMessage = policyErr.Error()
+	}
+	if err := s.auditRepo.Create(record); err != nil {
+		logger.Error("记录命令审计失败", logger.String("session", ctx.SessionID), logger.String("command", command), logger.Err("error", err))
+	}
+}
+
+// truncateAgent User-Agent 超过字段长度时截断
+func truncateAgent(agent string) string {
+	if len(agent) > 255 {
+		return agent[:255]
+	}
+	return agent
+}
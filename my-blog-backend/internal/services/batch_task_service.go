@@ -0,0 +1,493 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/models"
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/pkg/utils"
+	"my-blog-backend/internal/repository"
+	"my-blog-backend/internal/ssh"
+	"my-blog-backend/internal/websocket"
+)
+
+const (
+	// 单个任务同时执行的主机数
+	taskParallelism = 10
+	// 默认任务超时时间（秒）
+	defaultTaskTimeout = 300
+	// 单台主机保存的输出上限（task_host_relations.output 为 TEXT 类型）
+	maxTaskOutput = 60 * 1024
+)
+
+// hostRunner 在单台主机上执行任务的具体逻辑，输出写入 stdout/stderr（实时推送并落库），返回退出码
+type hostRunner func(ctx context.Context, client *ssh.SSHClient, relation *opsModel.TaskHostRelation, stdout, stderr io.Writer) (exitCode int, err error)
+
+type BatchTaskService struct {
+	taskRepo    repository.BatchTaskRepository
+	hostRepo    repository.HostRepository
+	hostService *HostService
+	sshPool     *ssh.Pool
+	taskHub     *websocket.TaskHub
+	masker      *OutputMasker               // 输出落库和推送前脱敏
+	cancels     map[uint]context.CancelFunc // taskID -> 取消函数
+	outputs     map[uint]*hostOutput        // relationID -> 执行中主机的输出
+	mu          sync.Mutex
+}
+
+func NewBatchTaskService(taskRepo repository.BatchTaskRepository, hostRepo repository.HostRepository, hostService *HostService, sshPool *ssh.Pool, taskHub *websocket.TaskHub, masker *OutputMasker) *BatchTaskService {
+	return &BatchTaskService{
+		taskRepo:    taskRepo,
+		hostRepo:    hostRepo,
+		hostService: hostService,
+		sshPool:     sshPool,
+		taskHub:     taskHub,
+		masker:      masker,
+		cancels:     make(map[uint]context.CancelFunc),
+		outputs:     make(map[uint]*hostOutput),
+	}
+}
+
+// TaskOperator 提交任务的用户，用于校验目标主机的访问权限
+type TaskOperator struct {
+	UserID     uint
+	SuperAdmin bool
+}
+
+// ErrTaskAccessDenied 用户无权查看任务
+var ErrTaskAccessDenied = errors.New("无权查看该任务")
+
+// submit 创建任务记录并异步在所有主机上执行 runner
+// 目标主机为 hostIDs 与 task.HostSelector 匹配主机的并集，选择器在执行时重新解析
+// 提交和执行时都按 operator 校验主机访问权限
+// cleanup 不为 nil 时在任务结束或提交失败后调用，用于清理任务使用的临时文件
+func (s *BatchTaskService) submit(task *opsModel.BatchTask, operator TaskOperator, hostIDs []uint, runner hostRunner, cleanup func()) (err error) {
+	defer func() {
+		if err != nil && cleanup != nil {
+			cleanup()
+		}
+	}()
+
+	selector, err := utils.ParseLabelSelector(task.HostSelector)
+	if err != nil {
+		return err
+	}
+	task.HostSelector = selector.String()
+
+	// 提交时先解析一次，尽早发现无效的主机选择
+	relations, err := s.buildRelations(operator, hostIDs, selector)
+	if err != nil {
+		return err
+	}
+
+	if task.Timeout <= 0 {
+		task.Timeout = defaultTaskTimeout
+	}
+	task.Status = opsModel.TaskPending
+	task.TotalHosts = len(relations)
+	task.CreatedAt = time.Now()
+
+	if err := s.taskRepo.Create(task, nil); err != nil {
+		return fmt.Errorf("创建任务失败: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.cancels[task.ID] = cancel
+	s.mu.Unlock()
+
+	go s.execute(ctx, task, operator, hostIDs, selector, runner, cleanup)
+	return nil
+}
+
+// buildRelations 根据主机ID列表和标签选择器构建任务主机关联记录
+// 显式指定的主机不存在、已禁用或无权访问时报错，选择器匹配到的这类主机直接跳过
+func (s *BatchTaskService) buildRelations(operator TaskOperator, hostIDs []uint, selector utils.LabelSelector) ([]*opsModel.TaskHostRelation, error) {
+	seen := make(map[uint]bool, len(hostIDs))
+	relations := make([]*opsModel.TaskHostRelation, 0, len(hostIDs))
+	addHost := func(host *opsModel.RemoteHost) {
+		seen[host.ID] = true
+		relations = append(relations, &opsModel.TaskHostRelation{
+			HostID:    host.ID,
+			HostName:  host.Name,
+			HostAddr:  host.Address,
+			Status:    opsModel.TaskPending,
+			CreatedAt: time.Now(),
+		})
+	}
+
+	for _, hostID := range hostIDs {
+		if seen[hostID] {
+			continue
+		}
+		host, err := s.hostRepo.GetByID(hostID)
+		if err != nil {
+			return nil, fmt.Errorf("主机 %d 不存在", hostID)
+		}
+		if host.Status != models.StatusEnabled {
+			return nil, fmt.Errorf("主机 %s 已禁用", host.Name)
+		}
+		if err := s.hostService.CheckHostAccess(operator.UserID, operator.SuperAdmin, host.ID); err != nil {
+			return nil, fmt.Errorf("主机 %s: %w", host.Name, err)
+		}
+		addHost(host)
+	}
+
+	if !selector.Empty() {
+		hosts, err := s.hostRepo.ListBySelector(selector)
+		if err != nil {
+			return nil, fmt.Errorf("解析主机选择器失败: %v", err)
+		}
+		for _, host := range hosts {
+			if seen[host.ID] || host.Status != models.StatusEnabled {
+				continue
+			}
+			if s.hostService.CheckHostAccess(operator.UserID, operator.SuperAdmin, host.ID) != nil {
+				continue
+			}
+			addHost(host)
+		}
+	}
+
+	if len(relations) == 0 {
+		if !selector.Empty() {
+			return nil, fmt.Errorf("选择器 %s 没有匹配到可用主机", selector)
+		}
+		return nil, fmt.Errorf("至少需要选择一台主机")
+	}
+	return relations, nil
+}
+
+// execute 并发执行任务，并在所有主机完成后汇总任务状态
+func (s *BatchTaskService) execute(ctx context.Context, task *opsModel.BatchTask, operator TaskOperator, hostIDs []uint, selector utils.LabelSelector, runner hostRunner, cleanup func()) {
+	defer func() {
+		s.mu.Lock()
+		if cancel, ok := s.cancels[task.ID]; ok {
+			cancel()
+			delete(s.cancels, task.ID)
+		}
+		s.mu.Unlock()
+		if cleanup != nil {
+			cleanup()
+		}
+	}()
+
+	startedAt := time.Now()
+	task.StartedAt = &startedAt
+
+	// 执行时重新解析目标主机并校验权限，标签或授权变更后的主机集合以此为准
+	relations, err := s.buildRelations(operator, hostIDs, selector)
+	if err == nil {
+		err = s.taskRepo.CreateRelations(task.ID, relations)
+	}
+	if err != nil {
+		finishedAt := time.Now()
+		task.Status = opsModel.TaskFailed
+		task.FinishedAt = &finishedAt
+		task.Remark = err.Error()
+		if err := s.taskRepo.Update(task); err != nil {
+			logger.Error("更新任务状态失败", logger.Uint("task_id", task.ID), logger.Err("error", err))
+		}
+		s.publish(taskDoneMessage(task))
+		if s.taskHub != nil {
+			s.taskHub.CloseTask(task.ID)
+		}
+		return
+	}
+
+	task.Status = opsModel.TaskRunning
+	task.TotalHosts = len(relations)
+	if err := s.taskRepo.Update(task); err != nil {
+		logger.Error("更新任务状态失败", logger.Uint("task_id", task.ID), logger.Err("error", err))
+	}
+
+	var (
+		wg       sync.WaitGroup
+		progress sync.Mutex
+		finished int
+	)
+	sem := make(chan struct{}, taskParallelism)
+
+	for _, relation := range relations {
+		wg.Add(1)
+		go func(relation *opsModel.TaskHostRelation) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+			}
+
+			s.runOnHost(ctx, task, relation, runner)
+
+			progress.Lock()
+			defer progress.Unlock()
+			finished++
+			if relation.Status == opsModel.TaskSuccess {
+				task.SuccessCount++
+			} else {
+				task.FailedCount++
+			}
+			task.Progress = float64(finished) / float64(len(relations)) * 100
+			if err := s.taskRepo.Update(task); err != nil {
+				logger.Error("更新任务进度失败", logger.Uint("task_id", task.ID), logger.Err("error", err))
+			}
+		}(relation)
+	}
+	wg.Wait()
+
+	finishedAt := time.Now()
+	task.FinishedAt = &finishedAt
+	switch {
+	case ctx.Err() != nil:
+		task.Status = opsModel.TaskCanceled
+	case task.FailedCount > 0:
+		task.Status = opsModel.TaskFailed
+	default:
+		task.Status = opsModel.TaskSuccess
+	}
+	if err := s.taskRepo.Update(task); err != nil {
+		logger.Error("更新任务状态失败", logger.Uint("task_id", task.ID), logger.Err("error", err))
+	}
+
+	s.publish(taskDoneMessage(task))
+	if s.taskHub != nil {
+		s.taskHub.CloseTask(task.ID)
+	}
+
+	logger.Info("批量任务执行完成",
+		logger.Uint("task_id", task.ID),
+		logger.String("status", task.Status.String()),
+		logger.Int("success", task.SuccessCount),
+		logger.Int("failed", task.FailedCount),
+	)
+}
+
+// runOnHost 在单台主机上执行 runner 并记录结果
+func (s *BatchTaskService) runOnHost(ctx context.Context, task *opsModel.BatchTask, relation *opsModel.TaskHostRelation, runner hostRunner) {
+	startedAt := time.Now()
+	relation.StartedAt = &startedAt
+
+	output := newHostOutput(s, relation)
+	s.mu.Lock()
+	s.outputs[relation.ID] = output
+	s.mu.Unlock()
+
+	finish := func(status opsModel.TaskStatus, exitCode int, err error) {
+		finishedAt := time.Now()
+		relation.Status = status
+		relation.ExitCode = exitCode
+		relation.Output = truncateOutput(output.flush())
+		if err != nil {
+			relation.Error = err.Error()
+		}
+		if status == opsModel.TaskSuccess {
+			relation.Progress = 100
+		}
+		relation.FinishedAt = &finishedAt
+		relation.Duration = finishedAt.Sub(startedAt).Milliseconds()
+		if err := s.taskRepo.UpdateRelation(relation); err != nil {
+			logger.Error("更新任务主机状态失败", logger.Uint("task_id", task.ID), logger.Uint("host_id", relation.HostID), logger.Err("error", err))
+		}
+
+		// 先落库、移除输出缓冲，再推送结束消息，保证订阅时的快照不会遗漏该主机的结果
+		s.mu.Lock()
+		delete(s.outputs, relation.ID)
+		s.mu.Unlock()
+		s.publish(hostExitMessage(relation))
+	}
+
+	if ctx.Err() != nil {
+		finish(opsModel.TaskCanceled, -1, fmt.Errorf("任务已取消"))
+		return
+	}
+
+	relation.Status = opsModel.TaskRunning
+	if err := s.taskRepo.UpdateRelation(relation); err != nil {
+		logger.Error("更新任务主机状态失败", logger.Uint("task_id", task.ID), logger.Uint("host_id", relation.HostID), logger.Err("error", err))
+	}
+
+	hostCtx, cancel := context.WithTimeout(ctx, time.Duration(task.Timeout)*time.Second)
+	defer cancel()
+
+	sshConfig, err := s.hostService.GetSSHConfig(relation.HostID)
+	if err != nil {
+		finish(opsModel.TaskFailed, -1, err)
+		return
+	}
+	client, err := s.sshPool.Get(hostCtx, sshConfig, relation.HostID)
+	if err != nil {
+		finish(opsModel.TaskFailed, -1, fmt.Errorf("SSH 连接失败: %v", err))
+		return
+	}
+
+	exitCode, err := runner(hostCtx, client, relation, output.writer(streamStdout), output.writer(streamStderr))
+	switch {
+	case ctx.Err() != nil:
+		finish(opsModel.TaskCanceled, exitCode, fmt.Errorf("任务已取消"))
+	case hostCtx.Err() != nil:
+		finish(opsModel.TaskFailed, exitCode, fmt.Errorf("执行超时（%d秒）", task.Timeout))
+	case err != nil:
+		finish(opsModel.TaskFailed, exitCode, err)
+	case exitCode != 0:
+		finish(opsModel.TaskFailed, exitCode, fmt.Errorf("退出码 %d", exitCode))
+	default:
+		finish(opsModel.TaskSuccess, exitCode, nil)
+	}
+}
+
+// reportProgress 更新单台主机的传输进度，按时间间隔节流以减少数据库写入
+func (s *BatchTaskService) reportProgress(relation *opsModel.TaskHostRelation) func(written, total int64) {
+	var last time.Time
+	return func(written, total int64) {
+		if total <= 0 || (time.Since(last) < time.Second && written < total) {
+			return
+		}
+		last = time.Now()
+		relation.Progress = float64(written) / float64(total) * 100
+		if err := s.taskRepo.UpdateRelation(relation); err != nil {
+			logger.Error("更新传输进度失败", logger.Uint("task_id", relation.TaskID), logger.Uint("host_id", relation.HostID), logger.Err("error", err))
+		}
+	}
+}
+
+// CancelTask 取消正在执行的任务
+func (s *BatchTaskService) CancelTask(id uint) error {
+	s.mu.Lock()
+	cancel, ok := s.cancels[id]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("任务未在执行中")
+	}
+	cancel()
+	return nil
+}
+
+// CheckTaskAccess 校验用户是否可以查看任务输出，超级管理员不受限制，其他用户只能查看自己提交的任务
+func (s *BatchTaskService) CheckTaskAccess(id uint, operator TaskOperator) error {
+	task, err := s.taskRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("任务不存在")
+	}
+	if !operator.SuperAdmin && task.CreatedBy != operator.UserID {
+		return ErrTaskAccessDenied
+	}
+	return nil
+}
+
+// GetTask 获取任务详情及每台主机的执行结果
+func (s *BatchTaskService) GetTask(id uint) (*response.BatchTaskDetailResponse, error) {
+	task, err := s.taskRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("任务不存在")
+	}
+	relations, err := s.taskRepo.ListRelations(id)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]response.TaskHostResponse, len(relations))
+	for i, rel := range relations {
+		hosts[i] = toTaskHostResponse(rel)
+	}
+
+	return &response.BatchTaskDetailResponse{
+		BatchTaskResponse: toBatchTaskResponse(task),
+		Command:           task.Command,
+		Hosts:             hosts,
+	}, nil
+}
+
+// ListTasks 任务列表
+func (s *BatchTaskService) ListTasks(req *request.ListBatchTaskRequest) (*response.BatchTaskListResponse, error) {
+	tasks, total, err := s.taskRepo.List(req.Page, req.PageSize, req.Type, req.Status, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.BatchTaskResponse, len(tasks))
+	for i, task := range tasks {
+		items[i] = toBatchTaskResponse(task)
+	}
+
+	return &response.BatchTaskListResponse{
+		Total: total,
+		Items: items,
+	}, nil
+}
+
+func toBatchTaskResponse(task *opsModel.BatchTask) response.BatchTaskResponse {
+	return response.BatchTaskResponse{
+		ID:           task.ID,
+		Name:         task.Name,
+		Type:         task.Type.String(),
+		Status:       task.Status.String(),
+		ScriptType:   task.ScriptType,
+		ScriptID:     task.ScriptID,
+		ScriptVer:    task.ScriptVer,
+		HostSelector: task.HostSelector,
+		SourcePath:   task.SourcePath,
+		TargetPath:   task.TargetPath,
+		FileMode:     task.FileMode,
+		FileOwner:    task.FileOwner,
+		Checksum:     task.Checksum,
+		Timeout:      task.Timeout,
+		SuccessCount: task.SuccessCount,
+		FailedCount:  task.FailedCount,
+		TotalHosts:   task.TotalHosts,
+		Progress:     task.Progress,
+		Remark:       task.Remark,
+		CreatedBy:    task.CreatedBy,
+		CreatedAt:    task.CreatedAt.Format("2006-01-02 15:04:05"),
+		StartedAt:    formatTimePtr(task.StartedAt),
+		FinishedAt:   formatTimePtr(task.FinishedAt),
+	}
+}
+
+func toTaskHostResponse(rel *opsModel.TaskHostRelation) response.TaskHostResponse {
+	return response.TaskHostResponse{
+		HostID:     rel.HostID,
+		HostName:   rel.HostName,
+		HostAddr:   rel.HostAddr,
+		Status:     rel.Status.String(),
+		ExitCode:   rel.ExitCode,
+		Progress:   rel.Progress,
+		Checksum:   rel.Checksum,
+		BackupPath: rel.BackupPath,
+		Output:     rel.Output,
+		Error:      rel.Error,
+		Duration:   rel.Duration,
+		StartedAt:  formatTimePtr(rel.StartedAt),
+		FinishedAt: formatTimePtr(rel.FinishedAt),
+	}
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// truncateOutput 截断过长的输出，保留末尾（通常包含错误信息）
+func truncateOutput(output string) string {
+	if len(output) <= maxTaskOutput {
+		return output
+	}
+	// 截断位置对齐到字符边界，避免切断多字节字符
+	start := len(output) - maxTaskOutput
+	for start < len(output) && !utf8.RuneStart(output[start]) {
+		start++
+	}
+	return "...(输出过长，已截断)\n" + output[start:]
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"my-blog-backend/internal/models"
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/utils"
+	"my-blog-backend/internal/repository"
+)
+
+func TestTruncateOutputKeepsValidUTF8(t *testing.T) {
+	// 每个汉字 3 字节，截断位置落在字符中间
+	output := strings.Repeat("错", maxTaskOutput/3+10) + "\n"
+	got := truncateOutput(output)
+	if !utf8.ValidString(got) {
+		t.Fatal("截断后的输出不是合法的 UTF-8")
+	}
+	if !strings.HasSuffix(got, "错错错\n") || len(got) > maxTaskOutput+len("...(输出过长，已截断)\n") {
+		t.Fatalf("截断结果不正确，长度 %d", len(got))
+	}
+}
+
+// fakeHostRepo 两台带 env 标签的主机，用户 5 只通过主机组 10 获得主机 1 的授权
+type fakeHostRepo struct {
+	repository.HostRepository
+	hosts map[uint]*opsModel.RemoteHost
+}
+
+func newFakeHostRepo() *fakeHostRepo {
+	return &fakeHostRepo{hosts: map[uint]*opsModel.RemoteHost{
+		1: {ID: 1, Name: "web-1", Status: models.StatusEnabled},
+		2: {ID: 2, Name: "db-1", Status: models.StatusEnabled},
+	}}
+}
+
+func (r *fakeHostRepo) GetByID(id uint) (*opsModel.RemoteHost, error) {
+	if host, ok := r.hosts[id]; ok {
+		return host, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (r *fakeHostRepo) ListBySelector(selector utils.LabelSelector) ([]*opsModel.RemoteHost, error) {
+	return []*opsModel.RemoteHost{r.hosts[1], r.hosts[2]}, nil
+}
+
+func (r *fakeHostRepo) ListUserPermissions(userID uint) ([]*opsModel.HostUserPermission, error) {
+	if userID == 5 {
+		return []*opsModel.HostUserPermission{{UserGroupID: 1, HostGroupID: 10}}, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeHostRepo) ListHostGroupIDs(hostID uint) ([]uint, error) {
+	if hostID == 1 {
+		return []uint{10}, nil
+	}
+	return nil, nil
+}
+
+func TestBuildRelationsChecksHostAccess(t *testing.T) {
+	hostRepo := newFakeHostRepo()
+	service := NewBatchTaskService(nil, hostRepo, NewHostService(hostRepo, nil, nil), nil, nil, nil)
+	selector, err := utils.ParseLabelSelector("env=prod")
+	if err != nil {
+		t.Fatalf("解析选择器失败: %v", err)
+	}
+	user := TaskOperator{UserID: 5}
+
+	if _, err := service.buildRelations(user, []uint{2}, utils.LabelSelector{}); !errors.Is(err, ErrHostAccessDenied) {
+		t.Fatalf("显式指定无权访问的主机应返回 ErrHostAccessDenied, 实际 %v", err)
+	}
+
+	relations, err := service.buildRelations(user, nil, selector)
+	if err != nil || len(relations) != 1 || relations[0].HostID != 1 {
+		t.Fatalf("选择器只应匹配有权访问的主机: %v %v", relations, err)
+	}
+
+	if _, err := service.buildRelations(TaskOperator{UserID: 6}, nil, selector); err == nil {
+		t.Fatal("没有任何授权的用户不应匹配到主机")
+	}
+
+	relations, err = service.buildRelations(TaskOperator{UserID: 6, SuperAdmin: true}, []uint{2}, selector)
+	if err != nil || len(relations) != 2 {
+		t.Fatalf("超级管理员不受主机权限限制: %v %v", relations, err)
+	}
+}
+
+func TestSubmitCleansUpOnFailure(t *testing.T) {
+	hostRepo := newFakeHostRepo()
+	service := NewBatchTaskService(nil, hostRepo, NewHostService(hostRepo, nil, nil), nil, nil, nil)
+
+	cleaned := false
+	err := service.submit(&opsModel.BatchTask{}, TaskOperator{UserID: 5}, []uint{2}, nil, func() { cleaned = true })
+	if !errors.Is(err, ErrHostAccessDenied) {
+		t.Fatalf("期望 ErrHostAccessDenied, 实际 %v", err)
+	}
+	if !cleaned {
+		t.Fatal("提交失败时应调用 cleanup")
+	}
+}
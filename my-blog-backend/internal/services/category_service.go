@@ -20,12 +20,14 @@ type CategoryService interface {
 }
 
 type categoryService struct {
-	categoryRepo repository.CategoryRepository
+	categoryRepo    repository.CategoryRepository
+	redirectService RedirectService
 }
 
-func NewCategoryService(categoryRepo repository.CategoryRepository) CategoryService {
+func NewCategoryService(categoryRepo repository.CategoryRepository, redirectService RedirectService) CategoryService {
 	return &categoryService{
-		categoryRepo: categoryRepo,
+		categoryRepo:    categoryRepo,
+		redirectService: redirectService,
 	}
 }
 
@@ -45,7 +47,12 @@ func (s *categoryService) UpdateCategory(id uint, category *models.Category) err
 	category.ID = id
 	category.CreatedAt = existing.CreatedAt
 	category.UpdatedAt = time.Now()
-	return s.categoryRepo.Update(category)
+	if err := s.categoryRepo.Update(category); err != nil {
+		return err
+	}
+	// 修改 slug 后旧地址跳转到新地址
+	recordSlugChange(s.redirectService, models.SlugEntityCategory, uint64(id), existing.Slug, category.Slug)
+	return nil
 }
 
 func (s *categoryService) DeleteCategory(id uint) error {
@@ -56,8 +63,9 @@ func (s *categoryService) GetCategory(id uint) (*models.Category, error) {
 	return s.categoryRepo.GetByID(id)
 }
 
+// GetCategoryBySlug 根据 slug 获取分类，slug 已修改时按旧 slug 找到分类
 func (s *categoryService) GetCategoryBySlug(slug string) (*models.Category, error) {
-	return s.categoryRepo.GetBySlug(slug)
+	return findBySlug(s.redirectService, models.SlugEntityCategory, slug, s.categoryRepo.GetBySlug, s.categoryRepo.GetByID)
 }
 
 func (s *categoryService) ListCategories(page, pageSize int) ([]*models.Category, int64, error) {
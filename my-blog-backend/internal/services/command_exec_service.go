@@ -0,0 +1,118 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/config"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/ssh"
+)
+
+// CommandExecService 非交互命令执行（供 CI 等自动化调用）
+type CommandExecService struct {
+	hostService *HostService
+	sshPool     *ssh.Pool
+	policy      *CommandPolicy
+	cfg         *config.OpsConfig
+}
+
+func NewCommandExecService(hostService *HostService, sshPool *ssh.Pool, policy *CommandPolicy, cfg *config.OpsConfig) *CommandExecService {
+	return &CommandExecService{
+		hostService: hostService,
+		sshPool:     sshPool,
+		policy:      policy,
+		cfg:         cfg,
+	}
+}
+
+// Exec 在新的 ssh 会话中执行命令（不分配 PTY），超时后终止远端进程并返回已收集的输出
+// 调用方需先通过 HostService.CheckHostAccess 校验主机权限
+func (s *CommandExecService) Exec(ctx context.Context, userID, hostID uint, req *request.ExecCommandRequest) (*response.ExecCommandResponse, error) {
+	if err := s.policy.Check(req.Command); err != nil {
+		logger.Warn("拒绝执行命令", logger.Uint("user_id", userID), logger.Uint("host_id", hostID), logger.String("command", req.Command))
+		return nil, err
+	}
+
+	timeout := s.cfg.ExecTimeout
+	if req.Timeout > 0 {
+		timeout = time.Duration(req.Timeout) * time.Second
+	}
+	if timeout > s.cfg.ExecMaxTimeout {
+		timeout = s.cfg.ExecMaxTimeout
+	}
+
+	sshConfig, err := s.hostService.GetSSHConfig(hostID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.sshPool.Get(ctx, sshConfig, hostID)
+	if err != nil {
+		return nil, err
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stdout := &limitedBuffer{limit: s.cfg.ExecMaxOutput}
+	stderr := &limitedBuffer{limit: s.cfg.ExecMaxOutput}
+	start := time.Now()
+	code, err := client.Exec(execCtx, req.Command, stdout, stderr)
+	duration := time.Since(start)
+
+	result := &response.ExecCommandResponse{
+		HostID:          hostID,
+		ExitCode:        code,
+		Stdout:          stdout.String(),
+		Stderr:          stderr.String(),
+		StdoutTruncated: stdout.truncated,
+		StderrTruncated: stderr.truncated,
+		Duration:        duration.Milliseconds(),
+	}
+	// 只有命令自身超时才返回部分结果；调用方断开等其他错误直接返回
+	if err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+			return nil, err
+		}
+		result.TimedOut = true
+	}
+
+	logger.Info("执行命令",
+		logger.Uint("user_id", userID),
+		logger.Uint("host_id", hostID),
+		logger.String("command", req.Command),
+		logger.Int("exit_code", result.ExitCode),
+		logger.Int64("duration_ms", result.Duration),
+	)
+	return result, nil
+}
+
+// limitedBuffer 只保留前 limit 字节，超出部分丢弃并标记截断，保证远端输出能持续读完
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remain := b.limit - int64(b.buf.Len())
+	if remain < int64(len(p)) {
+		b.truncated = true
+		if remain > 0 {
+			b.buf.Write(p[:remain])
+		}
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+// String 截断可能切开多字节字符，去掉无效的 UTF-8 字节
+func (b *limitedBuffer) String() string {
+	return strings.ToValidUTF8(b.buf.String(), "")
+}
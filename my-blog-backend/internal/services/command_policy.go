@@ -0,0 +1,54 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"my-blog-backend/internal/pkg/logger"
+)
+
+// ErrCommandDenied 命令被安全策略拒绝
+var ErrCommandDenied = errors.New("命令被安全策略禁止")
+
+// CommandPolicy 命令安全策略，Web 终端和命令执行接口共用同一份规则
+type CommandPolicy struct {
+	rules []*regexp.Regexp
+}
+
+// NewCommandPolicy 编译禁止命令规则，无效的正则记录日志后跳过
+func NewCommandPolicy(patterns []string) *CommandPolicy {
+	policy := &CommandPolicy{}
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		rule, err := regexp.Compile(pattern)
+		if err != nil {
+			logger.Warn("忽略无效的命令规则", logger.String("pattern", pattern), logger.Err("error", err))
+			continue
+		}
+		policy.rules = append(policy.rules, rule)
+	}
+	return policy
+}
+
+// Check 校验命令是否允许执行，多行命令逐行校验
+func (p *CommandPolicy) Check(cmd string) error {
+	if p == nil {
+		return nil
+	}
+	for _, line := range strings.Split(cmd, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		for _, rule := range p.rules {
+			if rule.MatchString(line) {
+				return fmt.Errorf("%w: %s", ErrCommandDenied, line)
+			}
+		}
+	}
+	return nil
+}
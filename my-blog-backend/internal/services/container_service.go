@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/ssh"
+)
+
+// ErrContainerNotFound 容器不存在或未运行
+var ErrContainerNotFound = errors.New("容器不存在或未运行")
+
+// 查询容器列表的超时时间
+const containerListTimeout = 15 * time.Second
+
+// ContainerService Docker 主机上的容器查询与权限校验
+type ContainerService struct {
+	hostService *HostService
+	sshPool     *ssh.Pool
+}
+
+func NewContainerService(hostService *HostService, sshPool *ssh.Pool) *ContainerService {
+	return &ContainerService{
+		hostService: hostService,
+		sshPool:     sshPool,
+	}
+}
+
+// ListContainers 列出主机上用户有权进入的运行中容器
+// 调用方需先通过 HostService.CheckHostAccess 校验主机权限
+func (s *ContainerService) ListContainers(ctx context.Context, userID uint, superAdmin bool, hostID uint) ([]response.ContainerResponse, error) {
+	containers, err := s.listContainers(ctx, hostID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.ContainerResponse, 0, len(containers))
+	for _, container := range containers {
+		if err := s.hostService.CheckContainerAccess(userID, superAdmin, hostID, container.ID, container.Names); err != nil {
+			if errors.Is(err, ErrContainerAccessDenied) {
+				continue
+			}
+			return nil, err
+		}
+		result = append(result, toContainerResponse(container))
+	}
+	return result, nil
+}
+
+// ResolveContainer 按ID、ID前缀或名称查找运行中的容器并校验权限
+func (s *ContainerService) ResolveContainer(ctx context.Context, userID uint, superAdmin bool, hostID uint, ref string) (*ssh.Container, error) {
+	containers, err := s.listContainers(ctx, hostID)
+	if err != nil {
+		return nil, err
+	}
+	container, ok := ssh.FindContainer(containers, ref)
+	if !ok {
+		return nil, ErrContainerNotFound
+	}
+	if err := s.hostService.CheckContainerAccess(userID, superAdmin, hostID, container.ID, container.Names); err != nil {
+		return nil, err
+	}
+	return container, nil
+}
+
+func (s *ContainerService) listContainers(ctx context.Context, hostID uint) ([]ssh.Container, error) {
+	sshConfig, err := s.hostService.GetSSHConfig(hostID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.sshPool.Get(ctx, sshConfig, hostID)
+	if err != nil {
+		return nil, err
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, containerListTimeout)
+	defer cancel()
+	return client.ListContainers(listCtx)
+}
+
+func toContainerResponse(container ssh.Container) response.ContainerResponse {
+	shortID := container.ID
+	if len(shortID) > 12 {
+		shortID = shortID[:12]
+	}
+	return response.ContainerResponse{
+		ID:        container.ID,
+		ShortID:   shortID,
+		Names:     container.Names,
+		Image:     container.Image,
+		State:     container.State,
+		Status:    container.Status,
+		Ports:     container.Ports,
+		CreatedAt: container.CreatedAt,
+	}
+}
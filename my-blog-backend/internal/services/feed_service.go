@@ -0,0 +1,264 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/config"
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/pkg/feed"
+	"my-blog-backend/internal/repository"
+)
+
+var ErrFeedNotFound = errors.New("订阅源不存在")
+
+// FeedScope 订阅源范围
+type FeedScope string
+
+const (
+	FeedScopeSite     FeedScope = "site"     // 全站
+	FeedScopeCategory FeedScope = "category" // 分类
+	FeedScopeTag      FeedScope = "tag"      // 标签
+	FeedScopeSeries   FeedScope = "series"   // 系列
+)
+
+// FeedRequest 订阅源请求
+type FeedRequest struct {
+	Scope  FeedScope
+	Slug   string // 分类、标签或系列的 slug，全站订阅源为空
+	Format feed.Format
+	Mode   string // full 或 summary，为空时使用配置
+	Path   string // 请求路径，用于生成订阅源自身地址
+}
+
+// FeedOutput 生成的订阅源
+type FeedOutput struct {
+	Body         []byte
+	ContentType  string
+	ETag         string
+	LastModified time.Time // 最近一篇文章的更新时间，没有文章时为零值
+}
+
+// FeedService 订阅源服务
+type FeedService interface {
+	GetFeed(req *FeedRequest) (*FeedOutput, error)
+	HandleArticleEvent(event ArticleEvent) // 文章变更时清空缓存
+}
+
+type feedService struct {
+	articleRepo  repository.ArticleRepository
+	categoryRepo repository.CategoryRepository
+	tagRepo      repository.TagRepository
+	seriesRepo   repository.SeriesRepository
+	markdown     MarkdownService
+	redirects    RedirectService
+	site         *config.SiteConfig
+	config       *config.FeedConfig
+
+	mu    sync.RWMutex
+	cache map[string]*feedCacheEntry
+}
+
+type feedCacheEntry struct {
+	output    *FeedOutput
+	expiresAt time.Time
+}
+
+func NewFeedService(
+	articleRepo repository.ArticleRepository,
+	categoryRepo repository.CategoryRepository,
+	tagRepo repository.TagRepository,
+	seriesRepo repository.SeriesRepository,
+	markdownService MarkdownService,
+	redirectService RedirectService,
+	site *config.SiteConfig,
+	feedConfig *config.FeedConfig,
+) FeedService {
+	return &feedService{
+		articleRepo:  articleRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		seriesRepo:   seriesRepo,
+		markdown:     markdownService,
+		redirects:    redirectService,
+		site:         site,
+		config:       feedConfig,
+		cache:        make(map[string]*feedCacheEntry),
+	}
+}
+
+// GetFeed 获取订阅源，结果按范围、格式和输出方式缓存
+func (s *feedService) GetFeed(req *FeedRequest) (*FeedOutput, error) {
+	if req.Mode != "full" && req.Mode != "summary" {
+		req.Mode = ""
+	}
+	key := fmt.Sprintf("%s:%s:%s:%s", req.Scope, req.Slug, req.Format, req.Mode)
+
+	s.mu.RLock()
+	entry, ok := s.cache[key]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.output, nil
+	}
+
+	output, err := s.build(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[key] = &feedCacheEntry{output: output, expiresAt: time.Now().Add(s.config.CacheTTL)}
+	s.mu.Unlock()
+	return output, nil
+}
+
+// HandleArticleEvent 文章发布、下线、修改或删除后清空全部缓存
+func (s *feedService) HandleArticleEvent(event ArticleEvent) {
+	s.mu.Lock()
+	s.cache = make(map[string]*feedCacheEntry)
+	s.mu.Unlock()
+}
+
+func (s *feedService) build(req *FeedRequest) (*FeedOutput, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = s.config.Mode
+	}
+	channel := &feed.Feed{
+		Title:       s.site.Title,
+		Description: s.site.Description,
+		Link:        s.site.URL + "/",
+		FeedLink:    s.feedLink(req),
+		Language:    s.site.Language,
+		Author:      s.site.Author,
+	}
+	filter := &repository.ArticleFeedFilter{Limit: s.config.Limit}
+
+	switch req.Scope {
+	case FeedScopeSite:
+	case FeedScopeCategory:
+		category, err := findBySlug(s.redirects, models.SlugEntityCategory, req.Slug, s.categoryRepo.GetBySlug, s.categoryRepo.GetByID)
+		if err != nil || category.Status != 1 {
+			return nil, ErrFeedNotFound
+		}
+		filter.CategoryID = uint64(category.ID)
+		channel.Title = s.site.Title + " - " + category.Name
+		channel.Description = category.Description
+		channel.Link = s.site.URL + s.site.CategoryPath + category.Slug
+	case FeedScopeTag:
+		tag, err := findBySlug(s.redirects, models.SlugEntityTag, req.Slug, s.tagRepo.GetBySlug, s.tagRepo.GetByID)
+		if err != nil {
+			return nil, ErrFeedNotFound
+		}
+		filter.TagID = tag.ID
+		channel.Title = s.site.Title + " - " + tag.Name
+		channel.Description = tag.Description
+		channel.Link = s.site.URL + s.site.TagPath + tag.Slug
+	case FeedScopeSeries:
+		series, err := findBySlug(s.redirects, models.SlugEntitySeries, req.Slug, s.seriesRepo.GetBySlug, s.seriesRepo.GetByID)
+		if err != nil || series.Status != 1 {
+			return nil, ErrFeedNotFound
+		}
+		filter.SeriesID = series.ID
+		channel.Title = s.site.Title + " - " + series.Name
+		channel.Description = series.Description
+		channel.Link = s.site.URL + s.site.SeriesPath + series.Slug
+	default:
+		return nil, ErrFeedNotFound
+	}
+
+	articles, err := s.articleRepo.ListLatestPublished(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryNames := make(map[uint64]string)
+	for _, article := range articles {
+		item := s.feedItem(article, mode == "full", categoryNames)
+		if item.Updated.After(channel.Updated) {
+			channel.Updated = item.Updated
+		}
+		channel.Items = append(channel.Items, item)
+	}
+
+	body, err := feed.Render(channel, req.Format)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum(body)
+	return &FeedOutput{
+		Body:         body,
+		ContentType:  req.Format.ContentType(),
+		ETag:         `"` + hex.EncodeToString(sum[:]) + `"`,
+		LastModified: channel.Updated,
+	}, nil
+}
+
+// feedLink 生成订阅源自身地址，只保留影响输出的参数
+func (s *feedService) feedLink(req *FeedRequest) string {
+	query := url.Values{}
+	if req.Scope != FeedScopeSite && req.Format != feed.FormatRSS {
+		query.Set("format", string(req.Format))
+	}
+	if req.Mode != "" {
+		query.Set("mode", req.Mode)
+	}
+	link := response.BuildFullURL(req.Path)
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
+
+// feedItem 将文章转换为订阅源条目，分类名称按ID缓存在 categoryNames 中
+func (s *feedService) feedItem(article *models.Article, full bool, categoryNames map[uint64]string) *feed.Item {
+	link := s.site.URL + s.site.ArticlePath + article.Slug
+	item := &feed.Item{
+		ID:      link,
+		Title:   article.Title,
+		Link:    link,
+		Summary: article.Summary,
+		Author:  s.site.Author,
+		Image:   response.BuildFullURL(article.Cover),
+		Updated: article.UpdatedAt,
+	}
+	if article.PublishedAt != nil {
+		item.Published = *article.PublishedAt
+	} else {
+		item.Published = article.CreatedAt
+	}
+	if item.Updated.Before(item.Published) {
+		item.Updated = item.Published
+	}
+	if full {
+		// 渲染失败时退回原文，不影响整个订阅源的输出
+		if rendered, err := s.markdown.Render(article.Content); err == nil {
+			item.ContentHTML = rendered.HTML
+		} else {
+			item.ContentText = article.Content
+		}
+	}
+
+	if article.CategoryID != 0 {
+		name, ok := categoryNames[article.CategoryID]
+		if !ok {
+			if category, err := s.categoryRepo.GetByID(uint(article.CategoryID)); err == nil {
+				name = category.Name
+			}
+			categoryNames[article.CategoryID] = name
+		}
+		if name != "" {
+			item.Categories = append(item.Categories, name)
+		}
+	}
+	for _, tag := range article.Tags {
+		item.Categories = append(item.Categories, tag.Name)
+	}
+	return item
+}
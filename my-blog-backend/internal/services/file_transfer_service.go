@@ -0,0 +1,456 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/config"
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/repository"
+	"my-blog-backend/internal/ssh"
+)
+
+// transferCleanupInterval 清理过期拉取结果的间隔
+const transferCleanupInterval = time.Hour
+
+// 文件属主格式：user 或 user:group
+var fileOwnerPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*(:[A-Za-z_][A-Za-z0-9_.-]*)?$`)
+
+type FileTransferService struct {
+	taskRepo    repository.BatchTaskRepository
+	taskService *BatchTaskService
+	config      *config.OpsConfig
+}
+
+func NewFileTransferService(taskRepo repository.BatchTaskRepository, taskService *BatchTaskService, cfg *config.OpsConfig) *FileTransferService {
+	return &FileTransferService{
+		taskRepo:    taskRepo,
+		taskService: taskService,
+		config:      cfg,
+	}
+}
+
+// Distribute 将上传的文件并行分发到多台主机，传输后校验 SHA-256，返回任务ID
+func (s *FileTransferService) Distribute(req *request.DistributeFileRequest, file *multipart.FileHeader, operator TaskOperator) (uint, error) {
+	if file.Size > s.config.MaxArtifactSize {
+		return 0, fmt.Errorf("文件大小超过限制（最大 %d 字节）", s.config.MaxArtifactSize)
+	}
+	if !path.IsAbs(req.TargetPath) {
+		return 0, fmt.Errorf("目标路径必须是绝对路径")
+	}
+	mode := os.FileMode(0644)
+	if req.Mode != "" {
+		m, err := strconv.ParseUint(req.Mode, 8, 32)
+		if err != nil || m > 0777 {
+			return 0, fmt.Errorf("无效的文件权限: %s", req.Mode)
+		}
+		mode = os.FileMode(m)
+	}
+	if req.Owner != "" && !fileOwnerPattern.MatchString(req.Owner) {
+		return 0, fmt.Errorf("无效的文件属主: %s", req.Owner)
+	}
+
+	// 目标路径以 / 结尾时视为目录，使用上传文件名
+	fileName := filepath.Base(file.Filename)
+	targetPath := req.TargetPath
+	if strings.HasSuffix(targetPath, "/") {
+		targetPath = path.Join(targetPath, fileName)
+	}
+
+	artifactPath, checksum, err := s.saveArtifact(file)
+	if err != nil {
+		return 0, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = fmt.Sprintf("分发 %s", fileName)
+	}
+	task := &opsModel.BatchTask{
+		Name:         name,
+		Type:         opsModel.FileUploadTask,
+		SourcePath:   artifactPath,
+		TargetPath:   targetPath,
+		FileMode:     fmt.Sprintf("%04o", mode),
+		FileOwner:    req.Owner,
+		Checksum:     checksum,
+		Backup:       req.Backup,
+		HostSelector: req.Selector,
+		Timeout:      req.Timeout,
+		CreatedBy:    operator.UserID,
+	}
+
+	runner := func(ctx context.Context, client *ssh.SSHClient, relation *opsModel.TaskHostRelation, stdout, stderr io.Writer) (int, error) {
+		return s.pushFile(ctx, client, task, mode, relation, stdout, stderr)
+	}
+	// 分发结束后删除暂存的文件，任务记录中的 SourcePath 只用于展示
+	cleanup := func() {
+		if err := os.RemoveAll(filepath.Dir(artifactPath)); err != nil {
+			logger.Warn("删除分发文件失败", logger.String("path", artifactPath), logger.Err("error", err))
+		}
+	}
+	if err := s.taskService.submit(task, operator, req.HostIDs, runner, cleanup); err != nil {
+		return 0, err
+	}
+	return task.ID, nil
+}
+
+// Collect 从多台主机拉取同一路径的文件，完成后可按主机打包下载，返回任务ID
+func (s *FileTransferService) Collect(req *request.CollectFileRequest, operator TaskOperator) (uint, error) {
+	if !path.IsAbs(req.SourcePath) || strings.HasSuffix(req.SourcePath, "/") {
+		return 0, fmt.Errorf("源路径必须是文件的绝对路径")
+	}
+
+	name := req.Name
+	if name == "" {
+		name = fmt.Sprintf("拉取 %s", path.Base(req.SourcePath))
+	}
+	task := &opsModel.BatchTask{
+		Name:         name,
+		Type:         opsModel.FileDownloadTask,
+		SourcePath:   req.SourcePath,
+		Timeout:      req.Timeout,
+		HostSelector: req.Selector,
+		CreatedBy:    operator.UserID,
+	}
+
+	runner := func(ctx context.Context, client *ssh.SSHClient, relation *opsModel.TaskHostRelation, stdout, stderr io.Writer) (int, error) {
+		return s.pullFile(ctx, client, task, relation, stdout)
+	}
+	if err := s.taskService.submit(task, operator, req.HostIDs, runner, nil); err != nil {
+		return 0, err
+	}
+	return task.ID, nil
+}
+
+// WriteArchive 将拉取任务的结果打包为 zip 写入 w，每台主机一个目录；hostID 不为0时只打包该主机
+func (s *FileTransferService) WriteArchive(taskID, hostID uint, operator TaskOperator, w io.Writer) error {
+	if err := s.taskService.CheckTaskAccess(taskID, operator); err != nil {
+		return err
+	}
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		return fmt.Errorf("任务不存在")
+	}
+	if task.Type != opsModel.FileDownloadTask {
+		return fmt.Errorf("该任务不是文件拉取任务")
+	}
+	relations, err := s.taskRepo.ListRelations(taskID)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, rel := range relations {
+		if rel.Status != opsModel.TaskSuccess || (hostID > 0 && rel.HostID != hostID) {
+			continue
+		}
+		localPath := s.collectPath(taskID, rel, task.SourcePath)
+		if _, err := os.Stat(localPath); os.IsNotExist(err) {
+			zw.Close()
+			return fmt.Errorf("拉取结果不存在或已超过保留期限被清理")
+		}
+		if err := addFileToZip(zw, localPath, path.Join(hostDirName(rel), path.Base(task.SourcePath))); err != nil {
+			zw.Close()
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// RunCleanup 定期删除过期的拉取结果，以及服务异常退出时遗留的分发文件，需在独立协程中运行
+func (s *FileTransferService) RunCleanup() {
+	ticker := time.NewTicker(transferCleanupInterval)
+	defer ticker.Stop()
+
+	s.cleanup(time.Now())
+	for now := range ticker.C {
+		s.cleanup(now)
+	}
+}
+
+// cleanup 删除修改时间早于保留期限的任务目录
+// 正常结束的分发任务已删除暂存文件，超过保留期限仍存在的只可能是服务中断时遗留的
+func (s *FileTransferService) cleanup(now time.Time) {
+	deadline := now.Add(-s.config.CollectRetention)
+	for _, name := range []string{"collect", "artifacts"} {
+		root := filepath.Join(s.config.DataDir, name)
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				logger.Warn("读取运维数据目录失败", logger.String("path", root), logger.Err("error", err))
+			}
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || !info.ModTime().Before(deadline) {
+				continue
+			}
+			dir := filepath.Join(root, entry.Name())
+			if err := os.RemoveAll(dir); err != nil {
+				logger.Warn("删除过期文件失败", logger.String("path", dir), logger.Err("error", err))
+				continue
+			}
+			logger.Info("已删除过期文件", logger.String("path", dir))
+		}
+	}
+}
+
+// ArchiveName 拉取任务归档的下载文件名
+func (s *FileTransferService) ArchiveName(taskID, hostID uint) string {
+	if hostID > 0 {
+		return fmt.Sprintf("task_%d_host_%d.zip", taskID, hostID)
+	}
+	return fmt.Sprintf("task_%d.zip", taskID)
+}
+
+// saveArtifact 将上传的文件保存到运维数据目录并计算 SHA-256
+func (s *FileTransferService) saveArtifact(file *multipart.FileHeader) (string, string, error) {
+	dir := filepath.Join(s.config.DataDir, "artifacts", uuid.New().String())
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", "", fmt.Errorf("创建存储目录失败: %v", err)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", "", fmt.Errorf("打开上传文件失败: %v", err)
+	}
+	defer src.Close()
+
+	dstPath := filepath.Join(dir, filepath.Base(file.Filename))
+	dst, err := os.OpenFile(dstPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", "", fmt.Errorf("保存上传文件失败: %v", err)
+	}
+	defer dst.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(dst, hash), src); err != nil {
+		return "", "", fmt.Errorf("保存上传文件失败: %v", err)
+	}
+	return dstPath, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// pushFile 单台主机的分发流程：上传到临时文件 -> 校验 -> 备份原文件 -> 设置权限属主 -> 原子替换
+func (s *FileTransferService) pushFile(ctx context.Context, client *ssh.SSHClient, task *opsModel.BatchTask, mode os.FileMode, relation *opsModel.TaskHostRelation, stdout, stderr io.Writer) (int, error) {
+	sftpClient, err := client.GetSFTP()
+	if err != nil {
+		return -1, err
+	}
+
+	src, err := os.Open(task.SourcePath)
+	if err != nil {
+		return -1, fmt.Errorf("打开分发文件失败: %v", err)
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return -1, err
+	}
+
+	tmpPath := fmt.Sprintf("%s.ops_tmp_%d", task.TargetPath, task.ID)
+	dst, err := sftpClient.Create(tmpPath)
+	if err != nil {
+		return -1, fmt.Errorf("创建远端临时文件失败: %v", err)
+	}
+	written, err := copyWithContext(ctx, dst, src, info.Size(), s.taskService.reportProgress(relation))
+	dst.Close()
+	if err != nil {
+		sftpClient.Remove(tmpPath)
+		return -1, fmt.Errorf("传输失败: %v", err)
+	}
+	fmt.Fprintf(stdout, "已传输 %d 字节到 %s\n", written, tmpPath)
+
+	remoteSum, err := remoteChecksum(ctx, client, tmpPath)
+	if err != nil {
+		sftpClient.Remove(tmpPath)
+		return -1, err
+	}
+	relation.Checksum = remoteSum
+	if remoteSum != task.Checksum {
+		sftpClient.Remove(tmpPath)
+		return -1, fmt.Errorf("SHA-256 校验失败: 期望 %s, 实际 %s", task.Checksum, remoteSum)
+	}
+	fmt.Fprintf(stdout, "SHA-256 校验通过: %s\n", remoteSum)
+
+	if task.Backup {
+		if _, err := sftpClient.Stat(task.TargetPath); err == nil {
+			backupPath := fmt.Sprintf("%s.bak.%s", task.TargetPath, time.Now().Format("20060102150405"))
+			cmd := fmt.Sprintf("cp -p %s %s", shellQuote(task.TargetPath), shellQuote(backupPath))
+			if code, err := client.Exec(ctx, cmd, stdout, stderr); err != nil || code != 0 {
+				sftpClient.Remove(tmpPath)
+				return code, fmt.Errorf("备份原文件失败")
+			}
+			relation.BackupPath = backupPath
+			fmt.Fprintf(stdout, "原文件已备份到 %s\n", backupPath)
+		}
+	}
+
+	if err := sftpClient.Chmod(tmpPath, mode); err != nil {
+		sftpClient.Remove(tmpPath)
+		return -1, fmt.Errorf("设置文件权限失败: %v", err)
+	}
+	if task.FileOwner != "" {
+		cmd := fmt.Sprintf("chown %s %s", shellQuote(task.FileOwner), shellQuote(tmpPath))
+		if code, err := client.Exec(ctx, cmd, stdout, stderr); err != nil || code != 0 {
+			sftpClient.Remove(tmpPath)
+			return code, fmt.Errorf("设置文件属主失败")
+		}
+	}
+
+	if err := sftpClient.PosixRename(tmpPath, task.TargetPath); err != nil {
+		sftpClient.Remove(tmpPath)
+		return -1, fmt.Errorf("替换目标文件失败: %v", err)
+	}
+	fmt.Fprintf(stdout, "已写入 %s (权限 %s)\n", task.TargetPath, task.FileMode)
+
+	return 0, nil
+}
+
+// pullFile 单台主机的拉取流程：下载到本地主机目录，并与远端 SHA-256 比对
+func (s *FileTransferService) pullFile(ctx context.Context, client *ssh.SSHClient, task *opsModel.BatchTask, relation *opsModel.TaskHostRelation, stdout io.Writer) (int, error) {
+	sftpClient, err := client.GetSFTP()
+	if err != nil {
+		return -1, err
+	}
+
+	src, err := sftpClient.Open(task.SourcePath)
+	if err != nil {
+		return -1, fmt.Errorf("打开远端文件失败: %v", err)
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return -1, err
+	}
+	if info.IsDir() {
+		return -1, fmt.Errorf("%s 是目录", task.SourcePath)
+	}
+
+	localPath := s.collectPath(task.ID, relation, task.SourcePath)
+	if err := os.MkdirAll(filepath.Dir(localPath), 0700); err != nil {
+		return -1, fmt.Errorf("创建本地目录失败: %v", err)
+	}
+	dst, err := os.OpenFile(localPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return -1, fmt.Errorf("创建本地文件失败: %v", err)
+	}
+
+	hash := sha256.New()
+	written, err := copyWithContext(ctx, io.MultiWriter(dst, hash), src, info.Size(), s.taskService.reportProgress(relation))
+	dst.Close()
+	if err != nil {
+		os.Remove(localPath)
+		return -1, fmt.Errorf("传输失败: %v", err)
+	}
+
+	localSum := hex.EncodeToString(hash.Sum(nil))
+	relation.Checksum = localSum
+	remoteSum, err := remoteChecksum(ctx, client, task.SourcePath)
+	if err != nil {
+		return -1, err
+	}
+	if remoteSum != localSum {
+		os.Remove(localPath)
+		return -1, fmt.Errorf("SHA-256 校验失败: 远端 %s, 本地 %s（文件可能在传输过程中被修改）", remoteSum, localSum)
+	}
+
+	fmt.Fprintf(stdout, "已拉取 %d 字节, SHA-256: %s\n", written, localSum)
+	return 0, nil
+}
+
+// collectPath 拉取文件在本地的存储路径
+func (s *FileTransferService) collectPath(taskID uint, relation *opsModel.TaskHostRelation, sourcePath string) string {
+	return filepath.Join(s.config.DataDir, "collect", strconv.FormatUint(uint64(taskID), 10), hostDirName(relation), path.Base(sourcePath))
+}
+
+func hostDirName(relation *opsModel.TaskHostRelation) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' {
+			return '_'
+		}
+		return r
+	}, relation.HostName)
+	return fmt.Sprintf("%s_%d", name, relation.HostID)
+}
+
+// remoteChecksum 在远端计算文件的 SHA-256
+func remoteChecksum(ctx context.Context, client *ssh.SSHClient, remotePath string) (string, error) {
+	output, code, err := client.ExecOutput(ctx, "sha256sum "+shellQuote(remotePath))
+	if err != nil || code != 0 {
+		return "", fmt.Errorf("计算远端 SHA-256 失败: %s", strings.TrimSpace(output))
+	}
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("sha256sum 输出为空")
+	}
+	return fields[0], nil
+}
+
+// copyWithContext 带取消和进度回调的复制
+func copyWithContext(ctx context.Context, dst io.Writer, src io.Reader, total int64, progress func(written, total int64)) (int64, error) {
+	buf := make([]byte, 256*1024)
+	var written int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return written, werr
+			}
+			written += int64(n)
+			progress(written, total)
+		}
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
+func addFileToZip(zw *zip.Writer, localPath, name string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("打开归档文件失败: %v", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
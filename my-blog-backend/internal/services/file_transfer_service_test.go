@@ -0,0 +1,44 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"my-blog-backend/internal/config"
+)
+
+func TestFileTransferCleanup(t *testing.T) {
+	dataDir := t.TempDir()
+	now := time.Now()
+	dirs := map[string]time.Time{
+		"collect/1":       now.Add(-8 * 24 * time.Hour),
+		"collect/2":       now.Add(-time.Hour),
+		"artifacts/stale": now.Add(-8 * 24 * time.Hour),
+		"artifacts/fresh": now,
+	}
+	for dir, modTime := range dirs {
+		path := filepath.Join(dataDir, dir)
+		if err := os.MkdirAll(path, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "data"), []byte("x"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	service := NewFileTransferService(nil, nil, &config.OpsConfig{DataDir: dataDir, CollectRetention: 7 * 24 * time.Hour})
+	service.cleanup(now)
+
+	for dir, modTime := range dirs {
+		_, err := os.Stat(filepath.Join(dataDir, dir))
+		expired := now.Sub(modTime) > 7*24*time.Hour
+		if expired != os.IsNotExist(err) {
+			t.Errorf("%s: 过期=%v, Stat 错误=%v", dir, expired, err)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/config"
+	"my-blog-backend/internal/models"
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/pkg/utils"
+	"my-blog-backend/internal/repository"
+	"my-blog-backend/internal/ssh"
+)
+
+const (
+	// 单次密码轮换（修改、验证、必要时回滚）的超时时间
+	passwordRotateTimeout = 2 * time.Minute
+	// 自动轮换失败后的重试间隔
+	passwordRotateRetryDelay = 6 * time.Hour
+
+	rotateMethodChpasswd = "chpasswd"
+	rotateMethodPasswd   = "passwd"
+
+	RotateSourceManual   = "manual"
+	RotateSourceSchedule = "schedule"
+)
+
+var errSecretKeyMissing = errors.New("未配置 ops.secretKey，无法加密保存密码和私钥")
+
+type HostAccountService struct {
+	accountRepo repository.HostAccountRepository
+	hostRepo    repository.HostRepository
+	hostService *HostService
+	sshPool     *ssh.Pool
+	cipher      *utils.SecretCipher
+	cfg         *config.OpsConfig
+	rotating    sync.Map // accountID -> struct{}，防止同一账号并发轮换
+}
+
+// NewHostAccountService cipher 为空时（未配置加密密钥）不能保存或轮换密码
+func NewHostAccountService(accountRepo repository.HostAccountRepository, hostRepo repository.HostRepository, hostService *HostService, sshPool *ssh.Pool, cipher *utils.SecretCipher, cfg *config.OpsConfig) *HostAccountService {
+	return &HostAccountService{
+		accountRepo: accountRepo,
+		hostRepo:    hostRepo,
+		hostService: hostService,
+		sshPool:     sshPool,
+		cipher:      cipher,
+		cfg:         cfg,
+	}
+}
+
+// CreateAccount 创建主机账号，密码加密保存
+func (s *HostAccountService) CreateAccount(req *request.CreateHostAccountRequest, userID uint) error {
+	if _, err := s.hostRepo.GetByID(req.HostID); err != nil {
+		return fmt.Errorf("主机不存在")
+	}
+	password, err := s.encrypt(req.Password)
+	if err != nil {
+		return err
+	}
+
+	account := &opsModel.HostAccount{
+		Name:         req.Name,
+		Username:     req.Username,
+		Password:     password,
+		SecretKey:    req.SecretKey,
+		Type:         accountType(req.Type),
+		HostID:       req.HostID,
+		Status:       models.StatusEnabled,
+		Remark:       req.Remark,
+		RotateDays:   req.RotateDays,
+		NextRotateAt: nextRotateAt(time.Now(), req.RotateDays),
+		CreatedBy:    userID,
+	}
+	return s.accountRepo.Create(account)
+}
+
+// UpdateAccount 更新主机账号，密码和私钥为空时保持不变
+func (s *HostAccountService) UpdateAccount(req *request.UpdateHostAccountRequest) error {
+	account, err := s.accountRepo.GetByID(req.ID)
+	if err != nil {
+		return fmt.Errorf("账号不存在")
+	}
+
+	account.Name = req.Name
+	account.Username = req.Username
+	account.Type = accountType(req.Type)
+	account.Remark = req.Remark
+	if req.Password != "" {
+		if account.Password, err = s.encrypt(req.Password); err != nil {
+			return err
+		}
+	}
+	if req.SecretKey != "" {
+		account.SecretKey = req.SecretKey
+	}
+	if req.Status != "" {
+		account.Status = models.StatusEnabled
+		if req.Status == "inactive" {
+			account.Status = models.StatusDisabled
+		}
+	}
+	if account.RotateDays != req.RotateDays {
+		account.RotateDays = req.RotateDays
+		account.NextRotateAt = nextRotateAt(time.Now(), req.RotateDays)
+	}
+	return s.accountRepo.Update(account)
+}
+
+// DeleteAccount 删除主机账号及其轮换记录
+func (s *HostAccountService) DeleteAccount(id uint) error {
+	if _, err := s.accountRepo.GetByID(id); err != nil {
+		return fmt.Errorf("账号不存在")
+	}
+	return s.accountRepo.Delete(id)
+}
+
+// GetAccount 获取账号详情（不包含密码）
+func (s *HostAccountService) GetAccount(id uint) (*response.HostAccountResponse, error) {
+	account, err := s.accountRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("账号不存在")
+	}
+	return toHostAccountResponse(account), nil
+}
+
+// ListAccounts 账号列表
+func (s *HostAccountService) ListAccounts(req *request.ListHostAccountRequest) (*response.HostAccountListResponse, error) {
+	accounts, total, err := s.accountRepo.List(req.Page, req.PageSize, req.HostID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.HostAccountResponse, len(accounts))
+	for i, account := range accounts {
+		items[i] = *toHostAccountResponse(account)
+	}
+	return &response.HostAccountListResponse{
+		Total: total,
+		Items: items,
+	}, nil
+}
+
+// ListRotations 账号的密码轮换记录
+func (s *HostAccountService) ListRotations(id uint, req *request.ListAccountRotationRequest) (*response.AccountRotationListResponse, error) {
+	rotations, total, err := s.accountRepo.ListRotations(id, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.AccountRotationResponse, len(rotations))
+	for i, rotation := range rotations {
+		items[i] = *toAccountRotationResponse(rotation)
+	}
+	return &response.AccountRotationListResponse{
+		Total: total,
+		Items: items,
+	}, nil
+}
+
+// RotatePassword 轮换账号密码：生成新密码 -> 远端修改 -> 新密码登录验证 -> 加密保存
+// 验证或保存失败时把远端密码改回旧密码，每次轮换都会留下记录
+func (s *HostAccountService) RotatePassword(id uint, userID uint, source string) (*response.AccountRotationResponse, error) {
+	if s.cipher == nil {
+		return nil, errSecretKeyMissing
+	}
+	if _, busy := s.rotating.LoadOrStore(id, struct{}{}); busy {
+		return nil, fmt.Errorf("该账号正在轮换密码")
+	}
+	defer s.rotating.Delete(id)
+
+	account, err := s.accountRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("账号不存在")
+	}
+	if account.Password == "" {
+		return nil, fmt.Errorf("账号未使用密码登录，无需轮换")
+	}
+	oldPassword, err := s.cipher.Decrypt(account.Password)
+	if err != nil {
+		return nil, err
+	}
+	host, err := s.hostRepo.GetByID(account.HostID)
+	if err != nil {
+		return nil, fmt.Errorf("主机不存在")
+	}
+	sshConfig, err := s.hostService.GetSSHConfig(host.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), passwordRotateTimeout)
+	defer cancel()
+
+	client, err := s.sshPool.Get(ctx, sshConfig, host.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 以 root 或其他账号登录时用 chpasswd 修改；轮换的正是非 root 登录账号自身时只能用 passwd
+	method := rotateMethodChpasswd
+	if sshConfig.Username != "root" && sshConfig.Username == account.Username {
+		method = rotateMethodPasswd
+	}
+	change := func(from, to string) error {
+		if method == rotateMethodPasswd {
+			return client.ChangePasswordPTY(ctx, from, to)
+		}
+		return client.ChangePasswordChpasswd(ctx, account.Username, to)
+	}
+
+	rotation := &opsModel.HostAccountRotation{
+		AccountID: account.ID,
+		HostID:    host.ID,
+		Method:    method,
+		Source:    source,
+		CreatedBy: userID,
+		StartedAt: time.Now(),
+	}
+
+	newPassword, err := utils.GeneratePassword(s.cfg.PasswordLength)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(oldPassword, newPassword); err != nil {
+		return s.failRotation(account, nil, rotation, opsModel.RotationFailed, fmt.Sprintf("修改密码失败: %v", err))
+	}
+
+	cause := ""
+	if err := ssh.VerifyPasswordLogin(ctx, sshConfig, account.Username, newPassword); err != nil {
+		cause = fmt.Sprintf("新密码登录验证失败: %v", err)
+	} else if err := s.saveRotation(account, host, newPassword, rotation); err != nil {
+		cause = fmt.Sprintf("保存新密码失败: %v", err)
+	} else {
+		logger.Info("主机账号密码已轮换",
+			logger.Uint("account_id", account.ID),
+			logger.Uint("host_id", host.ID),
+			logger.String("method", method),
+			logger.String("source", source),
+		)
+		return toAccountRotationResponse(rotation), nil
+	}
+
+	// 回滚远端密码；回滚也失败时保存实际生效的新密码，避免密码丢失
+	if err := change(newPassword, oldPassword); err != nil {
+		cause = fmt.Sprintf("%s；回滚旧密码失败: %v", cause, err)
+		var loginHost *opsModel.RemoteHost
+		if encrypted, encErr := s.cipher.Encrypt(newPassword); encErr == nil {
+			loginHost = loginHostUpdate(host, account, encrypted)
+			account.Password = encrypted
+		}
+		account.NextRotateAt = nil
+		return s.failRotation(account, loginHost, rotation, opsModel.RotationBroken, cause)
+	}
+	return s.failRotation(account, nil, rotation, opsModel.RotationRolledBack, cause+"；已恢复旧密码")
+}
+
+// RunRotation 定期检查到期的账号并自动轮换密码，需在独立协程中运行
+func (s *HostAccountService) RunRotation() {
+	ticker := time.NewTicker(keyRotationInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if s.cipher == nil {
+			continue
+		}
+		accounts, err := s.accountRepo.ListDueForRotation(time.Now())
+		if err != nil {
+			logger.Error("查询待轮换账号失败", logger.Err("error", err))
+			continue
+		}
+		for _, account := range accounts {
+			if _, err := s.RotatePassword(account.ID, account.CreatedBy, RotateSourceSchedule); err != nil {
+				logger.Error("自动轮换账号密码失败", logger.Uint("account_id", account.ID), logger.Err("error", err))
+			}
+		}
+	}
+}
+
+// saveRotation 加密保存新密码；主机以该账号密码登录时同步更新主机密码
+// 在副本上修改，保存失败时 account 仍是轮换前的状态
+func (s *HostAccountService) saveRotation(account *opsModel.HostAccount, host *opsModel.RemoteHost, newPassword string, rotation *opsModel.HostAccountRotation) error {
+	encrypted, err := s.cipher.Encrypt(newPassword)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	updated := *account
+	updated.Password = encrypted
+	updated.LastRotatedAt = &now
+	updated.NextRotateAt = nextRotateAt(now, account.RotateDays)
+
+	rotation.Status = opsModel.RotationSuccess
+	rotation.FinishedAt = now
+	return s.accountRepo.SaveRotation(&updated, loginHostUpdate(host, account, encrypted), rotation)
+}
+
+// loginHostUpdate 主机以该账号密码登录时，返回同步主机密码的更新，否则返回 nil
+// encrypted 为加密后的新密码，主机记录不保存明文
+func loginHostUpdate(host *opsModel.RemoteHost, account *opsModel.HostAccount, encrypted string) *opsModel.RemoteHost {
+	if host.Username == account.Username && host.Password != "" {
+		return &opsModel.RemoteHost{ID: host.ID, Password: encrypted}
+	}
+	return nil
+}
+
+// failRotation 记录失败的轮换；自动轮换的账号推迟下次重试时间
+// 远端密码已变更且无法回滚时，通过 loginHost 同步主机登录密码
+func (s *HostAccountService) failRotation(account *opsModel.HostAccount, loginHost *opsModel.RemoteHost, rotation *opsModel.HostAccountRotation, status opsModel.RotationStatus, message string) (*response.AccountRotationResponse, error) {
+	rotation.Status = status
+	rotation.Message = message
+	rotation.FinishedAt = time.Now()
+
+	if status != opsModel.RotationBroken && account.RotateDays > 0 {
+		retryAt := rotation.FinishedAt.Add(passwordRotateRetryDelay)
+		account.NextRotateAt = &retryAt
+	}
+	if err := s.accountRepo.SaveRotation(account, loginHost, rotation); err != nil {
+		logger.Error("保存轮换记录失败", logger.Uint("account_id", account.ID), logger.Err("error", err))
+	}
+	logger.Warn("主机账号密码轮换失败",
+		logger.Uint("account_id", account.ID),
+		logger.String("status", string(status)),
+		logger.String("message", message),
+	)
+	return toAccountRotationResponse(rotation), errors.New(message)
+}
+
+func (s *HostAccountService) encrypt(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	if s.cipher == nil {
+		return "", errSecretKeyMissing
+	}
+	return s.cipher.Encrypt(password)
+}
+
+func accountType(t string) opsModel.AccountType {
+	if t == "root" {
+		return opsModel.RootAccount
+	}
+	return opsModel.NormalAccount
+}
+
+func toHostAccountResponse(account *opsModel.HostAccount) *response.HostAccountResponse {
+	typ := "normal"
+	if account.Type == opsModel.RootAccount {
+		typ = "root"
+	}
+	status := "active"
+	if account.Status != models.StatusEnabled {
+		status = "inactive"
+	}
+	return &response.HostAccountResponse{
+		ID:            account.ID,
+		HostID:        account.HostID,
+		Name:          account.Name,
+		Username:      account.Username,
+		Type:          typ,
+		Status:        status,
+		Remark:        account.Remark,
+		RotateDays:    account.RotateDays,
+		NextRotateAt:  formatTimePtr(account.NextRotateAt),
+		LastRotatedAt: formatTimePtr(account.LastRotatedAt),
+		CreatedBy:     account.CreatedBy,
+		CreatedAt:     account.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:     account.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func toAccountRotationResponse(rotation *opsModel.HostAccountRotation) *response.AccountRotationResponse {
+	return &response.AccountRotationResponse{
+		ID:         rotation.ID,
+		AccountID:  rotation.AccountID,
+		HostID:     rotation.HostID,
+		Method:     rotation.Method,
+		Status:     string(rotation.Status),
+		Message:    rotation.Message,
+		Source:     rotation.Source,
+		CreatedBy:  rotation.CreatedBy,
+		StartedAt:  rotation.StartedAt.Format("2006-01-02 15:04:05"),
+		FinishedAt: rotation.FinishedAt.Format("2006-01-02 15:04:05"),
+	}
+}
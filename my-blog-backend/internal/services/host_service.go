@@ -2,26 +2,40 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"strings"
+	"sync"
 	"time"
 
 	"my-blog-backend/internal/api/v1/dto/request"
 	"my-blog-backend/internal/api/v1/dto/response"
 	"my-blog-backend/internal/models"
 	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/utils"
 	"my-blog-backend/internal/repository"
 	"my-blog-backend/internal/ssh"
 )
 
+const (
+	diagnoseTimeout     = 10 * time.Second // 诊断时每个网络阶段的超时时间
+	diagnoseConcurrency = 10               // 批量诊断的并发主机数
+	maxDiagnoseHosts    = 200              // 一次批量诊断的主机数上限
+)
+
 type HostService struct {
 	hostRepo repository.HostRepository
 	sshPool  *ssh.Pool
+	cipher   *utils.SecretCipher
 }
 
-func NewHostService(hostRepo repository.HostRepository, sshPool *ssh.Pool) *HostService {
+// NewHostService cipher 用于解密主机记录中加密保存的密码和私钥，为空时只能使用明文
+func NewHostService(hostRepo repository.HostRepository, sshPool *ssh.Pool, cipher *utils.SecretCipher) *HostService {
 	return &HostService{
 		hostRepo: hostRepo,
 		sshPool:  sshPool,
+		cipher:   cipher,
 	}
 }
 
@@ -57,7 +71,13 @@ func (s *HostService) CreateHost(req *request.CreateHostRequest) error {
 		Status:    status,
 	}
 
-	return s.hostRepo.Create(host)
+	if err := validateLabels(req.Labels); err != nil {
+		return err
+	}
+	if err := s.hostRepo.Create(host); err != nil {
+		return err
+	}
+	return s.hostRepo.SetLabels(host.ID, req.Labels)
 }
 
 // UpdateHost 更新主机
@@ -85,6 +105,10 @@ func (s *HostService) UpdateHost(req *request.UpdateHostRequest) error {
 	host.Port = int64(req.Port)
 	host.Username = req.Username
 	host.Password = req.Password
+	// 手工修改私钥后不再关联托管密钥
+	if host.SecretKey != req.SecretKey {
+		host.KeyID = 0
+	}
 	host.SecretKey = req.SecretKey
 	host.Type = sshType
 
@@ -97,7 +121,16 @@ func (s *HostService) UpdateHost(req *request.UpdateHostRequest) error {
 		}
 	}
 
-	return s.hostRepo.Update(host)
+	if err := validateLabels(req.Labels); err != nil {
+		return err
+	}
+	if err := s.hostRepo.Update(host); err != nil {
+		return err
+	}
+	if req.Labels == nil {
+		return nil
+	}
+	return s.hostRepo.SetLabels(host.ID, req.Labels)
 }
 
 // DeleteHost 删除主机
@@ -120,19 +153,34 @@ func (s *HostService) GetHost(id uint) (*response.HostResponse, error) {
 		return nil, fmt.Errorf("主机不存在")
 	}
 
-	return s.toHostResponse(host), nil
+	resp := s.toHostResponse(host)
+	labels, err := s.hostRepo.GetLabels([]uint{id})
+	if err != nil {
+		return nil, err
+	}
+	resp.Labels = labels[id]
+	return resp, nil
 }
 
 // ListHosts 主机列表
 func (s *HostService) ListHosts(req *request.ListHostRequest) (*response.HostListResponse, error) {
-	hosts, total, err := s.hostRepo.List(req.Page, req.PageSize, req.Name, req.Address, req.Type, req.Status)
+	selector, err := utils.ParseLabelSelector(req.Selector)
+	if err != nil {
+		return nil, err
+	}
+	hosts, total, err := s.hostRepo.List(req.Page, req.PageSize, req.Name, req.Address, req.Type, req.Status, selector)
 	if err != nil {
 		return nil, err
 	}
 
+	labels, err := s.hostRepo.GetLabels(hostIDs(hosts))
+	if err != nil {
+		return nil, err
+	}
 	items := make([]response.HostResponse, len(hosts))
 	for i, host := range hosts {
 		items[i] = *s.toHostResponse(host)
+		items[i].Labels = labels[host.ID]
 	}
 
 	return &response.HostListResponse{
@@ -148,80 +196,144 @@ func (s *HostService) GetAllHosts() ([]*response.HostResponse, error) {
 		return nil, err
 	}
 
+	labels, err := s.hostRepo.GetLabels(hostIDs(hosts))
+	if err != nil {
+		return nil, err
+	}
 	items := make([]*response.HostResponse, len(hosts))
 	for i, host := range hosts {
 		items[i] = s.toHostResponse(host)
+		items[i].Labels = labels[host.ID]
 	}
 
 	return items, nil
 }
 
-// TestConnection 测试连接
-func (s *HostService) TestConnection(id uint) (*response.TestConnectionResponse, error) {
-	host, err := s.hostRepo.GetByID(id)
+// ListLabels 获取所有已使用的标签键及其取值（用于构建选择器）
+func (s *HostService) ListLabels() ([]response.HostLabelResponse, error) {
+	labels, err := s.hostRepo.ListLabels()
 	if err != nil {
-		return nil, fmt.Errorf("主机不存在")
+		return nil, err
 	}
 
-	// 构建 SSH 配置
-	var authType ssh.AuthType
-	var key []byte
-
-	switch host.Type {
-	case opsModel.Pwd:
-		authType = ssh.AuthTypePassword
-	case opsModel.Key:
-		authType = ssh.AuthTypeKey
-		key = []byte(host.SecretKey)
+	items := make([]response.HostLabelResponse, 0)
+	for _, label := range labels {
+		if n := len(items); n > 0 && items[n-1].Key == label.Key {
+			items[n-1].Values = append(items[n-1].Values, label.Value)
+			continue
+		}
+		items = append(items, response.HostLabelResponse{Key: label.Key, Values: []string{label.Value}})
 	}
+	return items, nil
+}
 
-	cfg := &ssh.Config{
-		Host:     host.Address,
-		Port:     uint(host.Port),
-		Username: host.Username,
-		Password: host.Password,
-		Key:      key,
-		AuthType: authType,
-		Timeout:  10 * time.Second,
+// TestConnection 分阶段诊断主机连接，报告每个阶段的结果，便于定位连接失败的位置
+func (s *HostService) TestConnection(id uint) (*response.TestConnectionResponse, error) {
+	host, err := s.hostRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("主机不存在")
 	}
+	return s.diagnose(context.Background(), host), nil
+}
 
-	// 创建 SSH 客户端测试连接
-	opts := []ssh.Option{
-		ssh.WithHost(cfg.Host),
-		ssh.WithPort(cfg.Port),
-		ssh.WithUsername(cfg.Username),
-		ssh.WithTimeout(cfg.Timeout),
+// DiagnoseHosts 并发诊断多台主机，目标为 host_ids 与选择器匹配主机的并集
+// 显式指定的禁用主机也会诊断，选择器匹配到的禁用主机跳过
+func (s *HostService) DiagnoseHosts(req *request.DiagnoseHostsRequest) ([]*response.TestConnectionResponse, error) {
+	selector, err := utils.ParseLabelSelector(req.Selector)
+	if err != nil {
+		return nil, err
 	}
 
-	switch cfg.AuthType {
-	case ssh.AuthTypePassword:
-		if cfg.Password != "" {
-			opts = append(opts, ssh.WithPassword(cfg.Password))
+	seen := make(map[uint]bool)
+	var hosts []*opsModel.RemoteHost
+	for _, id := range req.HostIDs {
+		if seen[id] {
+			continue
 		}
-	case ssh.AuthTypeKey:
-		if len(cfg.Key) > 0 {
-			opts = append(opts, ssh.WithKey(cfg.Key))
+		host, err := s.hostRepo.GetByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("主机 %d 不存在", id)
 		}
-	case ssh.AuthTypeBoth:
-		if cfg.Password != "" && len(cfg.Key) > 0 {
-			opts = append(opts, ssh.WithBoth(cfg.Password, cfg.Key))
+		seen[id] = true
+		hosts = append(hosts, host)
+	}
+	if !selector.Empty() {
+		matched, err := s.hostRepo.ListBySelector(selector)
+		if err != nil {
+			return nil, fmt.Errorf("解析主机选择器失败: %v", err)
+		}
+		for _, host := range matched {
+			if !seen[host.ID] && host.Status == models.StatusEnabled {
+				seen[host.ID] = true
+				hosts = append(hosts, host)
+			}
 		}
 	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("没有匹配的主机")
+	}
+	if len(hosts) > maxDiagnoseHosts {
+		return nil, fmt.Errorf("一次最多诊断 %d 台主机", maxDiagnoseHosts)
+	}
 
-	client, err := ssh.NewClient(context.Background(), opts...)
+	results := make([]*response.TestConnectionResponse, len(hosts))
+	sem := make(chan struct{}, diagnoseConcurrency)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, host *opsModel.RemoteHost) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = s.diagnose(context.Background(), host)
+		}(i, host)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// diagnose 诊断单台主机并转换为响应
+func (s *HostService) diagnose(ctx context.Context, host *opsModel.RemoteHost) *response.TestConnectionResponse {
+	result := &response.TestConnectionResponse{
+		HostID:   host.ID,
+		HostName: host.Name,
+		Address:  fmt.Sprintf("%s:%d", host.Address, host.Port),
+		Stages:   []response.DiagnoseStageResponse{},
+	}
+	cfg, err := s.hostSSHConfig(host, diagnoseTimeout)
 	if err != nil {
-		return &response.TestConnectionResponse{
-			Success: false,
-			Message: fmt.Sprintf("连接失败: %v", err),
-		}, nil
+		result.Message = fmt.Sprintf("连接失败: %v", err)
+		return result
 	}
 
-	client.Close()
+	report := ssh.Diagnose(ctx, cfg)
+	result.Success = report.Success()
+	result.Duration = report.Duration.Milliseconds()
+	result.Stages = make([]response.DiagnoseStageResponse, 0, len(report.Stages))
+	warnings := 0
+	for _, stage := range report.Stages {
+		if stage.Status == ssh.DiagnoseWarning {
+			warnings++
+		}
+		result.Stages = append(result.Stages, response.DiagnoseStageResponse{
+			Name:     stage.Name,
+			Status:   string(stage.Status),
+			Duration: float64(stage.Duration.Microseconds()) / 1000,
+			Message:  stage.Message,
+			Details:  stage.Details,
+		})
+	}
 
-	return &response.TestConnectionResponse{
-		Success: true,
-		Message: "连接成功",
-	}, nil
+	switch failed := report.FailedStage(); {
+	case failed != nil:
+		result.FailedStage = failed.Name
+		result.Message = fmt.Sprintf("连接失败: %s", failed.Message)
+	case warnings > 0:
+		result.Message = fmt.Sprintf("连接成功，%d 项需要关注", warnings)
+	default:
+		result.Message = "连接成功"
+	}
+	return result
 }
 
 // GetSSHConfig 获取 SSH 配置（用于 WebSocket 连接）
@@ -230,7 +342,11 @@ func (s *HostService) GetSSHConfig(hostID uint) (*ssh.Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("主机不存在")
 	}
+	return s.hostSSHConfig(host, 30*time.Second)
+}
 
+// hostSSHConfig 根据主机记录构建 SSH 配置，解密加密保存的密码和私钥
+func (s *HostService) hostSSHConfig(host *opsModel.RemoteHost, timeout time.Duration) (*ssh.Config, error) {
 	var authType ssh.AuthType
 	var key []byte
 
@@ -239,20 +355,206 @@ func (s *HostService) GetSSHConfig(hostID uint) (*ssh.Config, error) {
 		authType = ssh.AuthTypePassword
 	case opsModel.Key:
 		authType = ssh.AuthTypeKey
-		key = []byte(host.SecretKey)
+		secretKey, err := s.cipher.Decrypt(host.SecretKey)
+		if err != nil {
+			return nil, fmt.Errorf("解密主机私钥失败: %v", err)
+		}
+		key = []byte(secretKey)
+	}
+	password, err := s.cipher.Decrypt(host.Password)
+	if err != nil {
+		return nil, fmt.Errorf("解密主机密码失败: %v", err)
 	}
 
 	return &ssh.Config{
 		Host:     host.Address,
 		Port:     uint(host.Port),
 		Username: host.Username,
-		Password: host.Password,
+		Password: password,
 		Key:      key,
 		AuthType: authType,
-		Timeout:  30 * time.Second,
+		Timeout:  timeout,
 	}, nil
 }
 
+// ErrHostAccessDenied 用户无权访问主机
+var ErrHostAccessDenied = errors.New("无权访问该主机")
+
+// ErrContainerAccessDenied 用户无权进入容器
+var ErrContainerAccessDenied = errors.New("无权进入该容器")
+
+// ErrHostPermissionExists 用户组已有相同的主机授权
+var ErrHostPermissionExists = errors.New("该用户组已有相同的主机授权")
+
+// ListPermissions 获取用户组的主机授权，userGroupID 为 0 时返回全部授权
+func (s *HostService) ListPermissions(userGroupID uint) ([]response.HostPermissionResponse, error) {
+	permissions, err := s.hostRepo.ListPermissions(userGroupID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]response.HostPermissionResponse, len(permissions))
+	for i, permission := range permissions {
+		items[i] = response.HostPermissionResponse{
+			ID:               permission.ID,
+			UserGroupID:      permission.UserGroupID,
+			HostGroupID:      permission.HostGroupID,
+			HostSelector:     permission.HostSelector,
+			ContainerPattern: permission.ContainerPattern,
+			CreatedBy:        permission.CreatedBy,
+			CreatedAt:        permission.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+	return items, nil
+}
+
+// CreatePermission 为用户组授权主机组或匹配标签选择器的主机
+// 选择器保存为规范形式，只有空格或 =/== 写法不同的选择器视为重复授权
+func (s *HostService) CreatePermission(req *request.CreateHostPermissionRequest, createdBy uint) error {
+	permission := &opsModel.HostUserPermission{
+		UserGroupID:      req.UserGroupID,
+		HostGroupID:      req.HostGroupID,
+		ContainerPattern: strings.TrimSpace(req.ContainerPattern),
+		CreatedBy:        createdBy,
+	}
+	if req.HostGroupID == 0 {
+		selector, err := utils.ParseLabelSelector(req.HostSelector)
+		if err != nil {
+			return err
+		}
+		if selector.Empty() {
+			return fmt.Errorf("请选择主机组或填写标签选择器")
+		}
+		permission.HostSelector = selector.String()
+	} else if strings.TrimSpace(req.HostSelector) != "" {
+		return fmt.Errorf("主机组和标签选择器只能选择一个")
+	}
+
+	existing, err := s.hostRepo.ListPermissions(req.UserGroupID)
+	if err != nil {
+		return err
+	}
+	for _, p := range existing {
+		if p.HostGroupID == permission.HostGroupID && p.HostSelector == permission.HostSelector {
+			return ErrHostPermissionExists
+		}
+	}
+	return s.hostRepo.CreatePermission(permission)
+}
+
+// DeletePermission 删除主机授权
+func (s *HostService) DeletePermission(id uint) error {
+	return s.hostRepo.DeletePermission(id)
+}
+
+// CheckHostAccess 校验用户是否有权访问主机（Web 终端和命令执行接口共用）
+// 超级管理员不受限制；其他用户需通过所在用户组的主机组或标签选择器授权
+func (s *HostService) CheckHostAccess(userID uint, superAdmin bool, hostID uint) error {
+	if superAdmin {
+		return nil
+	}
+
+	permissions, err := s.hostPermissions(userID, hostID)
+	if err != nil {
+		return err
+	}
+	if len(permissions) == 0 {
+		return ErrHostAccessDenied
+	}
+	return nil
+}
+
+// CheckContainerAccess 校验用户是否有权进入主机上的容器
+// 授权该主机的任一权限的容器通配符匹配容器名称或ID即可，names 为容器的所有名称
+func (s *HostService) CheckContainerAccess(userID uint, superAdmin bool, hostID uint, containerID string, names []string) error {
+	if superAdmin {
+		return nil
+	}
+
+	permissions, err := s.hostPermissions(userID, hostID)
+	if err != nil {
+		return err
+	}
+	if len(permissions) == 0 {
+		return ErrHostAccessDenied
+	}
+	for _, permission := range permissions {
+		if matchContainer(permission.ContainerPattern, containerID, names) {
+			return nil
+		}
+	}
+	return ErrContainerAccessDenied
+}
+
+// hostPermissions 返回用户对主机生效的授权
+func (s *HostService) hostPermissions(userID, hostID uint) ([]*opsModel.HostUserPermission, error) {
+	permissions, err := s.hostRepo.ListUserPermissions(userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取主机授权失败: %v", err)
+	}
+	if len(permissions) == 0 {
+		return nil, nil
+	}
+
+	groupIDs, err := s.hostRepo.ListHostGroupIDs(hostID)
+	if err != nil {
+		return nil, fmt.Errorf("获取主机授权失败: %v", err)
+	}
+	inGroup := make(map[uint]bool, len(groupIDs))
+	for _, id := range groupIDs {
+		inGroup[id] = true
+	}
+
+	var labels map[string]string
+	var matched []*opsModel.HostUserPermission
+	for _, permission := range permissions {
+		if permission.HostGroupID > 0 {
+			if inGroup[permission.HostGroupID] {
+				matched = append(matched, permission)
+			}
+			continue
+		}
+		selector, err := utils.ParseLabelSelector(permission.HostSelector)
+		if err != nil || selector.Empty() {
+			// 无效或空的选择器不授予任何主机，避免误放行
+			continue
+		}
+		if labels == nil {
+			hostLabels, err := s.hostRepo.GetLabels([]uint{hostID})
+			if err != nil {
+				return nil, fmt.Errorf("获取主机标签失败: %v", err)
+			}
+			labels = hostLabels[hostID]
+			if labels == nil {
+				labels = map[string]string{}
+			}
+		}
+		if selector.Matches(labels) {
+			matched = append(matched, permission)
+		}
+	}
+	return matched, nil
+}
+
+// matchContainer 判断容器是否匹配通配符列表（逗号分隔），按名称、完整ID和短ID匹配
+func matchContainer(patterns, containerID string, names []string) bool {
+	candidates := append([]string{containerID}, names...)
+	if len(containerID) > 12 {
+		candidates = append(candidates, containerID[:12])
+	}
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		for _, candidate := range candidates {
+			if ok, _ := path.Match(pattern, candidate); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // toHostResponse 转换为响应对象
 func (s *HostService) toHostResponse(host *opsModel.RemoteHost) *response.HostResponse {
 	var hostType string
@@ -278,7 +580,25 @@ func (s *HostService) toHostResponse(host *opsModel.RemoteHost) *response.HostRe
 		Username:  host.Username,
 		Type:      hostType,
 		Status:    status,
+		KeyID:     host.KeyID,
 		CreatedAt: host.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt: host.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
+
+func validateLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if err := utils.ValidateLabel(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hostIDs(hosts []*opsModel.RemoteHost) []uint {
+	ids := make([]uint, len(hosts))
+	for i, host := range hosts {
+		ids[i] = host.ID
+	}
+	return ids
+}
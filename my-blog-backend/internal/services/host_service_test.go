@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"my-blog-backend/internal/api/v1/dto/request"
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/utils"
+	"my-blog-backend/internal/repository"
+)
+
+// fakePermissionRepo 只保存主机授权
+type fakePermissionRepo struct {
+	repository.HostRepository
+	permissions []*opsModel.HostUserPermission
+}
+
+func (r *fakePermissionRepo) ListPermissions(userGroupID uint) ([]*opsModel.HostUserPermission, error) {
+	var permissions []*opsModel.HostUserPermission
+	for _, p := range r.permissions {
+		if p.UserGroupID == userGroupID {
+			permissions = append(permissions, p)
+		}
+	}
+	return permissions, nil
+}
+
+func (r *fakePermissionRepo) CreatePermission(permission *opsModel.HostUserPermission) error {
+	r.permissions = append(r.permissions, permission)
+	return nil
+}
+
+func TestCreatePermission(t *testing.T) {
+	repo := &fakePermissionRepo{}
+	service := NewHostService(repo, nil, nil)
+
+	if err := service.CreatePermission(&request.CreateHostPermissionRequest{UserGroupID: 1, HostSelector: "env = prod, role in (db, cache)"}, 9); err != nil {
+		t.Fatalf("按选择器授权失败: %v", err)
+	}
+	if got := repo.permissions[0].HostSelector; got != "env=prod,role in (db,cache)" {
+		t.Fatalf("选择器应保存为规范形式, 实际 %q", got)
+	}
+
+	tests := []struct {
+		name string
+		req  request.CreateHostPermissionRequest
+		want error
+	}{
+		{"写法不同的相同选择器", request.CreateHostPermissionRequest{UserGroupID: 1, HostSelector: "env==prod,role in (db,cache)"}, ErrHostPermissionExists},
+		{"无效的选择器", request.CreateHostPermissionRequest{UserGroupID: 1, HostSelector: "env in prod"}, nil},
+		{"同时指定主机组和选择器", request.CreateHostPermissionRequest{UserGroupID: 1, HostGroupID: 3, HostSelector: "env=prod"}, nil},
+	}
+	for _, tt := range tests {
+		err := service.CreatePermission(&tt.req, 9)
+		if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+			t.Fatalf("%s: 期望错误 %v, 实际 %v", tt.name, tt.want, err)
+		}
+	}
+
+	if err := service.CreatePermission(&request.CreateHostPermissionRequest{UserGroupID: 1, HostGroupID: 3}, 9); err != nil {
+		t.Fatalf("按主机组授权失败: %v", err)
+	}
+	if len(repo.permissions) != 2 || repo.permissions[1].HostSelector != "" {
+		t.Fatalf("授权记录 = %+v", repo.permissions)
+	}
+}
+
+func TestGetSSHConfigDecryptsSecrets(t *testing.T) {
+	cipher, err := utils.NewSecretCipher("test-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	password, _ := cipher.Encrypt("new-password")
+	account := &opsModel.HostAccount{Username: "root"}
+	repo := newFakeHostRepo()
+	repo.hosts[1] = &opsModel.RemoteHost{ID: 1, Username: "root", Password: "old-password", Type: opsModel.Pwd}
+	// 轮换后同步到主机的是密文
+	update := loginHostUpdate(repo.hosts[1], account, password)
+	if update == nil || update.Password != password {
+		t.Fatalf("主机应同步加密后的密码, 实际 %+v", update)
+	}
+	repo.hosts[1].Password = update.Password
+	repo.hosts[2] = &opsModel.RemoteHost{ID: 2, Username: "deploy", Password: "plain", Type: opsModel.Pwd}
+
+	service := NewHostService(repo, nil, cipher)
+	for id, want := range map[uint]string{1: "new-password", 2: "plain"} {
+		cfg, err := service.GetSSHConfig(id)
+		if err != nil || cfg.Password != want {
+			t.Fatalf("主机 %d: 期望密码 %q, 实际 %+v err=%v", id, want, cfg, err)
+		}
+	}
+
+	// 未配置加密密钥时只能使用明文
+	if _, err := NewHostService(repo, nil, nil).GetSSHConfig(1); err == nil {
+		t.Fatal("未配置加密密钥时解密应失败")
+	}
+	if _, err := NewHostService(repo, nil, nil).GetSSHConfig(2); err != nil {
+		t.Fatalf("明文密码不需要加密密钥: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+
+	"my-blog-backend/internal/config"
+	"my-blog-backend/internal/pkg/markdown"
+)
+
+// MarkdownService 文章 Markdown 渲染服务，渲染结果按内容哈希缓存，内容不变时不会重复渲染
+type MarkdownService interface {
+	// Render 渲染 Markdown，返回过滤后的 HTML、目录、字数和阅读时间
+	Render(content string) (*markdown.Result, error)
+	// HighlightCSS 代码高亮样式表
+	HighlightCSS() (string, error)
+}
+
+type markdownService struct {
+	renderer *markdown.Renderer
+	size     int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // 最近使用的在前
+}
+
+type markdownCacheEntry struct {
+	key    string
+	result *markdown.Result
+}
+
+func NewMarkdownService(markdownConfig *config.MarkdownConfig) MarkdownService {
+	return &markdownService{
+		renderer: markdown.New(markdown.Options{
+			HighlightStyle:    markdownConfig.HighlightStyle,
+			WordsPerMinute:    markdownConfig.WordsPerMinute,
+			CJKCharsPerMinute: markdownConfig.CJKCharsPerMinute,
+		}),
+		size:    markdownConfig.CacheSize,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (s *markdownService) Render(content string) (*markdown.Result, error) {
+	sum := sha256.Sum256([]byte(content))
+	key := strconv.Itoa(markdown.Version) + ":" + hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	if element, ok := s.entries[key]; ok {
+		s.order.MoveToFront(element)
+		s.mu.Unlock()
+		return element.Value.(*markdownCacheEntry).result, nil
+	}
+	s.mu.Unlock()
+
+	result, err := s.renderer.Render(content)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[key]; ok {
+		s.order.MoveToFront(element)
+		return element.Value.(*markdownCacheEntry).result, nil
+	}
+	s.entries[key] = s.order.PushFront(&markdownCacheEntry{key: key, result: result})
+	for s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*markdownCacheEntry).key)
+	}
+	return result, nil
+}
+
+func (s *markdownService) HighlightCSS() (string, error) {
+	return s.renderer.HighlightCSS()
+}
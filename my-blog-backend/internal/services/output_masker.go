@@ -0,0 +1,153 @@
+package services
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"my-blog-backend/internal/pkg/logger"
+)
+
+// maxMaskPending 终端输出中暂存的不完整行上限，超过后直接脱敏输出
+const maxMaskPending = 4096
+
+// OutputMasker 命令输出脱敏，终端输出日志、实时输出和批量任务输出共用同一份规则
+// 规则为正则表达式，包含捕获组时只遮盖第一个捕获组（如 password=xxx 只遮盖值），否则遮盖整个匹配
+// 私钥（-----BEGIN ... PRIVATE KEY-----）跨越多行，由 OutputMaskStream 内置处理
+type OutputMasker struct {
+	mu    sync.RWMutex
+	rules []*regexp.Regexp
+}
+
+func NewOutputMasker(patterns []string) *OutputMasker {
+	masker := &OutputMasker{}
+	masker.Reload(patterns)
+	return masker
+}
+
+// Reload 重新加载脱敏规则，无效的正则记录日志后跳过
+func (m *OutputMasker) Reload(patterns []string) {
+	rules := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		rule, err := regexp.Compile(pattern)
+		if err != nil {
+			logger.Warn("忽略无效的脱敏规则", logger.String("pattern", pattern), logger.Err("error", err))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules = rules
+}
+
+// Mask 对单行文本脱敏（替换为等长的 *，不破坏终端排版）
+func (m *OutputMasker) Mask(line string) string {
+	if m == nil {
+		return line
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, rule := range m.rules {
+		line = maskMatches(rule, line)
+	}
+	return line
+}
+
+// NewStream 创建一个输出流的脱敏状态，每个终端会话或任务主机各用一个
+func (m *OutputMasker) NewStream() *OutputMaskStream {
+	return &OutputMaskStream{masker: m}
+}
+
+// OutputMaskStream 按行脱敏连续的输出，记录是否处于私钥块中
+type OutputMaskStream struct {
+	masker  *OutputMasker
+	inKey   bool
+	pending []byte // 终端输出中尚未遇到换行的内容
+}
+
+// Line 脱敏一行输出
+func (s *OutputMaskStream) Line(line string) string {
+	if strings.Contains(line, "-----BEGIN") && strings.Contains(line, "PRIVATE KEY-----") {
+		s.inKey = true
+		return line
+	}
+	if s.inKey {
+		if strings.Contains(line, "-----END") {
+			s.inKey = false
+			return line
+		}
+		return maskNonSpace(line)
+	}
+	return s.masker.Mask(line)
+}
+
+// Mask 脱敏一段终端输出，more 表示读缓冲区已满、后续还有数据，
+// 此时末尾不完整的行先暂存，与后续数据拼成整行再脱敏，避免密钥被拆开后漏过规则
+func (s *OutputMaskStream) Mask(data []byte, more bool) []byte {
+	if len(s.pending) > 0 {
+		data = append(s.pending, data...)
+		s.pending = nil
+	}
+
+	out := make([]byte, 0, len(data))
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		out = append(out, s.Line(string(data[:i+1]))...)
+		data = data[i+1:]
+	}
+	if len(data) > 0 {
+		if more && len(data) < maxMaskPending {
+			s.pending = append([]byte(nil), data...)
+		} else {
+			out = append(out, s.Line(string(data))...)
+		}
+	}
+	return out
+}
+
+// maskMatches 遮盖规则的所有匹配，有捕获组时只遮盖第一个捕获组
+func maskMatches(rule *regexp.Regexp, line string) string {
+	matches := rule.FindAllStringSubmatchIndex(line, -1)
+	if len(matches) == 0 {
+		return line
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		if len(match) >= 4 && match[2] >= 0 {
+			start, end = match[2], match[3]
+		}
+		if start < last {
+			continue
+		}
+		b.WriteString(line[last:start])
+		b.WriteString(strings.Repeat("*", utf8.RuneCountInString(line[start:end])))
+		last = end
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+// maskNonSpace 遮盖除空白外的所有字符（私钥内容行）
+func maskNonSpace(line string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return r
+		}
+		return '*'
+	}, line)
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/config"
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRedirectNotFound    = errors.New("重定向不存在")
+	ErrRedirectExists      = errors.New("该路径已存在重定向")
+	ErrRedirectLoop        = errors.New("目标地址不能与旧路径相同")
+	ErrInvalidRedirectPath = errors.New("旧路径不能为空")
+	ErrInvalidRedirectURL  = errors.New("目标地址必须是以 / 开头的站内路径或 http(s) 地址")
+)
+
+// RedirectService 站点路径重定向服务，访问已失效的旧地址时跳转到新地址
+type RedirectService interface {
+	// Resolve 查找路径的重定向并记录命中，没有重定向时返回 nil
+	Resolve(requestPath string) (*response.RedirectTarget, error)
+	// SaveArticleRedirects 将旧路径重定向到文章地址，旧路径已有重定向时覆盖
+	SaveArticleRedirects(paths []string, slug, source string) error
+	// RecordSlugChange 记录实体修改前的 slug，并将旧 slug 的地址重定向到新地址
+	RecordSlugChange(entityType string, entityID uint64, oldSlug, newSlug string) error
+	// ResolveSlug 根据旧 slug 查找实体ID，没有记录时返回 0
+	ResolveSlug(entityType, slug string) (uint64, error)
+
+	ListRedirects(req *request.RedirectListRequest) ([]*models.Redirect, int64, error)
+	CreateRedirect(req *request.RedirectRequest) (*models.Redirect, error)
+	UpdateRedirect(id uint64, req *request.RedirectRequest) (*models.Redirect, error)
+	DeleteRedirect(id uint64) error
+}
+
+type redirectService struct {
+	redirectRepo    repository.RedirectRepository
+	slugHistoryRepo repository.SlugHistoryRepository
+	site            *config.SiteConfig
+}
+
+func NewRedirectService(redirectRepo repository.RedirectRepository, slugHistoryRepo repository.SlugHistoryRepository, site *config.SiteConfig) RedirectService {
+	return &redirectService{
+		redirectRepo:    redirectRepo,
+		slugHistoryRepo: slugHistoryRepo,
+		site:            site,
+	}
+}
+
+func (s *redirectService) Resolve(requestPath string) (*response.RedirectTarget, error) {
+	fromPath := NormalizeRedirectPath(requestPath)
+	if fromPath == "" {
+		return nil, nil
+	}
+	redirect, err := s.redirectRepo.GetByFromPath(fromPath)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := s.redirectRepo.IncrementHits(redirect.ID); err != nil {
+		logger.Warn("记录重定向命中失败", logger.String("from_path", fromPath), logger.Err("error", err))
+	}
+
+	location := redirect.ToPath
+	if strings.HasPrefix(location, "/") {
+		location = s.site.URL + location
+	}
+	return &response.RedirectTarget{
+		FromPath:   redirect.FromPath,
+		Location:   location,
+		StatusCode: redirect.StatusCode,
+	}, nil
+}
+
+func (s *redirectService) SaveArticleRedirects(paths []string, slug, source string) error {
+	toPath := s.entityPath(models.SlugEntityArticle, slug)
+	for _, p := range paths {
+		fromPath := NormalizeRedirectPath(p)
+		// 旧地址与新地址相同时不需要重定向，否则会循环跳转
+		if fromPath == "" || fromPath == NormalizeRedirectPath(toPath) {
+			continue
+		}
+		if err := s.redirectRepo.Save(&models.Redirect{
+			FromPath:   fromPath,
+			ToPath:     toPath,
+			StatusCode: http.StatusMovedPermanently,
+			Source:     source,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *redirectService) RecordSlugChange(entityType string, entityID uint64, oldSlug, newSlug string) error {
+	if oldSlug == "" || newSlug == "" || oldSlug == newSlug {
+		return nil
+	}
+	oldPath := s.entityPath(entityType, oldSlug)
+	newPath := s.entityPath(entityType, newSlug)
+
+	// 改回以前用过的 slug 时，删除该 slug 的历史记录和重定向，否则新地址会被跳转走
+	if err := s.slugHistoryRepo.Delete(entityType, newSlug); err != nil {
+		return err
+	}
+	if err := s.redirectRepo.DeleteByFromPath(NormalizeRedirectPath(newPath)); err != nil {
+		return err
+	}
+
+	if err := s.slugHistoryRepo.Save(&models.SlugHistory{
+		EntityType: entityType,
+		OldSlug:    oldSlug,
+		EntityID:   entityID,
+	}); err != nil {
+		return err
+	}
+	// 更早的旧地址直接跳转到新地址，不经过 oldPath 多跳一次
+	if err := s.redirectRepo.Retarget(oldPath, newPath); err != nil {
+		return err
+	}
+	return s.redirectRepo.Save(&models.Redirect{
+		FromPath:   NormalizeRedirectPath(oldPath),
+		ToPath:     newPath,
+		StatusCode: http.StatusMovedPermanently,
+		Source:     models.RedirectSourceSlug,
+	})
+}
+
+func (s *redirectService) ResolveSlug(entityType, slug string) (uint64, error) {
+	entityID, err := s.slugHistoryRepo.GetEntityID(entityType, slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return entityID, nil
+}
+
+func (s *redirectService) ListRedirects(req *request.RedirectListRequest) ([]*models.Redirect, int64, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 20
+	}
+	return s.redirectRepo.List(req.Page, req.PageSize, req.Source, strings.TrimSpace(req.Keyword))
+}
+
+func (s *redirectService) CreateRedirect(req *request.RedirectRequest) (*models.Redirect, error) {
+	redirect := &models.Redirect{Source: models.RedirectSourceManual}
+	if err := s.applyRedirectRequest(redirect, req); err != nil {
+		return nil, err
+	}
+	if err := s.redirectRepo.Create(redirect); err != nil {
+		return nil, err
+	}
+	return redirect, nil
+}
+
+// UpdateRedirect 更新重定向，保留来源和命中次数
+func (s *redirectService) UpdateRedirect(id uint64, req *request.RedirectRequest) (*models.Redirect, error) {
+	redirect, err := s.redirectRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRedirectNotFound
+		}
+		return nil, err
+	}
+	if err := s.applyRedirectRequest(redirect, req); err != nil {
+		return nil, err
+	}
+	if err := s.redirectRepo.Update(redirect); err != nil {
+		return nil, err
+	}
+	return redirect, nil
+}
+
+func (s *redirectService) DeleteRedirect(id uint64) error {
+	if _, err := s.redirectRepo.GetByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRedirectNotFound
+		}
+		return err
+	}
+	return s.redirectRepo.Delete(id)
+}
+
+// applyRedirectRequest 校验请求并写入重定向，旧路径不能与其他重定向重复
+func (s *redirectService) applyRedirectRequest(redirect *models.Redirect, req *request.RedirectRequest) error {
+	fromPath := NormalizeRedirectPath(req.FromPath)
+	toPath := strings.TrimSpace(req.ToPath)
+	if fromPath == "" {
+		return ErrInvalidRedirectPath
+	}
+	if strings.HasPrefix(toPath, "/") && !strings.HasPrefix(toPath, "//") {
+		if NormalizeRedirectPath(toPath) == fromPath {
+			return ErrRedirectLoop
+		}
+	} else if parsed, err := url.Parse(toPath); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidRedirectURL
+	}
+
+	existing, err := s.redirectRepo.GetByFromPath(fromPath)
+	if err == nil && existing.ID != redirect.ID {
+		return ErrRedirectExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	redirect.FromPath = fromPath
+	redirect.ToPath = toPath
+	redirect.StatusCode = req.StatusCode
+	if redirect.StatusCode == 0 {
+		redirect.StatusCode = http.StatusMovedPermanently
+	}
+	return nil
+}
+
+// entityPath 实体在前台站点的路径
+func (s *redirectService) entityPath(entityType, slug string) string {
+	switch entityType {
+	case models.SlugEntityCategory:
+		return s.site.CategoryPath + slug
+	case models.SlugEntityTag:
+		return s.site.TagPath + slug
+	case models.SlugEntitySeries:
+		return s.site.SeriesPath + slug
+	default:
+		return s.site.ArticlePath + slug
+	}
+}
+
+// recordSlugChange 实体保存后记录 slug 的修改，失败时只记录日志，不影响保存结果
+func recordSlugChange(redirectService RedirectService, entityType string, entityID uint64, oldSlug, newSlug string) {
+	if err := redirectService.RecordSlugChange(entityType, entityID, oldSlug, newSlug); err != nil {
+		logger.Warn("记录旧 slug 失败",
+			logger.String("entity_type", entityType),
+			logger.Uint("entity_id", uint(entityID)),
+			logger.String("old_slug", oldSlug),
+			logger.Err("error", err),
+		)
+	}
+}
+
+// findBySlug 按 slug 查找实体，找不到时按旧 slug 查找修改后的实体
+func findBySlug[T any](redirectService RedirectService, entityType, slug string, bySlug func(string) (T, error), byID func(uint) (T, error)) (T, error) {
+	entity, err := bySlug(slug)
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity, err
+	}
+	entityID, resolveErr := redirectService.ResolveSlug(entityType, slug)
+	if resolveErr != nil {
+		logger.Warn("查询旧 slug 失败", logger.String("entity_type", entityType), logger.String("slug", slug), logger.Err("error", resolveErr))
+	}
+	if entityID == 0 {
+		return entity, err
+	}
+	return byID(uint(entityID))
+}
+
+// NormalizeRedirectPath 统一路径格式：去掉查询参数和锚点，以 / 开头，合并重复的 /，去掉末尾的 /；
+// 旧地址末尾有无 / 都能匹配到同一条重定向
+func NormalizeRedirectPath(p string) string {
+	p = strings.SplitN(strings.SplitN(strings.TrimSpace(p), "?", 2)[0], "#", 2)[0]
+	if p == "" {
+		return ""
+	}
+	return path.Clean("/" + p)
+}
@@ -0,0 +1,519 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/google/uuid"
+
+	"my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/models"
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/utils"
+	"my-blog-backend/internal/repository"
+	"my-blog-backend/internal/ssh"
+)
+
+// 参数名需为合法的模板标识符
+var scriptParamNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type ScriptService struct {
+	scriptRepo  repository.ScriptRepository
+	taskService *BatchTaskService
+	policy      *CommandPolicy // 渲染后的脚本逐行校验
+}
+
+func NewScriptService(scriptRepo repository.ScriptRepository, taskService *BatchTaskService, policy *CommandPolicy) *ScriptService {
+	return &ScriptService{
+		scriptRepo:  scriptRepo,
+		taskService: taskService,
+		policy:      policy,
+	}
+}
+
+// CreateScript 创建脚本（版本号从1开始）
+func (s *ScriptService) CreateScript(req *request.CreateScriptRequest, userID uint) error {
+	if _, err := s.scriptRepo.GetByName(req.Name); err == nil {
+		return fmt.Errorf("脚本名称已存在")
+	}
+
+	params, err := buildScriptParameters(req.Parameters)
+	if err != nil {
+		return err
+	}
+	if _, err := parseScriptTemplate(req.Content); err != nil {
+		return err
+	}
+
+	script := &opsModel.Script{
+		Name:          req.Name,
+		Language:      opsModel.ScriptLanguage(req.Language),
+		Description:   req.Description,
+		LatestVersion: 1,
+		Owner:         userID,
+		Status:        models.StatusEnabled,
+	}
+	version := &opsModel.ScriptVersion{
+		Content:    req.Content,
+		Parameters: params,
+		ChangeLog:  req.ChangeLog,
+		CreatedBy:  userID,
+	}
+	return s.scriptRepo.Create(script, version)
+}
+
+// UpdateScript 更新脚本，内容或参数有变化时生成新版本，历史版本保持不变
+func (s *ScriptService) UpdateScript(req *request.UpdateScriptRequest, userID uint) error {
+	script, err := s.scriptRepo.GetByID(req.ID)
+	if err != nil {
+		return fmt.Errorf("脚本不存在")
+	}
+
+	params, err := buildScriptParameters(req.Parameters)
+	if err != nil {
+		return err
+	}
+	if _, err := parseScriptTemplate(req.Content); err != nil {
+		return err
+	}
+
+	script.Description = req.Description
+	if req.Status != "" {
+		if req.Status == "inactive" {
+			script.Status = models.StatusDisabled
+		} else {
+			script.Status = models.StatusEnabled
+		}
+	}
+
+	latest, err := s.scriptRepo.GetVersion(script.ID, script.LatestVersion)
+	if err != nil {
+		return fmt.Errorf("获取脚本最新版本失败: %v", err)
+	}
+	if latest.Content == req.Content && latest.Parameters == params {
+		return s.scriptRepo.Update(script)
+	}
+
+	version := &opsModel.ScriptVersion{
+		Content:    req.Content,
+		Parameters: params,
+		ChangeLog:  req.ChangeLog,
+		CreatedBy:  userID,
+	}
+	return s.scriptRepo.AddVersion(script, version)
+}
+
+// DeleteScript 删除脚本及全部版本
+func (s *ScriptService) DeleteScript(id uint) error {
+	if _, err := s.scriptRepo.GetByID(id); err != nil {
+		return fmt.Errorf("脚本不存在")
+	}
+	return s.scriptRepo.Delete(id)
+}
+
+// GetScript 获取脚本指定版本的详情，version 为0时返回最新版本
+func (s *ScriptService) GetScript(id uint, version int) (*response.ScriptDetailResponse, error) {
+	script, v, err := s.getScriptVersion(id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := decodeScriptParameters(v.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	paramResponses := make([]response.ScriptParameterResponse, len(params))
+	for i, p := range params {
+		paramResponses[i] = response.ScriptParameterResponse{
+			Name:        p.Name,
+			Type:        string(p.Type),
+			Default:     p.Default,
+			Required:    p.Required,
+			Options:     p.Options,
+			Raw:         p.Raw,
+			Description: p.Description,
+		}
+	}
+
+	return &response.ScriptDetailResponse{
+		ScriptResponse: toScriptResponse(script),
+		Version:        v.Version,
+		Content:        v.Content,
+		Parameters:     paramResponses,
+		ChangeLog:      v.ChangeLog,
+	}, nil
+}
+
+// ListScripts 脚本列表
+func (s *ScriptService) ListScripts(req *request.ListScriptRequest) (*response.ScriptListResponse, error) {
+	scripts, total, err := s.scriptRepo.List(req.Page, req.PageSize, req.Name, req.Language, req.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.ScriptResponse, len(scripts))
+	for i, script := range scripts {
+		items[i] = toScriptResponse(script)
+	}
+
+	return &response.ScriptListResponse{
+		Total: total,
+		Items: items,
+	}, nil
+}
+
+// ListVersions 获取脚本的版本历史
+func (s *ScriptService) ListVersions(id uint) ([]response.ScriptVersionResponse, error) {
+	if _, err := s.scriptRepo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("脚本不存在")
+	}
+	versions, err := s.scriptRepo.ListVersions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.ScriptVersionResponse, len(versions))
+	for i, v := range versions {
+		items[i] = response.ScriptVersionResponse{
+			Version:   v.Version,
+			ChangeLog: v.ChangeLog,
+			CreatedBy: v.CreatedBy,
+			CreatedAt: v.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+	return items, nil
+}
+
+// DiffVersions 对比脚本两个版本的内容
+func (s *ScriptService) DiffVersions(id uint, from, to int) (*response.ScriptDiffResponse, error) {
+	_, fromVersion, err := s.getScriptVersion(id, from)
+	if err != nil {
+		return nil, err
+	}
+	_, toVersion, err := s.getScriptVersion(id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := utils.DiffLines(fromVersion.Content, toVersion.Content)
+	added, removed := utils.DiffStat(lines)
+
+	return &response.ScriptDiffResponse{
+		ScriptID: id,
+		From:     from,
+		To:       to,
+		Added:    added,
+		Removed:  removed,
+		Lines:    lines,
+		Unified:  utils.UnifiedDiff(fmt.Sprintf("v%d", from), fmt.Sprintf("v%d", to), lines),
+	}, nil
+}
+
+// RunScript 渲染脚本参数并在所选主机上批量执行，返回任务ID
+// 渲染后的脚本需通过命令安全策略，目标主机需有访问权限
+func (s *ScriptService) RunScript(req *request.RunScriptRequest, operator TaskOperator) (uint, error) {
+	script, version, err := s.getScriptVersion(req.ScriptID, req.Version)
+	if err != nil {
+		return 0, err
+	}
+	if script.Status != models.StatusEnabled {
+		return 0, fmt.Errorf("脚本已禁用")
+	}
+
+	interpreter := script.Language.Interpreter()
+	if interpreter == "" {
+		return 0, fmt.Errorf("不支持的脚本语言: %s", script.Language)
+	}
+
+	content, err := renderScript(script.Language, version, req.Params)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.policy.Check(content); err != nil {
+		return 0, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = fmt.Sprintf("%s v%d", script.Name, version.Version)
+	}
+	task := &opsModel.BatchTask{
+		Name:         name,
+		Type:         opsModel.ScriptTask,
+		Command:      content,
+		ScriptType:   string(script.Language),
+		ScriptID:     script.ID,
+		ScriptVer:    version.Version,
+		HostSelector: req.Selector,
+		Timeout:      req.Timeout,
+		CreatedBy:    operator.UserID,
+	}
+
+	runner := func(ctx context.Context, client *ssh.SSHClient, relation *opsModel.TaskHostRelation, stdout, stderr io.Writer) (int, error) {
+		// 上传到临时文件后使用对应解释器执行，执行完毕后删除
+		remotePath := fmt.Sprintf("/tmp/.ops_script_%d_%s%s", task.ID, uuid.New().String()[:8], script.Language.Extension())
+		if _, err := client.WriteFile(remotePath, strings.NewReader(content), 0700); err != nil {
+			return -1, fmt.Errorf("上传脚本失败: %v", err)
+		}
+		defer client.RemoveFile(remotePath)
+
+		return client.Exec(ctx, interpreter+" "+shellQuote(remotePath), stdout, stderr)
+	}
+
+	if err := s.taskService.submit(task, operator, req.HostIDs, runner, nil); err != nil {
+		return 0, err
+	}
+	return task.ID, nil
+}
+
+// getScriptVersion 获取脚本及指定版本，version 为0时取最新版本
+func (s *ScriptService) getScriptVersion(id uint, version int) (*opsModel.Script, *opsModel.ScriptVersion, error) {
+	script, err := s.scriptRepo.GetByID(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("脚本不存在")
+	}
+	if version <= 0 {
+		version = script.LatestVersion
+	}
+	v, err := s.scriptRepo.GetVersion(id, version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("脚本版本 v%d 不存在", version)
+	}
+	return script, v, nil
+}
+
+// buildScriptParameters 校验参数定义并序列化为 JSON
+func buildScriptParameters(reqParams []request.ScriptParameterRequest) (string, error) {
+	params := make([]opsModel.ScriptParameter, 0, len(reqParams))
+	seen := make(map[string]bool, len(reqParams))
+	for _, p := range reqParams {
+		if !scriptParamNamePattern.MatchString(p.Name) {
+			return "", fmt.Errorf("参数名 %q 不合法，只能包含字母、数字和下划线且不能以数字开头", p.Name)
+		}
+		if seen[p.Name] {
+			return "", fmt.Errorf("参数名 %q 重复", p.Name)
+		}
+		seen[p.Name] = true
+
+		param := opsModel.ScriptParameter{
+			Name:        p.Name,
+			Type:        opsModel.ScriptParamType(p.Type),
+			Default:     p.Default,
+			Required:    p.Required,
+			Options:     p.Options,
+			Raw:         p.Raw,
+			Description: p.Description,
+		}
+		if param.Type == opsModel.EnumParam && len(param.Options) == 0 {
+			return "", fmt.Errorf("枚举参数 %q 必须提供可选值", p.Name)
+		}
+		if param.Raw && param.Type != opsModel.IntParam && param.Type != opsModel.EnumParam {
+			return "", fmt.Errorf("参数 %q 不能声明为 raw，仅整数和枚举参数可以原样渲染", p.Name)
+		}
+		if param.Default != "" {
+			if _, err := convertScriptParam(param, param.Default); err != nil {
+				return "", fmt.Errorf("参数 %q 的默认值不合法: %v", p.Name, err)
+			}
+		}
+		params = append(params, param)
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("序列化参数定义失败: %v", err)
+	}
+	return string(data), nil
+}
+
+func decodeScriptParameters(data string) ([]opsModel.ScriptParameter, error) {
+	var params []opsModel.ScriptParameter
+	if data == "" {
+		return params, nil
+	}
+	if err := json.Unmarshal([]byte(data), &params); err != nil {
+		return nil, fmt.Errorf("解析参数定义失败: %v", err)
+	}
+	return params, nil
+}
+
+// convertScriptParam 按参数类型校验并转换参数值
+func convertScriptParam(param opsModel.ScriptParameter, value string) (interface{}, error) {
+	switch param.Type {
+	case opsModel.IntParam:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q 不是整数", value)
+		}
+		return n, nil
+	case opsModel.BoolParam:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q 不是布尔值", value)
+		}
+		return b, nil
+	case opsModel.EnumParam:
+		for _, opt := range param.Options {
+			if opt == value {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("%q 不在可选值 %v 中", value, param.Options)
+	default:
+		return value, nil
+	}
+}
+
+// parseScriptTemplate 解析脚本模板，使用 ${{ }} 作为定界符，避免与脚本自身的 {{ }} 冲突
+func parseScriptTemplate(content string) (*template.Template, error) {
+	tmpl, err := template.New("script").
+		Delims("${{", "}}").
+		Option("missingkey=error").
+		Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("脚本模板语法错误: %v", err)
+	}
+	return tmpl, nil
+}
+
+// renderScript 使用运行参数渲染脚本内容，未传入的参数使用默认值，参数值按脚本语言转义
+func renderScript(language opsModel.ScriptLanguage, version *opsModel.ScriptVersion, values map[string]string) (string, error) {
+	params, err := decodeScriptParameters(version.Parameters)
+	if err != nil {
+		return "", err
+	}
+
+	data := make(map[string]interface{}, len(params))
+	for _, param := range params {
+		value, ok := values[param.Name]
+		if !ok || value == "" {
+			if param.Required && param.Default == "" {
+				return "", fmt.Errorf("缺少必填参数 %q", param.Name)
+			}
+			value = param.Default
+		}
+		converted, err := scriptParamValue(language, param, value)
+		if err != nil {
+			return "", fmt.Errorf("参数 %q 不合法: %v", param.Name, err)
+		}
+		data[param.Name] = converted
+	}
+	for name := range values {
+		if _, ok := data[name]; !ok {
+			return "", fmt.Errorf("未定义的参数 %q", name)
+		}
+	}
+
+	tmpl, err := parseScriptTemplate(version.Content)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("渲染脚本失败: %v", err)
+	}
+	return buf.String(), nil
+}
+
+// scriptParamValue 生成参数在模板中的值
+// 默认转义为脚本语言的字符串字面量；布尔值可用于条件判断，输出时为该语言的布尔字面量；
+// 声明为 raw 的整数和枚举参数原样渲染
+func scriptParamValue(language opsModel.ScriptLanguage, param opsModel.ScriptParameter, value string) (interface{}, error) {
+	if value == "" {
+		switch {
+		case param.Type == opsModel.BoolParam:
+			return scriptBool(language, false), nil
+		case param.Raw:
+			return "", nil
+		default:
+			return quoteScriptValue(language, ""), nil
+		}
+	}
+	converted, err := convertScriptParam(param, value)
+	if err != nil {
+		return nil, err
+	}
+	if param.Type == opsModel.BoolParam {
+		return scriptBool(language, converted.(bool)), nil
+	}
+	if param.Raw {
+		return converted, nil
+	}
+	return quoteScriptValue(language, fmt.Sprint(converted)), nil
+}
+
+// quoteScriptValue 按脚本语言把参数值转义为字符串字面量
+func quoteScriptValue(language opsModel.ScriptLanguage, s string) string {
+	switch language {
+	case opsModel.PythonScript:
+		// JSON 字符串的转义规则是 Python 3 字符串字面量的子集
+		data, _ := json.Marshal(s)
+		return string(data)
+	case opsModel.PerlScript:
+		// Perl 单引号字符串不插值 $ 和 @，只需转义 \ 和 '
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+	default:
+		return shellQuote(s)
+	}
+}
+
+// pythonBool 和 perlBool 在模板条件中与 bool 相同，输出时使用对应语言的布尔字面量
+type pythonBool bool
+
+func (b pythonBool) String() string {
+	if b {
+		return "True"
+	}
+	return "False"
+}
+
+type perlBool bool
+
+func (b perlBool) String() string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// scriptBool 返回布尔参数在模板中的值，shell 脚本输出 true/false
+func scriptBool(language opsModel.ScriptLanguage, b bool) interface{} {
+	switch language {
+	case opsModel.PythonScript:
+		return pythonBool(b)
+	case opsModel.PerlScript:
+		return perlBool(b)
+	default:
+		return b
+	}
+}
+
+// shellQuote 使用单引号转义字符串，渲染 bash/sh 脚本时参数值默认经过此转义
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func toScriptResponse(script *opsModel.Script) response.ScriptResponse {
+	status := "active"
+	if script.Status != models.StatusEnabled {
+		status = "inactive"
+	}
+	return response.ScriptResponse{
+		ID:            script.ID,
+		Name:          script.Name,
+		Language:      string(script.Language),
+		Description:   script.Description,
+		LatestVersion: script.LatestVersion,
+		Owner:         script.Owner,
+		Status:        status,
+		CreatedAt:     script.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:     script.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+
+	"my-blog-backend/internal/api/v1/dto/request"
+	opsModel "my-blog-backend/internal/models/opsModel"
+)
+
+func newTestScriptVersion(t *testing.T, content string, params []opsModel.ScriptParameter) *opsModel.ScriptVersion {
+	t.Helper()
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("序列化参数定义失败: %v", err)
+	}
+	return &opsModel.ScriptVersion{Content: content, Parameters: string(data)}
+}
+
+func TestRenderScriptLiteralBraces(t *testing.T) {
+	content := `echo "{{ .name }}" '{{end}}'` + "\n" + `echo ${{ .name }}`
+	version := newTestScriptVersion(t, content, []opsModel.ScriptParameter{
+		{Name: "name", Type: opsModel.StringParam},
+	})
+	if _, err := parseScriptTemplate(content); err != nil {
+		t.Fatalf("包含 {{ 的脚本解析失败: %v", err)
+	}
+
+	got, err := renderScript(opsModel.BashScript, version, map[string]string{"name": "blog"})
+	if err != nil {
+		t.Fatalf("渲染脚本失败: %v", err)
+	}
+	want := `echo "{{ .name }}" '{{end}}'` + "\n" + `echo 'blog'`
+	if got != want {
+		t.Fatalf("渲染结果不正确:\n%s\n期望:\n%s", got, want)
+	}
+}
+
+func TestRenderScriptQuotesByDefault(t *testing.T) {
+	content := `rm -rf /tmp/${{ .dir }}; mode=${{ .mode }}; n=${{ .count }}${{ if .force }} -f${{ end }}`
+	version := newTestScriptVersion(t, content, []opsModel.ScriptParameter{
+		{Name: "dir", Type: opsModel.StringParam},
+		{Name: "mode", Type: opsModel.EnumParam, Options: []string{"fast", "slow"}, Raw: true},
+		{Name: "count", Type: opsModel.IntParam},
+		{Name: "force", Type: opsModel.BoolParam},
+	})
+
+	got, err := renderScript(opsModel.BashScript, version, map[string]string{
+		"dir":   "x'; reboot; echo '",
+		"mode":  "fast",
+		"count": "3",
+		"force": "true",
+	})
+	if err != nil {
+		t.Fatalf("渲染脚本失败: %v", err)
+	}
+	want := `rm -rf /tmp/'x'\''; reboot; echo '\'''; mode=fast; n='3' -f`
+	if got != want {
+		t.Fatalf("渲染结果不正确:\n%s\n期望:\n%s", got, want)
+	}
+}
+
+func TestRenderScriptQuotesPerLanguage(t *testing.T) {
+	value := `it's "$HOME" @ARGV \n ${x} 中文`
+	tests := []struct {
+		language    opsModel.ScriptLanguage
+		interpreter string
+		flag        string // 从命令行参数读取脚本的选项
+		content     string
+	}{
+		{opsModel.BashScript, "bash", "-c", "printf '%s' ${{ .dir }}${{ if .force }}${{ else }}; exit 1${{ end }}"},
+		{opsModel.PythonScript, "python3", "-c", "import sys\nforce = ${{ .force }}\nsys.stdout.write(${{ .dir }} if force else '')"},
+		{opsModel.PerlScript, "perl", "-e", "my $force = ${{ .force }};\nprint ${{ .dir }} if $force;"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.language), func(t *testing.T) {
+			version := newTestScriptVersion(t, tt.content, []opsModel.ScriptParameter{
+				{Name: "dir", Type: opsModel.StringParam},
+				{Name: "force", Type: opsModel.BoolParam},
+			})
+			script, err := renderScript(tt.language, version, map[string]string{"dir": value, "force": "true"})
+			if err != nil {
+				t.Fatalf("渲染脚本失败: %v", err)
+			}
+			if _, err := exec.LookPath(tt.interpreter); err != nil {
+				t.Skipf("未安装 %s", tt.interpreter)
+			}
+			output, err := exec.Command(tt.interpreter, tt.flag, script).Output()
+			if err != nil {
+				t.Fatalf("执行渲染后的脚本失败: %v\n%s", err, script)
+			}
+			if string(output) != value {
+				t.Fatalf("脚本输出 %q，期望 %q\n%s", output, value, script)
+			}
+		})
+	}
+}
+
+func TestBuildScriptParametersRejectsRawString(t *testing.T) {
+	_, err := buildScriptParameters([]request.ScriptParameterRequest{
+		{Name: "dir", Type: "string", Raw: true},
+	})
+	if err == nil {
+		t.Fatal("字符串参数声明为 raw 时应返回错误")
+	}
+}
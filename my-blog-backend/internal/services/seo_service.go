@@ -0,0 +1,412 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/config"
+	models "my-blog-backend/internal/models/frontendModel"
+	"my-blog-backend/internal/pkg/search"
+	"my-blog-backend/internal/pkg/sitemap"
+	"my-blog-backend/internal/repository"
+)
+
+var ErrSitemapNotFound = errors.New("站点地图不存在")
+
+// seoDescriptionLength 自动生成的页面描述长度（字符）
+const seoDescriptionLength = 160
+
+// sitemapPages 站点地图索引中首页所在的子站点地图名称
+const sitemapPages = "pages"
+
+// sitemapPriority 各类页面在站点地图中的优先级
+var sitemapPriority = map[repository.SitemapKind]float64{
+	repository.SitemapArticles:   0.8,
+	repository.SitemapCategories: 0.6,
+	repository.SitemapTags:       0.4,
+	repository.SitemapSeries:     0.6,
+}
+
+// SitemapOutput 生成的站点地图
+type SitemapOutput struct {
+	Body []byte
+	ETag string
+}
+
+// SEOService 站点地图、robots.txt 和页面元数据服务
+type SEOService interface {
+	Sitemap() (*SitemapOutput, error)                // 内容不超过单个站点地图上限时直接输出，否则输出站点地图索引
+	SitemapPage(name string) (*SitemapOutput, error) // 站点地图索引中的子站点地图，name 形如 articles-1.xml
+	Robots() string
+	ArticleMeta(article *models.Article) *response.ArticleSEO
+	HandleArticleEvent(event ArticleEvent) // 文章变更时清空站点地图缓存
+}
+
+type seoService struct {
+	sitemapRepo  repository.SitemapRepository
+	categoryRepo repository.CategoryRepository
+	tagRepo      repository.TagRepository
+	site         *config.SiteConfig
+	config       *config.SEOConfig
+
+	mu    sync.RWMutex
+	cache map[string]*sitemapCacheEntry
+}
+
+type sitemapCacheEntry struct {
+	output    *SitemapOutput
+	expiresAt time.Time
+}
+
+func NewSEOService(
+	sitemapRepo repository.SitemapRepository,
+	categoryRepo repository.CategoryRepository,
+	tagRepo repository.TagRepository,
+	site *config.SiteConfig,
+	seoConfig *config.SEOConfig,
+) SEOService {
+	return &seoService{
+		sitemapRepo:  sitemapRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		site:         site,
+		config:       seoConfig,
+		cache:        make(map[string]*sitemapCacheEntry),
+	}
+}
+
+func (s *seoService) Sitemap() (*SitemapOutput, error) {
+	return s.cached("sitemap.xml", s.buildSitemap)
+}
+
+func (s *seoService) SitemapPage(name string) (*SitemapOutput, error) {
+	kind, page, err := parseSitemapName(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.cached(name, func() (*SitemapOutput, error) {
+		return s.buildSitemapPage(kind, page)
+	})
+}
+
+// HandleArticleEvent 文章发布、下线、修改或删除后清空站点地图缓存
+func (s *seoService) HandleArticleEvent(event ArticleEvent) {
+	s.mu.Lock()
+	s.cache = make(map[string]*sitemapCacheEntry)
+	s.mu.Unlock()
+}
+
+// Robots 生成 robots.txt，站点地图地址指向本服务
+func (s *seoService) Robots() string {
+	var builder strings.Builder
+	builder.WriteString("User-agent: *\n")
+	if len(s.config.RobotsDisallow) == 0 {
+		builder.WriteString("Disallow:\n")
+	}
+	for _, path := range s.config.RobotsDisallow {
+		builder.WriteString("Disallow: " + path + "\n")
+	}
+	builder.WriteString("\nSitemap: " + response.BuildFullURL("/sitemap.xml") + "\n")
+	return builder.String()
+}
+
+// ArticleMeta 生成文章页面元数据，SEO 字段为空时按标题、摘要、文章地址和封面生成
+func (s *seoService) ArticleMeta(article *models.Article) *response.ArticleSEO {
+	title := article.MetaTitle
+	if title == "" {
+		title = article.Title + " - " + s.site.Title
+	}
+	description := article.MetaDescription
+	if description == "" {
+		description = article.Summary
+	}
+	if description == "" {
+		description = truncateRunes(search.PlainText(article.Content), seoDescriptionLength)
+	}
+	canonical := article.CanonicalURL
+	if canonical == "" {
+		canonical = s.site.URL + s.site.ArticlePath + article.Slug
+	}
+	image := article.OGImage
+	if image == "" {
+		image = article.Cover
+	}
+	if image == "" {
+		image = s.config.DefaultImage
+	}
+	image = response.BuildFullURL(image)
+
+	robots := "index,follow"
+	if article.Status != models.ArticleStatusPublished {
+		robots = "noindex,nofollow"
+	}
+
+	var tagNames []string
+	if tags, err := s.tagRepo.GetByArticleID(uint(article.ID)); err == nil {
+		for _, tag := range tags {
+			tagNames = append(tagNames, tag.Name)
+		}
+	}
+	var section string
+	if article.CategoryID != 0 {
+		if category, err := s.categoryRepo.GetByID(uint(article.CategoryID)); err == nil {
+			section = category.Name
+		}
+	}
+	published := article.CreatedAt
+	if article.PublishedAt != nil {
+		published = *article.PublishedAt
+	}
+
+	meta := []response.SEOMetaTag{
+		{Property: "og:type", Content: "article"},
+		{Property: "og:site_name", Content: s.site.Title},
+		{Property: "og:title", Content: title},
+		{Property: "og:description", Content: description},
+		{Property: "og:url", Content: canonical},
+		{Property: "og:locale", Content: strings.ReplaceAll(s.site.Language, "-", "_")},
+		{Property: "article:published_time", Content: published.Format(time.RFC3339)},
+		{Property: "article:modified_time", Content: article.UpdatedAt.Format(time.RFC3339)},
+	}
+	if len(tagNames) > 0 {
+		meta = append([]response.SEOMetaTag{{Name: "keywords", Content: strings.Join(tagNames, ",")}}, meta...)
+	}
+	if image != "" {
+		meta = append(meta, response.SEOMetaTag{Property: "og:image", Content: image})
+	}
+	if section != "" {
+		meta = append(meta, response.SEOMetaTag{Property: "article:section", Content: section})
+	}
+	for _, name := range tagNames {
+		meta = append(meta, response.SEOMetaTag{Property: "article:tag", Content: name})
+	}
+	card := "summary"
+	if image != "" {
+		card = "summary_large_image"
+	}
+	meta = append(meta,
+		response.SEOMetaTag{Name: "twitter:card", Content: card},
+		response.SEOMetaTag{Name: "twitter:title", Content: title},
+		response.SEOMetaTag{Name: "twitter:description", Content: description},
+	)
+	if image != "" {
+		meta = append(meta, response.SEOMetaTag{Name: "twitter:image", Content: image})
+	}
+	if s.config.TwitterSite != "" {
+		meta = append(meta, response.SEOMetaTag{Name: "twitter:site", Content: s.config.TwitterSite})
+	}
+
+	jsonLD := map[string]interface{}{
+		"@context":         "https://schema.org",
+		"@type":            "BlogPosting",
+		"headline":         article.Title,
+		"description":      description,
+		"mainEntityOfPage": canonical,
+		"datePublished":    published.Format(time.RFC3339),
+		"dateModified":     article.UpdatedAt.Format(time.RFC3339),
+		"publisher":        map[string]interface{}{"@type": "Organization", "name": s.site.Title},
+	}
+	if image != "" {
+		jsonLD["image"] = image
+	}
+	if s.site.Author != "" {
+		jsonLD["author"] = map[string]interface{}{"@type": "Person", "name": s.site.Author}
+	}
+	if section != "" {
+		jsonLD["articleSection"] = section
+	}
+	if len(tagNames) > 0 {
+		jsonLD["keywords"] = strings.Join(tagNames, ",")
+	}
+
+	return &response.ArticleSEO{
+		Title:       title,
+		Description: description,
+		Canonical:   canonical,
+		Robots:      robots,
+		Meta:        meta,
+		JSONLD:      jsonLD,
+	}
+}
+
+// cached 读取缓存的站点地图，过期或不存在时重新生成
+func (s *seoService) cached(key string, build func() (*SitemapOutput, error)) (*SitemapOutput, error) {
+	s.mu.RLock()
+	entry, ok := s.cache[key]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.output, nil
+	}
+
+	output, err := build()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.cache[key] = &sitemapCacheEntry{output: output, expiresAt: time.Now().Add(s.config.CacheTTL)}
+	s.mu.Unlock()
+	return output, nil
+}
+
+func (s *seoService) buildSitemap() (*SitemapOutput, error) {
+	counts := make(map[repository.SitemapKind]int64, len(repository.SitemapKinds))
+	total := int64(1) // 首页
+	for _, kind := range repository.SitemapKinds {
+		count, err := s.sitemapRepo.Count(kind)
+		if err != nil {
+			return nil, err
+		}
+		counts[kind] = count
+		total += count
+	}
+
+	if total <= int64(s.config.SitemapPageSize) {
+		urls := []sitemap.URL{s.homeURL()}
+		for _, kind := range repository.SitemapKinds {
+			kindURLs, err := s.sitemapURLs(kind, 0, int(counts[kind]))
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, kindURLs...)
+		}
+		// 首页随最近更新的内容变化
+		for _, u := range urls[1:] {
+			if u.LastMod.After(urls[0].LastMod) {
+				urls[0].LastMod = u.LastMod
+			}
+		}
+		return newSitemapOutput(sitemap.RenderURLSet(urls))
+	}
+
+	indexes := []sitemap.Index{{Loc: sitemapPageURL(sitemapPages, 1)}}
+	for _, kind := range repository.SitemapKinds {
+		if counts[kind] == 0 {
+			continue
+		}
+		lastMod, err := s.sitemapRepo.LastModified(kind)
+		if err != nil {
+			return nil, err
+		}
+		if lastMod.After(indexes[0].LastMod) {
+			indexes[0].LastMod = lastMod
+		}
+		pages := int((counts[kind] + int64(s.config.SitemapPageSize) - 1) / int64(s.config.SitemapPageSize))
+		for page := 1; page <= pages; page++ {
+			indexes = append(indexes, sitemap.Index{Loc: sitemapPageURL(string(kind), page), LastMod: lastMod})
+		}
+	}
+	return newSitemapOutput(sitemap.RenderIndex(indexes))
+}
+
+func (s *seoService) buildSitemapPage(kind string, page int) (*SitemapOutput, error) {
+	if kind == sitemapPages {
+		if page != 1 {
+			return nil, ErrSitemapNotFound
+		}
+		home := s.homeURL()
+		for _, k := range repository.SitemapKinds {
+			lastMod, err := s.sitemapRepo.LastModified(k)
+			if err != nil {
+				return nil, err
+			}
+			if lastMod.After(home.LastMod) {
+				home.LastMod = lastMod
+			}
+		}
+		return newSitemapOutput(sitemap.RenderURLSet([]sitemap.URL{home}))
+	}
+
+	urls, err := s.sitemapURLs(repository.SitemapKind(kind), (page-1)*s.config.SitemapPageSize, s.config.SitemapPageSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(urls) == 0 {
+		return nil, ErrSitemapNotFound
+	}
+	return newSitemapOutput(sitemap.RenderURLSet(urls))
+}
+
+// sitemapURLs 分页获取一种内容的页面地址
+func (s *seoService) sitemapURLs(kind repository.SitemapKind, offset, limit int) ([]sitemap.URL, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	entries, err := s.sitemapRepo.List(kind, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	path := map[repository.SitemapKind]string{
+		repository.SitemapArticles:   s.site.ArticlePath,
+		repository.SitemapCategories: s.site.CategoryPath,
+		repository.SitemapTags:       s.site.TagPath,
+		repository.SitemapSeries:     s.site.SeriesPath,
+	}[kind]
+
+	urls := make([]sitemap.URL, 0, len(entries))
+	for _, entry := range entries {
+		urls = append(urls, sitemap.URL{
+			Loc:      s.site.URL + path + entry.Slug,
+			LastMod:  entry.UpdatedAt,
+			Priority: sitemapPriority[kind],
+		})
+	}
+	return urls, nil
+}
+
+func (s *seoService) homeURL() sitemap.URL {
+	return sitemap.URL{Loc: s.site.URL + "/", ChangeFreq: "daily", Priority: 1}
+}
+
+// parseSitemapName 解析子站点地图名称，格式为 {类型}-{页码}.xml
+func parseSitemapName(name string) (string, int, error) {
+	base, ok := strings.CutSuffix(name, ".xml")
+	if !ok {
+		return "", 0, ErrSitemapNotFound
+	}
+	i := strings.LastIndexByte(base, '-')
+	if i < 0 {
+		return "", 0, ErrSitemapNotFound
+	}
+	kind := base[:i]
+	page, err := strconv.Atoi(base[i+1:])
+	if err != nil || page < 1 {
+		return "", 0, ErrSitemapNotFound
+	}
+	if kind == sitemapPages {
+		return kind, page, nil
+	}
+	for _, k := range repository.SitemapKinds {
+		if string(k) == kind {
+			return kind, page, nil
+		}
+	}
+	return "", 0, ErrSitemapNotFound
+}
+
+func sitemapPageURL(kind string, page int) string {
+	return response.BuildFullURL(fmt.Sprintf("/sitemap/%s-%d.xml", kind, page))
+}
+
+func newSitemapOutput(body []byte, err error) (*SitemapOutput, error) {
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum(body)
+	return &SitemapOutput{Body: body, ETag: `"` + hex.EncodeToString(sum[:]) + `"`}, nil
+}
+
+// truncateRunes 按字符截断文本，超出时追加省略号
+func truncateRunes(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "…"
+}
@@ -15,9 +15,10 @@ var (
 
 // SeriesService 系列服务
 type SeriesService struct {
-	seriesRepo     repository.SeriesRepository
-	sectionRepo    repository.SeriesSectionRepository
-	subchapterRepo repository.SeriesSubchapterRepository
+	seriesRepo      repository.SeriesRepository
+	sectionRepo     repository.SeriesSectionRepository
+	subchapterRepo  repository.SeriesSubchapterRepository
+	redirectService RedirectService
 }
 
 // NewSeriesService 创建系列服务
@@ -25,11 +26,13 @@ func NewSeriesService(
 	seriesRepo repository.SeriesRepository,
 	sectionRepo repository.SeriesSectionRepository,
 	subchapterRepo repository.SeriesSubchapterRepository,
+	redirectService RedirectService,
 ) *SeriesService {
 	return &SeriesService{
-		seriesRepo:     seriesRepo,
-		sectionRepo:    sectionRepo,
-		subchapterRepo: subchapterRepo,
+		seriesRepo:      seriesRepo,
+		sectionRepo:     sectionRepo,
+		subchapterRepo:  subchapterRepo,
+		redirectService: redirectService,
 	}
 }
 
@@ -38,9 +41,18 @@ func (s *SeriesService) CreateSeries(series *models.Series) error {
 	return s.seriesRepo.Create(series)
 }
 
-// UpdateSeries 更新系列
+// UpdateSeries 更新系列，修改 slug 时旧地址跳转到新地址
 func (s *SeriesService) UpdateSeries(series *models.Series) error {
-	return s.seriesRepo.Update(series)
+	// 调用方通常修改查询到的系列后传入，需要重新查询修改前的 slug
+	previous, err := s.seriesRepo.GetByID(uint(series.ID))
+	if err != nil {
+		return ErrSeriesNotFound
+	}
+	if err := s.seriesRepo.Update(series); err != nil {
+		return err
+	}
+	recordSlugChange(s.redirectService, models.SlugEntitySeries, series.ID, previous.Slug, series.Slug)
+	return nil
 }
 
 // DeleteSeries 删除系列
@@ -58,8 +70,9 @@ func (s *SeriesService) GetSeriesByID(id uint) (*models.Series, error) {
 }
 
 // GetSeriesBySlug 根据slug获取系列
+// slug 已修改时按旧 slug 找到系列
 func (s *SeriesService) GetSeriesBySlug(slug string) (*models.Series, error) {
-	series, err := s.seriesRepo.GetBySlug(slug)
+	series, err := findBySlug(s.redirectService, models.SlugEntitySeries, slug, s.seriesRepo.GetBySlug, s.seriesRepo.GetByID)
 	if err != nil {
 		return nil, ErrSeriesNotFound
 	}
@@ -0,0 +1,419 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/models"
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/pkg/utils"
+	"my-blog-backend/internal/repository"
+	"my-blog-backend/internal/ssh"
+)
+
+const (
+	// 默认 RSA 密钥长度
+	defaultRSABits = 4096
+	// 自动轮换检查间隔
+	keyRotationInterval = time.Hour
+)
+
+type SSHKeyService struct {
+	keyRepo     repository.SSHKeyRepository
+	hostRepo    repository.HostRepository
+	hostService *HostService
+	taskService *BatchTaskService
+	cipher      *utils.SecretCipher
+}
+
+// NewSSHKeyService 私钥使用 cipher 加密保存，cipher 为空时（未配置加密密钥）不能生成密钥
+func NewSSHKeyService(keyRepo repository.SSHKeyRepository, hostRepo repository.HostRepository, hostService *HostService, taskService *BatchTaskService, cipher *utils.SecretCipher) *SSHKeyService {
+	return &SSHKeyService{
+		keyRepo:     keyRepo,
+		hostRepo:    hostRepo,
+		hostService: hostService,
+		taskService: taskService,
+		cipher:      cipher,
+	}
+}
+
+// GenerateKey 在服务端生成密钥对
+func (s *SSHKeyService) GenerateKey(req *request.GenerateSSHKeyRequest, userID uint) (*response.SSHKeyResponse, error) {
+	bits := 0
+	if req.KeyType == ssh.KeyTypeRSA {
+		bits = req.Bits
+		if bits == 0 {
+			bits = defaultRSABits
+		}
+	}
+
+	key, err := s.newKey(req.Name, req.KeyType, bits, req.RotateDays, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toSSHKeyResponse(key, 0), nil
+}
+
+// UpdateKey 修改密钥名称和轮换周期
+func (s *SSHKeyService) UpdateKey(req *request.UpdateSSHKeyRequest) error {
+	key, err := s.keyRepo.GetByID(req.ID)
+	if err != nil {
+		return fmt.Errorf("密钥不存在")
+	}
+
+	key.Name = req.Name
+	if key.RotateDays != req.RotateDays {
+		key.RotateDays = req.RotateDays
+		key.NextRotateAt = nextRotateAt(time.Now(), req.RotateDays)
+	}
+	return s.keyRepo.Update(key)
+}
+
+// DeleteKey 删除密钥，仍有主机使用该密钥登录时不允许删除
+func (s *SSHKeyService) DeleteKey(id uint) error {
+	if _, err := s.keyRepo.GetByID(id); err != nil {
+		return fmt.Errorf("密钥不存在")
+	}
+	hosts, err := s.hostRepo.ListByKeyID(id)
+	if err != nil {
+		return err
+	}
+	if len(hosts) > 0 {
+		return fmt.Errorf("仍有 %d 台主机使用该密钥登录，请先轮换或切换认证方式", len(hosts))
+	}
+	return s.keyRepo.Delete(id)
+}
+
+// GetKey 获取密钥详情（不包含私钥）
+func (s *SSHKeyService) GetKey(id uint) (*response.SSHKeyResponse, error) {
+	key, err := s.keyRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("密钥不存在")
+	}
+	hosts, err := s.hostRepo.ListByKeyID(id)
+	if err != nil {
+		return nil, err
+	}
+	return toSSHKeyResponse(key, len(hosts)), nil
+}
+
+// ListKeys 密钥列表
+func (s *SSHKeyService) ListKeys(req *request.ListSSHKeyRequest) (*response.SSHKeyListResponse, error) {
+	keys, total, err := s.keyRepo.List(req.Page, req.PageSize, req.Name, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.SSHKeyResponse, len(keys))
+	for i, key := range keys {
+		hosts, err := s.hostRepo.ListByKeyID(key.ID)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = *toSSHKeyResponse(key, len(hosts))
+	}
+
+	return &response.SSHKeyListResponse{
+		Total: total,
+		Items: items,
+	}, nil
+}
+
+// DeployKey 通过主机现有的认证方式将公钥写入 authorized_keys，并用新密钥验证登录
+// SwitchAuth 为 true 时验证通过的主机切换为该密钥登录，返回任务ID
+func (s *SSHKeyService) DeployKey(id uint, req *request.DeploySSHKeyRequest, operator TaskOperator) (uint, error) {
+	key, err := s.keyRepo.GetByID(id)
+	if err != nil {
+		return 0, fmt.Errorf("密钥不存在")
+	}
+	if key.Status != opsModel.SSHKeyActive {
+		return 0, fmt.Errorf("密钥已轮换下线，不能再分发")
+	}
+
+	task := &opsModel.BatchTask{
+		Name:         fmt.Sprintf("分发密钥 %s", key.Name),
+		Type:         opsModel.KeyDeployTask,
+		HostSelector: req.Selector,
+		Timeout:      req.Timeout,
+		Remark:       key.Fingerprint,
+		CreatedBy:    operator.UserID,
+	}
+
+	runner := func(ctx context.Context, client *ssh.SSHClient, relation *opsModel.TaskHostRelation, stdout, stderr io.Writer) (int, error) {
+		if err := s.installKey(ctx, client, relation.HostID, key, stdout); err != nil {
+			return -1, err
+		}
+		if !req.SwitchAuth {
+			return 0, nil
+		}
+		if err := s.switchHostKey(relation.HostID, key, req.RemovePassword); err != nil {
+			return -1, err
+		}
+		fmt.Fprintf(stdout, "主机已切换为密钥登录 (%s)\n", key.Fingerprint)
+		return 0, nil
+	}
+
+	if err := s.taskService.submit(task, operator, req.HostIDs, runner, nil); err != nil {
+		return 0, err
+	}
+	return task.ID, nil
+}
+
+// RotateKey 轮换密钥：生成新密钥，对使用旧密钥的每台主机依次执行
+// 添加新公钥 -> 验证新密钥登录 -> 切换主机密钥 -> 用新密钥连接并删除旧公钥
+// 所有主机都切换完成后旧密钥标记为下线；operator 需有权访问使用该密钥的所有主机
+func (s *SSHKeyService) RotateKey(id uint, operator TaskOperator) (*response.RotateSSHKeyResponse, error) {
+	oldKey, err := s.keyRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("密钥不存在")
+	}
+	if oldKey.Status != opsModel.SSHKeyActive {
+		return nil, fmt.Errorf("密钥已轮换下线")
+	}
+
+	// 上次轮换未全部完成时沿用已生成的新密钥，只处理仍在使用旧密钥的主机
+	var newKey *opsModel.SSHKey
+	if oldKey.ReplacedBy > 0 {
+		if replaced, err := s.keyRepo.GetByID(oldKey.ReplacedBy); err == nil && replaced.Status == opsModel.SSHKeyActive {
+			newKey = replaced
+		}
+	}
+	if newKey == nil {
+		if newKey, err = s.newKey(oldKey.Name, oldKey.KeyType, oldKey.Bits, oldKey.RotateDays, operator.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 旧密钥不再参与自动轮换，避免轮换未完成时重复触发
+	oldKey.NextRotateAt = nil
+	oldKey.ReplacedBy = newKey.ID
+	if err := s.keyRepo.Update(oldKey); err != nil {
+		return nil, err
+	}
+
+	hosts, err := s.hostRepo.ListByKeyID(oldKey.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(hosts) == 0 {
+		if err := s.retireIfUnused(oldKey); err != nil {
+			return nil, err
+		}
+		return &response.RotateSSHKeyResponse{NewKeyID: newKey.ID}, nil
+	}
+
+	task := &opsModel.BatchTask{
+		Name:      fmt.Sprintf("轮换密钥 %s", oldKey.Name),
+		Type:      opsModel.KeyDeployTask,
+		Remark:    fmt.Sprintf("%s -> %s", oldKey.Fingerprint, newKey.Fingerprint),
+		CreatedBy: operator.UserID,
+	}
+
+	runner := func(ctx context.Context, client *ssh.SSHClient, relation *opsModel.TaskHostRelation, stdout, stderr io.Writer) (int, error) {
+		if err := s.installKey(ctx, client, relation.HostID, newKey, stdout); err != nil {
+			return -1, err
+		}
+		if err := s.switchHostKey(relation.HostID, newKey, false); err != nil {
+			return -1, err
+		}
+		fmt.Fprintf(stdout, "主机已切换为新密钥 (%s)\n", newKey.Fingerprint)
+
+		// 用新密钥建立连接后再删除旧公钥，确保不会把自己锁在门外
+		cfg, err := s.hostService.GetSSHConfig(relation.HostID)
+		if err != nil {
+			return -1, err
+		}
+		privateKey, err := s.cipher.Decrypt(newKey.PrivateKey)
+		if err != nil {
+			return -1, err
+		}
+		newClient, err := ssh.NewClientWithKey(ctx, cfg, []byte(privateKey))
+		if err != nil {
+			return -1, fmt.Errorf("使用新密钥连接失败: %v", err)
+		}
+		defer newClient.Close()
+		removed, err := newClient.RemoveAuthorizedKey(oldKey.PublicKey)
+		if err != nil {
+			return -1, fmt.Errorf("删除旧公钥失败: %v", err)
+		}
+		fmt.Fprintf(stdout, "已删除旧公钥 %d 条 (%s)\n", removed, oldKey.Fingerprint)
+
+		if err := s.retireIfUnused(oldKey); err != nil {
+			logger.Error("下线旧密钥失败", logger.Uint("key_id", oldKey.ID), logger.Err("error", err))
+		}
+		return 0, nil
+	}
+
+	// 禁用的主机暂不轮换，旧密钥保持可用直到这些主机也完成轮换
+	enabled := make([]*opsModel.RemoteHost, 0, len(hosts))
+	for _, host := range hosts {
+		if host.Status == models.StatusEnabled {
+			enabled = append(enabled, host)
+		}
+	}
+	if len(enabled) == 0 {
+		return nil, fmt.Errorf("使用该密钥的 %d 台主机均已禁用", len(hosts))
+	}
+	if err := s.taskService.submit(task, operator, hostIDs(enabled), runner, nil); err != nil {
+		return nil, err
+	}
+	return &response.RotateSSHKeyResponse{NewKeyID: newKey.ID, TaskID: task.ID}, nil
+}
+
+// RunRotation 定期检查到期的密钥并自动轮换，需在独立协程中运行
+func (s *SSHKeyService) RunRotation() {
+	ticker := time.NewTicker(keyRotationInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		keys, err := s.keyRepo.ListDueForRotation(time.Now())
+		if err != nil {
+			logger.Error("查询待轮换密钥失败", logger.Err("error", err))
+			continue
+		}
+		for _, key := range keys {
+			// 自动轮换由系统执行，不受创建人的主机权限限制
+			result, err := s.RotateKey(key.ID, TaskOperator{UserID: key.CreatedBy, SuperAdmin: true})
+			if err != nil {
+				logger.Error("自动轮换密钥失败", logger.Uint("key_id", key.ID), logger.Err("error", err))
+				continue
+			}
+			logger.Info("自动轮换密钥",
+				logger.Uint("key_id", key.ID),
+				logger.Uint("new_key_id", result.NewKeyID),
+				logger.Uint("task_id", result.TaskID),
+			)
+		}
+	}
+}
+
+// newKey 生成并保存新的密钥对
+func (s *SSHKeyService) newKey(name, keyType string, bits, rotateDays int, userID uint) (*opsModel.SSHKey, error) {
+	if s.cipher == nil {
+		return nil, errSecretKeyMissing
+	}
+	now := time.Now()
+	pair, err := ssh.GenerateKeyPair(keyType, bits, fmt.Sprintf("ops-%s-%s", name, now.Format("20060102")))
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := s.cipher.Encrypt(string(pair.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+
+	key := &opsModel.SSHKey{
+		Name:         name,
+		KeyType:      keyType,
+		Bits:         bits,
+		PublicKey:    pair.PublicKey,
+		PrivateKey:   privateKey,
+		Fingerprint:  pair.Fingerprint,
+		Status:       opsModel.SSHKeyActive,
+		RotateDays:   rotateDays,
+		NextRotateAt: nextRotateAt(now, rotateDays),
+		CreatedBy:    userID,
+	}
+	if err := s.keyRepo.Create(key); err != nil {
+		return nil, fmt.Errorf("保存密钥失败: %v", err)
+	}
+	return key, nil
+}
+
+// installKey 写入公钥并使用该密钥重新登录验证
+func (s *SSHKeyService) installKey(ctx context.Context, client *ssh.SSHClient, hostID uint, key *opsModel.SSHKey, stdout io.Writer) error {
+	added, err := client.AddAuthorizedKey(key.PublicKey)
+	if err != nil {
+		return fmt.Errorf("写入公钥失败: %v", err)
+	}
+	if added {
+		fmt.Fprintf(stdout, "公钥已写入 authorized_keys (%s)\n", key.Fingerprint)
+	} else {
+		fmt.Fprintf(stdout, "公钥已存在于 authorized_keys (%s)\n", key.Fingerprint)
+	}
+
+	cfg, err := s.hostService.GetSSHConfig(hostID)
+	if err != nil {
+		return err
+	}
+	privateKey, err := s.cipher.Decrypt(key.PrivateKey)
+	if err != nil {
+		return err
+	}
+	if err := ssh.VerifyKeyLogin(ctx, cfg, []byte(privateKey)); err != nil {
+		return fmt.Errorf("密钥登录验证失败: %v", err)
+	}
+	fmt.Fprintln(stdout, "密钥登录验证通过")
+	return nil
+}
+
+// switchHostKey 将主机切换为使用托管密钥登录
+func (s *SSHKeyService) switchHostKey(hostID uint, key *opsModel.SSHKey, removePassword bool) error {
+	host, err := s.hostRepo.GetByID(hostID)
+	if err != nil {
+		return fmt.Errorf("主机不存在")
+	}
+	host.Type = opsModel.Key
+	// 复制加密后的私钥，主机记录同样不保存明文
+	host.SecretKey = key.PrivateKey
+	host.KeyID = key.ID
+	if removePassword {
+		host.Password = ""
+	}
+	if err := s.hostRepo.Update(host); err != nil {
+		return fmt.Errorf("更新主机认证信息失败: %v", err)
+	}
+	return nil
+}
+
+// retireIfUnused 没有主机再使用旧密钥时将其标记为下线
+func (s *SSHKeyService) retireIfUnused(key *opsModel.SSHKey) error {
+	hosts, err := s.hostRepo.ListByKeyID(key.ID)
+	if err != nil {
+		return err
+	}
+	if len(hosts) > 0 {
+		return nil
+	}
+	current, err := s.keyRepo.GetByID(key.ID)
+	if err != nil {
+		return err
+	}
+	if current.Status == opsModel.SSHKeyRetired {
+		return nil
+	}
+	current.Status = opsModel.SSHKeyRetired
+	current.NextRotateAt = nil
+	return s.keyRepo.Update(current)
+}
+
+func nextRotateAt(from time.Time, rotateDays int) *time.Time {
+	if rotateDays <= 0 {
+		return nil
+	}
+	next := from.AddDate(0, 0, rotateDays)
+	return &next
+}
+
+func toSSHKeyResponse(key *opsModel.SSHKey, hostCount int) *response.SSHKeyResponse {
+	return &response.SSHKeyResponse{
+		ID:           key.ID,
+		Name:         key.Name,
+		KeyType:      key.KeyType,
+		Bits:         key.Bits,
+		PublicKey:    key.PublicKey,
+		Fingerprint:  key.Fingerprint,
+		Status:       string(key.Status),
+		RotateDays:   key.RotateDays,
+		NextRotateAt: formatTimePtr(key.NextRotateAt),
+		ReplacedBy:   key.ReplacedBy,
+		HostCount:    hostCount,
+		CreatedBy:    key.CreatedBy,
+		CreatedAt:    key.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
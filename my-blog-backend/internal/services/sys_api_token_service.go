@@ -0,0 +1,139 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/models"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/pkg/session"
+	"my-blog-backend/internal/repository"
+)
+
+const (
+	apiTokenPrefix     = "ops_"
+	apiTokenPrefixLen  = 12 // 保存用于识别的明文前缀长度
+	apiTokenTouchAfter = time.Minute
+)
+
+var ErrInvalidAPIToken = errors.New("API Token 无效或已过期")
+
+// SysAPITokenService API Token 服务
+type SysAPITokenService interface {
+	// CreateToken 为用户创建 Token，返回的明文只出现这一次
+	CreateToken(userID uint64, req *request.CreateAPITokenRequest) (*response.CreateAPITokenResponse, error)
+	// ListTokens 获取用户的 Token 列表
+	ListTokens(userID uint64) ([]*models.SysAPIToken, error)
+	// DeleteToken 吊销用户的 Token
+	DeleteToken(userID, id uint64) error
+	// Authenticate 校验 Token，返回与登录 Session 一致的用户信息
+	Authenticate(rawToken, clientIP string) (*session.SessionInfo, error)
+}
+
+type sysAPITokenService struct {
+	tokenRepo repository.SysAPITokenRepository
+	userRepo  repository.SysUserRepository
+}
+
+func NewSysAPITokenService(
+	tokenRepo repository.SysAPITokenRepository,
+	userRepo repository.SysUserRepository,
+) SysAPITokenService {
+	return &sysAPITokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *sysAPITokenService) CreateToken(userID uint64, req *request.CreateAPITokenRequest) (*response.CreateAPITokenResponse, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	raw := apiTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	token := &models.SysAPIToken{
+		UserID:    userID,
+		Name:      req.Name,
+		TokenHash: hashAPIToken(raw),
+		Prefix:    raw[:apiTokenPrefixLen],
+	}
+	if req.ExpireDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpireDays)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, err
+	}
+	return &response.CreateAPITokenResponse{SysAPIToken: token, Token: raw}, nil
+}
+
+func (s *sysAPITokenService) ListTokens(userID uint64) ([]*models.SysAPIToken, error) {
+	return s.tokenRepo.ListByUserID(userID)
+}
+
+func (s *sysAPITokenService) DeleteToken(userID, id uint64) error {
+	ok, err := s.tokenRepo.Delete(userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("Token不存在")
+	}
+	return nil
+}
+
+func (s *sysAPITokenService) Authenticate(rawToken, clientIP string) (*session.SessionInfo, error) {
+	if !strings.HasPrefix(rawToken, apiTokenPrefix) {
+		return nil, ErrInvalidAPIToken
+	}
+	token, err := s.tokenRepo.FindByHash(hashAPIToken(rawToken))
+	if err != nil {
+		return nil, ErrInvalidAPIToken
+	}
+	now := time.Now()
+	if token.ExpiresAt != nil && now.After(*token.ExpiresAt) {
+		return nil, ErrInvalidAPIToken
+	}
+
+	// 用户被禁用或删除后 Token 随之失效
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil || user.Deleted != models.DeletedNo || user.Status != models.StatusEnabled {
+		return nil, ErrInvalidAPIToken
+	}
+	roles, err := s.userRepo.GetUserRoles(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > apiTokenTouchAfter {
+		if err := s.tokenRepo.UpdateLastUsed(token.ID, now, clientIP); err != nil {
+			logger.Warn("更新API Token使用时间失败", logger.Err("error", err))
+		}
+	}
+
+	info := &session.SessionInfo{
+		UserID:    uint(user.ID),
+		Email:     user.Email,
+		Username:  user.Username,
+		Nickname:  user.Nickname,
+		DeptID:    uint(user.DeptID),
+		CreatedAt: now,
+	}
+	for _, role := range roles {
+		info.RoleIDs = append(info.RoleIDs, uint(role.ID))
+	}
+	return info, nil
+}
+
+func hashAPIToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
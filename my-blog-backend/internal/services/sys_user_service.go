@@ -34,6 +34,8 @@ type SysUserService interface {
 	VerifyEmailCode(ctx context.Context, email, code string, clear bool) (bool, error)
 	// Logout 用户登出
 	Logout(userID uint64) error
+	// RevokeSessions 登录会话被清除后通知断开该用户的终端
+	RevokeSessions(userID uint64)
 	// GetUserInfo 获取用户信息
 	GetUserInfo(userID uint64) (*models.SysUser, error)
 	// GetUserInfoByEmail 根据邮箱获取用户信息
@@ -59,18 +61,21 @@ type sysUserService struct {
 	logRepo     repository.SysLogRepository
 	cacheRepo   repository.CacheRepo
 	emailServer *smtp.Sender
+	events      *UserEventBus
 }
 
 func NewSysUserService(
 	userRepo repository.SysUserRepository,
 	logRepo repository.SysLogRepository,
 	cacheRepo repository.CacheRepo,
+	events *UserEventBus,
 ) SysUserService {
 	return &sysUserService{
 		userRepo:    userRepo,
 		logRepo:     logRepo,
 		cacheRepo:   cacheRepo,
 		emailServer: smtp.GetEmailServer(),
+		events:      events,
 	}
 }
 
@@ -109,10 +114,14 @@ func (s *sysUserService) Login(username, password string) (*models.SysUser, stri
 }
 
 func (s *sysUserService) Logout(userID uint64) error {
-	// TODO: 清除 Session
+	s.events.Publish(UserEvent{Type: UserEventLogout, UserID: uint(userID)})
 	return nil
 }
 
+func (s *sysUserService) RevokeSessions(userID uint64) {
+	s.events.Publish(UserEvent{Type: UserEventSessionsRevoked, UserID: uint(userID)})
+}
+
 func (s *sysUserService) GetUserInfo(userID uint64) (*models.SysUser, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
@@ -166,7 +175,15 @@ func (s *sysUserService) UpdateUser(user *models.SysUser, updaterID uint64) erro
 		}
 		user.Password = oldUser.Password
 	}
-	return s.userRepo.Update(user)
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	// 禁用账号时断开其终端
+	if user.Status != 1 {
+		s.events.Publish(UserEvent{Type: UserEventDisabled, UserID: uint(user.ID)})
+	}
+	return nil
 }
 
 func (s *sysUserService) DeleteUser(id, operatorID uint64) error {
@@ -175,7 +192,11 @@ func (s *sysUserService) DeleteUser(id, operatorID uint64) error {
 		return errors.New("不能删除自己")
 	}
 
-	return s.userRepo.Delete(id)
+	if err := s.userRepo.Delete(id); err != nil {
+		return err
+	}
+	s.events.Publish(UserEvent{Type: UserEventDeleted, UserID: uint(id)})
+	return nil
 }
 
 func (s *sysUserService) ResetPassword(id uint64, newPassword string) error {
@@ -187,7 +208,17 @@ func (s *sysUserService) ResetPassword(id uint64, newPassword string) error {
 }
 
 func (s *sysUserService) AssignRoles(userID uint64, roleIDs []uint64) error {
-	return s.userRepo.AssignRoles(userID, roleIDs)
+	if err := s.userRepo.AssignRoles(userID, roleIDs); err != nil {
+		return err
+	}
+
+	// 角色变更可能失去超级管理员身份，由订阅方重新校验主机权限
+	ids := make([]uint, 0, len(roleIDs))
+	for _, id := range roleIDs {
+		ids = append(ids, uint(id))
+	}
+	s.events.Publish(UserEvent{Type: UserEventRolesChanged, UserID: uint(userID), RoleIDs: ids})
+	return nil
 }
 
 func (s *sysUserService) AssignPosts(userID uint64, postIDs []uint64) error {
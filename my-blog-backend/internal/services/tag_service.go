@@ -19,12 +19,14 @@ type TagService interface {
 }
 
 type tagService struct {
-	tagRepo repository.TagRepository
+	tagRepo         repository.TagRepository
+	redirectService RedirectService
 }
 
-func NewTagService(tagRepo repository.TagRepository) TagService {
+func NewTagService(tagRepo repository.TagRepository, redirectService RedirectService) TagService {
 	return &tagService{
-		tagRepo: tagRepo,
+		tagRepo:         tagRepo,
+		redirectService: redirectService,
 	}
 }
 
@@ -40,9 +42,15 @@ func (s *tagService) UpdateTag(id uint, tag *models.Tag) error {
 		return errors.New("tag not found")
 	}
 
-	// Tag的ID是uint64，不需要转换
+	// 未设置ID时 Save 会新建标签
+	tag.ID = existing.ID
 	tag.CreatedAt = existing.CreatedAt
-	return s.tagRepo.Update(tag)
+	if err := s.tagRepo.Update(tag); err != nil {
+		return err
+	}
+	// 修改 slug 后旧地址跳转到新地址
+	recordSlugChange(s.redirectService, models.SlugEntityTag, existing.ID, existing.Slug, tag.Slug)
+	return nil
 }
 
 func (s *tagService) DeleteTag(id uint) error {
@@ -53,8 +61,9 @@ func (s *tagService) GetTag(id uint) (*models.Tag, error) {
 	return s.tagRepo.GetByID(id)
 }
 
+// GetTagBySlug 根据 slug 获取标签，slug 已修改时按旧 slug 找到标签
 func (s *tagService) GetTagBySlug(slug string) (*models.Tag, error) {
-	return s.tagRepo.GetBySlug(slug)
+	return findBySlug(s.redirectService, models.SlugEntityTag, slug, s.tagRepo.GetBySlug, s.tagRepo.GetByID)
 }
 
 func (s *tagService) ListTags(page, pageSize int) ([]*models.Tag, int64, error) {
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	ws "github.com/gorilla/websocket"
+
+	"my-blog-backend/internal/api/v1/dto/response"
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/websocket"
+)
+
+const (
+	streamStdout = "stdout"
+	streamStderr = "stderr"
+	// 单行超过该长度时直接推送，避免无换行的输出一直积压
+	maxStreamLine = 4096
+)
+
+// hostOutput 收集单台主机的输出：按行推送给订阅者，同时保留合并后的输出用于落库
+type hostOutput struct {
+	mu       sync.Mutex
+	service  *BatchTaskService
+	relation *opsModel.TaskHostRelation
+	buf      strings.Builder              // 已推送的输出行
+	partial  map[string][]byte            // stream -> 尚未遇到换行的内容
+	masks    map[string]*OutputMaskStream // stream -> 脱敏状态
+	seq      int64
+}
+
+func newHostOutput(service *BatchTaskService, relation *opsModel.TaskHostRelation) *hostOutput {
+	return &hostOutput{
+		service:  service,
+		relation: relation,
+		partial:  make(map[string][]byte),
+		masks: map[string]*OutputMaskStream{
+			streamStdout: service.masker.NewStream(),
+			streamStderr: service.masker.NewStream(),
+		},
+	}
+}
+
+// writer 返回写入指定输出流的 io.Writer
+func (o *hostOutput) writer(stream string) io.Writer {
+	return &streamWriter{output: o, stream: stream}
+}
+
+type streamWriter struct {
+	output *hostOutput
+	stream string
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	o := w.output
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	data := append(o.partial[w.stream], p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		o.emit(w.stream, string(bytes.TrimSuffix(data[:i], []byte("\r"))))
+		data = data[i+1:]
+	}
+	for len(data) >= maxStreamLine {
+		o.emit(w.stream, string(data[:maxStreamLine]))
+		data = data[maxStreamLine:]
+	}
+	o.partial[w.stream] = append([]byte(nil), data...)
+	return len(p), nil
+}
+
+// emit 脱敏后记录并推送一行输出，调用方需持有 o.mu
+func (o *hostOutput) emit(stream, line string) {
+	line = o.masks[stream].Line(line)
+	o.seq++
+	o.buf.WriteString(line)
+	o.buf.WriteString("\n")
+	// 内存中只保留落库所需的末尾部分
+	if o.buf.Len() > 2*maxTaskOutput {
+		tail := o.buf.String()[o.buf.Len()-maxTaskOutput:]
+		o.buf.Reset()
+		o.buf.WriteString(tail)
+	}
+
+	o.service.publish(response.TaskStreamMessage{
+		Type:     "output",
+		TaskID:   o.relation.TaskID,
+		HostID:   o.relation.HostID,
+		HostName: o.relation.HostName,
+		Stream:   stream,
+		Data:     line,
+		Seq:      o.seq,
+	})
+}
+
+// flush 推送剩余的不完整行并返回全部输出
+func (o *hostOutput) flush() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, stream := range []string{streamStdout, streamStderr} {
+		if len(o.partial[stream]) > 0 {
+			o.emit(stream, string(o.partial[stream]))
+			o.partial[stream] = nil
+		}
+	}
+	return o.buf.String()
+}
+
+// snapshot 当前已推送的输出及其最后一行的序号
+func (o *hostOutput) snapshot() (string, int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.String(), o.seq
+}
+
+// publish 将消息推送给任务的所有订阅者
+func (s *BatchTaskService) publish(msg response.TaskStreamMessage) {
+	if s.taskHub == nil {
+		return
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		logger.Error("序列化任务输出消息失败", logger.Uint("task_id", msg.TaskID), logger.Err("error", err))
+		return
+	}
+	s.taskHub.Publish(msg.TaskID, data)
+}
+
+// SubscribeTask 订阅任务的实时输出
+// 先注册到 TaskHub 再生成快照，快照之后的输出行一定会推送给客户端；
+// 返回的快照消息需在启动 WritePump 之前写入连接，finished 为 true 表示任务已结束，发送快照后即可关闭连接
+func (s *BatchTaskService) SubscribeTask(taskID uint, conn *ws.Conn) (client *websocket.TaskClient, snapshot []response.TaskStreamMessage, finished bool, err error) {
+	task, err := s.taskRepo.GetByID(taskID)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("任务不存在")
+	}
+	if s.taskHub == nil {
+		return nil, nil, false, fmt.Errorf("实时输出未启用")
+	}
+
+	client = websocket.NewTaskClient(s.taskHub, taskID, conn)
+	s.taskHub.Register(client)
+
+	// 先取执行中主机的输出缓冲，再读取数据库：
+	// 主机结束时先落库再移除缓冲，因此不在缓冲中的主机要么尚未开始，要么可以从数据库读到最终结果
+	s.mu.Lock()
+	outputs := make(map[uint]*hostOutput)
+	for id, o := range s.outputs {
+		if o.relation.TaskID == taskID {
+			outputs[id] = o
+		}
+	}
+	s.mu.Unlock()
+
+	if task, err = s.taskRepo.GetByID(taskID); err != nil {
+		s.taskHub.Unregister(client)
+		return nil, nil, false, fmt.Errorf("任务不存在")
+	}
+	relations, err := s.taskRepo.ListRelations(taskID)
+	if err != nil {
+		s.taskHub.Unregister(client)
+		return nil, nil, false, err
+	}
+
+	for _, rel := range relations {
+		if o, ok := outputs[rel.ID]; ok {
+			data, seq := o.snapshot()
+			snapshot = append(snapshot, response.TaskStreamMessage{
+				Type:     "snapshot",
+				TaskID:   taskID,
+				HostID:   rel.HostID,
+				HostName: rel.HostName,
+				Data:     data,
+				Seq:      seq,
+			})
+			continue
+		}
+		if isFinalStatus(rel.Status) {
+			msg := hostExitMessage(rel)
+			msg.Data = rel.Output
+			snapshot = append(snapshot, msg)
+		}
+	}
+
+	if isFinalStatus(task.Status) {
+		snapshot = append(snapshot, taskDoneMessage(task))
+		finished = true
+	}
+	return client, snapshot, finished, nil
+}
+
+func isFinalStatus(status opsModel.TaskStatus) bool {
+	return status == opsModel.TaskSuccess || status == opsModel.TaskFailed || status == opsModel.TaskCanceled
+}
+
+func hostExitMessage(rel *opsModel.TaskHostRelation) response.TaskStreamMessage {
+	return response.TaskStreamMessage{
+		Type:     "exit",
+		TaskID:   rel.TaskID,
+		HostID:   rel.HostID,
+		HostName: rel.HostName,
+		ExitCode: rel.ExitCode,
+		Status:   rel.Status.String(),
+		Error:    rel.Error,
+	}
+}
+
+func taskDoneMessage(task *opsModel.BatchTask) response.TaskStreamMessage {
+	return response.TaskStreamMessage{
+		Type:    "done",
+		TaskID:  task.ID,
+		Status:  task.Status.String(),
+		Success: task.SuccessCount,
+		Failed:  task.FailedCount,
+	}
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"my-blog-backend/internal/api/v1/dto/request"
+	"my-blog-backend/internal/api/v1/dto/response"
+	"my-blog-backend/internal/config"
+	opsModel "my-blog-backend/internal/models/opsModel"
+	"my-blog-backend/internal/pkg/logger"
+	"my-blog-backend/internal/repository"
+	"my-blog-backend/internal/ssh"
+)
+
+// ErrRecordingNotFound 会话未录像
+var ErrRecordingNotFound = errors.New("该会话没有录像")
+
+// TerminalSessionService 终端会话列表、历史记录和录像
+type TerminalSessionService struct {
+	sessionRepo repository.TerminalSessionRepository
+	config      *config.OpsConfig
+}
+
+func NewTerminalSessionService(sessionRepo repository.TerminalSessionRepository, cfg *config.OpsConfig) *TerminalSessionService {
+	return &TerminalSessionService{sessionRepo: sessionRepo, config: cfg}
+}
+
+// StartRecording 开启录像时为会话创建录像文件并记录录像ID，需在会话启动前调用
+// 创建失败只记录日志，不影响终端使用
+func (s *TerminalSessionService) StartRecording(session *ssh.Session) {
+	if !s.config.TerminalRecording {
+		return
+	}
+	// 会话ID由浏览器生成，不能直接用作文件名
+	recordingID := uuid.New().String()
+	recorder, err := ssh.NewRecorder(s.recordingPath(recordingID))
+	if err != nil {
+		logger.Error("创建终端录像失败", logger.String("session", session.ID), logger.Err("error", err))
+		return
+	}
+	session.Recorder = recorder
+	session.Meta.RecordingID = recordingID
+}
+
+// RecordingFile 返回会话录像文件路径
+func (s *TerminalSessionService) RecordingFile(id uint) (string, error) {
+	record, err := s.sessionRepo.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", ErrRecordingNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if record.RecordingID == "" {
+		return "", ErrRecordingNotFound
+	}
+	return s.recordingPath(record.RecordingID), nil
+}
+
+func (s *TerminalSessionService) recordingPath(recordingID string) string {
+	return filepath.Join(s.config.DataDir, "recordings", recordingID+".cast")
+}
+
+// Open 会话建立后写入记录，写入失败只记录日志，不影响终端使用
+func (s *TerminalSessionService) Open(session *ssh.Session) *opsModel.TerminalSession {
+	record := &opsModel.TerminalSession{
+		SessionID:   session.ID,
+		UserID:      session.UserID,
+		UserName:    session.Meta.UserName,
+		HostID:      session.Client.GetHostID(),
+		HostName:    session.Meta.HostName,
+		HostAddress: session.Meta.HostAddress,
+		HostAccount: session.Meta.HostAccount,
+		ClientIP:    session.Meta.ClientIP,
+		ClientAgent: truncateAgent(session.Meta.ClientAgent),
+		RecordingID: session.Meta.RecordingID,
+		Status:      opsModel.TerminalSessionActive,
+		StartedAt:   session.StartTime,
+	}
+	if session.Container != nil {
+		record.ContainerID = session.Container.ID
+	}
+	if err := s.sessionRepo.Create(record); err != nil {
+		logger.Error("保存终端会话记录失败", logger.String("session", session.ID), logger.Err("error", err))
+		return nil
+	}
+	return record
+}
+
+// Close 会话结束后更新流量、最后输入时间和断开原因
+func (s *TerminalSessionService) Close(record *opsModel.TerminalSession, session *ssh.Session) {
+	if record == nil {
+		return
+	}
+	now := time.Now()
+	lastInput := session.LastInputTime()
+	record.BytesIn, record.BytesOut = session.Traffic()
+	record.LastInputAt = &lastInput
+	record.EndedAt = &now
+	record.CloseReason = session.CloseReason()
+	record.Status = opsModel.TerminalSessionClosed
+	if err := s.sessionRepo.Update(record); err != nil {
+		logger.Error("更新终端会话记录失败", logger.String("session", session.ID), logger.Err("error", err))
+	}
+}
+
+// CloseStale 服务启动时结束上次运行遗留的进行中记录
+func (s *TerminalSessionService) CloseStale() {
+	count, err := s.sessionRepo.CloseActive(time.Now(), "服务重启")
+	if err != nil {
+		logger.Error("结束遗留终端会话记录失败", logger.Err("error", err))
+		return
+	}
+	if count > 0 {
+		logger.Info("已结束遗留终端会话记录", logger.Int64("count", count))
+	}
+}
+
+// ListActive 筛选并分页进行中的会话，按开始时间倒序
+func (s *TerminalSessionService) ListActive(sessions []*ssh.Session, req *request.ListTerminalSessionRequest) *response.TerminalSessionListResponse {
+	matched := make([]*ssh.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if matchActiveSession(session, req) {
+			matched = append(matched, session)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].StartTime.After(matched[j].StartTime)
+	})
+
+	items := make([]response.TerminalSessionResponse, 0, req.PageSize)
+	start := (req.Page - 1) * req.PageSize
+	for i := start; i >= 0 && i < len(matched) && i < start+req.PageSize; i++ {
+		items = append(items, toActiveSessionResponse(matched[i]))
+	}
+	return &response.TerminalSessionListResponse{
+		Total: int64(len(matched)),
+		Items: items,
+	}
+}
+
+// ListHistory 会话历史记录
+func (s *TerminalSessionService) ListHistory(req *request.ListTerminalSessionRequest) (*response.TerminalSessionListResponse, error) {
+	records, total, err := s.sessionRepo.List(req.Page, req.PageSize, req.UserID, req.HostID, req.Keyword)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.TerminalSessionResponse, len(records))
+	for i, record := range records {
+		items[i] = toTerminalSessionResponse(record)
+	}
+	return &response.TerminalSessionListResponse{
+		Total: total,
+		Items: items,
+	}, nil
+}
+
+func matchActiveSession(session *ssh.Session, req *request.ListTerminalSessionRequest) bool {
+	if req.UserID > 0 && session.UserID != req.UserID {
+		return false
+	}
+	if req.HostID > 0 && session.Client.GetHostID() != req.HostID {
+		return false
+	}
+	if req.Keyword == "" {
+		return true
+	}
+	meta := session.Meta
+	for _, field := range []string{meta.UserName, meta.HostName, meta.HostAddress, meta.ClientIP} {
+		if strings.Contains(field, req.Keyword) {
+			return true
+		}
+	}
+	return session.ID == req.Keyword
+}
+
+func toActiveSessionResponse(session *ssh.Session) response.TerminalSessionResponse {
+	bytesIn, bytesOut := session.Traffic()
+	item := response.TerminalSessionResponse{
+		SessionID:   session.ID,
+		UserID:      session.UserID,
+		UserName:    session.Meta.UserName,
+		HostID:      session.Client.GetHostID(),
+		HostName:    session.Meta.HostName,
+		HostAddress: session.Meta.HostAddress,
+		HostAccount: session.Meta.HostAccount,
+		ClientIP:    session.Meta.ClientIP,
+		ClientAgent: session.Meta.ClientAgent,
+		RecordingID: session.Meta.RecordingID,
+		Status:      string(opsModel.TerminalSessionActive),
+		BytesIn:     bytesIn,
+		BytesOut:    bytesOut,
+		Duration:    int64(time.Since(session.StartTime).Seconds()),
+		StartedAt:   session.StartTime.Format("2006-01-02 15:04:05"),
+		LastInputAt: session.LastInputTime().Format("2006-01-02 15:04:05"),
+	}
+	if session.Container != nil {
+		item.ContainerID = session.Container.ID
+	}
+	return item
+}
+
+func toTerminalSessionResponse(record *opsModel.TerminalSession) response.TerminalSessionResponse {
+	item := response.TerminalSessionResponse{
+		ID:          record.ID,
+		SessionID:   record.SessionID,
+		UserID:      record.UserID,
+		UserName:    record.UserName,
+		HostID:      record.HostID,
+		HostName:    record.HostName,
+		HostAddress: record.HostAddress,
+		HostAccount: record.HostAccount,
+		ContainerID: record.ContainerID,
+		ClientIP:    record.ClientIP,
+		ClientAgent: record.ClientAgent,
+		RecordingID: record.RecordingID,
+		Status:      string(record.Status),
+		CloseReason: record.CloseReason,
+		BytesIn:     record.BytesIn,
+		BytesOut:    record.BytesOut,
+		StartedAt:   record.StartedAt.Format("2006-01-02 15:04:05"),
+		LastInputAt: formatTimePtr(record.LastInputAt),
+		EndedAt:     formatTimePtr(record.EndedAt),
+	}
+	if record.EndedAt != nil {
+		item.Duration = int64(record.EndedAt.Sub(record.StartedAt).Seconds())
+	}
+	return item
+}
@@ -3,12 +3,20 @@ package services
 import (
 	"crypto/md5"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"my-blog-backend/internal/config"
 	"my-blog-backend/internal/pkg/logger"
@@ -16,9 +24,16 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrNotLocalImage 图片不在本地存储中，如外部链接或远程存储
+var ErrNotLocalImage = errors.New("不是本地上传的文件")
+
 type UploadService interface {
 	UploadImage(file *multipart.FileHeader) (string, error)
+	SaveImage(filename string, content []byte) (string, error) // 保存已读取的图片内容，用于导入文章时上传引用的图片
+	ReadImage(url string) ([]byte, error)                      // 读取本地存储的图片，用于导出文章
+	DownloadImage(url string) (string, error)                  // 下载远程图片并保存，用于导入其他博客的文章
 	DeleteImage(filename string) error
+	MaxSize() int64 // 单个文件的大小上限
 }
 
 type uploadService struct {
@@ -59,8 +74,165 @@ func (s *uploadService) UploadImage(file *multipart.FileHeader) (string, error)
 		return "", fmt.Errorf("读取文件内容失败: %w", err)
 	}
 
+	fullURL, md5Hash, err := s.store(ext, fileContent)
+	if err != nil {
+		return "", err
+	}
+
+	logger.Info("图片上传成功",
+		logger.String("filename", file.Filename),
+		logger.Int64("size", file.Size),
+		logger.String("md5", md5Hash),
+		logger.String("url", fullURL),
+	)
+
+	return fullURL, nil
+}
+
+func (s *uploadService) MaxSize() int64 {
+	return s.config.Local.MaxSize
+}
+
+// SaveImage 保存图片内容，校验规则与 UploadImage 相同
+func (s *uploadService) SaveImage(filename string, content []byte) (string, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !s.isAllowedExt(ext) {
+		return "", fmt.Errorf("不支持的文件类型: %s", ext)
+	}
+	if int64(len(content)) > s.config.Local.MaxSize {
+		return "", fmt.Errorf("文件大小超过限制: 最大 %d MB", s.config.Local.MaxSize/(1024*1024))
+	}
+
+	fullURL, _, err := s.store(ext, content)
+	return fullURL, err
+}
+
+// imageDownloadTimeout 下载远程图片的超时时间
+const imageDownloadTimeout = 30 * time.Second
+
+// imageDownloadMaxRedirects 下载远程图片时允许的最大重定向次数
+const imageDownloadMaxRedirects = 5
+
+// errNonPublicAddress 图片地址指向回环、内网等非公网地址
+var errNonPublicAddress = errors.New("不允许访问非公网地址")
+
+// nonPublicPrefixes net.IP 方法未覆盖的保留地址段
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // 本网络
+	netip.MustParsePrefix("100.64.0.0/10"),  // 运营商级 NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF 协议分配
+	netip.MustParsePrefix("198.18.0.0/15"),  // 基准测试
+	netip.MustParsePrefix("240.0.0.0/4"),    // 保留及广播
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64，可映射到内网 IPv4
+	netip.MustParsePrefix("64:ff9b:1::/48"), // 本地 NAT64
+	netip.MustParsePrefix("2002::/16"),      // 6to4，可映射到内网 IPv4
+}
+
+// imageDownloadClient 下载远程图片的 HTTP 客户端
+// 建立连接时检查解析后的实际 IP，防止通过域名解析或重定向访问内网服务
+var imageDownloadClient = &http.Client{
+	Timeout: imageDownloadTimeout,
+	Transport: &http.Transport{
+		// 不使用代理，否则检查的是代理地址而非目标地址
+		Proxy: nil,
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: publicAddressControl,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= imageDownloadMaxRedirects {
+			return errors.New("重定向次数过多")
+		}
+		return checkImageURL(req.URL)
+	},
+}
+
+// checkImageURL 校验图片地址协议，主机为 IP 时要求是公网地址，域名在连接时检查
+func checkImageURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("无效的图片地址: %s", u)
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil && !isPublicAddr(addr) {
+		return errNonPublicAddress
+	}
+	return nil
+}
+
+// publicAddressControl 拨号前检查目标地址，address 为解析后的 IP:端口
+func publicAddressControl(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil || !isPublicAddr(addrPort.Addr()) {
+		return errNonPublicAddress
+	}
+	return nil
+}
+
+// isPublicAddr 判断是否为公网单播地址
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// imageContentTypeExts 链接没有扩展名时按响应的 Content-Type 确定扩展名
+var imageContentTypeExts = map[string]string{
+	"image/jpeg":    ".jpg",
+	"image/png":     ".png",
+	"image/gif":     ".gif",
+	"image/webp":    ".webp",
+	"image/svg+xml": ".svg",
+}
+
+// DownloadImage 下载 http(s) 图片并保存，校验规则与 UploadImage 相同
+// 只允许访问公网地址，重定向后的地址同样检查
+func (s *uploadService) DownloadImage(rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("无效的图片地址: %s", rawURL)
+	}
+	if err := checkImageURL(parsed); err != nil {
+		return "", err
+	}
+
+	resp, err := imageDownloadClient.Get(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("下载图片失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("下载图片失败: %s", resp.Status)
+	}
+
+	contentType := strings.TrimSpace(strings.SplitN(resp.Header.Get("Content-Type"), ";", 2)[0])
+	if contentType != "" && !strings.HasPrefix(contentType, "image/") {
+		return "", fmt.Errorf("不是图片: %s", contentType)
+	}
+	// 多读一个字节判断是否超过大小限制
+	content, err := io.ReadAll(io.LimitReader(resp.Body, s.config.Local.MaxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("下载图片失败: %w", err)
+	}
+
+	filename := path.Base(parsed.Path)
+	if filepath.Ext(filename) == "" {
+		filename += imageContentTypeExts[contentType]
+	}
+	return s.SaveImage(filename, content)
+}
+
+// store 按内容生成唯一文件名并保存，返回完整URL和内容MD5
+func (s *uploadService) store(ext string, content []byte) (string, string, error) {
 	// 生成MD5哈希
-	hash := md5.Sum(fileContent)
+	hash := md5.Sum(content)
 	md5Hash := hex.EncodeToString(hash[:])
 
 	// 生成唯一文件名: MD5前8位_UUID + 原始扩展名
@@ -68,27 +240,40 @@ func (s *uploadService) UploadImage(file *multipart.FileHeader) (string, error)
 
 	// 根据配置选择上传方式
 	var relativePath string
+	var err error
 	if s.config.Type == "local" {
-		relativePath, err = s.uploadToLocal(uniqueFilename, fileContent)
+		relativePath, err = s.uploadToLocal(uniqueFilename, content)
 	} else {
-		relativePath, err = s.uploadToRemote(uniqueFilename, fileContent)
+		relativePath, err = s.uploadToRemote(uniqueFilename, content)
 	}
 
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	// 拼接完整URL
-	fullURL := s.buildFullURL(relativePath)
+	return s.buildFullURL(relativePath), md5Hash, nil
+}
 
-	logger.Info("图片上传成功",
-		logger.String("filename", file.Filename),
-		logger.Int64("size", file.Size),
-		logger.String("md5", md5Hash),
-		logger.String("url", fullURL),
-	)
+// ReadImage 读取本地存储的图片，只支持 /uploads/ 下的文件
+func (s *uploadService) ReadImage(url string) ([]byte, error) {
+	relativePath := s.extractRelativePath(url)
+	if !strings.HasPrefix(relativePath, "/uploads/") {
+		return nil, fmt.Errorf("%w: %s", ErrNotLocalImage, url)
+	}
+	// 去掉查询参数和锚点，防止路径穿越
+	relativePath = strings.SplitN(strings.SplitN(relativePath, "?", 2)[0], "#", 2)[0]
+	filePath := filepath.Clean(strings.TrimPrefix(relativePath, "/uploads/"))
+	if filePath == "." || strings.HasPrefix(filePath, "..") || filepath.IsAbs(filePath) {
+		return nil, fmt.Errorf("无效的文件路径: %s", url)
+	}
 
-	return fullURL, nil
+	uploadPath := s.config.Local.UploadPath
+	if uploadPath == "" {
+		uploadPath = "./uploads"
+	}
+
+	return os.ReadFile(filepath.Join(uploadPath, filePath))
 }
 
 // uploadToLocal 上传到本地存储
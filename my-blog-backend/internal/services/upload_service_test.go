@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"my-blog-backend/internal/config"
+)
+
+func TestDownloadImageRejectsNonPublicAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("png"))
+	}))
+	defer server.Close()
+
+	service := NewUploadService(&config.UploadConfig{})
+	// 域名在连接时按解析结果检查
+	localhostURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	for _, rawURL := range []string{
+		server.URL + "/a.png",
+		localhostURL + "/a.png",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::ffff:10.0.0.1]/a.png",
+	} {
+		if _, err := service.DownloadImage(rawURL); !errors.Is(err, errNonPublicAddress) {
+			t.Fatalf("%s: 期望 errNonPublicAddress, 实际 %v", rawURL, err)
+		}
+	}
+}
+
+func TestDownloadImageRejectsRedirectToNonPublicAddress(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:8080/a.png", nil)
+	if err := imageDownloadClient.CheckRedirect(req, nil); !errors.Is(err, errNonPublicAddress) {
+		t.Fatalf("重定向到回环地址应被拒绝, 实际 %v", err)
+	}
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	cases := map[string]bool{
+		"8.8.8.8":          true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"::ffff:127.0.0.1": false,
+	}
+	for addr, want := range cases {
+		if got := isPublicAddr(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("isPublicAddr(%s) = %v, 期望 %v", addr, got, want)
+		}
+	}
+}
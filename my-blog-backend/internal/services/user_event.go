@@ -0,0 +1,74 @@
+package services
+
+import (
+	"sync"
+
+	"my-blog-backend/internal/pkg/logger"
+)
+
+// UserEventType 用户账号事件类型
+type UserEventType string
+
+const (
+	UserEventDisabled        UserEventType = "disabled"         // 账号被禁用
+	UserEventDeleted         UserEventType = "deleted"          // 账号被删除
+	UserEventLogout          UserEventType = "logout"           // 用户登出
+	UserEventSessionsRevoked UserEventType = "sessions_revoked" // 登录会话被清除（如单会话模式下重新登录）
+	UserEventRolesChanged    UserEventType = "roles_changed"    // 角色变更
+)
+
+// UserEvent 用户账号事件，RoleIDs 只在角色变更时携带变更后的角色
+type UserEvent struct {
+	Type    UserEventType
+	UserID  uint
+	RoleIDs []uint
+}
+
+// Reason 事件对应的提示文案，用于告知被断开的终端
+func (e UserEvent) Reason() string {
+	switch e.Type {
+	case UserEventDisabled:
+		return "账号已被禁用"
+	case UserEventDeleted:
+		return "账号已被删除"
+	case UserEventLogout:
+		return "账号已登出"
+	case UserEventSessionsRevoked:
+		return "登录状态已失效"
+	case UserEventRolesChanged:
+		return "权限已变更，无权访问该主机"
+	}
+	return "会话已被终止"
+}
+
+// UserEventBus 用户账号事件分发，订阅方同步调用，耗时操作需自行异步处理
+type UserEventBus struct {
+	mu       sync.RWMutex
+	handlers []func(UserEvent)
+}
+
+func NewUserEventBus() *UserEventBus {
+	return &UserEventBus{}
+}
+
+// Subscribe 订阅用户账号事件
+func (b *UserEventBus) Subscribe(handler func(UserEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish 发布用户账号事件，未初始化时忽略
+func (b *UserEventBus) Publish(event UserEvent) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	logger.Info("用户账号事件", logger.String("type", string(event.Type)), logger.Uint("user_id", event.UserID))
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,165 @@
+package ssh_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"my-blog-backend/internal/ssh"
+	"my-blog-backend/internal/ssh/sshtest"
+)
+
+func newClient(t *testing.T, cfg *ssh.Config) *ssh.SSHClient {
+	t.Helper()
+	pool := ssh.NewPool(time.Minute)
+	t.Cleanup(func() { _ = pool.Close() })
+	client, err := pool.Get(context.Background(), cfg, 1)
+	if err != nil {
+		t.Fatalf("连接测试服务器失败: %v", err)
+	}
+	return client
+}
+
+func TestClientAuth(t *testing.T) {
+	server := sshtest.NewServer(t)
+
+	tests := []struct {
+		name    string
+		cfg     *ssh.Config
+		wantErr bool
+	}{
+		{name: "password", cfg: server.PasswordConfig()},
+		{name: "key", cfg: server.KeyConfig()},
+		{name: "wrong password", cfg: func() *ssh.Config {
+			cfg := server.PasswordConfig()
+			cfg.Password = "wrong"
+			return cfg
+		}(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := ssh.NewPool(time.Minute)
+			defer pool.Close()
+
+			client, err := pool.Get(context.Background(), tt.cfg, 1)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("期望认证失败")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("认证失败: %v", err)
+			}
+			if !client.IsAlive() {
+				t.Fatal("连接不可用")
+			}
+		})
+	}
+}
+
+func TestClientExec(t *testing.T) {
+	server := sshtest.NewServer(t)
+	client := newClient(t, server.PasswordConfig())
+
+	output, code, err := client.ExecOutput(context.Background(), "echo hello world")
+	if err != nil || code != 0 || output != "hello world\n" {
+		t.Fatalf("ExecOutput = %q, %d, %v", output, code, err)
+	}
+
+	if _, code, err = client.ExecOutput(context.Background(), "false"); err != nil || code != 1 {
+		t.Fatalf("false 退出码 = %d, %v", code, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code, err = client.ExecInput(context.Background(), "cat", strings.NewReader("from stdin"), &stdout, &stderr)
+	if err != nil || code != 0 || stdout.String() != "from stdin" {
+		t.Fatalf("ExecInput = %q, %d, %v", stdout.String(), code, err)
+	}
+}
+
+func TestClientExecTimeout(t *testing.T) {
+	server := sshtest.NewServer(t)
+	client := newClient(t, server.PasswordConfig())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Exec(ctx, "sleep 10", &bytes.Buffer{}, &bytes.Buffer{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("期望超时错误，实际 %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("超时后未及时返回: %v", elapsed)
+	}
+	if !client.IsAlive() {
+		t.Fatal("命令超时不应影响连接")
+	}
+}
+
+func TestClientSFTP(t *testing.T) {
+	server := sshtest.NewServer(t)
+	client := newClient(t, server.KeyConfig())
+
+	sftpClient, err := client.GetSFTP()
+	if err != nil {
+		t.Fatalf("获取 SFTP 客户端失败: %v", err)
+	}
+	file, err := sftpClient.Create("/hello.txt")
+	if err != nil {
+		t.Fatalf("创建文件失败: %v", err)
+	}
+	if _, err := file.Write([]byte("hi")); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	file.Close()
+
+	again, err := client.GetSFTP()
+	if err != nil || again != sftpClient {
+		t.Fatalf("期望复用 SFTP 客户端: %v", err)
+	}
+	info, err := again.Stat("/hello.txt")
+	if err != nil || info.Size() != 2 {
+		t.Fatalf("Stat = %v, %v", info, err)
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	server := sshtest.NewServer(t)
+
+	report := ssh.Diagnose(context.Background(), server.KeyConfig())
+	want := map[string]ssh.DiagnoseStatus{
+		ssh.StageDNS:     ssh.DiagnoseOK,
+		ssh.StageTCP:     ssh.DiagnoseOK,
+		ssh.StageBanner:  ssh.DiagnoseOK,
+		ssh.StageKex:     ssh.DiagnoseOK,
+		ssh.StageHostKey: ssh.DiagnoseOK,
+		ssh.StageAuth:    ssh.DiagnoseOK,
+		ssh.StageShell:   ssh.DiagnoseOK,
+		ssh.StageSudo:    ssh.DiagnoseWarning, // 测试服务器没有 sudo 命令
+	}
+	for _, stage := range report.Stages {
+		if stage.Status != want[stage.Name] {
+			t.Errorf("阶段 %s = %s (%s)，期望 %s", stage.Name, stage.Status, stage.Message, want[stage.Name])
+		}
+	}
+	if !report.Success() {
+		t.Fatalf("诊断失败: %s", report.FailedStage().Message)
+	}
+
+	// 密码错误时停在认证阶段，之后的阶段跳过
+	cfg := server.PasswordConfig()
+	cfg.Password = "wrong"
+	report = ssh.Diagnose(context.Background(), cfg)
+	failed := report.FailedStage()
+	if failed == nil || failed.Name != ssh.StageAuth {
+		t.Fatalf("失败阶段 = %+v，期望 %s", failed, ssh.StageAuth)
+	}
+	if last := report.Stages[len(report.Stages)-1]; last.Status != ssh.DiagnoseSkipped {
+		t.Errorf("阶段 %s = %s，期望跳过", last.Name, last.Status)
+	}
+}
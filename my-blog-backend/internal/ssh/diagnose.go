@@ -0,0 +1,496 @@
+package ssh
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// 诊断阶段，按执行顺序排列
+const (
+	StageDNS     = "dns"      // 域名解析
+	StageTCP     = "tcp"      // TCP 连接
+	StageBanner  = "banner"   // SSH 版本标识
+	StageKex     = "kex"      // 密钥交换
+	StageHostKey = "host_key" // 主机公钥
+	StageAuth    = "auth"     // 认证
+	StageShell   = "shell"    // shell 启动
+	StageSudo    = "sudo"     // 免密 sudo
+)
+
+// DiagnoseStatus 诊断阶段结果
+type DiagnoseStatus string
+
+const (
+	DiagnoseOK      DiagnoseStatus = "ok"
+	DiagnoseWarning DiagnoseStatus = "warning" // 可以登录，但存在需要关注的问题
+	DiagnoseFailed  DiagnoseStatus = "failed"
+	DiagnoseSkipped DiagnoseStatus = "skipped" // 前置阶段失败，未执行
+)
+
+// shellReadyMarker shell 启动后回显的标记，用于计算 shell 启动耗时
+const shellReadyMarker = "__diagnose_ready__"
+
+// DiagnoseStage 单个诊断阶段的结果
+type DiagnoseStage struct {
+	Name     string
+	Status   DiagnoseStatus
+	Duration time.Duration
+	Message  string
+	Details  map[string]string
+}
+
+// Diagnosis 连接诊断报告
+type Diagnosis struct {
+	Stages   []*DiagnoseStage
+	Duration time.Duration
+}
+
+// Success 是否没有失败的阶段
+func (d *Diagnosis) Success() bool {
+	return d.FailedStage() == nil
+}
+
+// FailedStage 第一个失败的阶段，全部通过时返回 nil
+func (d *Diagnosis) FailedStage() *DiagnoseStage {
+	for _, stage := range d.Stages {
+		if stage.Status == DiagnoseFailed {
+			return stage
+		}
+	}
+	return nil
+}
+
+// Diagnose 分阶段诊断到主机的 SSH 连接：域名解析、TCP 连接、版本标识、密钥交换、主机公钥、
+// 各认证方式、shell 启动耗时和免密 sudo，某一阶段失败后其余阶段标记为跳过
+// 每个网络阶段的超时时间为 cfg.Timeout
+func Diagnose(ctx context.Context, cfg *Config) *Diagnosis {
+	d := &diagnoser{cfg: cfg}
+	defer d.close()
+
+	steps := []struct {
+		name string
+		run  func(ctx context.Context, stage *DiagnoseStage) error
+	}{
+		{StageDNS, d.resolve},
+		{StageTCP, d.connect},
+		{StageBanner, d.readBanner},
+		{StageKex, d.exchangeKeys},
+		{StageHostKey, d.checkHostKey},
+		{StageAuth, d.authenticate},
+		{StageShell, d.startShell},
+		{StageSudo, d.checkSudo},
+	}
+
+	report := &Diagnosis{}
+	start := time.Now()
+	failed := false
+	for _, step := range steps {
+		stage := &DiagnoseStage{Name: step.name, Status: DiagnoseSkipped, Details: map[string]string{}}
+		report.Stages = append(report.Stages, stage)
+		if failed {
+			continue
+		}
+
+		stageStart := time.Now()
+		stage.Status = DiagnoseOK
+		if err := step.run(ctx, stage); err != nil {
+			stage.Status = DiagnoseFailed
+			stage.Message = err.Error()
+			failed = true
+		}
+		if stage.Duration == 0 {
+			stage.Duration = time.Since(stageStart)
+		}
+	}
+	report.Duration = time.Since(start)
+	return report
+}
+
+// diagnoser 保存诊断过程中各阶段之间传递的状态
+type diagnoser struct {
+	cfg *Config
+
+	addrs   []string // 解析得到的地址
+	address string   // 成功连接的地址（ip:port）
+	conn    net.Conn
+
+	kexStage  *DiagnoseStage
+	handshake *handshakeResult // 首次握手结果，密钥交换、主机公钥和认证阶段共用
+	client    *ssh.Client      // 第一个认证成功的连接
+}
+
+// handshakeResult 一次 SSH 握手的结果，hostKey 为空表示密钥交换未完成
+type handshakeResult struct {
+	client    *ssh.Client
+	hostKey   ssh.PublicKey
+	kexTime   time.Duration // 开始握手到收到主机公钥
+	authTime  time.Duration // 收到主机公钥到认证结束
+	banner    string        // 认证前服务端发送的提示信息
+	err       error
+	connMeta  ssh.ConnMetadata
+	algorithm ssh.NegotiatedAlgorithms
+}
+
+func (d *diagnoser) close() {
+	if d.client != nil {
+		_ = d.client.Close()
+	}
+	if d.handshake != nil && d.handshake.client != nil && d.handshake.client != d.client {
+		_ = d.handshake.client.Close()
+	}
+	if d.conn != nil {
+		_ = d.conn.Close()
+	}
+}
+
+// resolve 解析主机地址，IP 地址无需解析
+func (d *diagnoser) resolve(ctx context.Context, stage *DiagnoseStage) error {
+	if ip := net.ParseIP(d.cfg.Host); ip != nil {
+		d.addrs = []string{ip.String()}
+		stage.Message = "IP 地址，无需解析"
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, d.cfg.Host)
+	if err != nil {
+		return fmt.Errorf("域名解析失败: %v", err)
+	}
+	for _, ip := range ips {
+		d.addrs = append(d.addrs, ip.IP.String())
+	}
+	stage.Message = fmt.Sprintf("解析到 %d 个地址", len(d.addrs))
+	stage.Details["addresses"] = strings.Join(d.addrs, ", ")
+	return nil
+}
+
+// connect 依次连接解析到的地址，使用第一个连接成功的地址
+func (d *diagnoser) connect(ctx context.Context, stage *DiagnoseStage) error {
+	dialer := net.Dialer{Timeout: d.cfg.Timeout}
+	var errs []string
+	for _, addr := range d.addrs {
+		address := net.JoinHostPort(addr, strconv.Itoa(int(d.cfg.Port)))
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, TCpNetwork, address)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", address, err))
+			continue
+		}
+		stage.Duration = time.Since(start)
+		d.conn = conn
+		d.address = address
+		stage.Message = fmt.Sprintf("已连接 %s", address)
+		stage.Details["address"] = address
+		if len(errs) > 0 {
+			stage.Status = DiagnoseWarning
+			stage.Details["failed_addresses"] = strings.Join(errs, "; ")
+		}
+		return nil
+	}
+	return fmt.Errorf("TCP 连接失败: %s", strings.Join(errs, "; "))
+}
+
+// readBanner 读取服务端的版本标识（SSH-2.0-xxx），读取的内容随后交还给握手过程
+func (d *diagnoser) readBanner(ctx context.Context, stage *DiagnoseStage) error {
+	_ = d.conn.SetReadDeadline(time.Now().Add(d.cfg.Timeout))
+	defer d.conn.SetReadDeadline(time.Time{})
+
+	reader := bufio.NewReader(d.conn)
+	var consumed strings.Builder
+	// RFC 4253 允许版本标识前有其他文本行
+	for i := 0; i < 20; i++ {
+		line, err := reader.ReadString('\n')
+		consumed.WriteString(line)
+		if err != nil {
+			return fmt.Errorf("读取 SSH 版本标识失败: %v", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "SSH-") {
+			if !strings.HasPrefix(line, "SSH-2.0-") && !strings.HasPrefix(line, "SSH-1.99-") {
+				return fmt.Errorf("不支持的 SSH 协议版本: %s", line)
+			}
+			stage.Message = line
+			stage.Details["version"] = line
+			d.conn = &replayConn{Conn: d.conn, reader: io.MultiReader(strings.NewReader(consumed.String()), reader)}
+			return nil
+		}
+	}
+	return fmt.Errorf("端口上的服务不是 SSH 服务")
+}
+
+// exchangeKeys 使用第一种认证方式握手，收到主机公钥即视为密钥交换完成
+func (d *diagnoser) exchangeKeys(ctx context.Context, stage *DiagnoseStage) error {
+	d.kexStage = stage
+	methods, err := d.authMethods()
+	if err != nil {
+		return err
+	}
+
+	d.handshake = d.handshakeOn(d.conn, methods[0].method)
+	d.conn = nil // 连接已交给握手结果管理
+	if d.handshake.hostKey == nil {
+		return fmt.Errorf("密钥交换失败: %v", d.handshake.err)
+	}
+	stage.Duration = d.handshake.kexTime
+	stage.Message = "密钥交换完成"
+	return nil
+}
+
+// checkHostKey 记录主机公钥类型和指纹，供管理员核对
+func (d *diagnoser) checkHostKey(ctx context.Context, stage *DiagnoseStage) error {
+	key := d.handshake.hostKey
+	stage.Message = fmt.Sprintf("%s %s", key.Type(), ssh.FingerprintSHA256(key))
+	stage.Details["type"] = key.Type()
+	stage.Details["fingerprint_sha256"] = ssh.FingerprintSHA256(key)
+	stage.Details["fingerprint_md5"] = ssh.FingerprintLegacyMD5(key)
+	return nil
+}
+
+// authenticate 分别验证每种认证方式，第一种复用密钥交换阶段的连接，其余方式重新连接
+// 有一种方式成功即可登录，部分方式失败时标记为警告
+func (d *diagnoser) authenticate(ctx context.Context, stage *DiagnoseStage) error {
+	methods, err := d.authMethods()
+	if err != nil {
+		return err
+	}
+
+	var total time.Duration
+	var failed []string
+	for i, method := range methods {
+		result := d.handshake
+		if i > 0 {
+			result = d.dialHandshake(ctx, method.method)
+		}
+		total += result.authTime
+		if result.banner != "" {
+			stage.Details["pre_auth_banner"] = strings.TrimSpace(result.banner)
+		}
+
+		if result.err != nil {
+			failed = append(failed, method.name)
+			stage.Details[method.name] = fmt.Sprintf("失败: %v", result.err)
+			continue
+		}
+		stage.Details[method.name] = fmt.Sprintf("成功，耗时 %s", result.authTime.Round(time.Microsecond))
+		if d.client == nil {
+			d.client = result.client
+			d.fillAlgorithms(result)
+		} else {
+			_ = result.client.Close()
+		}
+	}
+	stage.Duration = total
+
+	switch {
+	case d.client == nil:
+		return fmt.Errorf("认证失败，用户 %s 的所有认证方式均未通过", d.cfg.Username)
+	case len(failed) > 0:
+		stage.Status = DiagnoseWarning
+		stage.Message = fmt.Sprintf("用户 %s 认证成功，但 %s 认证失败", d.cfg.Username, strings.Join(failed, "、"))
+	default:
+		stage.Message = fmt.Sprintf("用户 %s 认证成功", d.cfg.Username)
+	}
+	return nil
+}
+
+// fillAlgorithms 将协商结果补充到密钥交换阶段，协商算法只有认证成功的连接才能获取
+func (d *diagnoser) fillAlgorithms(result *handshakeResult) {
+	details := d.kexStage.Details
+	details["server_version"] = string(result.connMeta.ServerVersion())
+	details["client_version"] = string(result.connMeta.ClientVersion())
+	if result.algorithm.KeyExchange == "" {
+		return
+	}
+	details["kex_algorithm"] = result.algorithm.KeyExchange
+	details["host_key_algorithm"] = result.algorithm.HostKey
+	details["cipher"] = result.algorithm.Read.Cipher
+	if result.algorithm.Read.MAC != "" { // AEAD 加密算法不单独协商 MAC
+		details["mac"] = result.algorithm.Read.MAC
+	}
+	d.kexStage.Message = fmt.Sprintf("密钥交换完成: %s / %s", result.algorithm.KeyExchange, result.algorithm.Read.Cipher)
+}
+
+// startShell 启动 shell 并执行 echo，测量到收到输出为止的耗时（包含登录脚本的执行时间）
+func (d *diagnoser) startShell(ctx context.Context, stage *DiagnoseStage) error {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+
+	session, err := d.client.NewSession()
+	if err != nil {
+		return fmt.Errorf("创建会话失败: %v", err)
+	}
+	defer session.Close()
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("获取标准输入失败: %v", err)
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("获取标准输出失败: %v", err)
+	}
+	if err := session.Shell(); err != nil {
+		return fmt.Errorf("启动 shell 失败: %v", err)
+	}
+	if _, err := fmt.Fprintf(stdin, "echo %s\nexit\n", shellReadyMarker); err != nil {
+		return fmt.Errorf("写入 shell 失败: %v", err)
+	}
+
+	ready := make(chan bool, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			// 输出前可能带有提示符，排除回显的命令本身
+			line := strings.TrimSpace(scanner.Text())
+			if strings.HasSuffix(line, shellReadyMarker) && !strings.Contains(line, "echo") {
+				ready <- true
+				return
+			}
+		}
+		ready <- false
+	}()
+
+	select {
+	case ok := <-ready:
+		if !ok {
+			return fmt.Errorf("shell 已退出，未收到命令输出")
+		}
+		stage.Message = "shell 启动正常"
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("shell 启动超时，超时时长%v", d.cfg.Timeout)
+	}
+}
+
+// checkSudo 检查是否可以免密执行 sudo，不可用时只标记为警告
+func (d *diagnoser) checkSudo(ctx context.Context, stage *DiagnoseStage) error {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+
+	client := &SSHClient{client: d.client, config: d.cfg}
+	output, code, err := client.ExecOutput(ctx, "sudo -n true")
+	if err != nil {
+		return fmt.Errorf("执行 sudo 检查失败: %v", err)
+	}
+	stage.Details["exit_code"] = strconv.Itoa(code)
+	if output = strings.TrimSpace(output); output != "" {
+		stage.Details["output"] = output
+	}
+
+	switch {
+	case code == 0:
+		stage.Message = "可以免密执行 sudo"
+	case d.cfg.Username == "root":
+		stage.Message = "root 用户，无需 sudo"
+	case code == 127:
+		stage.Status = DiagnoseWarning
+		stage.Message = "未安装 sudo"
+	default:
+		stage.Status = DiagnoseWarning
+		stage.Message = "无法免密执行 sudo，需要提权的操作将失败"
+	}
+	return nil
+}
+
+// namedAuthMethod 带名称的认证方式，名称与 SSH 协议中的方法名一致
+type namedAuthMethod struct {
+	name   string
+	method ssh.AuthMethod
+}
+
+// authMethods 按 BuildAuthMethods 的顺序为认证方式加上名称
+func (d *diagnoser) authMethods() ([]namedAuthMethod, error) {
+	methods, err := d.cfg.BuildAuthMethods()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	switch d.cfg.AuthType {
+	case AuthTypePassword:
+		names = []string{"password"}
+	case AuthTypeKey:
+		names = []string{"publickey"}
+	case AuthTypeBoth:
+		names = []string{"password", "publickey"}
+	}
+	if len(methods) == 0 || len(methods) != len(names) {
+		return nil, fmt.Errorf("未配置认证方式")
+	}
+
+	named := make([]namedAuthMethod, len(methods))
+	for i, method := range methods {
+		named[i] = namedAuthMethod{name: names[i], method: method}
+	}
+	return named, nil
+}
+
+// dialHandshake 重新连接后握手，用于验证其余认证方式
+func (d *diagnoser) dialHandshake(ctx context.Context, method ssh.AuthMethod) *handshakeResult {
+	dialer := net.Dialer{Timeout: d.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, TCpNetwork, d.address)
+	if err != nil {
+		return &handshakeResult{err: fmt.Errorf("TCP 连接失败: %v", err)}
+	}
+	return d.handshakeOn(conn, method)
+}
+
+// handshakeOn 在已建立的连接上完成 SSH 握手，失败时关闭连接
+func (d *diagnoser) handshakeOn(conn net.Conn, method ssh.AuthMethod) *handshakeResult {
+	result := &handshakeResult{}
+	start := time.Now()
+	var kexDone time.Time
+	config := &ssh.ClientConfig{
+		User: d.cfg.Username,
+		Auth: []ssh.AuthMethod{method},
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			kexDone = time.Now()
+			result.hostKey = key
+			return nil
+		},
+		BannerCallback: func(message string) error {
+			result.banner = message
+			return nil
+		},
+	}
+
+	// NewClientConn 不处理超时，通过连接的截止时间限制握手时长
+	_ = conn.SetDeadline(time.Now().Add(d.cfg.Timeout))
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, d.address, config)
+	end := time.Now()
+	if result.hostKey != nil {
+		result.kexTime = kexDone.Sub(start)
+		result.authTime = end.Sub(kexDone)
+	}
+	if err != nil {
+		_ = conn.Close()
+		result.err = err
+		return result
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	result.connMeta = sshConn
+	if meta, ok := sshConn.(ssh.AlgorithmsConnMetadata); ok {
+		result.algorithm = meta.Algorithms()
+	}
+	result.client = ssh.NewClient(sshConn, chans, reqs)
+	return result
+}
+
+// replayConn 先返回读取版本标识时已消费的数据，再继续读取底层连接
+type replayConn struct {
+	net.Conn
+	reader io.Reader
+}
+
+func (c *replayConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
@@ -0,0 +1,104 @@
+package ssh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Container docker ps 输出的容器信息
+type Container struct {
+	ID        string   `json:"id"`
+	Names     []string `json:"names"`
+	Image     string   `json:"image"`
+	State     string   `json:"state"`
+	Status    string   `json:"status"`
+	Ports     string   `json:"ports"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// dockerPsLine docker ps 每行一个 JSON 对象
+type dockerPsLine struct {
+	ID        string `json:"ID"`
+	Names     string `json:"Names"`
+	Image     string `json:"Image"`
+	State     string `json:"State"`
+	Status    string `json:"Status"`
+	Ports     string `json:"Ports"`
+	CreatedAt string `json:"CreatedAt"`
+}
+
+// containerIDPattern docker ps 输出的容器ID（短ID或完整ID）
+var containerIDPattern = regexp.MustCompile(`^[0-9a-f]{12,64}$`)
+
+// ListContainers 列出主机上运行中的容器
+// 使用 {{json .}} 而不是 --format json，兼容 23.0 之前的 docker 版本，两者输出格式相同
+func (c *SSHClient) ListContainers(ctx context.Context) ([]Container, error) {
+	output, code, err := c.ExecOutput(ctx, "docker ps --no-trunc --format '{{json .}}'")
+	if err != nil {
+		return nil, err
+	}
+	if code != 0 {
+		return nil, fmt.Errorf("docker ps 执行失败(退出码 %d): %s", code, strings.TrimSpace(output))
+	}
+
+	containers := make([]Container, 0)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var row dockerPsLine
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			return nil, fmt.Errorf("解析 docker ps 输出失败: %v", err)
+		}
+		if !containerIDPattern.MatchString(row.ID) {
+			continue
+		}
+		containers = append(containers, Container{
+			ID:        row.ID,
+			Names:     strings.Split(row.Names, ","),
+			Image:     row.Image,
+			State:     row.State,
+			Status:    row.Status,
+			Ports:     row.Ports,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return containers, nil
+}
+
+// FindContainer 按完整ID、ID前缀或名称查找运行中的容器
+func FindContainer(containers []Container, ref string) (*Container, bool) {
+	ref = strings.TrimPrefix(ref, "/")
+	if ref == "" {
+		return nil, false
+	}
+	for i := range containers {
+		for _, name := range containers[i].Names {
+			if name == ref {
+				return &containers[i], true
+			}
+		}
+	}
+	// ID 前缀需唯一匹配
+	var found *Container
+	if len(ref) >= 4 {
+		for i := range containers {
+			if strings.HasPrefix(containers[i].ID, ref) {
+				if found != nil {
+					return nil, false
+				}
+				found = &containers[i]
+			}
+		}
+	}
+	return found, found != nil
+}
+
+// DockerExecCommand 在 PTY 中进入容器的命令，容器ID只包含十六进制字符
+func DockerExecCommand(containerID string) string {
+	return "docker exec -it " + containerID + " sh"
+}
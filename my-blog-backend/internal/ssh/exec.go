@@ -0,0 +1,126 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Exec 在新的 ssh 会话中执行命令（不分配 PTY），返回远端退出码
+// ctx 取消或超时时会向远端进程发送 KILL 信号并关闭会话
+func (c *SSHClient) Exec(ctx context.Context, cmd string, stdout, stderr io.Writer) (int, error) {
+	return c.ExecInput(ctx, cmd, nil, stdout, stderr)
+}
+
+// ExecInput 与 Exec 相同，stdin 不为空时作为远端命令的标准输入
+// 用于传递密码等不应出现在命令行参数（进程列表）中的内容
+func (c *SSHClient) ExecInput(ctx context.Context, cmd string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return -1, fmt.Errorf("创建会话失败: %v", err)
+	}
+	defer session.Close()
+
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	if err := session.Start(cmd); err != nil {
+		return -1, fmt.Errorf("执行命令失败: %v", err)
+	}
+	c.UpdateLastUsed()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Wait()
+	}()
+
+	select {
+	case <-ctx.Done():
+		_ = session.Signal(ssh.SIGKILL)
+		_ = session.Close()
+		return -1, ctx.Err()
+	case err := <-done:
+		return exitCode(err)
+	}
+}
+
+// ExecOutput 执行命令并返回合并后的标准输出和标准错误
+func (c *SSHClient) ExecOutput(ctx context.Context, cmd string) (string, int, error) {
+	var buf lockedBuffer
+	code, err := c.Exec(ctx, cmd, &buf, &buf)
+	return buf.String(), code, err
+}
+
+// lockedBuffer 标准输出和标准错误由不同协程写入，共用缓冲区时需要加锁
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// exitCode 将 session.Wait 的结果转换为退出码
+func exitCode(err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus(), nil
+	}
+	var missingErr *ssh.ExitMissingError
+	if errors.As(err, &missingErr) {
+		return -1, fmt.Errorf("远端未返回退出码")
+	}
+	return -1, err
+}
+
+// WriteFile 通过 SFTP 将内容写入远端文件并设置权限
+func (c *SSHClient) WriteFile(remotePath string, content io.Reader, mode os.FileMode) (int64, error) {
+	sftpClient, err := c.GetSFTP()
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := sftpClient.OpenFile(remotePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return 0, fmt.Errorf("创建远端文件失败: %v", err)
+	}
+	defer file.Close()
+
+	written, err := io.Copy(file, content)
+	if err != nil {
+		return written, fmt.Errorf("写入远端文件失败: %v", err)
+	}
+
+	if err := sftpClient.Chmod(remotePath, mode); err != nil {
+		return written, fmt.Errorf("设置文件权限失败: %v", err)
+	}
+	return written, nil
+}
+
+// RemoveFile 通过 SFTP 删除远端文件
+func (c *SSHClient) RemoveFile(remotePath string) error {
+	sftpClient, err := c.GetSFTP()
+	if err != nil {
+		return err
+	}
+	return sftpClient.Remove(remotePath)
+}
@@ -0,0 +1,6 @@
+package ssh
+
+// CleanupIdle 供测试直接触发空闲连接清理
+func (p *Pool) CleanupIdle() {
+	p.cleanupIdle()
+}
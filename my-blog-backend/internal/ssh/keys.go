@@ -0,0 +1,219 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	KeyTypeEd25519 = "ed25519"
+	KeyTypeRSA     = "rsa"
+
+	authorizedKeysFile = ".ssh/authorized_keys"
+)
+
+// KeyPair 生成的密钥对
+type KeyPair struct {
+	PrivateKey  []byte // OpenSSH 格式私钥（PEM）
+	PublicKey   string // authorized_keys 格式公钥（含注释）
+	Fingerprint string // SHA256 指纹
+}
+
+// GenerateKeyPair 生成 ed25519 或 RSA 密钥对，bits 仅对 RSA 有效
+func GenerateKeyPair(keyType string, bits int, comment string) (*KeyPair, error) {
+	var (
+		private interface{}
+		public  interface{}
+	)
+	switch keyType {
+	case KeyTypeEd25519:
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("生成 ed25519 密钥失败: %v", err)
+		}
+		private, public = priv, pub
+	case KeyTypeRSA:
+		if bits < 2048 {
+			return nil, fmt.Errorf("RSA 密钥长度不能小于 2048")
+		}
+		priv, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, fmt.Errorf("生成 RSA 密钥失败: %v", err)
+		}
+		private, public = priv, &priv.PublicKey
+	default:
+		return nil, fmt.Errorf("不支持的密钥类型: %s", keyType)
+	}
+
+	block, err := ssh.MarshalPrivateKey(private, comment)
+	if err != nil {
+		return nil, fmt.Errorf("编码私钥失败: %v", err)
+	}
+	sshPub, err := ssh.NewPublicKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("编码公钥失败: %v", err)
+	}
+
+	authorized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+	if comment != "" {
+		authorized += " " + comment
+	}
+	return &KeyPair{
+		PrivateKey:  pem.EncodeToMemory(block),
+		PublicKey:   authorized,
+		Fingerprint: ssh.FingerprintSHA256(sshPub),
+	}, nil
+}
+
+// NewClientWithKey 使用 cfg 的地址和用户、指定私钥建立新连接（不经过连接池）
+func NewClientWithKey(ctx context.Context, cfg *Config, privateKey []byte) (*SSHClient, error) {
+	return NewClient(ctx,
+		WithHost(cfg.Host),
+		WithPort(cfg.Port),
+		WithUsername(cfg.Username),
+		WithTimeout(cfg.Timeout),
+		WithKey(privateKey),
+	)
+}
+
+// VerifyKeyLogin 使用指定私钥重新建立连接，验证公钥登录是否可用
+func VerifyKeyLogin(ctx context.Context, cfg *Config, privateKey []byte) error {
+	client, err := NewClientWithKey(ctx, cfg, privateKey)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	_, code, err := client.ExecOutput(ctx, "true")
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("验证命令退出码 %d", code)
+	}
+	return nil
+}
+
+// AddAuthorizedKey 将公钥追加到登录用户的 ~/.ssh/authorized_keys，已存在时不重复添加
+// 返回 false 表示公钥已存在
+func (c *SSHClient) AddAuthorizedKey(publicKey string) (bool, error) {
+	target, err := parseAuthorizedKey(publicKey)
+	if err != nil {
+		return false, err
+	}
+	lines, keyPath, err := c.readAuthorizedKeys()
+	if err != nil {
+		return false, err
+	}
+	for _, line := range lines {
+		if key, err := parseAuthorizedKey(line); err == nil && bytes.Equal(key.Marshal(), target.Marshal()) {
+			return false, nil
+		}
+	}
+	lines = append(lines, strings.TrimSpace(publicKey))
+	return true, c.writeAuthorizedKeys(keyPath, lines)
+}
+
+// RemoveAuthorizedKey 从 ~/.ssh/authorized_keys 中删除指定公钥，返回删除的行数
+func (c *SSHClient) RemoveAuthorizedKey(publicKey string) (int, error) {
+	target, err := parseAuthorizedKey(publicKey)
+	if err != nil {
+		return 0, err
+	}
+	lines, keyPath, err := c.readAuthorizedKeys()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if key, err := parseAuthorizedKey(line); err == nil && bytes.Equal(key.Marshal(), target.Marshal()) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	removed := len(lines) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, c.writeAuthorizedKeys(keyPath, kept)
+}
+
+// readAuthorizedKeys 读取 authorized_keys，文件不存在时返回空列表
+func (c *SSHClient) readAuthorizedKeys() ([]string, string, error) {
+	sftpClient, err := c.GetSFTP()
+	if err != nil {
+		return nil, "", err
+	}
+	home, err := sftpClient.Getwd()
+	if err != nil {
+		return nil, "", fmt.Errorf("获取用户主目录失败: %v", err)
+	}
+	keyPath := path.Join(home, authorizedKeysFile)
+
+	file, err := sftpClient.Open(keyPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, keyPath, nil
+		}
+		return nil, "", fmt.Errorf("读取 authorized_keys 失败: %v", err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("读取 authorized_keys 失败: %v", err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, keyPath, nil
+}
+
+// writeAuthorizedKeys 先写临时文件再替换，避免写入中断导致文件损坏
+func (c *SSHClient) writeAuthorizedKeys(keyPath string, lines []string) error {
+	sftpClient, err := c.GetSFTP()
+	if err != nil {
+		return err
+	}
+	if err := sftpClient.MkdirAll(path.Dir(keyPath)); err != nil {
+		return fmt.Errorf("创建 .ssh 目录失败: %v", err)
+	}
+	if err := sftpClient.Chmod(path.Dir(keyPath), 0700); err != nil {
+		return fmt.Errorf("设置 .ssh 目录权限失败: %v", err)
+	}
+
+	tmpPath := keyPath + ".ops_tmp"
+	content := strings.Join(lines, "\n") + "\n"
+	if _, err := c.WriteFile(tmpPath, strings.NewReader(content), 0600); err != nil {
+		sftpClient.Remove(tmpPath)
+		return err
+	}
+	if err := sftpClient.PosixRename(tmpPath, keyPath); err != nil {
+		sftpClient.Remove(tmpPath)
+		return fmt.Errorf("替换 authorized_keys 失败: %v", err)
+	}
+	return nil
+}
+
+func parseAuthorizedKey(line string) (ssh.PublicKey, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+	if err != nil {
+		return nil, fmt.Errorf("无效的公钥: %v", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,55 @@
+package ssh
+
+// LineBuffer 根据终端按键还原当前输入的命令行（尽力而为）
+// 只处理可见字符、退格、Ctrl+U、Ctrl+C；方向键、Tab 补全、历史命令等由远端 shell
+// 完成的编辑无法还原，转义序列会被跳过
+type LineBuffer struct {
+	buf    []byte
+	escape int // 0:普通 1:收到 ESC 2:CSI/SS3 序列中
+}
+
+// Feed 处理一个输入字节，遇到回车或换行时返回完整的一行
+func (l *LineBuffer) Feed(b byte) (string, bool) {
+	switch l.escape {
+	case 1:
+		if b == '[' || b == 'O' {
+			l.escape = 2
+		} else {
+			l.escape = 0
+		}
+		return "", false
+	case 2:
+		// 以 0x40-0x7E 之间的字节结束
+		if b >= 0x40 && b <= 0x7E {
+			l.escape = 0
+		}
+		return "", false
+	}
+
+	switch {
+	case b == '\r' || b == '\n':
+		line := string(l.buf)
+		l.buf = l.buf[:0]
+		return line, true
+	case b == 0x1B:
+		l.escape = 1
+	case b == 0x7F || b == 0x08:
+		l.backspace()
+	case b == 0x15 || b == 0x03: // Ctrl+U、Ctrl+C 清空当前行
+		l.buf = l.buf[:0]
+	case b >= 0x20 || b == '\t':
+		l.buf = append(l.buf, b)
+	}
+	return "", false
+}
+
+// backspace 删除最后一个字符（按 UTF-8 回退）
+func (l *LineBuffer) backspace() {
+	i := len(l.buf) - 1
+	for i > 0 && l.buf[i]&0xC0 == 0x80 {
+		i--
+	}
+	if i >= 0 {
+		l.buf = l.buf[:i]
+	}
+}
@@ -0,0 +1,136 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// passwdPromptTimeout 等待 passwd 提示符的最长时间
+const passwdPromptTimeout = 15 * time.Second
+
+// ChangePasswordChpasswd 使用 chpasswd 修改用户密码，密码通过标准输入传递
+// 登录用户不是 root 时使用 sudo -n（要求免密 sudo）
+func (c *SSHClient) ChangePasswordChpasswd(ctx context.Context, username, password string) error {
+	cmd := "chpasswd"
+	if c.config.Username != "root" {
+		cmd = "sudo -n chpasswd"
+	}
+	var output bytes.Buffer
+	input := strings.NewReader(username + ":" + password + "\n")
+	code, err := c.ExecInput(ctx, cmd, input, &output, &output)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("chpasswd 退出码 %d: %s", code, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
+
+// ChangePasswordPTY 在伪终端中运行 passwd 修改当前登录用户的密码
+// 以 LC_ALL=C 运行，按提示文本应答：包含 current/old 的提示回答当前密码，其余密码提示回答新密码
+func (c *SSHClient) ChangePasswordPTY(ctx context.Context, oldPassword, newPassword string) error {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return fmt.Errorf("创建会话失败: %v", err)
+	}
+	defer session.Close()
+
+	if err := session.RequestPty("dumb", 24, 80, ssh.TerminalModes{ssh.ECHO: 0}); err != nil {
+		return fmt.Errorf("设置伪终端失败: %v", err)
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := session.Start("LC_ALL=C passwd"); err != nil {
+		return fmt.Errorf("执行 passwd 失败: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, passwdPromptTimeout)
+	defer cancel()
+
+	var transcript bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1024)
+		pending := ""
+		for {
+			n, err := stdout.Read(buf)
+			if n > 0 {
+				transcript.Write(buf[:n])
+				pending += strings.ToLower(string(buf[:n]))
+				if prompt := strings.TrimRight(pending, " \r\n"); strings.Contains(prompt, "password") && strings.HasSuffix(prompt, ":") {
+					answer := newPassword
+					if strings.Contains(prompt, "current") || strings.Contains(prompt, "old") {
+						answer = oldPassword
+					}
+					if _, err := io.WriteString(stdin, answer+"\n"); err != nil {
+						done <- err
+						return
+					}
+					pending = ""
+				}
+			}
+			if err != nil {
+				done <- nil
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		_ = session.Signal(ssh.SIGKILL)
+		_ = session.Close()
+		<-done
+		return fmt.Errorf("passwd 执行超时: %s", strings.TrimSpace(transcript.String()))
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+	}
+
+	code, err := exitCode(session.Wait())
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("passwd 退出码 %d: %s", code, strings.TrimSpace(transcript.String()))
+	}
+	return nil
+}
+
+// VerifyPasswordLogin 使用指定用户名和密码重新建立连接，验证密码登录是否可用
+func VerifyPasswordLogin(ctx context.Context, cfg *Config, username, password string) error {
+	client, err := NewClient(ctx,
+		WithHost(cfg.Host),
+		WithPort(cfg.Port),
+		WithUsername(username),
+		WithTimeout(cfg.Timeout),
+		WithPassword(password),
+	)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	_, code, err := client.ExecOutput(ctx, "true")
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("验证命令退出码 %d", code)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package ssh_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"my-blog-backend/internal/ssh"
+	"my-blog-backend/internal/ssh/sshtest"
+)
+
+func TestPoolGetReusesClient(t *testing.T) {
+	server := sshtest.NewServer(t)
+	pool := ssh.NewPool(time.Minute)
+	defer pool.Close()
+
+	first, err := pool.Get(context.Background(), server.PasswordConfig(), 1)
+	if err != nil {
+		t.Fatalf("Get 失败: %v", err)
+	}
+	second, err := pool.Get(context.Background(), server.PasswordConfig(), 1)
+	if err != nil {
+		t.Fatalf("Get 失败: %v", err)
+	}
+	if first != second {
+		t.Fatal("同一主机应复用连接")
+	}
+	if first.GetHostID() != 1 {
+		t.Fatalf("hostID = %d", first.GetHostID())
+	}
+
+	other, err := pool.Get(context.Background(), server.KeyConfig(), 2)
+	if err != nil {
+		t.Fatalf("Get 失败: %v", err)
+	}
+	if other == first {
+		t.Fatal("不同主机不应共用连接")
+	}
+	if got := server.Accepted(); got != 2 {
+		t.Fatalf("服务端连接数 = %d，期望 2", got)
+	}
+	if stats := pool.Stats(); len(stats) != 2 || !stats[1] || !stats[2] {
+		t.Fatalf("Stats = %v", stats)
+	}
+}
+
+func TestPoolGetReconnectsDeadClient(t *testing.T) {
+	server := sshtest.NewServer(t)
+	pool := ssh.NewPool(time.Minute)
+	defer pool.Close()
+
+	first, err := pool.Get(context.Background(), server.PasswordConfig(), 1)
+	if err != nil {
+		t.Fatalf("Get 失败: %v", err)
+	}
+
+	server.DropConnections()
+	deadline := time.Now().Add(2 * time.Second)
+	for first.IsAlive() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	second, err := pool.Get(context.Background(), server.PasswordConfig(), 1)
+	if err != nil {
+		t.Fatalf("重连失败: %v", err)
+	}
+	if second == first {
+		t.Fatal("失效连接应被替换")
+	}
+	if !second.IsAlive() {
+		t.Fatal("新连接不可用")
+	}
+	if got := server.Accepted(); got != 2 {
+		t.Fatalf("服务端连接数 = %d，期望 2", got)
+	}
+}
+
+func TestPoolCleanup(t *testing.T) {
+	server := sshtest.NewServer(t)
+	pool := ssh.NewPool(300 * time.Millisecond)
+	defer pool.Close()
+
+	idle, err := pool.Get(context.Background(), server.PasswordConfig(), 1)
+	if err != nil {
+		t.Fatalf("Get 失败: %v", err)
+	}
+	time.Sleep(400 * time.Millisecond)
+	if _, err := pool.Get(context.Background(), server.PasswordConfig(), 2); err != nil {
+		t.Fatalf("Get 失败: %v", err)
+	}
+
+	// 只清理超过空闲时间的连接
+	pool.CleanupIdle()
+	stats := pool.Stats()
+	if _, ok := stats[1]; ok {
+		t.Fatal("空闲连接应被清理")
+	}
+	if _, ok := stats[2]; !ok {
+		t.Fatal("活跃连接不应被清理")
+	}
+	if idle.IsAlive() {
+		t.Fatal("被清理的连接应已关闭")
+	}
+
+	if err := pool.Release(2); err != nil {
+		t.Fatalf("Release 失败: %v", err)
+	}
+	if len(pool.Stats()) != 0 {
+		t.Fatal("Release 后连接池应为空")
+	}
+
+	third, err := pool.Get(context.Background(), server.PasswordConfig(), 3)
+	if err != nil {
+		t.Fatalf("Get 失败: %v", err)
+	}
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Close 失败: %v", err)
+	}
+	if third.IsAlive() || len(pool.Stats()) != 0 {
+		t.Fatal("Close 应关闭所有连接")
+	}
+}
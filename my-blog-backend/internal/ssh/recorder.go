@@ -0,0 +1,127 @@
+package ssh
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"my-blog-backend/internal/pkg/logger"
+)
+
+// Recorder 以 asciicast v2 格式录制终端输出，可用 asciinema 或 asciinema-player 回放
+// 方法在 nil 上调用时忽略，未录像的会话不需要判断
+type Recorder struct {
+	mu    sync.Mutex
+	file  *os.File
+	w     *bufio.Writer
+	start time.Time
+	tail  []byte // 上次输出末尾不完整的 UTF-8 字符，与下次输出拼接后写入
+	err   error  // 第一次写入失败的错误，之后不再写入
+}
+
+// NewRecorder 创建录像文件，目录不存在时自动创建
+func NewRecorder(path string) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("创建录像目录失败: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("创建录像文件失败: %w", err)
+	}
+	return &Recorder{file: file, w: bufio.NewWriter(file), start: time.Now()}, nil
+}
+
+// begin 写入文件头，记录终端初始大小
+func (r *Recorder) begin(cfg PtyConfig) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.start = time.Now()
+	r.writeLine(map[string]any{
+		"version":   2,
+		"width":     cfg.Cols,
+		"height":    cfg.Rows,
+		"timestamp": r.start.Unix(),
+		"env":       map[string]string{"TERM": cfg.Term},
+	})
+}
+
+// output 记录一段终端输出
+func (r *Recorder) output(data []byte) {
+	if r == nil || len(data) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data = append(r.tail, data...)
+	cut := len(data)
+	// 读缓冲区可能在多字节字符中间截断，末尾不完整的字符留到下次写入
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	r.tail = append([]byte(nil), data[cut:]...)
+	if cut > 0 {
+		r.event("o", string(data[:cut]))
+	}
+}
+
+// resize 记录终端大小变化
+func (r *Recorder) resize(rows, cols int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.event("r", fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// Close 写入剩余数据并关闭文件
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.tail) > 0 {
+		r.event("o", string(r.tail))
+		r.tail = nil
+	}
+	if err := r.w.Flush(); err != nil && r.err == nil {
+		r.err = err
+	}
+	if err := r.file.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
+
+// event 写入一条事件，时间为相对录像开始的秒数
+func (r *Recorder) event(code, data string) {
+	r.writeLine([]any{time.Since(r.start).Seconds(), code, data})
+}
+
+func (r *Recorder) writeLine(v any) {
+	if r.err != nil {
+		return
+	}
+	line, err := json.Marshal(v)
+	if err == nil {
+		_, err = r.w.Write(append(line, '\n'))
+	}
+	if err != nil {
+		r.err = err
+		logger.Error("写入终端录像失败", logger.String("file", r.file.Name()), logger.Err("error", err))
+	}
+}
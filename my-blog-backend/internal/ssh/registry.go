@@ -0,0 +1,61 @@
+package ssh
+
+import "sync"
+
+// Registry 活跃终端会话登记表，终端和 SFTP 接口共用，可按所属用户查找会话
+type Registry struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+}
+
+func NewRegistry() *Registry {
+	return &Registry{sessions: make(map[string]*Session)}
+}
+
+// Add 登记会话，相同ID的旧会话会被替换
+func (r *Registry) Add(session *Session) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[session.ID] = session
+}
+
+// Remove 移除会话，只有登记的仍是该会话时才移除，避免误删重连后的新会话
+func (r *Registry) Remove(session *Session) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sessions[session.ID] == session {
+		delete(r.sessions, session.ID)
+	}
+}
+
+// Get 按会话ID查找
+func (r *Registry) Get(id string) (*Session, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	session, ok := r.sessions[id]
+	return session, ok
+}
+
+// List 返回所有会话
+func (r *Registry) List() []*Session {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sessions := make([]*Session, 0, len(r.sessions))
+	for _, session := range r.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// ByUser 返回用户的所有会话
+func (r *Registry) ByUser(userID uint) []*Session {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var sessions []*Session
+	for _, session := range r.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
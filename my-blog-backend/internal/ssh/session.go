@@ -6,6 +6,7 @@ import (
 	"log"
 	"my-blog-backend/internal/pkg/logger"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -22,17 +23,56 @@ type Session struct {
 	InputChan     chan []byte
 	OutputChan    chan []byte
 	FlushChan     chan struct{} // 新增：用于立即刷新批量缓冲区
+	FrameChan     chan Frame    // 需要按顺序发送的二进制帧和控制消息（rz/sz 传输）
 	Done          chan struct{}
 	mu            sync.Mutex
 	wg            sync.WaitGroup
 	active        bool
-	lastInputTime time.Time // 新增：记录最后输入时间
+	lastInputTime time.Time     // 新增：记录最后输入时间
+	IdleTimeout   time.Duration // 无输入超时时间，超时后断开连接
+	UserID        uint          // 会话所属用户，用于账号禁用、登出时强制断开
+	Container     *Container    // 容器终端对应的容器，主机终端为 nil
+	Meta          SessionMeta   // 会话列表和历史记录展示的信息
+	Recorder      *Recorder     // 会话录像，未录像时为 nil
+	StartTime     time.Time
+	bytesIn       atomic.Int64 // 写入远端的字节数
+	bytesOut      atomic.Int64 // 远端输出的字节数
+	closeReason   string       // 会话被超时或强制断开的原因
+
+	// OutputMask 输出脱敏，脱敏后的输出用于日志；MaskLiveView 为 true 时浏览器也只收到脱敏后的输出
+	OutputMask   OutputFilter
+	MaskLiveView bool
+
+	DisableTransfer bool             // 禁用 rz/sz（如广播终端无法转发二进制帧），检测到握手时直接取消
+	MaxTransferSize int64            // rz/sz 单个文件大小限制，0 表示不限制
+	OnFileTransfer  func(ZmodemFile) // rz/sz 文件传输结束回调，用于审计
+	zmu             sync.Mutex
+	zmodem          *zmodemTransfer
+	ztail           []byte // 上一次普通输出的末尾，用于识别被拆开的握手
 }
 
+// OutputFilter 处理远端的普通输出，more 表示读缓冲区已满、后续还有数据，可暂存不完整的行
+type OutputFilter func(data []byte, more bool) []byte
+
+// SessionMeta 会话元数据，由建立会话的接口填写
+type SessionMeta struct {
+	UserName    string
+	HostName    string
+	HostAddress string
+	HostAccount string // 登录主机使用的账号
+	ClientIP    string
+	ClientAgent string
+	RecordingID string // 会话录像ID，未录像时为空
+}
+
+// terminateTimeout 强制结束会话时等待提示发送的时间
+const terminateTimeout = 3 * time.Second
+
 type PtyConfig struct {
-	Term string
-	Rows int
-	Cols int
+	Term    string
+	Rows    int
+	Cols    int
+	Command string // 在 PTY 中执行的命令（如进入容器），为空时启动登录 shell
 }
 
 func NewSession(client *SSHClient, conn *websocket.Conn, id string) *Session {
@@ -43,9 +83,12 @@ func NewSession(client *SSHClient, conn *websocket.Conn, id string) *Session {
 		InputChan:     make(chan []byte, 2048),
 		OutputChan:    make(chan []byte, 65536), // 增加到 64KB 缓冲区
 		FlushChan:     make(chan struct{}, 10),  // 用于立即刷新批量缓冲区，增加容量避免阻塞
+		FrameChan:     make(chan Frame, 256),
 		Done:          make(chan struct{}),
 		active:        true,
 		lastInputTime: time.Now(), // 初始化为当前时间
+		IdleTimeout:   5 * time.Minute,
+		StartTime:     time.Now(),
 	}
 }
 
@@ -75,6 +118,8 @@ func (s *Session) Start(cfg PtyConfig) error {
 		return fmt.Errorf("设置伪终端失败: %v", err)
 	}
 
+	s.Recorder.begin(cfg)
+
 	// 获取标准输入用于写入命令
 	s.stdin, err = session.StdinPipe()
 	if err != nil {
@@ -99,7 +144,13 @@ func (s *Session) Start(cfg PtyConfig) error {
 	s.outputReader = io.MultiReader(stdout, stderr)
 
 	// 启动会话
-	if err3 := session.Shell(); err3 != nil {
+	var err3 error
+	if cfg.Command != "" {
+		err3 = session.Start(cfg.Command)
+	} else {
+		err3 = session.Shell()
+	}
+	if err3 != nil {
 		session.Close()
 		return fmt.Errorf("启动会话失败: %v", err3)
 	}
@@ -137,7 +188,9 @@ func (s *Session) HandleInput() {
 			if len(data) > 10 {
 				log.Printf("SSH input [%d]: %d bytes, data: %q", inputCount, len(data), string(data))
 			}
-			if _, err := s.stdin.Write(data); err != nil {
+			n, err := s.stdin.Write(data)
+			s.bytesIn.Add(int64(n))
+			if err != nil {
 				log.Printf("SSH input write error: %v", err)
 				// SSH 写入失败，立即关闭整个会话
 				s.Close()
@@ -150,13 +203,16 @@ func (s *Session) HandleInput() {
 	}
 }
 
-// 检测输入超时，超过 5 分钟无输入则断开连接
+// 检测输入超时，超过 IdleTimeout（默认 5 分钟）无输入则断开连接
 func (s *Session) detectInputTimeout() {
-	ticker := time.NewTicker(60 * time.Second) // 每分钟检查一次
+	timeoutDuration := s.IdleTimeout
+	checkInterval := 60 * time.Second // 默认每分钟检查一次，超时时间较短时相应缩短
+	if timeoutDuration/5 < checkInterval {
+		checkInterval = timeoutDuration / 5
+	}
+	ticker := time.NewTicker(checkInterval)
 	defer ticker.Stop()
 
-	timeoutDuration := 5 * time.Minute // 5 分钟超时
-
 	for {
 		select {
 		case <-s.Done:
@@ -172,6 +228,7 @@ func (s *Session) detectInputTimeout() {
 			// 超过 5 分钟无输入，断开连接
 			if timeSinceLastInput > timeoutDuration {
 				log.Printf("Session %s timeout after %v of inactivity, closing connection", s.ID, timeoutDuration)
+				s.setCloseReason(fmt.Sprintf("%d 分钟无操作，会话超时", int(timeoutDuration.Minutes())))
 
 				// 发送超时消息到前端
 				if s.WsConn != nil {
@@ -200,21 +257,43 @@ func (s *Session) handleOutput() {
 	for {
 		// 阻塞读取数据，直到有数据或连接关闭
 		n, err := s.outputReader.Read(buf)
+		s.bytesOut.Add(int64(n))
 		if err != nil {
 			if err != io.EOF {
 				log.Printf("SSH output read error for session %s: %v", s.ID, err)
 			}
+			// 远端 shell 已退出，关闭会话使浏览器端随之断开
+			go s.Close()
 			return
 		}
 
 		if n > 0 {
+			// rz/sz 传输的数据单独发送，剩余部分按普通输出处理
+			more := n == len(buf)
+			text := s.zmodemOutput(buf[:n])
+			if len(text) == 0 {
+				continue
+			}
+			masked := text
+			if s.OutputMask != nil {
+				masked = s.OutputMask(text, more)
+			}
+			// 录像与日志一样只保存脱敏后的输出
+			s.Recorder.output(masked)
+			if s.MaskLiveView {
+				text = masked
+				if len(text) == 0 {
+					continue
+				}
+			}
+			n = len(text)
 			output := make([]byte, n)
-			copy(output, buf[:n])
+			copy(output, text)
 			outputCount++
 
-			// 只在数据较小时记录日志，避免长文本日志淹没
+			// 只在数据较小时记录日志，避免长文本日志淹没；日志只记录脱敏后的内容
 			if n <= 100 {
-				log.Printf("SSH output [%d]: %d bytes, content: %q", outputCount, n, string(output))
+				log.Printf("SSH output [%d]: %d bytes, content: %q", outputCount, n, string(masked))
 			} else {
 				log.Printf("SSH output [%d]: %d bytes (truncated)", outputCount, n)
 			}
@@ -242,7 +321,11 @@ func (s *Session) ReSize(rows, cols int) error {
 		return fmt.Errorf("ssh会话未初始化")
 	}
 
-	return s.SSHClient.WindowChange(rows, cols)
+	if err := s.SSHClient.WindowChange(rows, cols); err != nil {
+		return err
+	}
+	s.Recorder.resize(rows, cols)
+	return nil
 }
 
 func (s *Session) Close() error {
@@ -271,7 +354,9 @@ func (s *Session) Close() error {
 
 	// 关闭 InputChan
 	close(s.InputChan)
-	s.WsConn.Close()
+	if s.WsConn != nil {
+		s.WsConn.Close()
+	}
 
 	// 等待所有协程退出
 	s.wg.Wait()
@@ -281,9 +366,83 @@ func (s *Session) Close() error {
 		s.SSHClient.Close()
 	}
 
+	s.abortTransferOnClose()
+
+	if err := s.Recorder.Close(); err != nil {
+		log.Printf("Close recording for session %s error: %v", s.ID, err)
+	}
+
 	return nil
 }
 
+// Terminate 强制结束会话，先把原因显示在浏览器终端中再断开
+// 提示由写协程发送后关闭会话，写协程未运行或阻塞时超时直接关闭
+func (s *Session) Terminate(reason string) {
+	s.setCloseReason(reason)
+	s.CancelTransfer(reason)
+
+	msg := fmt.Sprintf("\r\n\033[31m[会话已终止] %s\033[0m\r\n", reason)
+	frame := Frame{Type: websocket.TextMessage, Data: []byte(msg), Close: true}
+	timeout := time.After(terminateTimeout)
+	select {
+	case s.FrameChan <- frame:
+	case <-s.Done:
+		return
+	case <-timeout:
+		s.Close()
+		return
+	}
+	select {
+	case <-s.Done:
+	case <-timeout:
+		s.Close()
+	}
+}
+
+// setCloseReason 记录会话断开原因，只保留第一次设置的原因
+func (s *Session) setCloseReason(reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closeReason == "" {
+		s.closeReason = reason
+	}
+}
+
+// CloseReason 会话被超时或强制断开的原因，正常退出时为空
+func (s *Session) CloseReason() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeReason
+}
+
+// LastInputTime 最后一次输入时间
+func (s *Session) LastInputTime() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastInputTime
+}
+
+// Traffic 返回写入远端和远端输出的字节数
+func (s *Session) Traffic() (in, out int64) {
+	return s.bytesIn.Load(), s.bytesOut.Load()
+}
+
+// SendInput 发送输入到远端，会话已关闭或输入通道已满时返回 false
+// 与 Close 持有同一把锁，不会向已关闭的 InputChan 发送
+func (s *Session) SendInput(data []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.active {
+		return false
+	}
+	select {
+	case s.InputChan <- data:
+		return true
+	default:
+		return false
+	}
+}
+
 func (s *Session) IsActive() bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
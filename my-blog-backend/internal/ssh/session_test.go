@@ -0,0 +1,215 @@
+package ssh_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"my-blog-backend/internal/ssh"
+	"my-blog-backend/internal/ssh/sshtest"
+
+	"github.com/gorilla/websocket"
+)
+
+// startSession 建立连接并启动终端会话，返回会话和模拟浏览器的 WebSocket 连接
+func startSession(t *testing.T, server *sshtest.Server, pty ssh.PtyConfig, setup func(*ssh.Session)) (*ssh.Session, *websocket.Conn) {
+	t.Helper()
+	client := newClient(t, server.PasswordConfig())
+	wsServer, wsClient := sshtest.WebSocketPair(t)
+
+	session := ssh.NewSession(client, wsServer, "test-session")
+	if setup != nil {
+		setup(session)
+	}
+	if err := session.Start(pty); err != nil {
+		t.Fatalf("启动会话失败: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return session, wsClient
+}
+
+// waitOutput 读取会话输出直到包含 want
+func waitOutput(t *testing.T, session *ssh.Session, want string) string {
+	t.Helper()
+	var output strings.Builder
+	timeout := time.After(5 * time.Second)
+	for !strings.Contains(output.String(), want) {
+		select {
+		case data := <-session.OutputChan:
+			output.Write(data)
+		case <-timeout:
+			t.Fatalf("等待输出 %q 超时，已收到 %q", want, output.String())
+		}
+	}
+	return output.String()
+}
+
+func TestSessionStartClose(t *testing.T) {
+	server := sshtest.NewServer(t)
+	session, _ := startSession(t, server, ssh.PtyConfig{Term: "xterm", Rows: 24, Cols: 80}, nil)
+
+	waitOutput(t, session, sshtest.Prompt)
+	session.InputChan <- []byte("echo hello\r")
+	output := waitOutput(t, session, "hello\r\n")
+	if !strings.Contains(output, "echo hello") {
+		t.Fatalf("PTY 应回显输入，实际输出 %q", output)
+	}
+
+	ptys := server.PtyRequests()
+	if len(ptys) != 1 || ptys[0] != (sshtest.PtyRequest{Term: "xterm", Cols: 80, Rows: 24}) {
+		t.Fatalf("PTY 请求 = %+v", ptys)
+	}
+
+	bytesIn, bytesOut := session.Traffic()
+	if bytesIn != int64(len("echo hello\r")) || bytesOut < int64(len(output)) {
+		t.Fatalf("流量统计 in=%d out=%d，输出 %d 字节", bytesIn, bytesOut, len(output))
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		_ = session.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close 未返回")
+	}
+
+	if session.IsActive() {
+		t.Fatal("关闭后会话不应处于活跃状态")
+	}
+	select {
+	case <-session.Done:
+	default:
+		t.Fatal("关闭后 Done 应已关闭")
+	}
+	if err := session.Close(); err != nil {
+		t.Fatalf("重复关闭应返回 nil: %v", err)
+	}
+	if err := session.Start(ssh.PtyConfig{Term: "xterm", Rows: 24, Cols: 80}); err == nil {
+		t.Fatal("关闭后不能再次启动")
+	}
+}
+
+func TestSessionCommand(t *testing.T) {
+	server := sshtest.NewServer(t)
+	session, _ := startSession(t, server, ssh.PtyConfig{Term: "xterm", Rows: 24, Cols: 80, Command: "echo from command"}, nil)
+
+	waitOutput(t, session, "from command\r\n")
+	commands := server.Commands()
+	if len(commands) != 1 || commands[0] != "echo from command" {
+		t.Fatalf("执行的命令 = %v", commands)
+	}
+	if len(server.PtyRequests()) != 1 {
+		t.Fatal("执行命令时也应分配 PTY")
+	}
+}
+
+func TestSessionResize(t *testing.T) {
+	server := sshtest.NewServer(t)
+	session, _ := startSession(t, server, ssh.PtyConfig{Term: "xterm", Rows: 24, Cols: 80}, nil)
+
+	if err := session.ReSize(40, 120); err != nil {
+		t.Fatalf("ReSize 失败: %v", err)
+	}
+	select {
+	case window := <-server.WindowChanges():
+		if window != (sshtest.Window{Cols: 120, Rows: 40}) {
+			t.Fatalf("窗口大小 = %+v", window)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("服务端未收到窗口大小变更")
+	}
+
+	unstarted := ssh.NewSession(nil, nil, "unstarted")
+	if err := unstarted.ReSize(40, 120); err == nil {
+		t.Fatal("未启动的会话调整窗口应返回错误")
+	}
+}
+
+func TestSessionIdleTimeout(t *testing.T) {
+	server := sshtest.NewServer(t)
+	session, wsClient := startSession(t, server, ssh.PtyConfig{Term: "xterm", Rows: 24, Cols: 80}, func(s *ssh.Session) {
+		s.IdleTimeout = 300 * time.Millisecond
+	})
+
+	select {
+	case <-session.Done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("超时未断开会话")
+	}
+
+	_ = wsClient.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, message, err := wsClient.ReadMessage()
+	if err != nil {
+		t.Fatalf("读取超时消息失败: %v", err)
+	}
+	if !strings.Contains(string(message), "会话超时") {
+		t.Fatalf("超时消息 = %q", message)
+	}
+	if session.IsActive() {
+		t.Fatal("超时后会话不应处于活跃状态")
+	}
+}
+
+func TestSessionRecording(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recordings", "test.cast")
+	recorder, err := ssh.NewRecorder(path)
+	if err != nil {
+		t.Fatalf("创建录像失败: %v", err)
+	}
+	server := sshtest.NewServer(t)
+	session, _ := startSession(t, server, ssh.PtyConfig{Term: "xterm", Rows: 24, Cols: 80}, func(s *ssh.Session) {
+		s.Recorder = recorder
+		s.OutputMask = func(data []byte, more bool) []byte {
+			return []byte(strings.ReplaceAll(string(data), "secret", "******"))
+		}
+	})
+
+	waitOutput(t, session, sshtest.Prompt)
+	session.InputChan <- []byte("echo 你好 secret\r")
+	waitOutput(t, session, "你好 secret\r\n")
+	if err := session.ReSize(40, 120); err != nil {
+		t.Fatalf("ReSize 失败: %v", err)
+	}
+	_ = session.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取录像失败: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var header struct {
+		Version int `json:"version"`
+		Width   int `json:"width"`
+		Height  int `json:"height"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil || header.Version != 2 || header.Width != 80 || header.Height != 24 {
+		t.Fatalf("录像文件头 = %s, err=%v", lines[0], err)
+	}
+
+	var output strings.Builder
+	var resized bool
+	for _, line := range lines[1:] {
+		var event []any
+		if err := json.Unmarshal([]byte(line), &event); err != nil || len(event) != 3 {
+			t.Fatalf("无效的录像事件 %s: %v", line, err)
+		}
+		switch event[1] {
+		case "o":
+			output.WriteString(event[2].(string))
+		case "r":
+			resized = event[2] == "120x40"
+		}
+	}
+	if !strings.Contains(output.String(), "你好 ******\r\n") || strings.Contains(output.String(), "secret") {
+		t.Fatalf("录像应保存脱敏后的输出，实际 %q", output.String())
+	}
+	if !resized {
+		t.Fatal("录像应记录窗口大小变更")
+	}
+}
@@ -0,0 +1,471 @@
+// Package sshtest 提供进程内的 SSH/SFTP 测试服务器
+// 支持密码和密钥认证、脚本化的 shell 与 exec、PTY 和窗口大小变更记录，以及基于内存文件系统的 SFTP 子系统
+package sshtest
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	internalssh "my-blog-backend/internal/ssh"
+)
+
+const (
+	// DefaultUser 测试服务器的用户名
+	DefaultUser = "tester"
+	// DefaultPassword 测试服务器的密码
+	DefaultPassword = "secret"
+	// Prompt shell 提示符
+	Prompt = "$ "
+)
+
+// Handler 执行一条命令，返回退出码；ctx 在客户端发送信号或关闭会话时取消
+type Handler func(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) int
+
+// PtyRequest 客户端请求的伪终端
+type PtyRequest struct {
+	Term string
+	Cols uint32
+	Rows uint32
+}
+
+// Window 窗口大小变更
+type Window struct {
+	Cols uint32
+	Rows uint32
+}
+
+// Server 进程内 SSH 服务器，测试结束时自动关闭
+type Server struct {
+	Host       string
+	Port       uint
+	User       string
+	Password   string
+	PrivateKey []byte // 已授权的客户端私钥（OpenSSH PEM）
+
+	listener  net.Listener
+	config    *ssh.ServerConfig
+	publicKey ssh.PublicKey
+	fs        sftp.Handlers
+	handlers  map[string]Handler
+	windows   chan Window
+
+	mu       sync.Mutex
+	conns    []*ssh.ServerConn
+	accepted int
+	ptys     []PtyRequest
+	commands []string
+	closed   bool
+}
+
+// NewServer 在 127.0.0.1 的随机端口启动服务器
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("生成主机密钥失败: %v", err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatalf("创建主机密钥失败: %v", err)
+	}
+	clientPub, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("生成客户端密钥失败: %v", err)
+	}
+	block, err := ssh.MarshalPrivateKey(clientKey, "")
+	if err != nil {
+		t.Fatalf("编码客户端密钥失败: %v", err)
+	}
+	publicKey, err := ssh.NewPublicKey(clientPub)
+	if err != nil {
+		t.Fatalf("创建客户端公钥失败: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听端口失败: %v", err)
+	}
+	addr := listener.Addr().(*net.TCPAddr)
+
+	s := &Server{
+		Host:       "127.0.0.1",
+		Port:       uint(addr.Port),
+		User:       DefaultUser,
+		Password:   DefaultPassword,
+		PrivateKey: pem.EncodeToMemory(block),
+		listener:   listener,
+		publicKey:  publicKey,
+		fs:         sftp.InMemHandler(),
+		handlers:   defaultHandlers(),
+		windows:    make(chan Window, 16),
+	}
+	s.config = &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == s.User && string(password) == s.Password {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("密码错误")
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == s.User && string(key.Marshal()) == string(s.publicKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("公钥未授权")
+		},
+	}
+	s.config.AddHostKey(hostSigner)
+
+	go s.serve()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// PasswordConfig 使用密码认证连接本服务器的配置
+func (s *Server) PasswordConfig() *internalssh.Config {
+	return &internalssh.Config{
+		Host:     s.Host,
+		Port:     s.Port,
+		Username: s.User,
+		Password: s.Password,
+		AuthType: internalssh.AuthTypePassword,
+		Timeout:  5 * time.Second,
+	}
+}
+
+// KeyConfig 使用密钥认证连接本服务器的配置
+func (s *Server) KeyConfig() *internalssh.Config {
+	return &internalssh.Config{
+		Host:     s.Host,
+		Port:     s.Port,
+		Username: s.User,
+		Key:      s.PrivateKey,
+		AuthType: internalssh.AuthTypeKey,
+		Timeout:  5 * time.Second,
+	}
+}
+
+// Handle 注册或覆盖命令，按命令行的第一个单词匹配
+func (s *Server) Handle(name string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[name] = handler
+}
+
+// Accepted 已认证成功的连接数
+func (s *Server) Accepted() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted
+}
+
+// PtyRequests 收到的伪终端请求
+func (s *Server) PtyRequests() []PtyRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]PtyRequest(nil), s.ptys...)
+}
+
+// Commands 通过 exec 或 shell 执行过的命令
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+// WindowChanges 收到的窗口大小变更
+func (s *Server) WindowChanges() <-chan Window {
+	return s.windows
+}
+
+// DropConnections 断开所有已建立的连接，模拟网络中断或服务端重启
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = nil
+	s.mu.Unlock()
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+}
+
+// Close 停止监听并断开所有连接
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	_ = s.listener.Close()
+	s.DropConnections()
+}
+
+func (s *Server) serve() {
+	for {
+		nc, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleConn(nc)
+	}
+}
+
+func (s *Server) handleConn(nc net.Conn) {
+	conn, chans, reqs, err := ssh.NewServerConn(nc, s.config)
+	if err != nil {
+		_ = nc.Close()
+		return
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = conn.Close()
+		return
+	}
+	s.accepted++
+	s.conns = append(s.conns, conn)
+	s.mu.Unlock()
+
+	// keepalive 等全局请求统一回复失败，客户端只关心是否收到回复
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "只支持 session")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go s.handleSession(channel, requests)
+	}
+}
+
+// handleSession 处理一个 session 通道上的请求
+func (s *Server) handleSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pty := false
+	for req := range requests {
+		switch req.Type {
+		case "pty-req":
+			var payload struct {
+				Term          string
+				Cols, Rows    uint32
+				Width, Height uint32
+				Modes         string
+			}
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			pty = true
+			s.mu.Lock()
+			s.ptys = append(s.ptys, PtyRequest{Term: payload.Term, Cols: payload.Cols, Rows: payload.Rows})
+			s.mu.Unlock()
+			_ = req.Reply(true, nil)
+		case "window-change":
+			var payload struct {
+				Cols, Rows    uint32
+				Width, Height uint32
+			}
+			if err := ssh.Unmarshal(req.Payload, &payload); err == nil {
+				select {
+				case s.windows <- Window{Cols: payload.Cols, Rows: payload.Rows}:
+				default:
+				}
+			}
+			_ = req.Reply(true, nil)
+		case "env":
+			_ = req.Reply(true, nil)
+		case "shell":
+			_ = req.Reply(true, nil)
+			go s.runShell(ctx, channel, pty)
+		case "exec":
+			var payload struct{ Command string }
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			_ = req.Reply(true, nil)
+			go s.runExec(ctx, channel, payload.Command, pty)
+		case "subsystem":
+			var payload struct{ Name string }
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil || payload.Name != "sftp" {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			_ = req.Reply(true, nil)
+			go func() {
+				server := sftp.NewRequestServer(channel, s.fs)
+				_ = server.Serve()
+				_ = server.Close()
+			}()
+		case "signal":
+			// 任何信号都终止正在执行的命令
+			cancel()
+			_ = req.Reply(true, nil)
+		default:
+			_ = req.Reply(false, nil)
+		}
+	}
+}
+
+// runExec 执行一条命令后发送退出码并关闭通道
+func (s *Server) runExec(ctx context.Context, channel ssh.Channel, command string, pty bool) {
+	var stdout io.Writer = channel
+	if pty {
+		stdout = crlfWriter{channel}
+	}
+	code := s.run(ctx, command, channel, stdout)
+	sendExit(channel, code)
+}
+
+// runShell 模拟交互式 shell：回显输入、按行执行命令，exit 或标准输入结束时退出
+func (s *Server) runShell(ctx context.Context, channel ssh.Channel, pty bool) {
+	var out io.Writer = channel
+	if pty {
+		out = crlfWriter{channel}
+	}
+	reader := bufio.NewReader(channel)
+	var line []byte
+
+	_, _ = io.WriteString(out, Prompt)
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			sendExit(channel, 0)
+			return
+		}
+
+		switch {
+		case b == '\r' || b == '\n':
+			if pty {
+				_, _ = io.WriteString(out, "\n")
+			}
+			command := strings.TrimSpace(string(line))
+			line = line[:0]
+			if fields := strings.Fields(command); len(fields) > 0 && fields[0] == "exit" {
+				code := 0
+				if len(fields) > 1 {
+					code, _ = strconv.Atoi(fields[1])
+				}
+				sendExit(channel, code)
+				return
+			}
+			if command != "" {
+				s.run(ctx, command, strings.NewReader(""), out)
+			}
+			_, _ = io.WriteString(out, Prompt)
+		case b == 0x03: // Ctrl+C
+			line = line[:0]
+			_, _ = io.WriteString(out, "^C\n"+Prompt)
+		case b == 0x15: // Ctrl+U
+			line = line[:0]
+		case b == 0x7f || b == 0x08:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+				if pty {
+					_, _ = io.WriteString(out, "\b \b")
+				}
+			}
+		default:
+			line = append(line, b)
+			if pty {
+				_, _ = out.Write([]byte{b})
+			}
+		}
+	}
+}
+
+// run 按第一个单词查找命令并执行
+func (s *Server) run(ctx context.Context, command string, stdin io.Reader, stdout io.Writer) int {
+	s.mu.Lock()
+	s.commands = append(s.commands, command)
+	fields := strings.Fields(command)
+	var handler Handler
+	if len(fields) > 0 {
+		handler = s.handlers[fields[0]]
+	}
+	s.mu.Unlock()
+
+	if len(fields) == 0 {
+		return 0
+	}
+	if handler == nil {
+		_, _ = fmt.Fprintf(stdout, "sh: %s: command not found\n", fields[0])
+		return 127
+	}
+	return handler(ctx, fields[1:], stdin, stdout)
+}
+
+func sendExit(channel ssh.Channel, code int) {
+	payload := ssh.Marshal(struct{ Status uint32 }{uint32(code)})
+	_, _ = channel.SendRequest("exit-status", false, payload)
+	_ = channel.Close()
+}
+
+// defaultHandlers 内置命令：echo、sleep、cat、pwd、false
+func defaultHandlers() map[string]Handler {
+	return map[string]Handler{
+		"echo": func(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) int {
+			_, _ = fmt.Fprintln(stdout, strings.Join(args, " "))
+			return 0
+		},
+		"sleep": func(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) int {
+			seconds := 1.0
+			if len(args) > 0 {
+				seconds, _ = strconv.ParseFloat(args[0], 64)
+			}
+			select {
+			case <-time.After(time.Duration(seconds * float64(time.Second))):
+				return 0
+			case <-ctx.Done():
+				return 130
+			}
+		},
+		"cat": func(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) int {
+			_, _ = io.Copy(stdout, stdin)
+			return 0
+		},
+		"pwd": func(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) int {
+			_, _ = fmt.Fprintln(stdout, "/home/"+DefaultUser)
+			return 0
+		},
+		"false": func(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) int {
+			return 1
+		},
+	}
+}
+
+// crlfWriter 模拟 PTY 输出：把 \n 转换为 \r\n
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write([]byte(strings.ReplaceAll(string(p), "\n", "\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
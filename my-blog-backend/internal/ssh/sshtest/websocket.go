@@ -0,0 +1,42 @@
+package sshtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketPair 建立一对相连的 WebSocket 连接，server 端交给 ssh.Session，client 端模拟浏览器
+func WebSocketPair(t testing.TB) (server *websocket.Conn, client *websocket.Conn) {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	serverConn := make(chan *websocket.Conn, 1)
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		serverConn <- conn
+	}))
+	t.Cleanup(httpServer.Close)
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("建立 WebSocket 连接失败: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	select {
+	case server = <-serverConn:
+	case <-time.After(5 * time.Second):
+		t.Fatal("等待 WebSocket 服务端连接超时")
+	}
+	t.Cleanup(func() { _ = server.Close() })
+	return server, client
+}
@@ -0,0 +1,302 @@
+package ssh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"hash/crc32"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ZMODEM 协议常量（只解析审计和限额需要的部分，协议本身由浏览器端 zmodem.js 完成）
+const (
+	zPad   = '*'
+	zDle   = 0x18 // 与 CAN 相同
+	zBin   = 'A'
+	zHex   = 'B'
+	zBin32 = 'C'
+
+	zFile = 4
+	zSkip = 5
+	zFin  = 8
+
+	// 数据子包最多解析的字节数，ZFILE 子包只有文件名和文件信息
+	zMaxSubpacket = 2048
+)
+
+var (
+	// sz 启动时发送 ZRQINIT，rz 启动时发送 ZRINIT，两者前 5 个字节相同
+	zmodemMarkerPrefix   = []byte("**\x18B0")
+	zmodemDownloadMarker = []byte("**\x18B00")
+	zmodemUploadMarker   = []byte("**\x18B01")
+	// zmodemAbort 取消传输：连续 CAN 后跟退格清除回显
+	zmodemAbort = []byte("\x18\x18\x18\x18\x18\x18\x18\x18\x08\x08\x08\x08\x08\x08\x08\x08")
+)
+
+// ZmodemDirection 传输方向
+type ZmodemDirection string
+
+const (
+	ZmodemUpload   ZmodemDirection = "upload"   // 浏览器 -> 远端（rz）
+	ZmodemDownload ZmodemDirection = "download" // 远端 -> 浏览器（sz）
+)
+
+// ZmodemStatus 单个文件的传输结果
+type ZmodemStatus string
+
+const (
+	ZmodemSuccess  ZmodemStatus = "success"
+	ZmodemFailed   ZmodemStatus = "failed"
+	ZmodemRejected ZmodemStatus = "rejected" // 超过大小限制
+	ZmodemSkipped  ZmodemStatus = "skipped"  // 接收方跳过
+)
+
+// ZmodemFile 一个文件的传输记录，传输结束后通过 Session.OnFileTransfer 回调用于审计
+type ZmodemFile struct {
+	Direction   ZmodemDirection
+	Name        string
+	Size        int64 // 发送方声明的文件大小
+	Transferred int64 // 实际传输的原始字节数（含协议开销）
+	Status      ZmodemStatus
+	Message     string
+	StartTime   time.Time
+	EndTime     time.Time
+}
+
+// findZmodemStart 查找 rz/sz 握手，返回握手开始的位置和传输方向
+func findZmodemStart(data []byte) (int, ZmodemDirection) {
+	idx := bytes.Index(data, zmodemMarkerPrefix)
+	for idx >= 0 {
+		rest := data[idx:]
+		if bytes.HasPrefix(rest, zmodemDownloadMarker) {
+			return idx, ZmodemDownload
+		}
+		if bytes.HasPrefix(rest, zmodemUploadMarker) {
+			return idx, ZmodemUpload
+		}
+		if len(rest) < len(zmodemDownloadMarker) {
+			break
+		}
+		next := bytes.Index(rest[1:], zmodemMarkerPrefix)
+		if next < 0 {
+			break
+		}
+		idx += next + 1
+	}
+	return -1, ""
+}
+
+type zmodemEventKind int
+
+const (
+	zEventFile  zmodemEventKind = iota + 1 // ZFILE 头及文件信息
+	zEventSkip                             // ZSKIP
+	zEventFin                              // ZFIN
+	zEventOver                             // "OO"
+	zEventAbort                            // 连续 CAN
+)
+
+type zmodemEvent struct {
+	kind zmodemEventKind
+	end  int // 事件最后一个字节之后的位置
+	name string
+	size int64
+}
+
+const (
+	zsScan = iota
+	zsFormat
+	zsHeader
+	zsHexHeader
+	zsSubpacket
+)
+
+// zmodemSniffer 从单向 ZMODEM 数据流中识别帧头，头部校验 CRC 避免把文件内容误判为帧头
+type zmodemSniffer struct {
+	state   int
+	format  byte
+	header  []byte
+	sub     []byte
+	escaped bool
+	pads    int
+	cans    int
+	prevO   bool
+}
+
+// Feed 处理一段数据，返回识别到的事件
+func (z *zmodemSniffer) Feed(data []byte) []zmodemEvent {
+	var events []zmodemEvent
+	for i, b := range data {
+		if b == zDle {
+			z.cans++
+			if z.cans >= 5 {
+				z.reset()
+				events = append(events, zmodemEvent{kind: zEventAbort, end: i + 1})
+				continue
+			}
+		} else {
+			z.cans = 0
+		}
+
+		switch z.state {
+		case zsScan:
+			if b == 'O' && z.prevO {
+				events = append(events, zmodemEvent{kind: zEventOver, end: i + 1})
+			}
+			z.prevO = b == 'O'
+			switch {
+			case b == zPad:
+				z.pads++
+			case b == zDle && z.pads > 0:
+				z.state = zsFormat
+			default:
+				z.pads = 0
+			}
+		case zsFormat:
+			z.pads = 0
+			z.header = z.header[:0]
+			z.escaped = false
+			switch b {
+			case zBin, zBin32:
+				z.format = b
+				z.state = zsHeader
+			case zHex:
+				z.format = b
+				z.state = zsHexHeader
+			default:
+				z.state = zsScan
+			}
+		case zsHexHeader:
+			z.header = append(z.header, b)
+			if len(z.header) < 14 {
+				continue
+			}
+			raw := make([]byte, 7)
+			if _, err := hex.Decode(raw, z.header); err != nil {
+				z.state = zsScan
+				continue
+			}
+			if crc16(raw[:5]) != binary.BigEndian.Uint16(raw[5:]) {
+				z.state = zsScan
+				continue
+			}
+			events = z.onHeader(raw[0], i, events)
+		case zsHeader:
+			v, ok, _ := z.decode(b)
+			if !ok {
+				continue
+			}
+			z.header = append(z.header, v)
+			size := 7
+			if z.format == zBin32 {
+				size = 9
+			}
+			if len(z.header) < size {
+				continue
+			}
+			valid := false
+			if z.format == zBin32 {
+				valid = crc32.ChecksumIEEE(z.header[:5]) == binary.LittleEndian.Uint32(z.header[5:])
+			} else {
+				valid = crc16(z.header[:5]) == binary.BigEndian.Uint16(z.header[5:])
+			}
+			if !valid {
+				z.state = zsScan
+				continue
+			}
+			events = z.onHeader(z.header[0], i, events)
+		case zsSubpacket:
+			// 十六进制头后面的 CR、LF、XON 不属于子包
+			if len(z.sub) == 0 && !z.escaped && (b == '\r' || b == '\n' || b == 0x8d || b == 0x8a || b == 0x11) {
+				continue
+			}
+			v, ok, end := z.decode(b)
+			if end || len(z.sub) >= zMaxSubpacket {
+				name, size := parseZmodemFileInfo(z.sub)
+				events = append(events, zmodemEvent{kind: zEventFile, end: i + 1, name: name, size: size})
+				z.state = zsScan
+				continue
+			}
+			if ok {
+				z.sub = append(z.sub, v)
+			}
+		}
+	}
+	return events
+}
+
+// onHeader 处理一个校验通过的帧头
+func (z *zmodemSniffer) onHeader(frameType byte, i int, events []zmodemEvent) []zmodemEvent {
+	z.state = zsScan
+	switch frameType {
+	case zFile:
+		z.state = zsSubpacket
+		z.sub = z.sub[:0]
+		z.escaped = false
+	case zSkip:
+		events = append(events, zmodemEvent{kind: zEventSkip, end: i + 1})
+	case zFin:
+		events = append(events, zmodemEvent{kind: zEventFin, end: i + 1})
+	}
+	return events
+}
+
+// decode 还原 ZDLE 转义，返回解码后的字节、是否有效以及是否为子包结束符
+func (z *zmodemSniffer) decode(b byte) (byte, bool, bool) {
+	if z.escaped {
+		z.escaped = false
+		switch b {
+		case 'h', 'i', 'j', 'k': // ZCRCE、ZCRCG、ZCRCQ、ZCRCW
+			return 0, false, true
+		case 'l':
+			return 0x7f, true, false
+		case 'm':
+			return 0xff, true, false
+		}
+		return b ^ 0x40, true, false
+	}
+	switch b {
+	case zDle:
+		z.escaped = true
+		return 0, false, false
+	case 0x11, 0x13, 0x91, 0x93: // XON/XOFF 流控字符
+		return 0, false, false
+	}
+	return b, true, false
+}
+
+func (z *zmodemSniffer) reset() {
+	z.state = zsScan
+	z.pads = 0
+	z.cans = 0
+	z.prevO = false
+	z.escaped = false
+}
+
+// parseZmodemFileInfo 解析 ZFILE 子包："文件名\0大小 修改时间 权限 ..."
+func parseZmodemFileInfo(sub []byte) (string, int64) {
+	name, info, _ := bytes.Cut(sub, []byte{0})
+	var size int64
+	if fields := strings.Fields(string(bytes.TrimRight(info, "\x00"))); len(fields) > 0 {
+		size, _ = strconv.ParseInt(fields[0], 10, 64)
+	}
+	return string(name), size
+}
+
+// crc16 CRC-16/XMODEM
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,279 @@
+package ssh
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Frame 需要按顺序写入 WebSocket 的消息（ZMODEM 传输数据和控制消息）
+type Frame struct {
+	Type  int
+	Data  []byte
+	Close bool // 写入后关闭会话（强制结束会话的提示）
+}
+
+// zmodemControl 发给浏览器的 ZMODEM 控制消息
+type zmodemControl struct {
+	Type      string          `json:"type"`
+	Event     string          `json:"event"` // start / end / error
+	Direction ZmodemDirection `json:"direction,omitempty"`
+	Message   string          `json:"message,omitempty"`
+}
+
+// zmodemTransfer 一次 rz/sz 传输的状态
+type zmodemTransfer struct {
+	direction ZmodemDirection
+	remote    zmodemSniffer // 远端输出
+	browser   zmodemSniffer // 浏览器输入
+	file      *ZmodemFile
+	finSeen   bool
+	finished  []ZmodemFile
+}
+
+// feed 处理一个方向的数据，返回传输结束的位置（-1 表示未结束）和需要主动取消的原因
+func (t *zmodemTransfer) feed(data []byte, fromRemote bool, limit int64) (int, string) {
+	sniffer := &t.browser
+	if fromRemote {
+		sniffer = &t.remote
+	}
+	for _, ev := range sniffer.Feed(data) {
+		switch ev.kind {
+		case zEventFile:
+			t.finishFile(ZmodemSuccess, "")
+			t.file = &ZmodemFile{Direction: t.direction, Name: ev.name, Size: ev.size, StartTime: time.Now()}
+			if limit > 0 && ev.size > limit {
+				reason := fmt.Sprintf("文件 %s 大小 %d 字节，超过限制 %d 字节", ev.name, ev.size, limit)
+				t.finishFile(ZmodemRejected, reason)
+				return ev.end, reason
+			}
+		case zEventSkip:
+			t.finishFile(ZmodemSkipped, "接收方跳过")
+		case zEventFin:
+			t.finishFile(ZmodemSuccess, "")
+			t.finSeen = true
+		case zEventOver:
+			if t.finSeen {
+				return ev.end, ""
+			}
+		case zEventAbort:
+			t.finishFile(ZmodemFailed, "传输被取消")
+			return ev.end, ""
+		}
+	}
+
+	// 声明的大小可能不可信，按实际传输量兜底；ZDLE 转义最多使数据膨胀一倍
+	if t.file != nil && fromRemote == (t.direction == ZmodemDownload) {
+		t.file.Transferred += int64(len(data))
+		if limit > 0 && t.file.Transferred > 2*limit {
+			reason := fmt.Sprintf("文件 %s 传输量超过限制 %d 字节", t.file.Name, limit)
+			t.finishFile(ZmodemRejected, reason)
+			return len(data), reason
+		}
+	}
+	return -1, ""
+}
+
+func (t *zmodemTransfer) finishFile(status ZmodemStatus, message string) {
+	if t.file == nil {
+		return
+	}
+	t.file.Status = status
+	t.file.Message = message
+	t.file.EndTime = time.Now()
+	t.finished = append(t.finished, *t.file)
+	t.file = nil
+}
+
+func (t *zmodemTransfer) take() []ZmodemFile {
+	files := t.finished
+	t.finished = nil
+	return files
+}
+
+// InTransfer 是否处于 rz/sz 传输中
+func (s *Session) InTransfer() bool {
+	s.zmu.Lock()
+	defer s.zmu.Unlock()
+	return s.zmodem != nil
+}
+
+// zmodemOutput 处理远端输出中的 rz/sz 传输，返回仍按普通终端输出处理的数据
+// 检测到握手后，握手及之后的数据都通过 FrameChan 按顺序以二进制帧发给浏览器
+func (s *Session) zmodemOutput(data []byte) []byte {
+	s.zmu.Lock()
+	if s.zmodem == nil {
+		// 握手可能被拆到两次输出中，带上上一次输出的末尾一起查找
+		combined := append(s.ztail, data...)
+		idx, direction := findZmodemStart(combined)
+		if idx < 0 {
+			s.zmu.Unlock()
+			if len(combined) > len(zmodemMarkerPrefix) {
+				combined = combined[len(combined)-len(zmodemMarkerPrefix):]
+			}
+			s.ztail = append([]byte(nil), combined...)
+			return data
+		}
+		if s.DisableTransfer {
+			// 取消远端的 rz/sz，握手之前的内容仍按普通输出发送
+			var text []byte
+			if idx > len(s.ztail) {
+				text = append(text, combined[len(s.ztail):idx]...)
+			}
+			s.ztail = nil
+			s.zmu.Unlock()
+			log.Printf("ZMODEM %s rejected in session %s: transfer disabled", direction, s.ID)
+			s.writeRemote(zmodemAbort)
+			return append(text, "\r\n\033[31m[rz/sz] 当前终端不支持文件传输\033[0m\r\n"...)
+		}
+		log.Printf("ZMODEM %s started in session %s", direction, s.ID)
+		// 已作为普通输出发送的握手前缀会在二进制帧中重发，浏览器需要完整的握手
+		if idx > len(s.ztail) {
+			s.sendFrame(websocket.TextMessage, combined[len(s.ztail):idx])
+		}
+		s.ztail = nil
+		s.zmodem = &zmodemTransfer{direction: direction}
+		s.sendControl(zmodemControl{Event: "start", Direction: direction})
+		data = combined[idx:]
+	}
+
+	// 传输期间没有键盘输入，同样视为活跃
+	s.mu.Lock()
+	s.lastInputTime = time.Now()
+	s.mu.Unlock()
+
+	var rest []byte
+	var files []ZmodemFile
+	end, reason := s.zmodem.feed(data, true, s.MaxTransferSize)
+	if end < 0 {
+		s.sendFrame(websocket.BinaryMessage, data)
+		files = s.zmodem.take()
+	} else {
+		if reason == "" {
+			s.sendFrame(websocket.BinaryMessage, data[:end])
+			rest = data[end:]
+		}
+		files = s.endTransfer(reason)
+	}
+	s.zmu.Unlock()
+
+	s.reportFiles(files)
+	if len(rest) > 0 {
+		s.sendFrame(websocket.TextMessage, rest)
+	}
+	return nil
+}
+
+// WriteTransfer 转发浏览器发送的 ZMODEM 数据，不在传输中时返回 false
+func (s *Session) WriteTransfer(data []byte) bool {
+	s.zmu.Lock()
+	if s.zmodem == nil {
+		s.zmu.Unlock()
+		return false
+	}
+
+	s.mu.Lock()
+	s.lastInputTime = time.Now()
+	s.mu.Unlock()
+
+	var files []ZmodemFile
+	end, reason := s.zmodem.feed(data, false, s.MaxTransferSize)
+	switch {
+	case end < 0:
+		s.writeRemote(data)
+		files = s.zmodem.take()
+	case reason != "":
+		// 超限的数据不再转发，直接取消
+		files = s.endTransfer(reason)
+	default:
+		s.writeRemote(data[:end])
+		files = s.endTransfer("")
+	}
+	s.zmu.Unlock()
+
+	s.reportFiles(files)
+	return true
+}
+
+// CancelTransfer 取消正在进行的 rz/sz 传输
+func (s *Session) CancelTransfer(reason string) {
+	s.zmu.Lock()
+	if s.zmodem == nil {
+		s.zmu.Unlock()
+		return
+	}
+	files := s.endTransfer(reason)
+	s.zmu.Unlock()
+	s.reportFiles(files)
+}
+
+// endTransfer 结束传输，reason 不为空时向两端发送取消序列，调用方需持有 zmu
+func (s *Session) endTransfer(reason string) []ZmodemFile {
+	t := s.zmodem
+	if reason != "" {
+		log.Printf("ZMODEM transfer cancelled in session %s: %s", s.ID, reason)
+		s.writeRemote(zmodemAbort)
+		s.sendFrame(websocket.BinaryMessage, zmodemAbort)
+		s.sendControl(zmodemControl{Event: "error", Message: reason})
+		t.finishFile(ZmodemFailed, reason)
+	} else {
+		t.finishFile(ZmodemFailed, "传输未完成")
+	}
+	s.sendControl(zmodemControl{Event: "end", Direction: t.direction})
+	s.zmodem = nil
+	log.Printf("ZMODEM %s finished in session %s", t.direction, s.ID)
+	return t.take()
+}
+
+// abortTransferOnClose 会话关闭时结束未完成的传输，只记录结果
+func (s *Session) abortTransferOnClose() {
+	s.zmu.Lock()
+	var files []ZmodemFile
+	if s.zmodem != nil {
+		s.zmodem.finishFile(ZmodemFailed, "会话已关闭")
+		files = s.zmodem.take()
+		s.zmodem = nil
+	}
+	s.zmu.Unlock()
+	s.reportFiles(files)
+}
+
+func (s *Session) reportFiles(files []ZmodemFile) {
+	if s.OnFileTransfer == nil {
+		return
+	}
+	for _, file := range files {
+		s.OnFileTransfer(file)
+	}
+}
+
+// sendFrame 按顺序发送消息，传输数据不能丢弃，通道满时阻塞以限制远端输出速度
+func (s *Session) sendFrame(msgType int, data []byte) {
+	frame := Frame{Type: msgType, Data: append([]byte(nil), data...)}
+	select {
+	case s.FrameChan <- frame:
+	case <-s.Done:
+	}
+}
+
+func (s *Session) sendControl(msg zmodemControl) {
+	msg.Type = "zmodem"
+	data, _ := json.Marshal(msg)
+	s.sendFrame(websocket.TextMessage, data)
+}
+
+// writeRemote 直接写入远端标准输入，传输期间浏览器不会发送普通按键
+// 不经过 InputChan，避免文件内容被输入日志记录
+func (s *Session) writeRemote(data []byte) {
+	if len(data) == 0 || s.stdin == nil {
+		return
+	}
+	n, err := s.stdin.Write(data)
+	s.bytesIn.Add(int64(n))
+	if err != nil {
+		log.Printf("ZMODEM write error for session %s: %v", s.ID, err)
+	}
+}
@@ -0,0 +1,173 @@
+package websocket
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// 每个查看者的待发送消息缓冲，超过后视为慢客户端并断开
+const taskSendBuffer = 512
+
+// TaskHub 批量任务输出的订阅中心，同一任务可被多个客户端同时查看
+type TaskHub struct {
+	tasks      map[uint]map[*TaskClient]bool // taskID -> 订阅的客户端
+	register   chan *TaskClient
+	unregister chan *TaskClient
+	mu         sync.RWMutex
+}
+
+type TaskClient struct {
+	TaskID uint
+	Conn   *websocket.Conn
+	Send   chan []byte
+	Hub    *TaskHub
+	ready  chan struct{}
+}
+
+func NewTaskHub() *TaskHub {
+	return &TaskHub{
+		tasks:      make(map[uint]map[*TaskClient]bool),
+		register:   make(chan *TaskClient),
+		unregister: make(chan *TaskClient),
+	}
+}
+
+func NewTaskClient(hub *TaskHub, taskID uint, conn *websocket.Conn) *TaskClient {
+	return &TaskClient{
+		TaskID: taskID,
+		Conn:   conn,
+		Send:   make(chan []byte, taskSendBuffer),
+		Hub:    hub,
+		ready:  make(chan struct{}),
+	}
+}
+
+// 使用通道处理客户端注册/注销，避免竞态条件
+func (h *TaskHub) Run() {
+	for {
+		select {
+		case client := <-h.register:
+			h.mu.Lock()
+			if h.tasks[client.TaskID] == nil {
+				h.tasks[client.TaskID] = make(map[*TaskClient]bool)
+			}
+			h.tasks[client.TaskID][client] = true
+			h.mu.Unlock()
+			close(client.ready)
+
+		case client := <-h.unregister:
+			h.mu.Lock()
+			if clients, ok := h.tasks[client.TaskID]; ok && clients[client] {
+				delete(clients, client)
+				if len(clients) == 0 {
+					delete(h.tasks, client.TaskID)
+				}
+				close(client.Send)
+			}
+			h.mu.Unlock()
+		}
+	}
+}
+
+// Register 注册客户端，返回后该客户端即可收到之后发布的消息
+func (h *TaskHub) Register(client *TaskClient) {
+	h.register <- client
+	<-client.ready
+}
+
+// Unregister 注销客户端并关闭其发送通道
+func (h *TaskHub) Unregister(client *TaskClient) {
+	h.unregister <- client
+}
+
+// Publish 向订阅某任务的所有客户端推送消息
+func (h *TaskHub) Publish(taskID uint, message []byte) {
+	h.mu.RLock()
+	var slow []*TaskClient
+	for client := range h.tasks[taskID] {
+		select {
+		case client.Send <- message:
+		default:
+			// 缓冲区满，断开连接
+			slow = append(slow, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range slow {
+		go func(client *TaskClient) { h.unregister <- client }(client)
+	}
+}
+
+// CloseTask 任务结束后断开该任务的所有订阅客户端（已在缓冲中的消息会先发送完）
+func (h *TaskHub) CloseTask(taskID uint) {
+	h.mu.RLock()
+	clients := make([]*TaskClient, 0, len(h.tasks[taskID]))
+	for client := range h.tasks[taskID] {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		h.unregister <- client
+	}
+}
+
+// ReadPump 读取协程：任务输出为只读推送，这里只负责处理心跳和检测连接关闭
+func (c *TaskClient) ReadPump() {
+	defer func() {
+		c.Hub.unregister <- c
+		c.Conn.Close()
+	}()
+
+	c.Conn.SetReadLimit(maxMessageSize)
+	c.Conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.Conn.SetPongHandler(func(string) error {
+		c.Conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		if _, _, err := c.Conn.ReadMessage(); err != nil {
+			if websocket.IsUnexpectedCloseError(err,
+				websocket.CloseGoingAway,
+				websocket.CloseAbnormalClosure) {
+				log.Printf("WebSocket error: %v", err)
+			}
+			break
+		}
+	}
+}
+
+// WritePump 写入协程：将 Send 中的消息写入 WebSocket，Send 关闭后发送关闭帧
+func (c *TaskClient) WritePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.Conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-c.Send:
+			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.Conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			if err := c.Conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+-- ==================== 脚本库表结构迁移 ====================
+
+-- 1. 脚本表
+CREATE TABLE IF NOT EXISTS `scripts` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
+    `name` VARCHAR(100) NOT NULL COMMENT '脚本名称',
+    `language` VARCHAR(20) NOT NULL COMMENT '脚本语言(bash,shell,python,perl)',
+    `description` VARCHAR(500) COMMENT '描述',
+    `latest_version` INT NOT NULL DEFAULT 1 COMMENT '最新版本号',
+    `owner` BIGINT UNSIGNED NOT NULL COMMENT '所有者ID',
+    `status` TINYINT(1) NOT NULL DEFAULT 1 COMMENT '状态(0:禁用,1:启用)',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY `uk_name` (`name`),
+    KEY `idx_owner` (`owner`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='脚本表';
+
+-- 2. 脚本版本表
+CREATE TABLE IF NOT EXISTS `script_versions` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
+    `script_id` BIGINT UNSIGNED NOT NULL COMMENT '脚本ID',
+    `version` INT NOT NULL COMMENT '版本号',
+    `content` LONGTEXT NOT NULL COMMENT '脚本内容',
+    `parameters` TEXT COMMENT '参数定义(JSON)',
+    `change_log` VARCHAR(255) COMMENT '变更说明',
+    `created_by` BIGINT UNSIGNED NOT NULL COMMENT '创建人ID',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    UNIQUE KEY `uk_script_version` (`script_id`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='脚本版本表';
+
+-- 3. 批量任务关联脚本版本
+ALTER TABLE `batch_tasks`
+    ADD COLUMN `script_id` BIGINT UNSIGNED DEFAULT 0 COMMENT '脚本库脚本ID(0表示内联脚本)' AFTER `script_type`,
+    ADD COLUMN `script_ver` INT DEFAULT 0 COMMENT '脚本版本号' AFTER `script_id`;
+
+-- 4. 任务主机记录退出码
+ALTER TABLE `task_host_relations`
+    ADD COLUMN `exit_code` INT DEFAULT 0 COMMENT '退出码' AFTER `status`;
@@ -0,0 +1,14 @@
+-- ==================== 文件分发与拉取字段迁移 ====================
+
+-- 1. 批量任务记录分发参数和源文件校验和
+ALTER TABLE `batch_tasks`
+    ADD COLUMN `file_mode` VARCHAR(10) COMMENT '文件权限(如0644，文件分发)' AFTER `target_path`,
+    ADD COLUMN `file_owner` VARCHAR(100) COMMENT '文件属主(user[:group]，文件分发)' AFTER `file_mode`,
+    ADD COLUMN `checksum` VARCHAR(64) COMMENT '源文件SHA-256(文件分发)' AFTER `file_owner`,
+    ADD COLUMN `backup` TINYINT(1) DEFAULT 0 COMMENT '是否备份目标主机上的原文件' AFTER `checksum`;
+
+-- 2. 任务主机记录传输进度、校验和与备份路径
+ALTER TABLE `task_host_relations`
+    ADD COLUMN `progress` DECIMAL(5,2) DEFAULT 0 COMMENT '传输进度(0-100)' AFTER `exit_code`,
+    ADD COLUMN `checksum` VARCHAR(64) COMMENT '传输后文件SHA-256' AFTER `progress`,
+    ADD COLUMN `backup_path` VARCHAR(500) COMMENT '原文件备份路径' AFTER `checksum`;
@@ -0,0 +1,24 @@
+-- ==================== 主机标签与标签选择器迁移 ====================
+
+-- 1. 主机标签表
+CREATE TABLE IF NOT EXISTS `host_labels` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
+    `host_id` BIGINT UNSIGNED NOT NULL COMMENT '主机ID',
+    `label_key` VARCHAR(63) NOT NULL COMMENT '标签键',
+    `label_value` VARCHAR(63) NOT NULL DEFAULT '' COMMENT '标签值',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    UNIQUE KEY `uk_host_label_key` (`host_id`, `label_key`),
+    KEY `idx_label_key_value` (`label_key`, `label_value`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='主机标签表';
+
+-- 2. 批量任务按标签选择器选择主机
+ALTER TABLE `batch_tasks`
+    ADD COLUMN `host_selector` VARCHAR(500) COMMENT '主机标签选择器(执行时解析)' AFTER `script_type`;
+
+-- 3. 主机权限支持按标签选择器授权
+-- host_selector 不允许 NULL，按主机组授权时为空字符串，唯一索引才能拦截重复授权
+ALTER TABLE `host_user_permissions`
+    MODIFY COLUMN `host_group_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '主机组ID(0表示按标签选择器授权)',
+    ADD COLUMN `host_selector` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '主机标签选择器(校验权限时解析，按主机组授权时为空)' AFTER `host_group_id`,
+    DROP INDEX `uk_user_host`,
+    ADD UNIQUE KEY `uk_user_host` (`user_group_id`, `host_group_id`, `host_selector`);
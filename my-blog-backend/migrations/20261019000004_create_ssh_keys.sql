@@ -0,0 +1,31 @@
+-- ==================== 托管密钥迁移 ====================
+
+-- 1. 托管密钥表
+CREATE TABLE IF NOT EXISTS `ssh_keys` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
+    `name` VARCHAR(100) NOT NULL COMMENT '密钥名称',
+    `key_type` VARCHAR(20) NOT NULL COMMENT '密钥类型(ed25519,rsa)',
+    `bits` INT DEFAULT 0 COMMENT '密钥长度(RSA)',
+    `public_key` TEXT NOT NULL COMMENT '公钥(authorized_keys格式)',
+    `private_key` TEXT NOT NULL COMMENT '私钥内容(加密)',
+    `fingerprint` VARCHAR(100) NOT NULL COMMENT 'SHA256指纹',
+    `status` VARCHAR(20) NOT NULL DEFAULT 'active' COMMENT '状态(active,retired)',
+    `rotate_days` INT DEFAULT 0 COMMENT '自动轮换周期(天，0表示不自动轮换)',
+    `next_rotate_at` DATETIME COMMENT '下次轮换时间',
+    `replaced_by` BIGINT UNSIGNED DEFAULT 0 COMMENT '轮换后的新密钥ID',
+    `created_by` BIGINT UNSIGNED NOT NULL COMMENT '创建人ID',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY `uk_fingerprint` (`fingerprint`),
+    KEY `idx_status` (`status`),
+    KEY `idx_next_rotate_at` (`next_rotate_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='托管密钥表';
+
+-- 2. 主机关联托管密钥
+ALTER TABLE `remote_hosts`
+    ADD COLUMN `key_id` BIGINT UNSIGNED DEFAULT 0 COMMENT '托管密钥ID(0表示手工配置的私钥)' AFTER `secret_key`,
+    ADD KEY `idx_key_id` (`key_id`);
+
+-- 3. 批量任务类型增加密钥分发
+ALTER TABLE `batch_tasks`
+    MODIFY COLUMN `type` TINYINT(1) NOT NULL COMMENT '任务类型(1:命令,2:文件上传,3:文件下载,4:脚本,5:密钥分发)';
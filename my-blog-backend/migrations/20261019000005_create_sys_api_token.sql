@@ -0,0 +1,17 @@
+-- ==================== API Token 迁移 ====================
+
+-- 用户 API Token 表（供 CI 等机器调用方使用，只保存哈希）
+CREATE TABLE IF NOT EXISTS `sys_api_token` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `user_id` bigint NOT NULL COMMENT '所属用户ID',
+  `name` varchar(100) NOT NULL COMMENT '名称',
+  `token_hash` char(64) NOT NULL COMMENT 'Token的SHA-256哈希',
+  `prefix` varchar(20) NOT NULL COMMENT 'Token前缀(用于识别)',
+  `expires_at` datetime DEFAULT NULL COMMENT '过期时间(空表示永不过期)',
+  `last_used_at` datetime DEFAULT NULL COMMENT '最后使用时间',
+  `last_used_ip` varchar(50) DEFAULT NULL COMMENT '最后使用IP',
+  `create_time` datetime DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_token_hash` (`token_hash`),
+  KEY `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='用户API Token表';
@@ -0,0 +1,23 @@
+-- ==================== 主机账号密码轮换迁移 ====================
+
+-- 1. 主机账号增加轮换计划
+ALTER TABLE `host_accounts`
+    ADD COLUMN `rotate_days` INT DEFAULT 0 COMMENT '密码自动轮换周期(天，0表示不自动轮换)' AFTER `remark`,
+    ADD COLUMN `next_rotate_at` DATETIME COMMENT '下次轮换时间' AFTER `rotate_days`,
+    ADD COLUMN `last_rotated_at` DATETIME COMMENT '最后轮换时间' AFTER `next_rotate_at`,
+    ADD KEY `idx_next_rotate_at` (`next_rotate_at`);
+
+-- 2. 密码轮换记录表
+CREATE TABLE IF NOT EXISTS `host_account_rotations` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
+    `account_id` BIGINT UNSIGNED NOT NULL COMMENT '主机账号ID',
+    `host_id` BIGINT UNSIGNED NOT NULL COMMENT '主机ID',
+    `method` VARCHAR(20) NOT NULL COMMENT '修改方式(chpasswd,passwd)',
+    `status` VARCHAR(20) NOT NULL COMMENT '状态(success,failed,rolled_back,broken)',
+    `message` TEXT COMMENT '失败原因',
+    `source` VARCHAR(20) NOT NULL COMMENT '触发来源(manual,schedule)',
+    `created_by` BIGINT UNSIGNED NOT NULL COMMENT '操作人ID',
+    `started_at` DATETIME NOT NULL COMMENT '开始时间',
+    `finished_at` DATETIME NOT NULL COMMENT '结束时间',
+    KEY `idx_account_id` (`account_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='主机账号密码轮换记录表';
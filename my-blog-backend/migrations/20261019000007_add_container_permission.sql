@@ -0,0 +1,9 @@
+-- ==================== 容器终端迁移 ====================
+
+-- 1. 主机授权增加可进入的容器
+ALTER TABLE `host_user_permissions`
+    ADD COLUMN `container_pattern` VARCHAR(500) COMMENT '可进入的容器(名称或ID通配符，逗号分隔，空表示不允许)' AFTER `host_selector`;
+
+-- 2. 审计日志记录容器ID
+ALTER TABLE `audit_logs`
+    ADD COLUMN `container_id` VARCHAR(64) COMMENT '容器ID(容器终端会话)' AFTER `session_id`;
@@ -0,0 +1,27 @@
+-- ==================== 终端会话记录迁移 ====================
+
+CREATE TABLE IF NOT EXISTS `terminal_sessions` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
+    `session_id` VARCHAR(100) NOT NULL COMMENT '会话ID',
+    `user_id` BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    `user_name` VARCHAR(50) NOT NULL COMMENT '用户名',
+    `host_id` BIGINT UNSIGNED NOT NULL COMMENT '主机ID',
+    `host_name` VARCHAR(100) NOT NULL COMMENT '主机名称',
+    `host_address` VARCHAR(100) NOT NULL COMMENT '主机地址',
+    `host_account` VARCHAR(50) NOT NULL COMMENT '登录主机使用的账号',
+    `container_id` VARCHAR(64) COMMENT '容器ID(容器终端会话)',
+    `client_ip` VARCHAR(50) COMMENT '客户端IP',
+    `client_agent` VARCHAR(255) COMMENT '客户端User-Agent',
+    `recording_id` VARCHAR(100) COMMENT '会话录像ID',
+    `status` VARCHAR(20) NOT NULL COMMENT '状态(active,closed)',
+    `close_reason` VARCHAR(255) COMMENT '断开原因(超时或强制断开)',
+    `bytes_in` BIGINT NOT NULL DEFAULT 0 COMMENT '输入字节数',
+    `bytes_out` BIGINT NOT NULL DEFAULT 0 COMMENT '输出字节数',
+    `started_at` DATETIME NOT NULL COMMENT '开始时间',
+    `last_input_at` DATETIME COMMENT '最后输入时间',
+    `ended_at` DATETIME COMMENT '结束时间',
+    KEY `idx_session_id` (`session_id`),
+    KEY `idx_user_started` (`user_id`, `started_at`),
+    KEY `idx_host_id` (`host_id`),
+    KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='终端会话记录表';
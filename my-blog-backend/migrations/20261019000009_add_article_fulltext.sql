@@ -0,0 +1,8 @@
+-- ==================== 文章全文索引迁移 ====================
+-- 仅在 search.engine 配置为 mysql 时需要执行
+-- 使用 ngram 分词器支持中文检索，分词长度由 ngram_token_size 控制（默认 2）
+-- 标题、摘要、正文分别建立索引，检索时按字段权重计算得分
+
+ALTER TABLE `articles` ADD FULLTEXT INDEX `ft_articles_title` (`title`) WITH PARSER ngram;
+ALTER TABLE `articles` ADD FULLTEXT INDEX `ft_articles_summary` (`summary`) WITH PARSER ngram;
+ALTER TABLE `articles` ADD FULLTEXT INDEX `ft_articles_content` (`content`) WITH PARSER ngram;
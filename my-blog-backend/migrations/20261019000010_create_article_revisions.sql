@@ -0,0 +1,16 @@
+-- ==================== 文章修订历史迁移 ====================
+
+CREATE TABLE IF NOT EXISTS `article_revisions` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
+    `article_id` BIGINT UNSIGNED NOT NULL COMMENT '文章ID',
+    `version` INT NOT NULL COMMENT '修订版本号',
+    `title` VARCHAR(200) NOT NULL COMMENT '文章标题',
+    `summary` TEXT COMMENT '文章摘要',
+    `content` LONGTEXT NOT NULL COMMENT '文章正文',
+    `tags` TEXT COMMENT '标签快照(JSON)',
+    `editor_id` BIGINT UNSIGNED NOT NULL COMMENT '编辑人ID',
+    `action` VARCHAR(20) NOT NULL COMMENT '操作类型(create,update,restore)',
+    `restored_from` INT NOT NULL DEFAULT 0 COMMENT '恢复来源版本号',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '修订时间',
+    UNIQUE KEY `uk_article_revision` (`article_id`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='文章修订历史表';
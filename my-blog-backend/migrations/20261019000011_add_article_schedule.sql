@@ -0,0 +1,7 @@
+-- ==================== 文章定时发布迁移 ====================
+-- status 新增 2(定时发布)，定时发布的文章 published_at 为计划发布时间
+
+ALTER TABLE `articles`
+    ADD COLUMN `unpublish_at` DATETIME NULL DEFAULT NULL COMMENT '定时下线时间' AFTER `published_at`,
+    ADD KEY `idx_articles_status_published_at` (`status`, `published_at`),
+    ADD KEY `idx_articles_unpublish_at` (`unpublish_at`);
@@ -0,0 +1,8 @@
+-- ==================== 文章 SEO 字段迁移 ====================
+-- 字段为空时由接口按标题、摘要、文章地址和封面生成默认值
+
+ALTER TABLE `articles`
+    ADD COLUMN `meta_title` VARCHAR(200) NOT NULL DEFAULT '' COMMENT 'SEO 标题' AFTER `unpublish_at`,
+    ADD COLUMN `meta_description` VARCHAR(500) NOT NULL DEFAULT '' COMMENT 'SEO 描述' AFTER `meta_title`,
+    ADD COLUMN `canonical_url` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '规范链接' AFTER `meta_description`,
+    ADD COLUMN `og_image` VARCHAR(500) NOT NULL DEFAULT '' COMMENT 'Open Graph 分享图片' AFTER `canonical_url`;
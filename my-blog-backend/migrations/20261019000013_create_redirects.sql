@@ -0,0 +1,20 @@
+-- ==================== 重定向与导入评论迁移 ====================
+-- 从其他博客系统导入时，文章原地址保存为重定向；导入的游客评论没有关联用户，保留作者信息
+
+CREATE TABLE IF NOT EXISTS `redirects` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
+    `from_path` VARCHAR(500) NOT NULL COMMENT '旧路径',
+    `to_path` VARCHAR(500) NOT NULL COMMENT '目标路径或完整URL',
+    `status_code` INT NOT NULL DEFAULT 301 COMMENT '跳转状态码',
+    `source` VARCHAR(20) NOT NULL COMMENT '来源(import)',
+    `hits` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '命中次数',
+    `last_hit_at` DATETIME NULL COMMENT '最后命中时间',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    UNIQUE KEY `uk_redirect_from` (`from_path`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='重定向表';
+
+ALTER TABLE `comment`
+    ADD COLUMN `author_name` VARCHAR(100) NOT NULL DEFAULT '' COMMENT '游客评论的作者名称' AFTER `ip_address`,
+    ADD COLUMN `author_email` VARCHAR(100) NOT NULL DEFAULT '' COMMENT '游客评论的作者邮箱' AFTER `author_name`,
+    ADD COLUMN `author_url` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '游客评论的作者网站' AFTER `author_email`;
@@ -0,0 +1,17 @@
+-- ==================== slug 历史记录迁移 ====================
+-- 修改文章、分类、标签、系列的 slug 时记录旧 slug，按旧 slug 查询时找到当前的实体；
+-- 旧地址同时写入重定向表（来源 slug），重定向表也用于管理员手动添加的重定向（来源 manual）
+
+CREATE TABLE IF NOT EXISTS `slug_histories` (
+    `id` BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
+    `entity_type` VARCHAR(20) NOT NULL COMMENT '实体类型(article/category/tag/series)',
+    `old_slug` VARCHAR(200) NOT NULL COMMENT '旧slug',
+    `entity_id` BIGINT UNSIGNED NOT NULL COMMENT '实体ID',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '修改时间',
+    UNIQUE KEY `uk_slug_history` (`entity_type`, `old_slug`),
+    KEY `idx_slug_histories_entity_id` (`entity_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='slug历史记录表';
+
+ALTER TABLE `redirects`
+    MODIFY COLUMN `source` VARCHAR(20) NOT NULL COMMENT '来源(import/slug/manual)',
+    ADD KEY `idx_redirects_to_path` (`to_path`(191));
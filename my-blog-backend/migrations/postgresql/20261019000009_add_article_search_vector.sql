@@ -0,0 +1,6 @@
+-- ==================== 文章全文检索迁移 ====================
+-- 仅在 search.engine 配置为 postgres 时需要执行
+-- search_vector 由应用分词后写入（中文按二元组切分），应用启动时会为已发布文章重建
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN(search_vector);
@@ -57,6 +57,7 @@ CREATE TABLE IF NOT EXISTS articles (
     comment_count INTEGER DEFAULT 0,
     is_top BOOLEAN DEFAULT FALSE,
     published_at TIMESTAMP,
+    search_vector TSVECTOR,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL,
@@ -71,6 +72,7 @@ CREATE INDEX idx_articles_published_at ON articles(published_at);
 CREATE INDEX idx_articles_view_count ON articles(view_count);
 CREATE INDEX idx_articles_created_at ON articles(created_at);
 CREATE INDEX idx_articles_is_top ON articles(is_top);
+CREATE INDEX idx_articles_search_vector ON articles USING GIN(search_vector);
 
 -- 标签表
 CREATE TABLE IF NOT EXISTS tags (